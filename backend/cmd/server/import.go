@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"devopslabs/internal/config"
+	"devopslabs/internal/importer"
+	"devopslabs/internal/service"
+)
+
+// runImport реализует подкоманду import: читает выгрузку из файла или
+// стандартного ввода и записывает задачи напрямую в базу, минуя HTTP API.
+//
+//	server import -source jira -mapping mapping.json [-dry-run] export.xml
+func runImport(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	source := flags.String("source", importer.SourceFile, "источник: "+strings.Join(importer.Sources, ", "))
+	format := flags.String("format", "", "формат файла для source=file: csv или json; по умолчанию по расширению")
+	mappingPath := flags.String("mapping", "", "JSON-файл сопоставления статусов, приоритетов, пользователей, меток и колонок")
+	columns := flags.String("map", "", "сопоставление колонок CSV: Summary:title,Story Points:effortHours")
+	dryRun := flags.Bool("dry-run", false, "только проверить файл и показать отчёт")
+	batchSize := flags.Int("batch", importer.DefaultBatchSize, "число строк в одной транзакции")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("укажите файл выгрузки или - для стандартного ввода")
+	}
+	path := flags.Arg(0)
+
+	var errs service.ValidationErrors
+	sourceName, err := importer.ParseSource(*source)
+	errs.Add(err)
+	formatName, err := importer.ParseFormat(*format, contentTypeOf(path))
+	errs.Add(err)
+	columnMapping, err := importer.ParseMapping(*columns)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+	mapping, err := loadMappingFile(*mappingPath)
+	if err != nil {
+		return err
+	}
+
+	input := stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	records, ignored, err := importer.Read(sourceName, formatName, input, mapping.WithColumns(columnMapping))
+	if err != nil {
+		return err
+	}
+
+	database, err := connectDB(config.Load().DBDSN)
+	if err != nil {
+		return err
+	}
+	if err := migrateDB(database); err != nil {
+		return err
+	}
+
+	report := importer.Run(context.Background(), newTaskStore(database), records, importer.Options{DryRun: *dryRun, BatchSize: *batchSize})
+	printImportReport(stdout, report, ignored, *dryRun)
+	if report.Failed > 0 {
+		return fmt.Errorf("не импортировано строк: %d", report.Failed)
+	}
+	return nil
+}
+
+func loadMappingFile(path string) (importer.SourceMapping, error) {
+	if path == "" {
+		return importer.SourceMapping{}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return importer.SourceMapping{}, err
+	}
+	defer file.Close()
+	return importer.LoadMapping(file)
+}
+
+func contentTypeOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "application/json"
+	}
+	return ""
+}
+
+func printImportReport(w io.Writer, report importer.Report, ignored []string, dryRun bool) {
+	if dryRun {
+		fmt.Fprintln(w, "пробный запуск: изменения не сохранены")
+	}
+	if len(ignored) > 0 {
+		fmt.Fprintf(w, "пропущены колонки: %s\n", strings.Join(ignored, ", "))
+	}
+	fmt.Fprintf(w, "создано: %d, обновлено: %d, ошибок: %d\n", report.Created, report.Updated, report.Failed)
+	for _, row := range report.Rows {
+		if row.Err == nil {
+			continue
+		}
+		reference := ""
+		if row.ExternalID != "" {
+			reference = " (" + row.ExternalID + ")"
+		}
+		fmt.Fprintf(w, "строка %d%s: %v\n", row.Row, reference, row.Err)
+	}
+}
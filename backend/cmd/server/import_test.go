@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type memoryTaskStore struct {
+	tasks []domain.Task
+}
+
+func (s *memoryTaskStore) List(_ context.Context, filter repository.TaskFilter) ([]domain.Task, error) {
+	var result []domain.Task
+	for _, task := range s.tasks {
+		if filter.Matches(task) {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryTaskStore) Get(_ context.Context, id uint) (*domain.Task, error) {
+	for _, task := range s.tasks {
+		if task.ID == id {
+			return &task, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (s *memoryTaskStore) Create(_ context.Context, task *domain.Task) error {
+	task.ID = uint(len(s.tasks) + 1)
+	s.tasks = append(s.tasks, *task)
+	return nil
+}
+
+func (s *memoryTaskStore) Update(_ context.Context, task *domain.Task) error {
+	s.tasks[task.ID-1] = *task
+	return nil
+}
+
+func (s *memoryTaskStore) Delete(context.Context, uint) error {
+	return nil
+}
+
+func stubImportStore(t *testing.T) *memoryTaskStore {
+	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
+	store := &memoryTaskStore{}
+	originalConnect := connectDB
+	originalMigrate := migrateDB
+	originalStore := newTaskStore
+	connectDB = func(path string) (*gorm.DB, error) {
+		return &gorm.DB{}, nil
+	}
+	migrateDB = func(database *gorm.DB) error {
+		return nil
+	}
+	newTaskStore = func(database *gorm.DB) repository.TaskStore {
+		return store
+	}
+	t.Cleanup(func() {
+		connectDB = originalConnect
+		migrateDB = originalMigrate
+		newTaskStore = originalStore
+	})
+	return store
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRunImportGitHubWithMapping(t *testing.T) {
+	store := stubImportStore(t)
+	issues := writeFile(t, "issues.json", `[
+		{"number": 3, "title": "Fix login", "state": "open", "html_url": "https://github.com/acme/web/issues/3",
+		 "labels": [{"name": "bug"}, {"name": "sev1"}], "assignee": {"login": "octocat"}}
+	]`)
+	mapping := writeFile(t, "mapping.json", `{"priorities": {"sev1": "critical"}, "users": {"octocat": "alice"}}`)
+
+	var out bytes.Buffer
+	require.NoError(t, runImport([]string{"-source", "github", "-mapping", mapping, "-dry-run", issues}, nil, &out))
+	require.Contains(t, out.String(), "пробный запуск")
+	require.Empty(t, store.tasks)
+
+	out.Reset()
+	require.NoError(t, runImport([]string{"-source", "github", "-mapping", mapping, issues}, nil, &out))
+	require.Contains(t, out.String(), "создано: 1, обновлено: 0, ошибок: 0")
+	require.Len(t, store.tasks, 1)
+	require.Equal(t, "github:acme/web#3", store.tasks[0].ExternalID)
+	require.Equal(t, domain.PriorityCritical, store.tasks[0].Priority)
+	require.Equal(t, "alice", store.tasks[0].Owner)
+
+	out.Reset()
+	require.NoError(t, runImport([]string{"-source", "github", "-mapping", mapping, issues}, nil, &out))
+	require.Contains(t, out.String(), "создано: 0, обновлено: 1")
+	require.Len(t, store.tasks, 1)
+}
+
+func TestRunImportReportsFailedRowsFromStdin(t *testing.T) {
+	store := stubImportStore(t)
+	input := strings.NewReader("Key,Title,Status\nA-1,Ship,done\nA-2,Plan,someday\n")
+
+	var out bytes.Buffer
+	err := runImport([]string{"-"}, input, &out)
+	require.EqualError(t, err, "не импортировано строк: 1")
+	require.Contains(t, out.String(), "строка 3 (A-2): некорректный статус: someday")
+	require.Len(t, store.tasks, 1)
+}
+
+func TestRunImportRejectsArguments(t *testing.T) {
+	stubImportStore(t)
+	var out bytes.Buffer
+	require.Error(t, runImport(nil, nil, &out))
+	require.Error(t, runImport([]string{"-source", "asana", "file.csv"}, nil, &out))
+	require.Error(t, runImport([]string{"-mapping", filepath.Join(t.TempDir(), "missing.json"), "file.csv"}, nil, &out))
+	require.Error(t, runImport([]string{"-unknown"}, nil, &out))
+}
+
+func TestMainDispatchesImport(t *testing.T) {
+	stubImportStore(t)
+	originalArgs := os.Args
+	originalExit := exit
+	code := 0
+	exit = func(status int) {
+		code = status
+	}
+	t.Cleanup(func() {
+		os.Args = originalArgs
+		exit = originalExit
+	})
+
+	os.Args = []string{"server", "import", filepath.Join(t.TempDir(), "missing.csv")}
+	main()
+	require.Equal(t, 1, code)
+}
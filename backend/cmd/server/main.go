@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"os"
+	"time"
+	// Образ не содержит tzdata, а выгрузки принимают часовой пояс IANA.
+	_ "time/tzdata"
 
 	"devopslabs/internal/config"
 	"devopslabs/internal/database"
 	"devopslabs/internal/domain"
+	"devopslabs/internal/events"
+	"devopslabs/internal/history"
 	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/grpcapi"
 	"devopslabs/internal/transport/httpapi"
+	"devopslabs/internal/worktime"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
@@ -20,13 +31,40 @@ var startServer = func(addr string, router Router) error {
 	return router.Run(addr)
 }
 
+var startGRPCServer = func(addr string, server *grpc.Server) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
 var exit = os.Exit
 var connectDB = database.Connect
+var newTaskStore = func(database *gorm.DB) repository.TaskStore {
+	return history.NewRecordingStore(repository.NewGormTaskStore(database), repository.NewGormChangeStore(database), nil)
+}
 var migrateDB = func(database *gorm.DB) error {
-	return database.AutoMigrate(&domain.Task{})
+	return database.AutoMigrate(
+		&domain.Task{}, &repository.IdempotencyRecord{}, &domain.SavedView{},
+		&domain.TaskChange{}, &repository.InsightSnapshot{}, &domain.OwnerCapacity{},
+	)
+}
+var startSnapshots = func(ctx context.Context, job *history.SnapshotJob, interval time.Duration) {
+	go job.Run(ctx, interval)
+}
+var startSLAMonitor = func(ctx context.Context, monitor *events.SLAMonitor, interval time.Duration) {
+	go monitor.Run(ctx, interval)
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			log.Printf("ошибка импорта: %v", err)
+			exit(1)
+		}
+		return
+	}
 	if err := run(); err != nil {
 		log.Printf("ошибка сервера: %v", err)
 		exit(1)
@@ -35,6 +73,13 @@ func main() {
 
 func run() error {
 	cfg := config.Load()
+	policy, err := service.LoadPolicy(cfg.ScoringPolicyFile)
+	if err != nil {
+		return err
+	}
+	if policy.Calendars, err = worktime.Load(cfg.WorkCalendarFile); err != nil {
+		return err
+	}
 
 	database, err := connectDB(cfg.DBDSN)
 	if err != nil {
@@ -45,12 +90,41 @@ func run() error {
 		return err
 	}
 
-	taskStore := repository.NewGormTaskStore(database)
-	router := httpapi.NewRouter(taskStore)
+	// Оба API пишут через одно хранилище, поэтому WatchTasks в gRPC видит
+	// и изменения, сделанные через REST.
+	bus := events.NewBus()
+	taskStore := events.NewNotifyingStore(newTaskStore(database), bus, nil)
+	snapshots := repository.NewGormSnapshotStore(database)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startSnapshots(ctx, history.NewSnapshotJob(taskStore, snapshots, nil, &policy), cfg.SnapshotInterval)
+	startSLAMonitor(ctx, events.NewSLAMonitor(taskStore, bus, nil, &policy), cfg.SLACheckInterval)
 
-	if err := startServer(":"+cfg.Port, router); err != nil {
+	router := httpapi.NewRouter(
+		taskStore,
+		httpapi.WithIdempotency(repository.NewGormIdempotencyStore(database), cfg.IdempotencyTTL),
+		httpapi.WithViews(repository.NewGormViewStore(database)),
+		httpapi.WithCapacity(repository.NewGormCapacityStore(database)),
+		httpapi.WithCalendarSecret(cfg.CalendarSecret),
+		httpapi.WithHistory(repository.NewGormChangeStore(database), snapshots),
+		httpapi.WithPolicy(policy),
+	)
+	grpcServer := grpcapi.NewServer(taskStore, bus, nil, &policy)
+
+	grpcErrs := make(chan error, 1)
+	go func() {
+		grpcErrs <- startGRPCServer(":"+cfg.GRPCPort, grpcServer)
+	}()
+	httpErrs := make(chan error, 1)
+	go func() {
+		httpErrs <- startServer(":"+cfg.Port, router)
+	}()
+
+	select {
+	case err := <-httpErrs:
+		grpcServer.Stop()
+		return err
+	case err := <-grpcErrs:
 		return err
 	}
-
-	return nil
 }
@@ -1,15 +1,76 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"os"
 	"testing"
+	"time"
+
+	"devopslabs/internal/events"
+	"devopslabs/internal/history"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
+// Заглушка базы в тестах run не выдерживает запросов, поэтому задания
+// снимков и проверки SLA по умолчанию не запускаются.
+func TestMain(m *testing.M) {
+	startSnapshots = func(context.Context, *history.SnapshotJob, time.Duration) {}
+	startSLAMonitor = func(context.Context, *events.SLAMonitor, time.Duration) {}
+	os.Exit(m.Run())
+}
+
+func TestRunStartsSnapshots(t *testing.T) {
+	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
+	t.Setenv("SNAPSHOT_INTERVAL", "10m")
+	t.Setenv("SLA_CHECK_INTERVAL", "30s")
+
+	originalStart := startServer
+	originalConnect := connectDB
+	originalMigrate := migrateDB
+	originalSnapshots := startSnapshots
+	originalSLAMonitor := startSLAMonitor
+	startServer = func(addr string, router Router) error {
+		return nil
+	}
+	connectDB = func(path string) (*gorm.DB, error) {
+		return &gorm.DB{}, nil
+	}
+	migrateDB = func(database *gorm.DB) error {
+		return nil
+	}
+	var interval time.Duration
+	var jobCtx context.Context
+	startSnapshots = func(ctx context.Context, job *history.SnapshotJob, every time.Duration) {
+		require.NotNil(t, job)
+		jobCtx, interval = ctx, every
+	}
+	var slaInterval time.Duration
+	startSLAMonitor = func(ctx context.Context, monitor *events.SLAMonitor, every time.Duration) {
+		require.NotNil(t, monitor)
+		slaInterval = every
+	}
+	t.Cleanup(func() {
+		startServer = originalStart
+		connectDB = originalConnect
+		migrateDB = originalMigrate
+		startSnapshots = originalSnapshots
+		startSLAMonitor = originalSLAMonitor
+	})
+
+	require.NoError(t, run())
+	require.Equal(t, 10*time.Minute, interval)
+	require.Equal(t, 30*time.Second, slaInterval)
+	require.Error(t, jobCtx.Err(), "задание снимков останавливается вместе с сервером")
+}
+
 func TestRunSuccess(t *testing.T) {
 	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
 	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
 
 	originalStart := startServer
@@ -35,6 +96,7 @@ func TestRunSuccess(t *testing.T) {
 
 func TestRunStartError(t *testing.T) {
 	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
 	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
 
 	originalStart := startServer
@@ -58,8 +120,43 @@ func TestRunStartError(t *testing.T) {
 	require.Error(t, run())
 }
 
+func TestRunGRPCStartError(t *testing.T) {
+	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
+	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
+
+	originalStart := startServer
+	originalGRPCStart := startGRPCServer
+	originalConnect := connectDB
+	originalMigrate := migrateDB
+	release := make(chan struct{})
+	startServer = func(addr string, router Router) error {
+		<-release
+		return nil
+	}
+	startGRPCServer = func(addr string, server *grpc.Server) error {
+		return errors.New("grpc boom")
+	}
+	connectDB = func(path string) (*gorm.DB, error) {
+		return &gorm.DB{}, nil
+	}
+	migrateDB = func(database *gorm.DB) error {
+		return nil
+	}
+	t.Cleanup(func() {
+		close(release)
+		startServer = originalStart
+		startGRPCServer = originalGRPCStart
+		connectDB = originalConnect
+		migrateDB = originalMigrate
+	})
+
+	require.EqualError(t, run(), "grpc boom")
+}
+
 func TestRunConnectError(t *testing.T) {
 	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
 	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
 
 	originalStart := startServer
@@ -85,6 +182,7 @@ func TestRunConnectError(t *testing.T) {
 
 func TestRunMigrateError(t *testing.T) {
 	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
 	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
 
 	originalStart := startServer
@@ -110,6 +208,7 @@ func TestRunMigrateError(t *testing.T) {
 
 func TestMainExitOnError(t *testing.T) {
 	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
 	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
 
 	originalStart := startServer
@@ -142,6 +241,7 @@ func TestMainExitOnError(t *testing.T) {
 
 func TestMainSuccess(t *testing.T) {
 	t.Setenv("PORT", "0")
+	t.Setenv("GRPC_PORT", "0")
 	t.Setenv("DB_DSN", "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable")
 
 	originalStart := startServer
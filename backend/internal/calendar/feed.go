@@ -0,0 +1,253 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+)
+
+const (
+	ComponentTodo  = "todo"
+	ComponentEvent = "event"
+)
+
+var Components = []string{ComponentTodo, ComponentEvent}
+
+const CodeInvalidComponent = "calendar_invalid_component"
+
+const ContentType = "text/calendar; charset=utf-8"
+
+const productID = "-//DevOpsLabs//FlowBoard//RU"
+
+// RefreshInterval подсказывает клиентам, как часто перечитывать подписку.
+const RefreshInterval = time.Hour
+
+const (
+	dateTimeLayout = "20060102T150405Z"
+	dateLayout     = "20060102"
+	maxLineOctets  = 75
+)
+
+// ParseComponent разбирает вид записей календаря; пустое значение означает
+// VTODO.
+func ParseComponent(raw string) (string, error) {
+	component := strings.ToLower(strings.TrimSpace(raw))
+	switch component {
+	case "":
+		return ComponentTodo, nil
+	case ComponentTodo, ComponentEvent:
+		return component, nil
+	}
+	return "", service.NewFieldError(CodeInvalidComponent, "component", map[string]any{"value": component})
+}
+
+// Feed описывает календарь. Location задаёт дату событий на весь день для
+// ComponentEvent.
+type Feed struct {
+	Name      string
+	Component string
+	Location  *time.Location
+}
+
+// UID — постоянный идентификатор записи задачи: по нему клиент узнаёт
+// изменённую задачу при следующем обновлении подписки.
+func UID(task domain.Task) string {
+	return fmt.Sprintf("task-%d@flowboard", task.ID)
+}
+
+// Write пишет календарь RFC 5545 из задач со сроком; задачи без срока
+// пропускаются. Вывод зависит только от задач, поэтому неизменившийся
+// календарь побайтно совпадает с предыдущим. Удалённые задачи и задачи со
+// снятым сроком просто пропадают из ленты: клиенты подписок заменяют
+// календарь целиком.
+func Write(w io.Writer, feed Feed, tasks []domain.Task) error {
+	location := feed.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	out := &lineWriter{w: bufio.NewWriter(w)}
+	out.line("BEGIN:VCALENDAR")
+	out.line("VERSION:2.0")
+	out.line("PRODID:" + productID)
+	out.line("CALSCALE:GREGORIAN")
+	out.text("X-WR-CALNAME", feed.Name)
+	out.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration(RefreshInterval))
+	out.line("X-PUBLISHED-TTL:" + duration(RefreshInterval))
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		if feed.Component == ComponentEvent {
+			writeEvent(out, task, location)
+		} else {
+			writeTodo(out, task)
+		}
+	}
+	out.line("END:VCALENDAR")
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+func writeTodo(out *lineWriter, task domain.Task) {
+	out.line("BEGIN:VTODO")
+	writeCommon(out, task)
+	out.line("DUE:" + dateTime(*task.DueDate))
+	out.line("STATUS:" + TodoStatus(task.Status))
+	if task.Status == domain.StatusDone {
+		out.line("PERCENT-COMPLETE:100")
+		if task.CompletedAt != nil {
+			out.line("COMPLETED:" + dateTime(*task.CompletedAt))
+		}
+	}
+	out.line("END:VTODO")
+}
+
+// writeEvent пишет срок событием на весь день: так его показывают
+// календари, не поддерживающие VTODO.
+func writeEvent(out *lineWriter, task domain.Task, location *time.Location) {
+	day := task.DueDate.In(location)
+	out.line("BEGIN:VEVENT")
+	writeCommon(out, task)
+	out.line("DTSTART;VALUE=DATE:" + day.Format(dateLayout))
+	out.line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format(dateLayout))
+	out.line("STATUS:" + EventStatus(task.Status))
+	out.line("TRANSP:TRANSPARENT")
+	out.line("END:VEVENT")
+}
+
+func writeCommon(out *lineWriter, task domain.Task) {
+	out.line("UID:" + UID(task))
+	// Для календаря без METHOD DTSTAMP совпадает с LAST-MODIFIED.
+	out.line("DTSTAMP:" + dateTime(task.UpdatedAt))
+	out.line("CREATED:" + dateTime(task.CreatedAt))
+	out.line("LAST-MODIFIED:" + dateTime(task.UpdatedAt))
+	out.line(fmt.Sprintf("SEQUENCE:%d", Sequence(task)))
+	out.text("SUMMARY", task.Title)
+	if task.Description != "" {
+		out.text("DESCRIPTION", task.Description)
+	}
+	if priority := Priority(task.Priority); priority > 0 {
+		out.line(fmt.Sprintf("PRIORITY:%d", priority))
+	}
+	if task.Owner != "" {
+		out.text("CONTACT", task.Owner)
+	}
+	categories := make([]string, 0, len(task.Tags)+1)
+	if task.Status == domain.StatusBlocked {
+		categories = append(categories, domain.StatusBlocked)
+	}
+	for _, tag := range task.Tags {
+		categories = append(categories, escapeText(tag))
+	}
+	if len(categories) > 0 {
+		out.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+}
+
+// TodoStatus переводит статус задачи в STATUS записи VTODO. У VTODO нет
+// статуса «заблокирована», поэтому такая задача ждёт действий и помечается
+// категорией blocked.
+func TodoStatus(status string) string {
+	switch status {
+	case domain.StatusInProgress:
+		return "IN-PROCESS"
+	case domain.StatusDone:
+		return "COMPLETED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// EventStatus переводит статус задачи в STATUS записи VEVENT: срок
+// заблокированной задачи под вопросом.
+func EventStatus(status string) string {
+	if status == domain.StatusBlocked {
+		return "TENTATIVE"
+	}
+	return "CONFIRMED"
+}
+
+// Priority переводит вес приоритета в шкалу PRIORITY от 1 (наивысший) до 9
+// (низший); 0 означает, что приоритет не задан.
+func Priority(priority string) int {
+	weight, ok := domain.PriorityWeights[priority]
+	if !ok {
+		return 0
+	}
+	lowest, highest := math.MaxInt, math.MinInt
+	for _, w := range domain.PriorityWeights {
+		lowest = min(lowest, w)
+		highest = max(highest, w)
+	}
+	if highest == lowest {
+		return 5
+	}
+	return int(math.Round(9 - float64(weight-lowest)*8/float64(highest-lowest)))
+}
+
+// Sequence растёт с каждым изменением задачи: это число секунд между
+// созданием и последним изменением.
+func Sequence(task domain.Task) int {
+	if !task.UpdatedAt.After(task.CreatedAt) {
+		return 0
+	}
+	return int(task.UpdatedAt.Sub(task.CreatedAt) / time.Second)
+}
+
+func dateTime(value time.Time) string {
+	return value.UTC().Format(dateTimeLayout)
+}
+
+func duration(value time.Duration) string {
+	return fmt.Sprintf("PT%dM", int(value/time.Minute))
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// lineWriter пишет строки содержимого с CRLF и переносит строки длиннее 75
+// октетов, не разрывая символы UTF-8.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (l *lineWriter) text(name string, value string) {
+	l.line(name + ":" + escapeText(value))
+}
+
+func (l *lineWriter) line(value string) {
+	if l.err != nil {
+		return
+	}
+	limit := maxLineOctets
+	for len(value) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		l.write(value[:cut] + "\r\n ")
+		value = value[cut:]
+		// Продолжение начинается с пробела, который тоже занимает октет.
+		limit = maxLineOctets - 1
+	}
+	l.write(value + "\r\n")
+}
+
+func (l *lineWriter) write(value string) {
+	if l.err == nil {
+		_, l.err = l.w.WriteString(value)
+	}
+}
@@ -0,0 +1,121 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func feedTasks() []domain.Task {
+	created := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 2, 10, 22, 30, 0, 0, time.UTC)
+	completed := time.Date(2026, 2, 5, 12, 0, 0, 0, time.UTC)
+	return []domain.Task{
+		{
+			ID: 7, Title: "Rotate keys; then, restart", Description: "Шаги:\nревизия", Status: domain.StatusBlocked,
+			Priority: domain.PriorityCritical, Owner: "alice", Tags: domain.StringList{"ops", "a,b"},
+			DueDate: &due, CreatedAt: created, UpdatedAt: created.Add(90 * time.Second),
+		},
+		{ID: 8, Title: "No due date", Status: domain.StatusTodo, Priority: domain.PriorityLow, CreatedAt: created, UpdatedAt: created},
+		{
+			ID: 9, Title: "Ship", Status: domain.StatusDone, Priority: domain.PriorityMedium,
+			DueDate: &due, CompletedAt: &completed, CreatedAt: created, UpdatedAt: completed,
+		},
+	}
+}
+
+func TestWriteTodos(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, Feed{Name: "FlowBoard", Component: ComponentTodo}, feedTasks()))
+	body := out.String()
+
+	require.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+	require.Equal(t, 2, strings.Count(body, "BEGIN:VTODO"))
+	require.NotContains(t, body, "No due date")
+
+	require.Contains(t, body, "UID:task-7@flowboard\r\n")
+	require.Contains(t, body, "SUMMARY:Rotate keys\\; then\\, restart\r\n")
+	require.Contains(t, body, "DESCRIPTION:Шаги:\\nревизия\r\n")
+	require.Contains(t, body, "DUE:20260210T223000Z\r\n")
+	require.Contains(t, body, "SEQUENCE:90\r\n")
+	require.Contains(t, body, "PRIORITY:1\r\n")
+	require.Contains(t, body, "CONTACT:alice\r\n")
+	require.Contains(t, body, "CATEGORIES:blocked,ops,a\\,b\r\n")
+	require.Contains(t, body, "STATUS:NEEDS-ACTION\r\n")
+
+	require.Contains(t, body, "STATUS:COMPLETED\r\nPERCENT-COMPLETE:100\r\nCOMPLETED:20260205T120000Z\r\n")
+	require.Contains(t, body, "PRIORITY:6\r\n")
+
+	var again bytes.Buffer
+	require.NoError(t, Write(&again, Feed{Name: "FlowBoard", Component: ComponentTodo}, feedTasks()))
+	require.Equal(t, body, again.String())
+}
+
+func TestWriteEventsUseLocalDate(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, Feed{Name: "FlowBoard", Component: ComponentEvent, Location: moscow}, feedTasks()))
+	body := out.String()
+	require.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+	require.Contains(t, body, "DTSTART;VALUE=DATE:20260211\r\nDTEND;VALUE=DATE:20260212\r\nSTATUS:TENTATIVE\r\n")
+	require.NotContains(t, body, "VTODO")
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	task := feedTasks()[0]
+	task.Title = strings.Repeat("ж", 100)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, Feed{}, []domain.Task{task}))
+	var summary strings.Builder
+	inSummary := false
+	for _, line := range strings.Split(out.String(), "\r\n") {
+		require.LessOrEqual(t, len(line), 75)
+		switch {
+		case strings.HasPrefix(line, "SUMMARY:"):
+			inSummary = true
+			summary.WriteString(strings.TrimPrefix(line, "SUMMARY:"))
+		case inSummary && strings.HasPrefix(line, " "):
+			summary.WriteString(line[1:])
+		default:
+			inSummary = false
+		}
+	}
+	require.Equal(t, task.Title, summary.String())
+}
+
+func TestMappingsAndComponent(t *testing.T) {
+	require.Equal(t, 1, Priority(domain.PriorityCritical))
+	require.Equal(t, 4, Priority(domain.PriorityHigh))
+	require.Equal(t, 9, Priority(domain.PriorityLow))
+	require.Zero(t, Priority("unknown"))
+	require.Equal(t, "IN-PROCESS", TodoStatus(domain.StatusInProgress))
+	require.Equal(t, "CONFIRMED", EventStatus(domain.StatusDone))
+
+	component, err := ParseComponent(" EVENT ")
+	require.NoError(t, err)
+	require.Equal(t, ComponentEvent, component)
+	_, err = ParseComponent("journal")
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidComponent, fieldErr.Code)
+}
+
+func TestSignerTokens(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	token := signer.Token(OwnerSubject("alice"))
+	require.True(t, signer.Valid(OwnerSubject("alice"), token))
+	require.False(t, signer.Valid(OwnerSubject("bob"), token))
+	require.False(t, signer.Valid(ViewSubject(1, domain.VisibilityShared), ""))
+	shared := signer.Token(ViewSubject(1, domain.VisibilityShared))
+	require.False(t, signer.Valid(ViewSubject(1, domain.VisibilityPrivate), shared))
+	require.False(t, NewSigner([]byte("rotated")).Valid(OwnerSubject("alice"), token))
+}
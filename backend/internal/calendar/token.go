@@ -0,0 +1,41 @@
+package calendar
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+)
+
+// Signer выдаёт токены подписок на календарь. Календарные приложения не
+// передают заголовки, поэтому доступ к личной ленте даёт токен в ссылке:
+// HMAC от названия ленты. Смена секрета отзывает все выданные ссылки.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+func (s *Signer) Token(subject string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(subject))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Signer) Valid(subject string, token string) bool {
+	return token != "" && hmac.Equal([]byte(s.Token(subject)), []byte(token))
+}
+
+// OwnerSubject и ViewSubject называют ленты исполнителя и сохранённого
+// представления. Видимость входит в название ленты представления, поэтому
+// ссылки, выданные на общее представление, перестают работать, когда
+// владелец делает его личным.
+func OwnerSubject(owner string) string {
+	return "owner:" + owner
+}
+
+func ViewSubject(id uint, visibility string) string {
+	return "view:" + strconv.FormatUint(uint64(id), 10) + ":" + visibility
+}
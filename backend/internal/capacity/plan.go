@@ -0,0 +1,416 @@
+package capacity
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+)
+
+const (
+	CodeInvalidWeeks  = "capacity_invalid_weeks"
+	CodeInvalidDayOff = "capacity_invalid_day_off"
+)
+
+const (
+	DefaultWeeks = 4
+	MaxWeeks     = 26
+	// MaxDaysOff ограничивает число нерабочих дней в настройках.
+	MaxDaysOff = 366
+)
+
+// WorkDaysPerWeek — будни, между которыми делятся часы в неделю.
+const WorkDaysPerWeek = 5
+
+// WeekLoad — нагрузка исполнителя на неделю, которая начинается с Week
+// (понедельник). Для текущей недели ёмкость считается с сегодняшнего дня.
+type WeekLoad struct {
+	Week          string  `json:"week"`
+	CapacityHours float64 `json:"capacityHours"`
+	AssignedHours float64 `json:"assignedHours"`
+	Utilization   float64 `json:"utilization"`
+	Overallocated bool    `json:"overallocated"`
+}
+
+// OwnerWorkload — нагрузка исполнителя по неделям горизонта. Задачи без
+// срока попадают в UnscheduledHours, часы после горизонта — в LaterHours.
+type OwnerWorkload struct {
+	Owner            string     `json:"owner"`
+	HoursPerWeek     float64    `json:"hoursPerWeek"`
+	Configured       bool       `json:"configured"`
+	OpenTasks        int        `json:"openTasks"`
+	OverdueTasks     int        `json:"overdueTasks"`
+	CapacityHours    float64    `json:"capacityHours"`
+	AssignedHours    float64    `json:"assignedHours"`
+	UnscheduledHours float64    `json:"unscheduledHours"`
+	LaterHours       float64    `json:"laterHours"`
+	Overallocated    bool       `json:"overallocated"`
+	Weeks            []WeekLoad `json:"weeks"`
+}
+
+// Suggestion предлагает передать задачу исполнителю, у которого хватает
+// свободных часов в те же недели, чтобы снять перегрузку недели Week.
+type Suggestion struct {
+	TaskID      uint    `json:"taskId"`
+	Title       string  `json:"title"`
+	Priority    string  `json:"priority"`
+	EffortHours int     `json:"effortHours"`
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Week        string  `json:"week"`
+	Hours       float64 `json:"hours"`
+}
+
+// Workload — план нагрузки на недели From..To.
+type Workload struct {
+	From            string          `json:"from"`
+	To              string          `json:"to"`
+	UnassignedHours float64         `json:"unassignedHours"`
+	Owners          []OwnerWorkload `json:"owners"`
+	Suggestions     []Suggestion    `json:"suggestions"`
+}
+
+// ParseWeeks проверяет длину горизонта планирования в неделях.
+func ParseWeeks(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DefaultWeeks, nil
+	}
+	weeks, err := strconv.Atoi(value)
+	if err != nil || weeks < 1 || weeks > MaxWeeks {
+		return 0, service.NewFieldError(CodeInvalidWeeks, "weeks", map[string]any{"max": MaxWeeks})
+	}
+	return weeks, nil
+}
+
+// NormalizeDaysOff проверяет нерабочие дни и возвращает их по порядку без
+// повторов.
+func NormalizeDaysOff(days []string) (domain.StringList, error) {
+	var errs service.ValidationErrors
+	seen := make(map[string]bool, len(days))
+	result := domain.StringList{}
+	for _, value := range days {
+		day, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+		if err != nil {
+			errs.Add(service.NewFieldError(CodeInvalidDayOff, "daysOff", map[string]any{"value": value}))
+			continue
+		}
+		key := day.Format(time.DateOnly)
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Plan распределяет часы незавершённых задач по рабочим дням исполнителя от
+// сегодняшнего дня до срока пропорционально его ёмкости. Задача, срок
+// которой прошёл или до срока которой нет рабочих дней, целиком ложится на
+// текущую неделю. Исполнители без настроек работают DefaultHoursPerWeek
+// часов в неделю без нерабочих дней. Задачи без исполнителя, в том числе с
+// service.DefaultOwner, идут в UnassignedHours: такой «исполнитель» не
+// планируется и не получает задачи в предложениях.
+func Plan(tasks []domain.Task, capacities []domain.OwnerCapacity, weeks int, now time.Time) Workload {
+	today := startOfDay(now)
+	first := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	end := first.AddDate(0, 0, 7*weeks)
+	planner := &planner{today: today, first: first, end: end, weeks: weeks, owners: make(map[string]*ownerPlan)}
+
+	for _, capacity := range capacities {
+		if !unassigned(capacity.Owner) {
+			planner.owner(capacity.Owner, &capacity)
+		}
+	}
+	var unassignedHours float64
+	for _, task := range tasks {
+		if task.Status == domain.StatusDone {
+			continue
+		}
+		if unassigned(task.Owner) {
+			unassignedHours += float64(task.EffortHours)
+			continue
+		}
+		planner.owner(task.Owner, nil).assign(task)
+	}
+
+	result := Workload{
+		From:            first.Format(time.DateOnly),
+		To:              end.AddDate(0, 0, -1).Format(time.DateOnly),
+		UnassignedHours: round2(unassignedHours),
+		Owners:          []OwnerWorkload{},
+	}
+	names := make([]string, 0, len(planner.owners))
+	for name := range planner.owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	plans := make([]*ownerPlan, 0, len(names))
+	for _, name := range names {
+		plan := planner.owners[name]
+		plans = append(plans, plan)
+		result.Owners = append(result.Owners, plan.workload())
+	}
+	result.Suggestions = suggest(plans)
+	return result
+}
+
+type planner struct {
+	today  time.Time
+	first  time.Time
+	end    time.Time
+	weeks  int
+	owners map[string]*ownerPlan
+}
+
+type contribution struct {
+	task  domain.Task
+	hours []float64
+}
+
+type ownerPlan struct {
+	planner    *planner
+	capacity   domain.OwnerCapacity
+	configured bool
+	daysOff    map[string]bool
+	available  []float64
+	assigned   []float64
+	tasks      []contribution
+	overdue    int
+	open       int
+	unplanned  float64
+	later      float64
+}
+
+func (p *planner) owner(name string, capacity *domain.OwnerCapacity) *ownerPlan {
+	if plan, ok := p.owners[name]; ok {
+		return plan
+	}
+	plan := &ownerPlan{
+		planner:  p,
+		capacity: domain.OwnerCapacity{Owner: name, HoursPerWeek: domain.DefaultHoursPerWeek},
+		daysOff:  make(map[string]bool),
+		assigned: make([]float64, p.weeks),
+	}
+	if capacity != nil {
+		plan.capacity, plan.configured = *capacity, true
+		for _, day := range capacity.DaysOff {
+			plan.daysOff[day] = true
+		}
+	}
+	plan.available = make([]float64, p.weeks)
+	for day := p.today; day.Before(p.end); day = day.AddDate(0, 0, 1) {
+		plan.available[p.week(day)] += plan.hoursOn(day)
+	}
+	p.owners[name] = plan
+	return plan
+}
+
+func (p *planner) week(day time.Time) int {
+	return int(day.Sub(p.first).Hours() / (24 * 7))
+}
+
+func (o *ownerPlan) hoursOn(day time.Time) float64 {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday || o.daysOff[day.Format(time.DateOnly)] {
+		return 0
+	}
+	return o.capacity.HoursPerWeek / WorkDaysPerWeek
+}
+
+func (o *ownerPlan) assign(task domain.Task) {
+	o.open++
+	effort := float64(task.EffortHours)
+	if task.DueDate == nil {
+		o.unplanned += effort
+		return
+	}
+
+	// По дням обходится только горизонт; часы после него считаются разом.
+	due := startOfDay(*task.DueDate)
+	hours := make([]float64, o.planner.weeks)
+	later := o.hoursBetween(o.planner.end, due)
+	total := later
+	for day := o.planner.today; !day.After(due) && day.Before(o.planner.end); day = day.AddDate(0, 0, 1) {
+		value := o.hoursOn(day)
+		hours[o.planner.week(day)] += value
+		total += value
+	}
+	if total == 0 {
+		if due.Before(o.planner.today) {
+			o.overdue++
+		}
+		hours[0] = effort
+	} else {
+		for week := range hours {
+			hours[week] *= effort / total
+		}
+		o.later += effort * later / total
+	}
+	for week, value := range hours {
+		o.assigned[week] += value
+	}
+	o.tasks = append(o.tasks, contribution{task: task, hours: hours})
+}
+
+// hoursBetween считает рабочие часы исполнителя с from по to включительно:
+// целые недели дают HoursPerWeek за вычетом нерабочих дней в будни, остаток
+// обходится по дням.
+func (o *ownerPlan) hoursBetween(from time.Time, to time.Time) float64 {
+	if to.Before(from) {
+		return 0
+	}
+	weeks := int((to.Unix()-from.Unix())/(24*60*60)+1) / 7
+	rest := from.AddDate(0, 0, 7*weeks)
+	total := float64(weeks) * o.capacity.HoursPerWeek
+	for day := rest; !day.After(to); day = day.AddDate(0, 0, 1) {
+		total += o.hoursOn(day)
+	}
+	first, last := from.Format(time.DateOnly), rest.Format(time.DateOnly)
+	for value := range o.daysOff {
+		if value < first || value >= last {
+			continue
+		}
+		if day, err := time.Parse(time.DateOnly, value); err == nil && day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			total -= o.capacity.HoursPerWeek / WorkDaysPerWeek
+		}
+	}
+	return total
+}
+
+func (o *ownerPlan) workload() OwnerWorkload {
+	result := OwnerWorkload{
+		Owner:            o.capacity.Owner,
+		HoursPerWeek:     o.capacity.HoursPerWeek,
+		Configured:       o.configured,
+		OpenTasks:        o.open,
+		OverdueTasks:     o.overdue,
+		UnscheduledHours: round2(o.unplanned),
+		LaterHours:       round2(o.later),
+		Weeks:            make([]WeekLoad, 0, len(o.assigned)),
+	}
+	for week := range o.assigned {
+		load := WeekLoad{
+			Week:          o.planner.first.AddDate(0, 0, 7*week).Format(time.DateOnly),
+			CapacityHours: round2(o.available[week]),
+			AssignedHours: round2(o.assigned[week]),
+			Overallocated: overallocated(o.assigned[week], o.available[week]),
+		}
+		if o.available[week] > 0 {
+			load.Utilization = round2(o.assigned[week] / o.available[week])
+		}
+		result.CapacityHours += o.available[week]
+		result.AssignedHours += o.assigned[week]
+		result.Overallocated = result.Overallocated || load.Overallocated
+		result.Weeks = append(result.Weeks, load)
+	}
+	result.CapacityHours = round2(result.CapacityHours)
+	result.AssignedHours = round2(result.AssignedHours)
+	return result
+}
+
+// suggest по очереди снимает перегрузку недель: задачи исполнителя с самым
+// низким приоритетом передаются тому, у кого больше всего свободных часов и
+// хватает их во все недели задачи. Оценка не учитывает нерабочие дни
+// получателя внутри недели.
+func suggest(plans []*ownerPlan) []Suggestion {
+	suggestions := []Suggestion{}
+	if len(plans) < 2 {
+		return suggestions
+	}
+	assigned := make(map[*ownerPlan][]float64, len(plans))
+	for _, plan := range plans {
+		assigned[plan] = append([]float64(nil), plan.assigned...)
+	}
+	spare := func(plan *ownerPlan, week int) float64 {
+		return plan.available[week] - assigned[plan][week]
+	}
+
+	for _, source := range plans {
+		candidates := append([]contribution(nil), source.tasks...)
+		sort.SliceStable(candidates, func(i, j int) bool {
+			left, right := domain.PriorityWeights[candidates[i].task.Priority], domain.PriorityWeights[candidates[j].task.Priority]
+			if left != right {
+				return left < right
+			}
+			return candidates[i].task.ID < candidates[j].task.ID
+		})
+		moved := make(map[uint]bool)
+
+		for week := range source.assigned {
+			for _, candidate := range candidates {
+				if !overallocated(assigned[source][week], source.available[week]) {
+					break
+				}
+				if moved[candidate.task.ID] || candidate.hours[week] == 0 {
+					continue
+				}
+				var target *ownerPlan
+				for _, plan := range plans {
+					if plan == source || !fits(candidate.hours, func(w int) float64 { return spare(plan, w) }) {
+						continue
+					}
+					if target == nil || spare(plan, week) > spare(target, week) {
+						target = plan
+					}
+				}
+				if target == nil {
+					continue
+				}
+				for w, hours := range candidate.hours {
+					assigned[source][w] -= hours
+					assigned[target][w] += hours
+				}
+				moved[candidate.task.ID] = true
+				suggestions = append(suggestions, Suggestion{
+					TaskID:      candidate.task.ID,
+					Title:       candidate.task.Title,
+					Priority:    candidate.task.Priority,
+					EffortHours: candidate.task.EffortHours,
+					From:        source.capacity.Owner,
+					To:          target.capacity.Owner,
+					Week:        source.planner.first.AddDate(0, 0, 7*week).Format(time.DateOnly),
+					Hours:       round2(candidate.hours[week]),
+				})
+			}
+		}
+	}
+	return suggestions
+}
+
+func fits(hours []float64, spare func(week int) float64) bool {
+	for week, value := range hours {
+		if value > 0 && value > spare(week)+epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// epsilon сглаживает ошибки округления при делении часов между днями.
+const epsilon = 1e-6
+
+func overallocated(assigned float64, available float64) bool {
+	return assigned > available+epsilon
+}
+
+func unassigned(owner string) bool {
+	owner = strings.TrimSpace(owner)
+	return owner == "" || owner == service.DefaultOwner
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,128 @@
+package capacity
+
+import (
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+// 2026-03-04 — среда.
+var now = time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+func due(month time.Month, day int) *time.Time {
+	value := time.Date(2026, month, day, 18, 0, 0, 0, time.UTC)
+	return &value
+}
+
+func TestPlanSpreadsEffortOverWorkingDays(t *testing.T) {
+	capacities := []domain.OwnerCapacity{
+		{Owner: "anna", HoursPerWeek: 20, DaysOff: domain.StringList{"2026-03-05"}},
+		{Owner: "olga", HoursPerWeek: 10},
+	}
+	tasks := []domain.Task{
+		// Ср, пт и пн: по 4 часа в день, четверг — выходной.
+		{ID: 1, Owner: "anna", Status: domain.StatusTodo, EffortHours: 12, DueDate: due(3, 9)},
+		{ID: 2, Owner: "anna", Status: domain.StatusInProgress, EffortHours: 3, DueDate: due(3, 1)},
+		{ID: 3, Owner: "anna", Status: domain.StatusTodo, EffortHours: 5},
+		{ID: 4, Owner: "anna", Status: domain.StatusDone, EffortHours: 50, DueDate: due(3, 5)},
+		{ID: 5, Owner: "ivan", Status: domain.StatusTodo, EffortHours: 16, DueDate: due(4, 24)},
+		{ID: 6, Status: domain.StatusTodo, EffortHours: 7},
+	}
+
+	plan := Plan(tasks, capacities, 2, now)
+	require.Equal(t, "2026-03-02", plan.From)
+	require.Equal(t, "2026-03-15", plan.To)
+	require.Equal(t, 7.0, plan.UnassignedHours)
+	require.Len(t, plan.Owners, 3)
+
+	anna := plan.Owners[0]
+	require.Equal(t, "anna", anna.Owner)
+	require.True(t, anna.Configured)
+	require.Equal(t, 3, anna.OpenTasks)
+	require.Equal(t, 1, anna.OverdueTasks)
+	require.Equal(t, 5.0, anna.UnscheduledHours)
+	require.Equal(t, WeekLoad{Week: "2026-03-02", CapacityHours: 8, AssignedHours: 11, Utilization: 1.38, Overallocated: true}, anna.Weeks[0])
+	require.Equal(t, WeekLoad{Week: "2026-03-09", CapacityHours: 20, AssignedHours: 4, Utilization: 0.2}, anna.Weeks[1])
+	require.True(t, anna.Overallocated)
+
+	ivan := plan.Owners[1]
+	require.False(t, ivan.Configured)
+	require.Equal(t, float64(domain.DefaultHoursPerWeek), ivan.HoursPerWeek)
+	require.Equal(t, 24.0, ivan.Weeks[0].CapacityHours)
+	require.Equal(t, 16.0, ivan.AssignedHours+ivan.LaterHours)
+	require.Greater(t, ivan.LaterHours, 0.0)
+
+	olga := plan.Owners[2]
+	require.Equal(t, 0, olga.OpenTasks)
+	require.Equal(t, 0.0, olga.AssignedHours)
+	require.Equal(t, 6.0, olga.Weeks[0].CapacityHours)
+}
+
+func TestPlanCountsHoursBeyondHorizonWithoutWalkingDays(t *testing.T) {
+	capacities := []domain.OwnerCapacity{{Owner: "anna", HoursPerWeek: 20, DaysOff: domain.StringList{"2026-03-12", "2026-03-14"}}}
+	farDue := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	tasks := []domain.Task{
+		// Ср–пт горизонта: 12 часов; 9–20 марта без четверга 12-го: 36 часов.
+		{ID: 1, Owner: "anna", Status: domain.StatusTodo, EffortHours: 48, DueDate: due(3, 20)},
+		{ID: 2, Owner: "olga", Status: domain.StatusTodo, EffortHours: 100, DueDate: &farDue},
+	}
+
+	started := time.Now()
+	plan := Plan(tasks, capacities, 1, now)
+	require.Less(t, time.Since(started), 50*time.Millisecond)
+
+	anna := plan.Owners[0]
+	require.Equal(t, 12.0, anna.AssignedHours)
+	require.Equal(t, 36.0, anna.LaterHours)
+
+	olga := plan.Owners[1]
+	require.Equal(t, 100.0, olga.AssignedHours+olga.LaterHours)
+	require.Greater(t, olga.LaterHours, 99.0)
+}
+
+func TestPlanSuggestsRebalancing(t *testing.T) {
+	capacities := []domain.OwnerCapacity{
+		{Owner: "anna", HoursPerWeek: 10},
+		{Owner: "ivan", HoursPerWeek: 40},
+		{Owner: "olga", HoursPerWeek: 20},
+	}
+	tasks := []domain.Task{
+		{ID: 1, Title: "Critical", Owner: "anna", Status: domain.StatusTodo, Priority: domain.PriorityCritical, EffortHours: 6, DueDate: due(3, 6)},
+		{ID: 2, Title: "Low", Owner: "anna", Status: domain.StatusTodo, Priority: domain.PriorityLow, EffortHours: 4, DueDate: due(3, 6)},
+		{ID: 3, Title: "Ivan", Owner: "ivan", Status: domain.StatusTodo, Priority: domain.PriorityHigh, EffortHours: 20, DueDate: due(3, 6)},
+	}
+
+	plan := Plan(tasks, capacities, 1, now)
+	require.True(t, plan.Owners[0].Overallocated)
+	require.Equal(t, []Suggestion{{
+		TaskID: 2, Title: "Low", Priority: domain.PriorityLow, EffortHours: 4,
+		From: "anna", To: "olga", Week: "2026-03-02", Hours: 4,
+	}}, plan.Suggestions)
+
+	// Свободных часов ни у кого нет.
+	plan = Plan(tasks, []domain.OwnerCapacity{{Owner: "anna", HoursPerWeek: 10}, {Owner: "ivan", HoursPerWeek: 10}}, 1, now)
+	require.Empty(t, plan.Suggestions)
+}
+
+func TestParseWeeksAndDaysOff(t *testing.T) {
+	weeks, err := ParseWeeks("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultWeeks, weeks)
+	weeks, err = ParseWeeks("8")
+	require.NoError(t, err)
+	require.Equal(t, 8, weeks)
+	_, err = ParseWeeks("27")
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidWeeks, fieldErr.Code)
+
+	days, err := NormalizeDaysOff([]string{"2026-03-10", " 2026-03-09", "2026-03-10"})
+	require.NoError(t, err)
+	require.Equal(t, domain.StringList{"2026-03-09", "2026-03-10"}, days)
+	_, err = NormalizeDaysOff([]string{"10.03.2026"})
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidDayOff, fieldErr.Code)
+}
@@ -1,10 +1,32 @@
 package config
 
-import "os"
+import (
+	"os"
+	"time"
+)
+
+const (
+	defaultIdempotencyTTL   = 24 * time.Hour
+	defaultSnapshotInterval = time.Hour
+	defaultSLACheckInterval = time.Minute
+)
 
 type Config struct {
-	Port  string
-	DBDSN string
+	Port           string
+	GRPCPort       string
+	DBDSN          string
+	IdempotencyTTL time.Duration
+	CalendarSecret string
+	// SnapshotInterval — период снимков сводных метрик.
+	SnapshotInterval time.Duration
+	// SLACheckInterval — период проверки SLA для событий о нарушениях.
+	SLACheckInterval time.Duration
+	// ScoringPolicyFile — JSON с политикой оценки и риска задач; пустое
+	// значение означает исходные правила.
+	ScoringPolicyFile string
+	// WorkCalendarFile — JSON с рабочими календарями; пустое значение
+	// означает круглосуточный календарь.
+	WorkCalendarFile string
 }
 
 func Load() Config {
@@ -13,13 +35,33 @@ func Load() Config {
 		port = "8080"
 	}
 
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+
 	dbDSN := os.Getenv("DB_DSN")
 	if dbDSN == "" {
 		dbDSN = "host=localhost user=postgres password=postgres dbname=flowboard port=5432 sslmode=disable TimeZone=UTC"
 	}
 
 	return Config{
-		Port:  port,
-		DBDSN: dbDSN,
+		Port:              port,
+		GRPCPort:          grpcPort,
+		DBDSN:             dbDSN,
+		IdempotencyTTL:    durationEnv("IDEMPOTENCY_TTL", defaultIdempotencyTTL),
+		CalendarSecret:    os.Getenv("CALENDAR_SECRET"),
+		SnapshotInterval:  durationEnv("SNAPSHOT_INTERVAL", defaultSnapshotInterval),
+		SLACheckInterval:  durationEnv("SLA_CHECK_INTERVAL", defaultSLACheckInterval),
+		ScoringPolicyFile: os.Getenv("SCORING_POLICY"),
+		WorkCalendarFile:  os.Getenv("WORK_CALENDAR"),
+	}
+}
+
+func durationEnv(name string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
 	}
+	return value
 }
@@ -3,16 +3,21 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestLoadDefaults(t *testing.T) {
 	t.Setenv("PORT", "")
+	t.Setenv("GRPC_PORT", "")
 	t.Setenv("DB_DSN", "")
+	t.Setenv("IDEMPOTENCY_TTL", "")
 
 	cfg := Load()
 	require.Equal(t, "8080", cfg.Port)
+	require.Equal(t, "9090", cfg.GRPCPort)
+	require.Equal(t, 24*time.Hour, cfg.IdempotencyTTL)
 	require.Equal(t, "host=localhost user=postgres password=postgres dbname=flowboard port=5432 sslmode=disable TimeZone=UTC", cfg.DBDSN)
 }
 
@@ -24,7 +29,35 @@ func TestLoadCustom(t *testing.T) {
 		_ = os.Unsetenv("DB_DSN")
 	}()
 
+	t.Setenv("GRPC_PORT", "9191")
+	t.Setenv("CALENDAR_SECRET", "s3cret")
+	t.Setenv("SCORING_POLICY", "/etc/flowboard/policy.json")
+	t.Setenv("WORK_CALENDAR", "/etc/flowboard/calendar.json")
+
 	cfg := Load()
 	require.Equal(t, "9090", cfg.Port)
+	require.Equal(t, "9191", cfg.GRPCPort)
 	require.Equal(t, "host=db user=demo password=secret dbname=demo port=5432 sslmode=disable", cfg.DBDSN)
+	require.Equal(t, "s3cret", cfg.CalendarSecret)
+	require.Equal(t, "/etc/flowboard/policy.json", cfg.ScoringPolicyFile)
+	require.Equal(t, "/etc/flowboard/calendar.json", cfg.WorkCalendarFile)
+}
+
+func TestLoadDurations(t *testing.T) {
+	t.Setenv("IDEMPOTENCY_TTL", "90m")
+	require.Equal(t, 90*time.Minute, Load().IdempotencyTTL)
+
+	t.Setenv("IDEMPOTENCY_TTL", "soon")
+	require.Equal(t, 24*time.Hour, Load().IdempotencyTTL)
+
+	t.Setenv("IDEMPOTENCY_TTL", "-1h")
+	require.Equal(t, 24*time.Hour, Load().IdempotencyTTL)
+
+	require.Equal(t, time.Hour, Load().SnapshotInterval)
+	t.Setenv("SNAPSHOT_INTERVAL", "15m")
+	require.Equal(t, 15*time.Minute, Load().SnapshotInterval)
+
+	require.Equal(t, time.Minute, Load().SLACheckInterval)
+	t.Setenv("SLA_CHECK_INTERVAL", "30s")
+	require.Equal(t, 30*time.Second, Load().SLACheckInterval)
 }
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// DefaultHoursPerWeek — ёмкость исполнителя без сохранённых настроек.
+const DefaultHoursPerWeek = 40
+
+// MaxHoursPerWeek — часов в неделе.
+const MaxHoursPerWeek = 168
+
+// OwnerCapacity — рабочее время исполнителя. Часы в неделю делятся поровну
+// между буднями; DaysOff перечисляет нерабочие дни в формате YYYY-MM-DD.
+type OwnerCapacity struct {
+	Owner        string     `json:"owner" gorm:"primaryKey;size:80"`
+	HoursPerWeek float64    `json:"hoursPerWeek" gorm:"not null"`
+	DaysOff      StringList `json:"daysOff" gorm:"type:text"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
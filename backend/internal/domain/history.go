@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// TaskChange — запись истории задачи: состояние задачи сразу после
+// изменения. У записи удаления заполнен только TaskID.
+type TaskChange struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TaskID     uint      `json:"taskId" gorm:"not null;index"`
+	Type       string    `json:"type" gorm:"size:16;not null"`
+	OccurredAt time.Time `json:"occurredAt" gorm:"not null;index"`
+	Task       Task      `json:"task" gorm:"serializer:json;type:text"`
+}
@@ -37,8 +37,14 @@ var PriorityWeights = map[string]int{
 	PriorityCritical: 4,
 }
 
+// MaxExternalIDLength — предельная длина идентификатора задачи во внешней
+// системе, из которой она импортирована.
+const MaxExternalIDLength = 120
+
 type Task struct {
-	ID          uint       `json:"id" gorm:"primaryKey"`
+	ID uint `json:"id" gorm:"primaryKey"`
+	// ExternalID уникален среди импортированных задач; у остальных он пуст.
+	ExternalID  string     `json:"externalId,omitempty" gorm:"size:120;uniqueIndex:idx_tasks_external_id,where:external_id <> ''"`
 	Title       string     `json:"title" gorm:"size:200;not null"`
 	Description string     `json:"description" gorm:"type:text"`
 	Status      string     `json:"status" gorm:"size:32;not null"`
@@ -49,6 +55,9 @@ type Task struct {
 	DueDate     *time.Time `json:"dueDate,omitempty"`
 	StartedAt   *time.Time `json:"startedAt,omitempty"`
 	CompletedAt *time.Time `json:"completedAt,omitempty"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	UpdatedAt   time.Time  `json:"updatedAt"`
+	// StatusChangedAt — момент перехода в текущий статус; у задач, созданных
+	// до появления поля, не заполнен.
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
 }
@@ -60,3 +60,44 @@ func (s *StringList) parseBytes(data []byte) error {
 	*s = StringList(decoded)
 	return nil
 }
+
+// StringMap хранит пары строк в текстовой колонке в виде JSON-объекта.
+type StringMap map[string]string
+
+func (m StringMap) Value() (driver.Value, error) {
+	data, _ := json.Marshal(map[string]string(m.orEmpty()))
+	return string(data), nil
+}
+
+func (m *StringMap) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип словаря: %T", value)
+	}
+
+	decoded := map[string]string{}
+	if len(data) > 0 && string(data) != "null" {
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+	}
+	*m = StringMap(decoded)
+	return nil
+}
+
+func (m StringMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string(m.orEmpty()))
+}
+
+func (m StringMap) orEmpty() StringMap {
+	if m == nil {
+		return StringMap{}
+	}
+	return m
+}
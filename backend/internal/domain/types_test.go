@@ -57,3 +57,38 @@ func TestStringListJSON(t *testing.T) {
 	var decodedErr StringList
 	require.Error(t, decodedErr.UnmarshalJSON([]byte("{")))
 }
+
+func TestStringMapValueScanAndJSON(t *testing.T) {
+	value, err := StringMap{"status": "todo"}.Value()
+	require.NoError(t, err)
+	require.Equal(t, `{"status":"todo"}`, value)
+
+	value, err = StringMap(nil).Value()
+	require.NoError(t, err)
+	require.Equal(t, `{}`, value)
+
+	var scanned StringMap
+	require.NoError(t, scanned.Scan([]byte(`{"owner":"anna"}`)))
+	require.Equal(t, StringMap{"owner": "anna"}, scanned)
+
+	var scannedNil StringMap
+	require.NoError(t, scannedNil.Scan(nil))
+	require.Equal(t, StringMap{}, scannedNil)
+
+	require.Error(t, scanned.Scan(42))
+	require.Error(t, scanned.Scan("not-json"))
+
+	data, err := json.Marshal(StringMap(nil))
+	require.NoError(t, err)
+	require.Equal(t, `{}`, string(data))
+}
+
+func TestSavedViewVisibleTo(t *testing.T) {
+	private := SavedView{Owner: "anna", Visibility: VisibilityPrivate}
+	require.True(t, private.VisibleTo("anna"))
+	require.False(t, private.VisibleTo("ivan"))
+	require.False(t, private.VisibleTo(""))
+
+	shared := SavedView{Owner: "anna", Visibility: VisibilityShared}
+	require.True(t, shared.VisibleTo(""))
+}
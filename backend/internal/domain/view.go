@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+const (
+	VisibilityPrivate = "private"
+	VisibilityShared  = "shared"
+)
+
+var AllowedVisibilities = map[string]bool{
+	VisibilityPrivate: true,
+	VisibilityShared:  true,
+}
+
+// SavedView — сохранённый набор параметров списка задач. Filter содержит те
+// же ключи, что и строка запроса GET /api/tasks: status, priority, owner,
+// tag, q и filter. Личное представление видно только владельцу.
+type SavedView struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Name       string    `json:"name" gorm:"size:120;not null"`
+	Owner      string    `json:"owner" gorm:"size:80;not null;index"`
+	Visibility string    `json:"visibility" gorm:"size:16;not null"`
+	Filter     StringMap `json:"filter" gorm:"type:text"`
+	Sort       string    `json:"sort" gorm:"size:32;not null"`
+	Order      string    `json:"order" gorm:"size:8;not null"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// VisibleTo сообщает, может ли пользователь видеть представление.
+func (v SavedView) VisibleTo(user string) bool {
+	return v.Visibility == VisibilityShared || (user != "" && v.Owner == user)
+}
@@ -0,0 +1,100 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+const (
+	TaskCreated = "created"
+	TaskUpdated = "updated"
+	TaskDeleted = "deleted"
+	// TaskSLAImminent и TaskSLABreached публикует SLAMonitor.
+	TaskSLAImminent = "sla_imminent"
+	TaskSLABreached = "sla_breached"
+)
+
+const DefaultSubscriberBuffer = 64
+
+// TaskEvent описывает изменение задачи. Для удаления заполнен только ID, для
+// событий SLA в SLA указана цель: service.SLAResponse или service.SLAResolve.
+type TaskEvent struct {
+	Type       string
+	TaskID     uint
+	Task       domain.Task
+	SLA        string
+	OccurredAt time.Time
+}
+
+// Bus рассылает события подписчикам внутри процесса. Publish не блокируется:
+// подписчик, не успевающий читать события, отключается, а его канал
+// закрывается с признаком Lagged.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+type Subscription struct {
+	C <-chan TaskEvent
+
+	bus    *Bus
+	ch     chan TaskEvent
+	lagged bool
+	closed bool
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*Subscription]struct{})}
+}
+
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultSubscriberBuffer
+	}
+	ch := make(chan TaskEvent, buffer)
+	sub := &Subscription{C: ch, bus: b, ch: ch}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+func (b *Bus) Publish(event TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			sub.lagged = true
+			b.closeLocked(sub)
+		}
+	}
+}
+
+// Close отписывает подписчика; повторный вызов ничего не делает.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.closeLocked(s)
+}
+
+// Lagged сообщает, что подписка закрыта из-за переполнения буфера.
+func (s *Subscription) Lagged() bool {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.lagged
+}
+
+func (b *Bus) closeLocked(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
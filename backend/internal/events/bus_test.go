@@ -0,0 +1,56 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusDeliversToSubscribers(t *testing.T) {
+	bus := NewBus()
+	first := bus.Subscribe(2)
+	second := bus.Subscribe(0)
+	t.Cleanup(first.Close)
+	t.Cleanup(second.Close)
+
+	bus.Publish(TaskEvent{Type: TaskCreated, TaskID: 1})
+
+	require.Equal(t, uint(1), (<-first.C).TaskID)
+	require.Equal(t, uint(1), (<-second.C).TaskID)
+	require.Equal(t, DefaultSubscriberBuffer, cap(second.C))
+}
+
+func TestBusClosesLaggingSubscriber(t *testing.T) {
+	bus := NewBus()
+	slow := bus.Subscribe(1)
+	fast := bus.Subscribe(4)
+	t.Cleanup(fast.Close)
+
+	bus.Publish(TaskEvent{Type: TaskCreated, TaskID: 1})
+	bus.Publish(TaskEvent{Type: TaskUpdated, TaskID: 1})
+
+	event, ok := <-slow.C
+	require.True(t, ok)
+	require.Equal(t, TaskCreated, event.Type)
+	_, ok = <-slow.C
+	require.False(t, ok)
+	require.True(t, slow.Lagged())
+	require.Len(t, fast.C, 2)
+
+	slow.Close()
+	bus.Publish(TaskEvent{Type: TaskDeleted, TaskID: 1})
+	require.Len(t, fast.C, 3)
+}
+
+func TestSubscriptionCloseIsIdempotent(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1)
+
+	sub.Close()
+	sub.Close()
+
+	_, ok := <-sub.C
+	require.False(t, ok)
+	require.False(t, sub.Lagged())
+	bus.Publish(TaskEvent{Type: TaskCreated, TaskID: 1})
+}
@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+// DefaultSLACheckInterval — период проверки SLA по умолчанию.
+const DefaultSLACheckInterval = time.Minute
+
+type slaKey struct {
+	taskID uint
+	target string
+}
+
+// SLAMonitor публикует TaskSLAImminent и TaskSLABreached, когда часы SLA
+// незавершённой задачи переходят в состояние imminent или breached. О каждом
+// переходе событие публикуется один раз. Переходы до запуска монитора не
+// публикуются: о них сообщил предыдущий процесс, и перезапуск не повторяет
+// эскалации.
+type SLAMonitor struct {
+	store     repository.TaskStore
+	bus       *Bus
+	clock     service.Clock
+	policy    service.Policy
+	startedAt time.Time
+	notified  map[slaKey]string
+}
+
+func NewSLAMonitor(store repository.TaskStore, bus *Bus, clock service.Clock, policy *service.Policy) *SLAMonitor {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	return &SLAMonitor{
+		store:     store,
+		bus:       bus,
+		clock:     clock,
+		policy:    policy.OrDefault(),
+		startedAt: clock.Now(),
+		notified:  make(map[slaKey]string),
+	}
+}
+
+// Check проверяет задачи с целями SLA и возвращает число опубликованных
+// событий.
+func (m *SLAMonitor) Check(ctx context.Context) (int, error) {
+	priorities := make([]string, 0, len(m.policy.SLA.Targets))
+	for priority := range m.policy.SLA.Targets {
+		priorities = append(priorities, priority)
+	}
+	if len(priorities) == 0 {
+		return 0, nil
+	}
+	sort.Strings(priorities)
+
+	tasks, err := m.store.List(ctx, repository.TaskFilter{Priorities: priorities, Statuses: openStatuses})
+	if err != nil {
+		return 0, err
+	}
+
+	now := m.clock.Now()
+	notified := make(map[slaKey]string, len(m.notified))
+	published := 0
+	for _, task := range tasks {
+		status := m.policy.TaskSLA(now, task)
+		if status == nil {
+			continue
+		}
+		for _, target := range []string{service.SLAResponse, service.SLAResolve} {
+			clock := status.Clock(target)
+			if clock == nil {
+				continue
+			}
+			key := slaKey{taskID: task.ID, target: target}
+			previous, ok := m.notified[key]
+			if !ok {
+				previous = m.stateAtStart(task, target)
+			}
+			notified[key] = previous
+
+			var eventType string
+			switch {
+			case clock.State == service.SLAImminent && previous == "":
+				eventType = TaskSLAImminent
+			case clock.State == service.SLABreached && previous != service.SLABreached:
+				eventType = TaskSLABreached
+			default:
+				continue
+			}
+			notified[key] = clock.State
+			m.bus.Publish(TaskEvent{Type: eventType, TaskID: task.ID, Task: task, SLA: target, OccurredAt: now})
+			published++
+		}
+	}
+	m.notified = notified
+	return published, nil
+}
+
+// Run проверяет SLA сразу и затем каждые interval до отмены ctx. Ошибки
+// записываются в журнал и не останавливают проверку.
+func (m *SLAMonitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSLACheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("не удалось проверить SLA: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// openStatuses — статусы задач, часы SLA которых могут ещё перейти в новое
+// состояние.
+var openStatuses = []string{domain.StatusTodo, domain.StatusInProgress, domain.StatusBlocked}
+
+// stateAtStart возвращает состояние imminent или breached, если часы были в
+// нём уже при запуске монитора, и пустую строку в остальных случаях.
+// Остановки часов после запуска при этом не учитываются.
+func (m *SLAMonitor) stateAtStart(task domain.Task, target string) string {
+	if !task.CreatedAt.Before(m.startedAt) {
+		return ""
+	}
+	if task.StartedAt != nil && !task.StartedAt.Before(m.startedAt) {
+		task.StartedAt = nil
+	}
+	if task.CompletedAt != nil && !task.CompletedAt.Before(m.startedAt) {
+		task.CompletedAt = nil
+	}
+	status := m.policy.TaskSLA(m.startedAt, task)
+	if status == nil {
+		return ""
+	}
+	clock := status.Clock(target)
+	if clock == nil || clock.State != service.SLAImminent && clock.State != service.SLABreached {
+		return ""
+	}
+	return clock.State
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSLAMonitorPublishesEachTransitionOnce(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	policy, err := service.ParsePolicy([]byte(`{"sla": {"targets": {"critical": {"responseHours": 1, "resolveHours": 8}}}}`))
+	require.NoError(t, err)
+
+	store := newMemoryStore()
+	completedAt := start.Add(-time.Hour)
+	store.tasks[1] = domain.Task{ID: 1, Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: start}
+	store.tasks[2] = domain.Task{ID: 2, Status: domain.StatusTodo, Priority: domain.PriorityLow, CreatedAt: start.Add(-72 * time.Hour)}
+	store.tasks[3] = domain.Task{ID: 3, Status: domain.StatusDone, Priority: domain.PriorityCritical, CreatedAt: start.Add(-72 * time.Hour), CompletedAt: &completedAt}
+	// Нарушение до запуска монитора уже эскалировал предыдущий процесс.
+	store.tasks[4] = domain.Task{ID: 4, Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: start.Add(-72 * time.Hour)}
+
+	bus := NewBus()
+	sub := bus.Subscribe(16)
+	t.Cleanup(sub.Close)
+	clock := &service.FixedClock{NowValue: start}
+	monitor := NewSLAMonitor(store, bus, clock, &policy)
+
+	check := func(at time.Duration) []TaskEvent {
+		clock.NowValue = start.Add(at)
+		count, err := monitor.Check(ctx)
+		require.NoError(t, err)
+		published := make([]TaskEvent, 0, count)
+		for range count {
+			published = append(published, <-sub.C)
+		}
+		require.Empty(t, sub.C)
+		return published
+	}
+
+	require.Empty(t, check(10*time.Minute))
+
+	published := check(50 * time.Minute)
+	require.Len(t, published, 1)
+	require.Equal(t, TaskSLAImminent, published[0].Type)
+	require.Equal(t, service.SLAResponse, published[0].SLA)
+	require.Equal(t, uint(1), published[0].TaskID)
+	require.Equal(t, start.Add(50*time.Minute), published[0].OccurredAt)
+	require.Empty(t, check(55*time.Minute))
+
+	published = check(2 * time.Hour)
+	require.Len(t, published, 1)
+	require.Equal(t, TaskSLABreached, published[0].Type)
+	require.Empty(t, check(3*time.Hour))
+
+	published = check(8*time.Hour + 30*time.Minute)
+	require.Len(t, published, 1)
+	require.Equal(t, TaskSLABreached, published[0].Type)
+	require.Equal(t, service.SLAResolve, published[0].SLA)
+
+	// Завершённые задачи не проверяются.
+	late := start.Add(9 * time.Hour)
+	task := store.tasks[1]
+	task.Status, task.CompletedAt = domain.StatusDone, &late
+	store.tasks[1] = task
+	require.Empty(t, check(10*time.Hour))
+
+	store.fail = errors.New("база недоступна")
+	_, err = monitor.Check(ctx)
+	require.Error(t, err)
+}
+
+func TestSLAMonitorDoesNotRepeatEscalationsAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	policy, err := service.ParsePolicy([]byte(`{"sla": {"targets": {"critical": {"responseHours": 1, "resolveHours": 8}}}}`))
+	require.NoError(t, err)
+
+	store := newMemoryStore()
+	store.tasks[1] = domain.Task{ID: 1, Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: start}
+	bus := NewBus()
+	sub := bus.Subscribe(16)
+	t.Cleanup(sub.Close)
+
+	clock := &service.FixedClock{NowValue: start}
+	first := NewSLAMonitor(store, bus, clock, &policy)
+	clock.NowValue = start.Add(2 * time.Hour)
+	count, err := first.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, TaskSLABreached, (<-sub.C).Type)
+
+	// Новый процесс после перезапуска не публикует нарушение повторно, но
+	// сообщает о переходах, случившихся после его запуска.
+	restarted := NewSLAMonitor(store, bus, clock, &policy)
+	count, err = restarted.Check(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	clock.NowValue = start.Add(7 * time.Hour)
+	count, err = restarted.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	event := <-sub.C
+	require.Equal(t, TaskSLAImminent, event.Type)
+	require.Equal(t, service.SLAResolve, event.SLA)
+}
+
+func TestSLAMonitorWithoutTargetsDoesNothing(t *testing.T) {
+	store := newMemoryStore()
+	store.fail = errors.New("список не запрашивается")
+	count, err := NewSLAMonitor(store, NewBus(), nil, nil).Check(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
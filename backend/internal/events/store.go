@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+// NotifyingStore публикует событие после каждого успешного изменения задачи.
+// Изменения внутри WithinTransaction публикуются только после фиксации
+// транзакции, а при откате отбрасываются.
+type NotifyingStore struct {
+	store   repository.TaskStore
+	bus     *Bus
+	clock   service.Clock
+	pending *[]TaskEvent
+}
+
+func NewNotifyingStore(store repository.TaskStore, bus *Bus, clock service.Clock) *NotifyingStore {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	return &NotifyingStore{store: store, bus: bus, clock: clock}
+}
+
+func (s *NotifyingStore) List(ctx context.Context, filter repository.TaskFilter) ([]domain.Task, error) {
+	return s.store.List(ctx, filter)
+}
+
+func (s *NotifyingStore) Stream(ctx context.Context, filter repository.TaskFilter, order repository.TaskOrder, fn func(domain.Task) error) error {
+	return repository.StreamTasks(ctx, s.store, filter, order, fn)
+}
+
+func (s *NotifyingStore) Get(ctx context.Context, id uint) (*domain.Task, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *NotifyingStore) Create(ctx context.Context, task *domain.Task) error {
+	if err := s.store.Create(ctx, task); err != nil {
+		return err
+	}
+	s.emit(TaskEvent{Type: TaskCreated, TaskID: task.ID, Task: *task})
+	return nil
+}
+
+func (s *NotifyingStore) Update(ctx context.Context, task *domain.Task) error {
+	if err := s.store.Update(ctx, task); err != nil {
+		return err
+	}
+	s.emit(TaskEvent{Type: TaskUpdated, TaskID: task.ID, Task: *task})
+	return nil
+}
+
+func (s *NotifyingStore) Delete(ctx context.Context, id uint) error {
+	if err := s.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.emit(TaskEvent{Type: TaskDeleted, TaskID: id})
+	return nil
+}
+
+func (s *NotifyingStore) WithinTransaction(ctx context.Context, fn func(store repository.TaskStore) error) error {
+	var pending []TaskEvent
+	run := func(inner repository.TaskStore) error {
+		return fn(&NotifyingStore{store: inner, bus: s.bus, clock: s.clock, pending: &pending})
+	}
+
+	var err error
+	if transactor, ok := s.store.(repository.TaskTransactor); ok {
+		err = transactor.WithinTransaction(ctx, run)
+	} else {
+		err = run(s.store)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, event := range pending {
+		s.deliver(event)
+	}
+	return nil
+}
+
+func (s *NotifyingStore) emit(event TaskEvent) {
+	event.OccurredAt = s.clock.Now()
+	s.deliver(event)
+}
+
+func (s *NotifyingStore) deliver(event TaskEvent) {
+	if s.pending != nil {
+		*s.pending = append(*s.pending, event)
+		return
+	}
+	s.bus.Publish(event)
+}
@@ -0,0 +1,169 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	tasks  map[uint]domain.Task
+	nextID uint
+	fail   error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{tasks: make(map[uint]domain.Task), nextID: 1}
+}
+
+func (s *memoryStore) List(_ context.Context, filter repository.TaskFilter) ([]domain.Task, error) {
+	result := make([]domain.Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		if filter.Matches(task) {
+			result = append(result, task)
+		}
+	}
+	return result, s.fail
+}
+
+func (s *memoryStore) Get(_ context.Context, id uint) (*domain.Task, error) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &task, nil
+}
+
+func (s *memoryStore) Create(_ context.Context, task *domain.Task) error {
+	if s.fail != nil {
+		return s.fail
+	}
+	task.ID = s.nextID
+	s.nextID++
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func (s *memoryStore) Update(_ context.Context, task *domain.Task) error {
+	if _, ok := s.tasks[task.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id uint) error {
+	if _, ok := s.tasks[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(s.tasks, id)
+	return nil
+}
+
+type transactionalStore struct {
+	*memoryStore
+	calls int
+}
+
+func (s *transactionalStore) WithinTransaction(_ context.Context, fn func(store repository.TaskStore) error) error {
+	s.calls++
+	return fn(s.memoryStore)
+}
+
+var storeClock = service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+
+func TestNotifyingStorePublishesChanges(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(8)
+	t.Cleanup(sub.Close)
+	store := NewNotifyingStore(newMemoryStore(), bus, storeClock)
+	ctx := context.Background()
+
+	task := domain.Task{Title: "Deploy"}
+	require.NoError(t, store.Create(ctx, &task))
+	task.Title = "Deploy v2"
+	require.NoError(t, store.Update(ctx, &task))
+	require.NoError(t, store.Delete(ctx, task.ID))
+
+	created := <-sub.C
+	require.Equal(t, TaskCreated, created.Type)
+	require.Equal(t, task.ID, created.TaskID)
+	require.Equal(t, "Deploy", created.Task.Title)
+	require.Equal(t, storeClock.NowValue, created.OccurredAt)
+
+	updated := <-sub.C
+	require.Equal(t, TaskUpdated, updated.Type)
+	require.Equal(t, "Deploy v2", updated.Task.Title)
+
+	deleted := <-sub.C
+	require.Equal(t, TaskDeleted, deleted.Type)
+	require.Equal(t, task.ID, deleted.TaskID)
+	require.Zero(t, deleted.Task.ID)
+
+	listed, err := store.List(ctx, repository.TaskFilter{})
+	require.NoError(t, err)
+	require.Empty(t, listed)
+	_, err = store.Get(ctx, task.ID)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestNotifyingStoreSkipsFailedChanges(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(8)
+	t.Cleanup(sub.Close)
+	memory := newMemoryStore()
+	memory.fail = errors.New("boom")
+	store := NewNotifyingStore(memory, bus, nil)
+	ctx := context.Background()
+
+	require.Error(t, store.Create(ctx, &domain.Task{Title: "Deploy"}))
+	require.ErrorIs(t, store.Update(ctx, &domain.Task{ID: 7}), repository.ErrNotFound)
+	require.ErrorIs(t, store.Delete(ctx, 7), repository.ErrNotFound)
+	require.Empty(t, sub.C)
+}
+
+func TestNotifyingStoreDefersEventsUntilCommit(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(8)
+	t.Cleanup(sub.Close)
+	inner := &transactionalStore{memoryStore: newMemoryStore()}
+	store := NewNotifyingStore(inner, bus, storeClock)
+	ctx := context.Background()
+
+	err := store.WithinTransaction(ctx, func(tx repository.TaskStore) error {
+		require.NoError(t, tx.Create(ctx, &domain.Task{Title: "One"}))
+		nested, ok := tx.(repository.TaskTransactor)
+		require.True(t, ok)
+		require.NoError(t, nested.WithinTransaction(ctx, func(inner repository.TaskStore) error {
+			return inner.Create(ctx, &domain.Task{Title: "Two"})
+		}))
+		require.Empty(t, sub.C)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, inner.calls)
+	require.Len(t, sub.C, 2)
+	require.Equal(t, "One", (<-sub.C).Task.Title)
+	require.Equal(t, "Two", (<-sub.C).Task.Title)
+}
+
+func TestNotifyingStoreDropsEventsOnRollback(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(8)
+	t.Cleanup(sub.Close)
+	store := NewNotifyingStore(newMemoryStore(), bus, storeClock)
+	ctx := context.Background()
+
+	err := store.WithinTransaction(ctx, func(tx repository.TaskStore) error {
+		require.NoError(t, tx.Create(ctx, &domain.Task{Title: "One"}))
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+	require.Empty(t, sub.C)
+}
@@ -0,0 +1,193 @@
+package export
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+)
+
+const (
+	CodeInvalidFormat   = "export_invalid_format"
+	CodeUnknownColumn   = "export_unknown_column"
+	CodeInvalidTimezone = "export_invalid_timezone"
+)
+
+// DateTimeLayout — формат дат в выгрузке: его без настройки распознают
+// табличные редакторы. Часовой пояс задаёт параметр выгрузки.
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+type column struct {
+	name  string
+	value func(task domain.Task, metrics service.TaskMetrics, loc *time.Location) any
+}
+
+var taskColumns = []column{
+	{"id", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any { return task.ID }},
+	{"title", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any { return task.Title }},
+	{"description", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any { return task.Description }},
+	{"status", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any { return task.Status }},
+	{"priority", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any { return task.Priority }},
+	{"owner", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any { return task.Owner }},
+	{"tags", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any {
+		return strings.Join(task.Tags, ", ")
+	}},
+	{"effortHours", func(task domain.Task, _ service.TaskMetrics, _ *time.Location) any { return task.EffortHours }},
+	{"dueDate", func(task domain.Task, _ service.TaskMetrics, loc *time.Location) any {
+		return formatTime(task.DueDate, loc)
+	}},
+	{"startedAt", func(task domain.Task, _ service.TaskMetrics, loc *time.Location) any {
+		return formatTime(task.StartedAt, loc)
+	}},
+	{"completedAt", func(task domain.Task, _ service.TaskMetrics, loc *time.Location) any {
+		return formatTime(task.CompletedAt, loc)
+	}},
+	{"statusChangedAt", func(task domain.Task, _ service.TaskMetrics, loc *time.Location) any {
+		return formatTime(task.StatusChangedAt, loc)
+	}},
+	{"createdAt", func(task domain.Task, _ service.TaskMetrics, loc *time.Location) any {
+		return formatTime(&task.CreatedAt, loc)
+	}},
+	{"updatedAt", func(task domain.Task, _ service.TaskMetrics, loc *time.Location) any {
+		return formatTime(&task.UpdatedAt, loc)
+	}},
+	{"risk", func(_ domain.Task, metrics service.TaskMetrics, _ *time.Location) any { return metrics.Risk }},
+	{"score", func(_ domain.Task, metrics service.TaskMetrics, _ *time.Location) any { return metrics.Score }},
+	{"ageHours", func(_ domain.Task, metrics service.TaskMetrics, _ *time.Location) any { return metrics.AgeHours }},
+	{"statusHours", func(_ domain.Task, metrics service.TaskMetrics, _ *time.Location) any { return metrics.StatusHours }},
+	{"cycleHours", func(_ domain.Task, metrics service.TaskMetrics, _ *time.Location) any {
+		if metrics.CycleHours == nil {
+			return nil
+		}
+		return *metrics.CycleHours
+	}},
+}
+
+// DefaultColumns — колонки выгрузки задач по умолчанию: описание задачи
+// выгружается только по явному запросу.
+var DefaultColumns = []string{"id", "title", "status", "priority", "owner", "tags", "effortHours", "dueDate", "startedAt", "completedAt", "createdAt", "updatedAt", "risk", "score", "ageHours", "cycleHours"}
+
+// Columns возвращает имена всех доступных колонок выгрузки задач.
+func Columns() []string {
+	names := make([]string, 0, len(taskColumns))
+	for _, col := range taskColumns {
+		names = append(names, col.name)
+	}
+	return names
+}
+
+// TaskLayout — выбранные колонки выгрузки задач.
+type TaskLayout struct {
+	columns  []column
+	location *time.Location
+}
+
+// ParseLayout разбирает список колонок через запятую; пустой список
+// означает DefaultColumns. Повторы отбрасываются, порядок сохраняется.
+func ParseLayout(raw string, loc *time.Location) (TaskLayout, error) {
+	names := DefaultColumns
+	if strings.TrimSpace(raw) != "" {
+		names = strings.Split(raw, ",")
+	}
+
+	layout := TaskLayout{location: loc}
+	seen := make(map[string]bool)
+	for _, entry := range names {
+		name := strings.TrimSpace(entry)
+		if name == "" {
+			continue
+		}
+		col, ok := findColumn(name)
+		if !ok {
+			return TaskLayout{}, service.NewFieldError(CodeUnknownColumn, "columns", map[string]any{"value": name, "available": strings.Join(Columns(), ", ")})
+		}
+		if seen[col.name] {
+			continue
+		}
+		seen[col.name] = true
+		layout.columns = append(layout.columns, col)
+	}
+	return layout, nil
+}
+
+// ParseLocation разбирает часовой пояс IANA; пустое значение означает UTC.
+func ParseLocation(raw string) (*time.Location, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, service.NewFieldError(CodeInvalidTimezone, "tz", map[string]any{"value": name})
+	}
+	return loc, nil
+}
+
+func (l TaskLayout) Header() []any {
+	header := make([]any, 0, len(l.columns))
+	for _, col := range l.columns {
+		header = append(header, col.name)
+	}
+	return header
+}
+
+// Row возвращает значения колонок задачи вместе с её вычисляемыми
+// метриками.
+func (l TaskLayout) Row(task domain.Task, metrics service.TaskMetrics) []any {
+	row := make([]any, 0, len(l.columns))
+	for _, col := range l.columns {
+		row = append(row, col.value(task, metrics, l.location))
+	}
+	return row
+}
+
+// InsightRows раскладывает сводку в пары «метрика — значение»; счётчики по
+// статусам и приоритетам получают имена вида byStatus.todo.
+func InsightRows(insights service.Insights) [][]any {
+	rows := [][]any{
+		{"metric", "value"},
+		{"total", insights.Total},
+		{"overdue", insights.Overdue},
+		{"atRisk", insights.AtRisk},
+		{"blocked", insights.Blocked},
+		{"done", insights.Done},
+		{"averageAgeHours", insights.AverageAgeHours},
+		{"averageCycleHours", insights.AverageCycleHours},
+		{"workloadHours", insights.WorkloadHours},
+		{"focusIndex", insights.FocusIndex},
+	}
+	for _, key := range sortedKeys(insights.ByStatus) {
+		rows = append(rows, []any{"byStatus." + key, insights.ByStatus[key]})
+	}
+	for _, key := range sortedKeys(insights.ByPriority) {
+		rows = append(rows, []any{"byPriority." + key, insights.ByPriority[key]})
+	}
+	return rows
+}
+
+func findColumn(name string) (column, bool) {
+	for _, col := range taskColumns {
+		if strings.EqualFold(col.name, name) {
+			return col, true
+		}
+	}
+	return column{}, false
+}
+
+func formatTime(value *time.Time, loc *time.Location) any {
+	if value == nil || value.IsZero() {
+		return nil
+	}
+	return value.In(loc).Format(DateTimeLayout)
+}
+
+func sortedKeys(values map[string]int) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLayoutDefaultsAndSelection(t *testing.T) {
+	layout, err := ParseLayout("", time.UTC)
+	require.NoError(t, err)
+	require.Len(t, layout.Header(), len(DefaultColumns))
+	require.NotContains(t, layout.Header(), "description")
+
+	layout, err = ParseLayout(" title, Risk ,title,,score", time.UTC)
+	require.NoError(t, err)
+	require.Equal(t, []any{"title", "risk", "score"}, layout.Header())
+
+	_, err = ParseLayout("title,assignee", time.UTC)
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeUnknownColumn, fieldErr.Code)
+	require.Equal(t, "assignee", fieldErr.Details["value"])
+}
+
+func TestTaskLayoutRowRendersMetricsInLocation(t *testing.T) {
+	loc, err := ParseLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-30 * time.Hour)
+	completed := now.Add(-6 * time.Hour)
+	task := domain.Task{
+		ID:          7,
+		Title:       "Ship CI",
+		Status:      domain.StatusDone,
+		Priority:    domain.PriorityHigh,
+		Tags:        domain.StringList{"ci", "release"},
+		StartedAt:   &started,
+		CompletedAt: &completed,
+		CreatedAt:   now.Add(-48 * time.Hour),
+	}
+
+	layout, err := ParseLayout("id,tags,dueDate,completedAt,risk,ageHours,cycleHours", loc)
+	require.NoError(t, err)
+	require.Equal(t, []any{uint(7), "ci, release", nil, "2026-02-06 09:00:00", service.RiskCompleted, 48.0, 24.0}, layout.Row(task, service.ComputeMetrics(now, task)))
+
+	_, err = ParseLocation("Mars/Olympus")
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidTimezone, fieldErr.Code)
+}
+
+func TestInsightRows(t *testing.T) {
+	rows := InsightRows(service.Insights{
+		Total:      3,
+		ByStatus:   map[string]int{domain.StatusTodo: 2, domain.StatusDone: 1},
+		ByPriority: map[string]int{domain.PriorityHigh: 3},
+	})
+	require.Equal(t, []any{"metric", "value"}, rows[0])
+	require.Equal(t, []any{"total", 3}, rows[1])
+	require.Equal(t, []any{"byStatus.done", 1}, rows[len(rows)-3])
+	require.Equal(t, []any{"byPriority.high", 3}, rows[len(rows)-1])
+}
@@ -0,0 +1,209 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"devopslabs/internal/service"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var Formats = []string{FormatCSV, FormatXLSX}
+
+// RowWriter записывает строки таблицы по одной. Значения — строки, числа или
+// nil для пустой ячейки. Close дописывает служебные части формата и должен
+// вызываться после последней строки.
+type RowWriter interface {
+	Write(row []any) error
+	Close() error
+}
+
+// ParseFormat разбирает формат выгрузки; пустое значение означает CSV.
+func ParseFormat(raw string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(raw))
+	switch format {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatXLSX:
+		return format, nil
+	}
+	return "", service.NewFieldError(CodeInvalidFormat, "format", map[string]any{"value": format})
+}
+
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func NewWriter(format string, w io.Writer) RowWriter {
+	if format == FormatXLSX {
+		return newXLSXWriter(w)
+	}
+	return newCSVWriter(w)
+}
+
+type csvWriter struct {
+	out *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{out: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(row []any) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = csvValue(value)
+	}
+	return w.out.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.out.Flush()
+	return w.out.Error()
+}
+
+// csvValue экранирует строки, которые табличный редактор принял бы за
+// формулу: выгрузка содержит текст, введённый пользователями.
+func csvValue(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if typed != "" && strings.ContainsRune("=+-@\t\r", rune(typed[0])) {
+			return "'" + typed
+		}
+		return typed
+	default:
+		return formatNumber(value)
+	}
+}
+
+func formatNumber(value any) string {
+	switch typed := value.(type) {
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// xlsxWriter пишет книгу Office Open XML с одним листом. Части книги
+// записываются в zip-архив потоком, строки листа не накапливаются.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+	err     error
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	writer := &xlsxWriter{archive: zip.NewWriter(w)}
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		writer.writePart(part.name, part.body)
+	}
+
+	// Лист записывается последним, поэтому его часть остаётся открытой до
+	// Close.
+	sheet, err := writer.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		writer.err = err
+		return writer
+	}
+	writer.sheet = bufio.NewWriter(sheet)
+	_, writer.err = writer.sheet.WriteString(xlsxSheetStart)
+	return writer
+}
+
+func (w *xlsxWriter) writePart(name string, body string) {
+	if w.err != nil {
+		return
+	}
+	part, err := w.archive.Create(name)
+	if err != nil {
+		w.err = err
+		return
+	}
+	_, w.err = io.WriteString(part, body)
+}
+
+func (w *xlsxWriter) Write(row []any) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for i, value := range row {
+		ref := columnName(i) + strconv.Itoa(w.rows)
+		switch typed := value.(type) {
+		case nil:
+			continue
+		case string:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			w.err = xml.EscapeText(w.sheet, []byte(typed))
+			w.sheet.WriteString(`</t></is></c>`)
+		default:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, formatNumber(typed))
+		}
+		if w.err != nil {
+			return w.err
+		}
+	}
+	_, w.err = w.sheet.WriteString(`</row>`)
+	return w.err
+}
+
+func (w *xlsxWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if _, err := w.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// columnName переводит номер колонки с нуля в обозначение A, B, …, Z, AA, ….
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewWriter(FormatCSV, &out)
+	require.NoError(t, writer.Write([]any{"title", "score", "cycleHours"}))
+	require.NoError(t, writer.Write([]any{"=HYPERLINK(\"x\")", 42.5, nil}))
+	require.NoError(t, writer.Write([]any{"a, \"quoted\" value", 3, nil}))
+	require.NoError(t, writer.Close())
+
+	require.Equal(t, "title,score,cycleHours\n\"'=HYPERLINK(\"\"x\"\")\",42.5,\n\"a, \"\"quoted\"\" value\",3,\n", out.String())
+}
+
+func TestXLSXWriterProducesWorkbook(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewWriter(FormatXLSX, &out)
+	require.NoError(t, writer.Write([]any{"title", "score"}))
+	require.NoError(t, writer.Write([]any{"R&D <infra>", 42.5}))
+	require.NoError(t, writer.Write([]any{nil, 1}))
+	require.NoError(t, writer.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	names := []string{}
+	var sheet string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, err := file.Open()
+			require.NoError(t, err)
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			sheet = string(body)
+		}
+	}
+	require.ElementsMatch(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+	require.True(t, strings.HasSuffix(sheet, `</sheetData></worksheet>`))
+	require.Contains(t, sheet, `<row r="2"><c r="A2" t="inlineStr"><is><t xml:space="preserve">R&amp;D &lt;infra&gt;</t></is></c><c r="B2"><v>42.5</v></c></row>`)
+	require.Contains(t, sheet, `<row r="3"><c r="B3"><v>1</v></c></row>`)
+}
+
+func TestParseFormatAndColumnName(t *testing.T) {
+	format, err := ParseFormat("")
+	require.NoError(t, err)
+	require.Equal(t, FormatCSV, format)
+	format, err = ParseFormat(" XLSX ")
+	require.NoError(t, err)
+	require.Equal(t, FormatXLSX, format)
+	_, err = ParseFormat("pdf")
+	require.Error(t, err)
+
+	require.Equal(t, "A", columnName(0))
+	require.Equal(t, "Z", columnName(25))
+	require.Equal(t, "AA", columnName(26))
+	require.Equal(t, "BA", columnName(52))
+}
@@ -0,0 +1,175 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+const (
+	DefaultHistoryDays = 30
+	MaxHistoryDays     = 365
+	DefaultTrials      = 10000
+	// MaxTrials вместе с MaxDays ограничивает работу одного прогноза:
+	// испытание длится не больше MaxDays шагов.
+	MaxTrials = 20000
+	// MaxDays ограничивает срок одного испытания, чтобы редкие завершения
+	// не делали прогноз бесконечным.
+	MaxDays = 3650
+)
+
+// Confidences — уровни уверенности прогноза в процентах.
+var Confidences = []int{50, 85, 95}
+
+// ErrNoThroughput означает, что за окно истории не завершено ни одной задачи
+// и прогнозировать не по чему.
+var ErrNoThroughput = errors.New("forecast: no throughput in history window")
+
+// Throughput возвращает число задач, завершённых в каждый день from..to
+// включительно по UTC.
+func Throughput(tasks []domain.Task, from time.Time, to time.Time) []int {
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days <= 0 {
+		return []int{}
+	}
+	samples := make([]int, days)
+	end := to.AddDate(0, 0, 1)
+	for _, task := range tasks {
+		if task.Status != domain.StatusDone || task.CompletedAt == nil {
+			continue
+		}
+		completed := task.CompletedAt.UTC()
+		if completed.Before(from) || !completed.Before(end) {
+			continue
+		}
+		samples[int(completed.Sub(from).Hours()/24)]++
+	}
+	return samples
+}
+
+// ItemsForecast — сколько задач будет завершено к сроку с заданной
+// уверенностью.
+type ItemsForecast struct {
+	Confidence int `json:"confidence"`
+	Items      int `json:"items"`
+}
+
+// DateForecast — через сколько дней и к какой дате будут завершены задачи с
+// заданной уверенностью. Unreachable означает, что задачи не успевают
+// завершиться за MaxDays дней в нужной доле испытаний; срока тогда нет.
+type DateForecast struct {
+	Confidence  int    `json:"confidence"`
+	Days        int    `json:"days,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Unreachable bool   `json:"unreachable,omitempty"`
+}
+
+// Simulator выбирает дневную пропускную способность из истории случайно с
+// возвращением. При одинаковом seed результаты совпадают.
+type Simulator struct {
+	trials int
+	seed   uint64
+}
+
+func NewSimulator(trials int, seed uint64) *Simulator {
+	if trials <= 0 {
+		trials = DefaultTrials
+	}
+	return &Simulator{trials: trials, seed: seed}
+}
+
+func (s *Simulator) rng() *rand.Rand {
+	return rand.New(rand.NewPCG(s.seed, s.seed^0x9e3779b97f4a7c15))
+}
+
+// HowMany моделирует days дней и возвращает число задач, которое будет
+// завершено не меньше чем в Confidence процентов испытаний.
+func (s *Simulator) HowMany(samples []int, days int) ([]ItemsForecast, error) {
+	if !hasThroughput(samples) {
+		return nil, ErrNoThroughput
+	}
+	rng := s.rng()
+	totals := make([]int, s.trials)
+	for trial := range totals {
+		for day := 0; day < days; day++ {
+			totals[trial] += samples[rng.IntN(len(samples))]
+		}
+	}
+	sort.Ints(totals)
+
+	result := make([]ItemsForecast, 0, len(Confidences))
+	for _, confidence := range Confidences {
+		result = append(result, ItemsForecast{Confidence: confidence, Items: rank(totals, 100-confidence)})
+	}
+	return result, nil
+}
+
+// When моделирует завершение items задач, начиная со дня после start, и
+// возвращает срок, в который укладываются Confidence процентов испытаний, и
+// число испытаний, не завершившихся за MaxDays дней.
+func (s *Simulator) When(samples []int, items int, start time.Time) ([]DateForecast, int, error) {
+	result := make([]DateForecast, 0, len(Confidences))
+	if items <= 0 {
+		for _, confidence := range Confidences {
+			result = append(result, DateForecast{Confidence: confidence, Date: start.Format(time.DateOnly)})
+		}
+		return result, 0, nil
+	}
+	if !hasThroughput(samples) {
+		return nil, 0, ErrNoThroughput
+	}
+
+	rng := s.rng()
+	durations := make([]int, s.trials)
+	unfinished := 0
+	for trial := range durations {
+		done, day := 0, 0
+		for done < items && day < MaxDays {
+			done += samples[rng.IntN(len(samples))]
+			day++
+		}
+		if done < items {
+			// Незавершённое испытание сортируется после всех завершённых.
+			day = MaxDays + 1
+			unfinished++
+		}
+		durations[trial] = day
+	}
+	sort.Ints(durations)
+
+	for _, confidence := range Confidences {
+		days := rank(durations, confidence)
+		if days > MaxDays {
+			result = append(result, DateForecast{Confidence: confidence, Unreachable: true})
+			continue
+		}
+		result = append(result, DateForecast{
+			Confidence: confidence,
+			Days:       days,
+			Date:       start.AddDate(0, 0, days).Format(time.DateOnly),
+		})
+	}
+	return result, unfinished, nil
+}
+
+// rank возвращает p-й процентиль упорядоченных значений по ближайшему рангу.
+func rank(sorted []int, p int) int {
+	index := int(math.Ceil(float64(p)/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+func hasThroughput(samples []int) bool {
+	for _, value := range samples {
+		if value > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThroughput(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	done := func(completed time.Time) domain.Task {
+		return domain.Task{Status: domain.StatusDone, CompletedAt: &completed}
+	}
+	tasks := []domain.Task{
+		done(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)),
+		done(time.Date(2026, 3, 3, 23, 59, 0, 0, time.UTC)),
+		done(time.Date(2026, 3, 3, 8, 0, 0, 0, time.FixedZone("MSK", 3*3600))),
+		// Вне окна.
+		done(time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC)),
+		done(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)),
+		{Status: domain.StatusInProgress},
+	}
+	require.Equal(t, []int{1, 0, 2}, Throughput(tasks, from, to))
+}
+
+func TestSimulatorIsDeterministic(t *testing.T) {
+	samples := []int{0, 1, 2, 3, 0, 5, 1}
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	first, _, err := NewSimulator(2000, 42).When(samples, 20, start)
+	require.NoError(t, err)
+	second, _, err := NewSimulator(2000, 42).When(samples, 20, start)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Len(t, first, 3)
+	for index := 1; index < len(first); index++ {
+		require.GreaterOrEqual(t, first[index].Days, first[index-1].Days)
+	}
+	require.Equal(t, start.AddDate(0, 0, first[0].Days).Format(time.DateOnly), first[0].Date)
+
+	howMany, err := NewSimulator(2000, 42).HowMany(samples, 10)
+	require.NoError(t, err)
+	for index := 1; index < len(howMany); index++ {
+		require.LessOrEqual(t, howMany[index].Items, howMany[index-1].Items)
+	}
+}
+
+func TestSimulatorConstantThroughput(t *testing.T) {
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	simulator := NewSimulator(100, 1)
+
+	howMany, err := simulator.HowMany([]int{2, 2, 2}, 5)
+	require.NoError(t, err)
+	require.Equal(t, []ItemsForecast{{Confidence: 50, Items: 10}, {Confidence: 85, Items: 10}, {Confidence: 95, Items: 10}}, howMany)
+
+	when, unfinished, err := simulator.When([]int{2}, 7, start)
+	require.NoError(t, err)
+	require.Equal(t, DateForecast{Confidence: 95, Days: 4, Date: "2026-03-14"}, when[2])
+	require.Zero(t, unfinished)
+
+	when, _, err = simulator.When(nil, 0, start)
+	require.NoError(t, err)
+	require.Equal(t, DateForecast{Confidence: 50, Date: "2026-03-10"}, when[0])
+
+	_, _, err = simulator.When([]int{0, 0}, 3, start)
+	require.ErrorIs(t, err, ErrNoThroughput)
+	_, err = simulator.HowMany([]int{}, 3)
+	require.ErrorIs(t, err, ErrNoThroughput)
+}
+
+func TestSimulatorReportsUnreachableDates(t *testing.T) {
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	// Задача завершается в среднем раз в 100 дней, а нужно завершить 40.
+	samples := make([]int, 100)
+	samples[0] = 1
+
+	when, unfinished, err := NewSimulator(200, 7).When(samples, 40, start)
+	require.NoError(t, err)
+	require.Greater(t, unfinished, 100)
+	require.Equal(t, DateForecast{Confidence: 95, Unreachable: true}, when[2])
+
+	when, unfinished, err = NewSimulator(200, 7).When([]int{1}, MaxDays+1, start)
+	require.NoError(t, err)
+	require.Equal(t, 200, unfinished)
+	for _, forecast := range when {
+		require.True(t, forecast.Unreachable)
+		require.Empty(t, forecast.Date)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	query, err := ParseQuery("", "", "", "", "", now)
+	require.NoError(t, err)
+	require.Equal(t, Query{Mode: ModeWhen, History: DefaultHistoryDays, Trials: DefaultTrials, Seed: uint64(now.UnixNano())}, query)
+
+	query, err = ParseQuery("2026-03-20", "", "14", "500", "7", now)
+	require.NoError(t, err)
+	require.Equal(t, ModeHowMany, query.Mode)
+	require.Equal(t, time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC), query.Target)
+	require.Equal(t, 14, query.History)
+	require.Equal(t, 500, query.Trials)
+	require.Equal(t, uint64(7), query.Seed)
+
+	_, err = ParseQuery("2026-03-10", "", "", "", "", now)
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodePastTarget, fieldErr.Code)
+	_, err = ParseQuery("9999-12-31", "", "", "", "", now)
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeFarTarget, fieldErr.Code)
+	require.Equal(t, MaxDays, fieldErr.Details["max"])
+	_, err = ParseQuery(now.AddDate(0, 0, MaxDays).Format(time.DateOnly), "", "", "", "", now)
+	require.NoError(t, err)
+	_, err = ParseQuery("2026-03-20", "5", "0", "1000000", "-1", now)
+	requireCodes(t, err, CodeConflictMode, CodeInvalidHistory, CodeInvalidTrials, CodeInvalidSeed)
+	_, err = ParseQuery("tomorrow", "many", "", "", "", now)
+	requireCodes(t, err, CodeConflictMode, CodeInvalidTarget, CodeInvalidItems)
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	completed := func(day int) *time.Time {
+		value := time.Date(2026, 3, day, 12, 0, 0, 0, time.UTC)
+		return &value
+	}
+	tasks := []domain.Task{
+		{ID: 1, Status: domain.StatusDone, CompletedAt: completed(8)},
+		{ID: 2, Status: domain.StatusDone, CompletedAt: completed(9)},
+		// Сегодняшний день неполный и в окно не входит.
+		{ID: 3, Status: domain.StatusDone, CompletedAt: completed(10)},
+		{ID: 4, Status: domain.StatusTodo},
+		{ID: 5, Status: domain.StatusInProgress},
+	}
+
+	result, err := Build(tasks, Query{Mode: ModeWhen, History: 2, Trials: 10, Seed: 1}, now)
+	require.NoError(t, err)
+	require.Equal(t, "2026-03-08", result.HistoryFrom)
+	require.Equal(t, "2026-03-09", result.HistoryTo)
+	require.Equal(t, []int{1, 1}, result.Throughput)
+	require.Equal(t, 2, *result.Items)
+	require.Equal(t, DateForecast{Confidence: 85, Days: 2, Date: "2026-03-12"}, result.When[1])
+
+	target := time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)
+	result, err = Build(tasks, Query{Mode: ModeHowMany, Target: target, History: 2, Trials: 10, Seed: 1}, now)
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Days)
+	require.Nil(t, result.Items)
+	require.Equal(t, ItemsForecast{Confidence: 95, Items: 3}, result.HowMany[2])
+
+	_, err = Build(tasks, Query{Mode: ModeWhen, History: 1, Trials: 10, Seed: 1}, now.AddDate(0, 0, 5))
+	require.ErrorIs(t, err, ErrNoThroughput)
+}
+
+func requireCodes(t *testing.T, err error, codes ...string) {
+	t.Helper()
+	var errs service.ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	var actual []string
+	for _, fieldErr := range errs {
+		actual = append(actual, fieldErr.Code)
+	}
+	require.Equal(t, codes, actual)
+}
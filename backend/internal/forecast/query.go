@@ -0,0 +1,181 @@
+package forecast
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+)
+
+// Режимы прогноза: сколько задач будет завершено к дате или когда будут
+// завершены заданные задачи.
+const (
+	ModeHowMany = "how_many"
+	ModeWhen    = "when"
+)
+
+const (
+	CodeInvalidTarget  = "forecast_invalid_target"
+	CodePastTarget     = "forecast_past_target"
+	CodeFarTarget      = "forecast_far_target"
+	CodeInvalidItems   = "forecast_invalid_items"
+	CodeConflictMode   = "forecast_conflicting_mode"
+	CodeInvalidHistory = "forecast_invalid_history"
+	CodeInvalidTrials  = "forecast_invalid_trials"
+	CodeInvalidSeed    = "forecast_invalid_seed"
+	CodeNoThroughput   = "forecast_no_throughput"
+)
+
+// Query описывает прогноз. Items равен nil, если число задач не указано и
+// должно браться из незавершённых задач под фильтром.
+type Query struct {
+	Mode    string
+	Target  time.Time
+	Items   *int
+	History int
+	Trials  int
+	Seed    uint64
+}
+
+// ParseQuery проверяет параметры прогноза. С target считается, сколько задач
+// будет завершено к этому дню, иначе — когда будут завершены items задач.
+// Без seed генератор инициализируется текущим временем.
+func ParseQuery(target, items, history, trials, seed string, now time.Time) (Query, error) {
+	var errs service.ValidationErrors
+	query := Query{
+		Mode:    ModeWhen,
+		History: DefaultHistoryDays,
+		Trials:  DefaultTrials,
+		Seed:    uint64(now.UnixNano()),
+	}
+
+	target, items = strings.TrimSpace(target), strings.TrimSpace(items)
+	if target != "" && items != "" {
+		errs.Add(service.NewFieldError(CodeConflictMode, "target", nil))
+	}
+	if target != "" {
+		query.Mode = ModeHowMany
+		day, err := time.Parse(time.DateOnly, target)
+		switch {
+		case err != nil:
+			errs.Add(service.NewFieldError(CodeInvalidTarget, "target", map[string]any{"value": target}))
+		case !day.After(startOfDay(now)):
+			errs.Add(service.NewFieldError(CodePastTarget, "target", map[string]any{"value": target}))
+		case day.After(startOfDay(now).AddDate(0, 0, MaxDays)):
+			// Испытание всё равно не длится дольше MaxDays, а дальняя дата
+			// лишь удлиняет моделирование.
+			errs.Add(service.NewFieldError(CodeFarTarget, "target", map[string]any{"value": target, "max": MaxDays}))
+		default:
+			query.Target = day
+		}
+	}
+	if items != "" {
+		count, err := strconv.Atoi(items)
+		if err != nil || count < 0 {
+			errs.Add(service.NewFieldError(CodeInvalidItems, "items", map[string]any{"value": items}))
+		} else {
+			query.Items = &count
+		}
+	}
+
+	if value := strings.TrimSpace(history); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > MaxHistoryDays {
+			errs.Add(service.NewFieldError(CodeInvalidHistory, "history", map[string]any{"max": MaxHistoryDays}))
+		} else {
+			query.History = days
+		}
+	}
+	if value := strings.TrimSpace(trials); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 || count > MaxTrials {
+			errs.Add(service.NewFieldError(CodeInvalidTrials, "trials", map[string]any{"max": MaxTrials}))
+		} else {
+			query.Trials = count
+		}
+	}
+	if value := strings.TrimSpace(seed); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			errs.Add(service.NewFieldError(CodeInvalidSeed, "seed", map[string]any{"value": value}))
+		} else {
+			query.Seed = parsed
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return Query{}, err
+	}
+	return query, nil
+}
+
+// Forecast — результат моделирования. Throughput содержит число завершённых
+// задач по дням окна истории HistoryFrom..HistoryTo; Unfinished — число
+// испытаний, в которых задачи не завершились за MaxDays дней.
+type Forecast struct {
+	Mode        string          `json:"mode"`
+	HistoryFrom string          `json:"historyFrom"`
+	HistoryTo   string          `json:"historyTo"`
+	Throughput  []int           `json:"throughput"`
+	Trials      int             `json:"trials"`
+	Seed        uint64          `json:"seed"`
+	Target      string          `json:"target,omitempty"`
+	Days        int             `json:"days,omitempty"`
+	Items       *int            `json:"items,omitempty"`
+	HowMany     []ItemsForecast `json:"howMany,omitempty"`
+	When        []DateForecast  `json:"when,omitempty"`
+	Unfinished  int             `json:"unfinished,omitempty"`
+}
+
+// Build строит прогноз по задачам под фильтром. Окно истории состоит из
+// полных дней перед текущим, а моделирование начинается с завтрашнего дня.
+func Build(tasks []domain.Task, query Query, now time.Time) (Forecast, error) {
+	today := startOfDay(now)
+	from := today.AddDate(0, 0, -query.History)
+	to := today.AddDate(0, 0, -1)
+	result := Forecast{
+		Mode:        query.Mode,
+		HistoryFrom: from.Format(time.DateOnly),
+		HistoryTo:   to.Format(time.DateOnly),
+		Throughput:  Throughput(tasks, from, to),
+		Trials:      query.Trials,
+		Seed:        query.Seed,
+	}
+	simulator := NewSimulator(query.Trials, query.Seed)
+
+	if query.Mode == ModeHowMany {
+		result.Target = query.Target.Format(time.DateOnly)
+		result.Days = int(query.Target.Sub(today).Hours() / 24)
+		howMany, err := simulator.HowMany(result.Throughput, result.Days)
+		if err != nil {
+			return Forecast{}, err
+		}
+		result.HowMany = howMany
+		return result, nil
+	}
+
+	items := 0
+	if query.Items != nil {
+		items = *query.Items
+	} else {
+		for _, task := range tasks {
+			if task.Status != domain.StatusDone {
+				items++
+			}
+		}
+	}
+	result.Items = &items
+	when, unfinished, err := simulator.When(result.Throughput, items, today)
+	if err != nil {
+		return Forecast{}, err
+	}
+	result.When, result.Unfinished = when, unfinished
+	return result, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,165 @@
+package history
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+// DefaultBurndownDays — длина итерации, если начало не указано.
+const DefaultBurndownDays = 14
+
+// Виды изменения объёма работ.
+const (
+	ScopeAdded   = "added"
+	ScopeRemoved = "removed"
+	ScopeResized = "resized"
+)
+
+// BurndownPoint — объём работ на конец дня. Completed — задачи в статусе
+// done, Remaining — все остальные; Scope — их сумма.
+type BurndownPoint struct {
+	Date           string `json:"date"`
+	RemainingHours int    `json:"remainingHours"`
+	CompletedHours int    `json:"completedHours"`
+	ScopeHours     int    `json:"scopeHours"`
+	RemainingTasks int    `json:"remainingTasks"`
+	CompletedTasks int    `json:"completedTasks"`
+	ScopeTasks     int    `json:"scopeTasks"`
+}
+
+// IdealPoint — остаток работ на конец дня при равномерном темпе.
+type IdealPoint struct {
+	Date           string  `json:"date"`
+	RemainingHours float64 `json:"remainingHours"`
+}
+
+// ScopeChange отмечает задачу, которая вошла в объём работ, вышла из него
+// или изменила оценку. EffortHours — изменение объёма в часах.
+type ScopeChange struct {
+	Date        string `json:"date"`
+	TaskID      uint   `json:"taskId"`
+	Title       string `json:"title"`
+	Kind        string `json:"kind"`
+	EffortHours int    `json:"effortHours"`
+}
+
+type Burndown struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	Points       []BurndownPoint `json:"points"`
+	Ideal        []IdealPoint    `json:"ideal"`
+	ScopeChanges []ScopeChange   `json:"scopeChanges"`
+}
+
+// BurndownBuilder строит данные диаграмм сгорания и выполнения по истории
+// изменений задач.
+type BurndownBuilder struct {
+	tasks   repository.TaskStore
+	changes repository.ChangeStore
+	clock   service.Clock
+}
+
+func NewBurndownBuilder(tasks repository.TaskStore, changes repository.ChangeStore, clock service.Clock) *BurndownBuilder {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	return &BurndownBuilder{tasks: tasks, changes: changes, clock: clock}
+}
+
+// Build считает объём работ за дни from..to по задачам, подходящим под
+// filter. Фильтр применяется к состоянию задачи на каждый момент, поэтому
+// задача, получившая нужный тег посреди итерации, отмечается как добавленная.
+// Задачи, завершённые до начала итерации, в объём не входят. Точки строятся
+// до текущего дня, идеальная линия — на весь диапазон.
+func (b *BurndownBuilder) Build(ctx context.Context, from, to time.Time, filter repository.TaskFilter) (Burndown, error) {
+	now := b.clock.Now()
+	result := Burndown{From: Day(from), To: Day(to), Points: []BurndownPoint{}, Ideal: []IdealPoint{}, ScopeChanges: []ScopeChange{}}
+
+	timeline, err := LoadTimeline(ctx, b.tasks, b.changes, now)
+	if err != nil {
+		return Burndown{}, err
+	}
+	inScope := func(at time.Time) map[uint]domain.Task {
+		scope := make(map[uint]domain.Task)
+		for _, task := range timeline.At(at) {
+			if !filter.Matches(task) || doneBefore(task, from) {
+				continue
+			}
+			scope[task.ID] = task
+		}
+		return scope
+	}
+
+	previous := inScope(from)
+	baseline := 0
+	for _, task := range previous {
+		if task.Status != domain.StatusDone {
+			baseline += task.EffortHours
+		}
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+	for index := 0; index < days; index++ {
+		day := from.AddDate(0, 0, index)
+		result.Ideal = append(result.Ideal, IdealPoint{
+			Date:           Day(day),
+			RemainingHours: math.Round(float64(baseline)*float64(days-index-1)/float64(days)*100) / 100,
+		})
+		if day.After(now) {
+			continue
+		}
+
+		end := day.AddDate(0, 0, 1)
+		if end.After(now) {
+			end = now
+		}
+		current := inScope(end)
+		result.Points = append(result.Points, burndownPoint(Day(day), current))
+		result.ScopeChanges = append(result.ScopeChanges, scopeChanges(Day(day), previous, current, timeline.ids)...)
+		previous = current
+	}
+	return result, nil
+}
+
+func burndownPoint(date string, scope map[uint]domain.Task) BurndownPoint {
+	point := BurndownPoint{Date: date, ScopeTasks: len(scope)}
+	for _, task := range scope {
+		point.ScopeHours += task.EffortHours
+		if task.Status == domain.StatusDone {
+			point.CompletedHours += task.EffortHours
+			point.CompletedTasks++
+		} else {
+			point.RemainingHours += task.EffortHours
+			point.RemainingTasks++
+		}
+	}
+	return point
+}
+
+// scopeChanges сравнивает объём работ на начало и конец дня; ids задают
+// порядок отметок.
+func scopeChanges(date string, before, after map[uint]domain.Task, ids []uint) []ScopeChange {
+	var changes []ScopeChange
+	for _, id := range ids {
+		old, wasIn := before[id]
+		task, isIn := after[id]
+		switch {
+		case isIn && !wasIn:
+			changes = append(changes, ScopeChange{Date: date, TaskID: id, Title: task.Title, Kind: ScopeAdded, EffortHours: task.EffortHours})
+		case wasIn && !isIn:
+			changes = append(changes, ScopeChange{Date: date, TaskID: id, Title: old.Title, Kind: ScopeRemoved, EffortHours: -old.EffortHours})
+		case isIn && task.EffortHours != old.EffortHours:
+			changes = append(changes, ScopeChange{Date: date, TaskID: id, Title: task.Title, Kind: ScopeResized, EffortHours: task.EffortHours - old.EffortHours})
+		}
+	}
+	return changes
+}
+
+func doneBefore(task domain.Task, at time.Time) bool {
+	return task.Status == domain.StatusDone && task.CompletedAt != nil && task.CompletedAt.Before(at)
+}
@@ -0,0 +1,174 @@
+package history
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+// FlowStatuses — статусы в порядке слоёв накопительной диаграммы, от
+// начала работы к завершению.
+var FlowStatuses = []string{domain.StatusTodo, domain.StatusInProgress, domain.StatusBlocked, domain.StatusDone}
+
+// FlowPoint — число задач в каждом статусе на конец дня.
+type FlowPoint struct {
+	Date   string         `json:"date"`
+	Counts map[string]int `json:"counts"`
+}
+
+type CumulativeFlow struct {
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Statuses []string    `json:"statuses"`
+	Points   []FlowPoint `json:"points"`
+}
+
+// TaskStatusTime — переходы задачи и часы, проведённые в каждом статусе,
+// кроме done.
+type TaskStatusTime struct {
+	TaskID      uint               `json:"taskId"`
+	Title       string             `json:"title"`
+	Status      string             `json:"status"`
+	Transitions []Transition       `json:"transitions"`
+	Hours       map[string]float64 `json:"hours"`
+}
+
+// StatusTime — распределение времени в статусе по задачам, которые в нём
+// побывали.
+type StatusTime struct {
+	Status string `json:"status"`
+	service.DurationStats
+	TotalHours float64 `json:"totalHours"`
+}
+
+type TimeInStatus struct {
+	Statuses []StatusTime     `json:"statuses"`
+	Tasks    []TaskStatusTime `json:"tasks"`
+}
+
+// FlowBuilder строит накопительную диаграмму потока и время в статусах по
+// истории изменений задач.
+type FlowBuilder struct {
+	tasks   repository.TaskStore
+	changes repository.ChangeStore
+	clock   service.Clock
+}
+
+func NewFlowBuilder(tasks repository.TaskStore, changes repository.ChangeStore, clock service.Clock) *FlowBuilder {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	return &FlowBuilder{tasks: tasks, changes: changes, clock: clock}
+}
+
+// CumulativeFlow считает задачи по статусам на конец каждого дня from..to
+// до текущего. Фильтр применяется к состоянию задачи на конец дня.
+func (b *FlowBuilder) CumulativeFlow(ctx context.Context, from, to time.Time, filter repository.TaskFilter) (CumulativeFlow, error) {
+	now := b.clock.Now()
+	flow := CumulativeFlow{From: Day(from), To: Day(to), Statuses: FlowStatuses, Points: []FlowPoint{}}
+
+	timeline, err := LoadTimeline(ctx, b.tasks, b.changes, now)
+	if err != nil {
+		return CumulativeFlow{}, err
+	}
+	for day := from; !day.After(to) && !day.After(now); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		if end.After(now) {
+			end = now
+		}
+		point := FlowPoint{Date: Day(day), Counts: make(map[string]int, len(FlowStatuses))}
+		for _, status := range FlowStatuses {
+			point.Counts[status] = 0
+		}
+		for _, task := range timeline.At(end) {
+			if filter.Matches(task) {
+				point.Counts[task.Status]++
+			}
+		}
+		flow.Points = append(flow.Points, point)
+	}
+	return flow, nil
+}
+
+// TimeInStatus считает время в статусах для задач, подходящих под filter
+// сейчас. Время в текущем статусе идёт до текущего момента; время в done не
+// считается.
+func (b *FlowBuilder) TimeInStatus(ctx context.Context, filter repository.TaskFilter) (TimeInStatus, error) {
+	now := b.clock.Now()
+	current, err := b.tasks.List(ctx, filter)
+	if err != nil {
+		return TimeInStatus{}, err
+	}
+	ids := make([]uint, 0, len(current))
+	for _, task := range current {
+		ids = append(ids, task.ID)
+	}
+	var changes []domain.TaskChange
+	if len(ids) > 0 {
+		if changes, err = b.changes.List(ctx, repository.ChangeFilter{TaskIDs: ids, Until: now}); err != nil {
+			return TimeInStatus{}, err
+		}
+	}
+	timeline := NewTimeline(current, changes)
+
+	result := TimeInStatus{Tasks: make([]TaskStatusTime, 0, len(current))}
+	perStatus := make(map[string][]float64)
+	for _, task := range current {
+		transitions := timeline.Transitions(task.ID, now)
+		item := TaskStatusTime{
+			TaskID:      task.ID,
+			Title:       task.Title,
+			Status:      task.Status,
+			Transitions: transitions,
+			Hours:       statusHours(transitions, now),
+		}
+		if item.Transitions == nil {
+			item.Transitions = []Transition{}
+		}
+		for status, hours := range item.Hours {
+			perStatus[status] = append(perStatus[status], hours)
+		}
+		result.Tasks = append(result.Tasks, item)
+	}
+
+	for _, status := range FlowStatuses {
+		if status == domain.StatusDone {
+			continue
+		}
+		stats := StatusTime{Status: status, DurationStats: service.SummarizeHours(perStatus[status])}
+		for _, hours := range perStatus[status] {
+			stats.TotalHours += hours
+		}
+		stats.TotalHours = round2(stats.TotalHours)
+		result.Statuses = append(result.Statuses, stats)
+	}
+	return result, nil
+}
+
+func statusHours(transitions []Transition, now time.Time) map[string]float64 {
+	hours := make(map[string]float64)
+	for index, transition := range transitions {
+		if transition.Status == domain.StatusDone {
+			continue
+		}
+		end := now
+		if index+1 < len(transitions) {
+			end = transitions[index+1].At
+		}
+		if end.After(transition.At) {
+			hours[transition.Status] += end.Sub(transition.At).Hours()
+		}
+	}
+	for status, value := range hours {
+		hours[status] = round2(value)
+	}
+	return hours
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,372 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	tasks  map[uint]domain.Task
+	nextID uint
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{tasks: make(map[uint]domain.Task), nextID: 1}
+}
+
+func (s *memoryStore) List(context.Context, repository.TaskFilter) ([]domain.Task, error) {
+	result := make([]domain.Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		result = append(result, task)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (s *memoryStore) Get(_ context.Context, id uint) (*domain.Task, error) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &task, nil
+}
+
+func (s *memoryStore) Create(_ context.Context, task *domain.Task) error {
+	task.ID = s.nextID
+	s.nextID++
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func (s *memoryStore) Update(_ context.Context, task *domain.Task) error {
+	if _, ok := s.tasks[task.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id uint) error {
+	if _, ok := s.tasks[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(s.tasks, id)
+	return nil
+}
+
+// manualClock — часы, которые тест переводит вручную.
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func day(value string) time.Time {
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestRecordingStoreRecordsCommittedChanges(t *testing.T) {
+	changes := repository.NewMemoryChangeStore()
+	clock := &manualClock{now: day("2026-03-02").Add(9 * time.Hour)}
+	store := NewRecordingStore(newMemoryStore(), changes, clock)
+	ctx := context.Background()
+
+	task := domain.Task{Title: "Deploy", Status: domain.StatusTodo}
+	require.NoError(t, store.Create(ctx, &task))
+	clock.now = clock.now.Add(time.Hour)
+	task.Status = domain.StatusInProgress
+	require.NoError(t, store.Update(ctx, &task))
+	require.ErrorIs(t, store.Delete(ctx, 42), repository.ErrNotFound)
+
+	err := store.WithinTransaction(ctx, func(tx repository.TaskStore) error {
+		return tx.Create(ctx, &domain.Task{Title: "Lost"})
+	})
+	require.NoError(t, err)
+	err = store.WithinTransaction(ctx, func(tx repository.TaskStore) error {
+		require.NoError(t, tx.Delete(ctx, task.ID))
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+
+	recorded, err := changes.List(ctx, repository.ChangeFilter{})
+	require.NoError(t, err)
+	require.Len(t, recorded, 3)
+	require.Equal(t, domain.ChangeCreated, recorded[0].Type)
+	require.Equal(t, domain.StatusTodo, recorded[0].Task.Status)
+	require.Equal(t, domain.ChangeUpdated, recorded[1].Type)
+	require.Equal(t, domain.StatusInProgress, recorded[1].Task.Status)
+	require.Equal(t, clock.now, recorded[1].OccurredAt)
+	// Откат транзакции в памяти не отменяет удаление, но запись о нём
+	// отбрасывается вместе с транзакцией.
+	require.Equal(t, "Lost", recorded[2].Task.Title)
+}
+
+func TestRecordingStoreDropsNestedChangesOnOuterRollback(t *testing.T) {
+	changes := repository.NewMemoryChangeStore()
+	clock := &manualClock{now: day("2026-03-02").Add(9 * time.Hour)}
+	store := NewRecordingStore(newMemoryStore(), changes, clock)
+	ctx := context.Background()
+
+	err := store.WithinTransaction(ctx, func(tx repository.TaskStore) error {
+		nested, ok := tx.(repository.TaskTransactor)
+		require.True(t, ok)
+		require.NoError(t, nested.WithinTransaction(ctx, func(inner repository.TaskStore) error {
+			return inner.Create(ctx, &domain.Task{Title: "Nested"})
+		}))
+		recorded, err := changes.List(ctx, repository.ChangeFilter{})
+		require.NoError(t, err)
+		require.Empty(t, recorded)
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+	recorded, err := changes.List(ctx, repository.ChangeFilter{})
+	require.NoError(t, err)
+	require.Empty(t, recorded)
+
+	err = store.WithinTransaction(ctx, func(tx repository.TaskStore) error {
+		return tx.(repository.TaskTransactor).WithinTransaction(ctx, func(inner repository.TaskStore) error {
+			return inner.Create(ctx, &domain.Task{Title: "Committed"})
+		})
+	})
+	require.NoError(t, err)
+	recorded, err = changes.List(ctx, repository.ChangeFilter{})
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	require.Equal(t, "Committed", recorded[0].Task.Title)
+}
+
+func TestTimelineReplaysChanges(t *testing.T) {
+	created := day("2026-03-01").Add(10 * time.Hour)
+	started := day("2026-03-03").Add(10 * time.Hour)
+	current := []domain.Task{
+		{ID: 1, Status: domain.StatusDone, Owner: "anna", CreatedAt: created, StartedAt: &started},
+		// Задача появилась до начала истории и с тех пор не менялась.
+		{ID: 2, Status: domain.StatusDone, CreatedAt: created.Add(-48 * time.Hour), StartedAt: &started, CompletedAt: ptr(started.Add(24 * time.Hour))},
+	}
+	changes := []domain.TaskChange{
+		{TaskID: 1, Type: domain.ChangeCreated, OccurredAt: created, Task: domain.Task{ID: 1, Status: domain.StatusTodo, Owner: "ivan", CreatedAt: created}},
+		{TaskID: 1, Type: domain.ChangeUpdated, OccurredAt: started, Task: domain.Task{ID: 1, Status: domain.StatusInProgress, Owner: "anna", CreatedAt: created}},
+		{TaskID: 3, Type: domain.ChangeUpdated, OccurredAt: started, Task: domain.Task{ID: 3, Status: domain.StatusBlocked, CreatedAt: created}},
+		{TaskID: 3, Type: domain.ChangeDeleted, OccurredAt: started.Add(24 * time.Hour)},
+	}
+	timeline := NewTimeline(current, changes)
+
+	at := timeline.At(day("2026-02-28"))
+	require.Len(t, at, 1)
+	require.Equal(t, uint(2), at[0].ID)
+	require.Equal(t, domain.StatusTodo, at[0].Status)
+
+	at = timeline.At(day("2026-03-02"))
+	require.Len(t, at, 3)
+	require.Equal(t, "ivan", at[0].Owner)
+	require.Equal(t, domain.StatusTodo, at[0].Status)
+	// Удалённая задача восстанавливается по самому раннему известному
+	// состоянию: без StartedAt она считается ещё не начатой.
+	require.Equal(t, uint(3), at[2].ID)
+	require.Equal(t, domain.StatusTodo, at[2].Status)
+
+	at = timeline.At(day("2026-03-04"))
+	require.Len(t, at, 3)
+	require.Equal(t, domain.StatusInProgress, at[0].Status)
+	require.Equal(t, domain.StatusInProgress, at[1].Status)
+	require.Nil(t, at[1].CompletedAt)
+
+	at = timeline.At(day("2026-03-05"))
+	require.Len(t, at, 2)
+	require.Equal(t, domain.StatusDone, at[1].Status)
+}
+
+func TestSnapshotJobCapturesScopes(t *testing.T) {
+	tasks := newMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, tasks.Create(ctx, &domain.Task{Owner: "anna", Status: domain.StatusTodo, Tags: domain.StringList{"ops", "db"}}))
+	require.NoError(t, tasks.Create(ctx, &domain.Task{Owner: "ivan", Status: domain.StatusDone, Tags: domain.StringList{"ops"}}))
+
+	snapshots := repository.NewMemorySnapshotStore()
+	clock := service.FixedClock{NowValue: day("2026-03-02").Add(23 * time.Hour)}
+	count, err := NewSnapshotJob(tasks, snapshots, clock, nil).Capture(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+
+	stored, err := snapshots.List(ctx, repository.SnapshotFilter{Scope: repository.SnapshotScopeTag, Key: "ops", From: "2026-03-02", To: "2026-03-02"})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, 2, stored[0].Insights.Total)
+	require.Equal(t, 1, stored[0].Insights.Done)
+
+	stored, err = snapshots.List(ctx, repository.SnapshotFilter{Scope: repository.SnapshotScopeOwner, Key: "anna", From: "2026-03-01", To: "2026-03-03"})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, 1, stored[0].Insights.Total)
+}
+
+func TestSeriesCombinesSnapshotsBackfillAndLive(t *testing.T) {
+	ctx := context.Background()
+	tasks := newMemoryStore()
+	changes := repository.NewMemoryChangeStore()
+	snapshots := repository.NewMemorySnapshotStore()
+	clock := &manualClock{now: day("2026-03-02").Add(9 * time.Hour)}
+	store := NewRecordingStore(tasks, changes, clock)
+
+	due := day("2026-03-03").Add(12 * time.Hour)
+	task := domain.Task{Owner: "anna", Status: domain.StatusTodo, DueDate: &due, CreatedAt: clock.now, UpdatedAt: clock.now}
+	require.NoError(t, store.Create(ctx, &task))
+	require.NoError(t, snapshots.Save(ctx, []repository.InsightSnapshot{
+		{Day: "2026-03-03", Scope: repository.SnapshotScopeAll, Insights: service.Insights{Total: 7}},
+	}))
+
+	clock.now = day("2026-03-05").Add(8 * time.Hour)
+	task.Status = domain.StatusDone
+	task.CompletedAt = ptr(clock.now)
+	require.NoError(t, store.Update(ctx, &task))
+	clock.now = day("2026-03-06").Add(8 * time.Hour)
+
+	series := NewSeries(tasks, changes, snapshots, clock, nil)
+	query, err := ParseQuery("2026-03-01", "2026-03-10", "", "", "", clock.now)
+	require.NoError(t, err)
+	points, err := series.Points(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, points, 6, "дни после сегодняшнего не попадают в ряд")
+
+	require.Equal(t, Point{Date: "2026-03-01", Source: SourceBackfill, Insights: service.ComputeInsights(day("2026-03-02"), nil)}, points[0])
+	require.Equal(t, SourceBackfill, points[1].Source)
+	require.Equal(t, 1, points[1].Insights.Total)
+	require.Equal(t, SourceSnapshot, points[2].Source)
+	require.Equal(t, 7, points[2].Insights.Total)
+	require.Equal(t, 1, points[3].Insights.Overdue)
+	require.Equal(t, 1, points[4].Insights.Done)
+	require.Equal(t, SourceLive, points[5].Source)
+
+	query.Interval = IntervalWeek
+	points, err = series.Points(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	require.Equal(t, "2026-02-23", points[0].Date)
+	require.Zero(t, points[0].Insights.Total, "неделю закрывает воскресенье 1 марта")
+	require.Equal(t, "2026-03-02", points[1].Date)
+	require.Equal(t, SourceLive, points[1].Source)
+
+	query, err = ParseQuery("", "", "month", "", "ops", clock.now)
+	require.NoError(t, err)
+	points, err = series.Points(ctx, query)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2026-02-01", "2026-03-01"}, []string{points[0].Date, points[1].Date})
+	require.Zero(t, points[1].Insights.Total)
+}
+
+func TestParseQuery(t *testing.T) {
+	now := day("2026-03-06").Add(8 * time.Hour)
+
+	query, err := ParseQuery("", "", "", " anna ", "", now)
+	require.NoError(t, err)
+	require.Equal(t, day("2026-03-06"), query.To)
+	require.Equal(t, day("2026-02-05"), query.From)
+	require.Equal(t, IntervalDay, query.Interval)
+	require.Equal(t, repository.SnapshotScopeOwner, query.Scope)
+	require.Equal(t, "anna", query.Key)
+
+	_, err = ParseQuery("03/01/2026", "", "year", "anna", "ops", now)
+	var list service.ValidationErrors
+	require.ErrorAs(t, err, &list)
+	codes := make([]string, 0, len(list))
+	for _, fieldErr := range list {
+		codes = append(codes, fieldErr.Code)
+	}
+	require.Equal(t, []string{CodeInvalidDate, CodeInvalidInterval, CodeConflictScope}, codes)
+
+	_, err = ParseQuery("2025-01-01", "2026-03-01", "", "", "", now)
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidRange, fieldErr.Code)
+	_, err = ParseQuery("2026-03-02", "2026-03-01", "", "", "", now)
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidRange, fieldErr.Code)
+}
+
+func ptr(value time.Time) *time.Time {
+	return &value
+}
+
+func TestBurndownMarksRemovedTasks(t *testing.T) {
+	ctx := context.Background()
+	clock := &manualClock{now: day("2026-03-01").Add(9 * time.Hour)}
+	changes := repository.NewMemoryChangeStore()
+	tasks := newMemoryStore()
+	store := NewRecordingStore(tasks, changes, clock)
+
+	first := domain.Task{Title: "One", Status: domain.StatusTodo, EffortHours: 5, Tags: domain.StringList{"ops"}, CreatedAt: clock.now}
+	second := domain.Task{Title: "Two", Status: domain.StatusTodo, EffortHours: 3, Tags: domain.StringList{"ops"}, CreatedAt: clock.now}
+	require.NoError(t, store.Create(ctx, &first))
+	require.NoError(t, store.Create(ctx, &second))
+
+	clock.now = day("2026-03-02").Add(10 * time.Hour)
+	first.Tags = domain.StringList{"web"}
+	require.NoError(t, store.Update(ctx, &first))
+	clock.now = day("2026-03-03").Add(10 * time.Hour)
+	require.NoError(t, store.Delete(ctx, second.ID))
+	clock.now = clock.now.Add(time.Hour)
+
+	burndown, err := NewBurndownBuilder(tasks, changes, clock).Build(ctx, day("2026-03-02"), day("2026-03-04"), repository.TaskFilter{Tag: "ops"})
+	require.NoError(t, err)
+	require.Equal(t, []ScopeChange{
+		{Date: "2026-03-02", TaskID: first.ID, Title: "One", Kind: ScopeRemoved, EffortHours: -5},
+		{Date: "2026-03-03", TaskID: second.ID, Title: "Two", Kind: ScopeRemoved, EffortHours: -3},
+	}, burndown.ScopeChanges)
+	require.Len(t, burndown.Points, 2)
+	require.Equal(t, 3, burndown.Points[0].RemainingHours)
+	require.Zero(t, burndown.Points[1].ScopeTasks)
+	require.Equal(t, 5.33, burndown.Ideal[0].RemainingHours)
+}
+
+func TestTransitionsFromHistoryAndTimestamps(t *testing.T) {
+	created := day("2026-03-01").Add(9 * time.Hour)
+	started := created.Add(24 * time.Hour)
+	current := []domain.Task{
+		{ID: 1, Status: domain.StatusBlocked, CreatedAt: created, StartedAt: &started, UpdatedAt: started.Add(5 * time.Hour)},
+		{ID: 2, Status: domain.StatusDone, CreatedAt: created},
+	}
+	changes := []domain.TaskChange{
+		{TaskID: 2, Type: domain.ChangeCreated, OccurredAt: created, Task: domain.Task{ID: 2, Status: domain.StatusTodo}},
+		{TaskID: 2, Type: domain.ChangeUpdated, OccurredAt: created.Add(time.Hour), Task: domain.Task{ID: 2, Status: domain.StatusTodo, Title: "renamed"}},
+		{TaskID: 2, Type: domain.ChangeUpdated, OccurredAt: created.Add(2 * time.Hour), Task: domain.Task{ID: 2, Status: domain.StatusBlocked}},
+		{TaskID: 2, Type: domain.ChangeUpdated, OccurredAt: created.Add(5 * time.Hour), Task: domain.Task{ID: 2, Status: domain.StatusInProgress}},
+		{TaskID: 2, Type: domain.ChangeUpdated, OccurredAt: created.Add(6 * time.Hour), Task: domain.Task{ID: 2, Status: domain.StatusDone}},
+	}
+	timeline := NewTimeline(current, changes)
+	now := created.Add(48 * time.Hour)
+
+	require.Equal(t, []Transition{
+		{Status: domain.StatusTodo, At: created},
+		{Status: domain.StatusInProgress, At: started},
+		{Status: domain.StatusBlocked, At: started.Add(5 * time.Hour)},
+	}, timeline.Transitions(1, now))
+	require.Equal(t, map[string]float64{
+		domain.StatusTodo:       24,
+		domain.StatusInProgress: 5,
+		domain.StatusBlocked:    19,
+	}, statusHours(timeline.Transitions(1, now), now))
+
+	recorded := timeline.Transitions(2, now)
+	require.Len(t, recorded, 4)
+	require.Equal(t, map[string]float64{
+		domain.StatusTodo:       2,
+		domain.StatusBlocked:    3,
+		domain.StatusInProgress: 1,
+	}, statusHours(recorded, now))
+	require.Len(t, timeline.Transitions(2, created.Add(3*time.Hour)), 2)
+	require.Nil(t, timeline.Transitions(9, now))
+}
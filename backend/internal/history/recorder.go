@@ -0,0 +1,97 @@
+package history
+
+import (
+	"context"
+	"log"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+// RecordingStore записывает в историю состояние задачи после каждого
+// успешного изменения. Изменения внутри WithinTransaction записываются только
+// после фиксации внешней транзакции. Сбой записи истории не отменяет изменение задачи:
+// пропущенный день восстанавливается по полям задачи.
+type RecordingStore struct {
+	store   repository.TaskStore
+	changes repository.ChangeStore
+	clock   service.Clock
+	pending *[]domain.TaskChange
+}
+
+func NewRecordingStore(store repository.TaskStore, changes repository.ChangeStore, clock service.Clock) *RecordingStore {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	return &RecordingStore{store: store, changes: changes, clock: clock}
+}
+
+func (s *RecordingStore) List(ctx context.Context, filter repository.TaskFilter) ([]domain.Task, error) {
+	return s.store.List(ctx, filter)
+}
+
+func (s *RecordingStore) Stream(ctx context.Context, filter repository.TaskFilter, order repository.TaskOrder, fn func(domain.Task) error) error {
+	return repository.StreamTasks(ctx, s.store, filter, order, fn)
+}
+
+func (s *RecordingStore) Get(ctx context.Context, id uint) (*domain.Task, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *RecordingStore) Create(ctx context.Context, task *domain.Task) error {
+	if err := s.store.Create(ctx, task); err != nil {
+		return err
+	}
+	s.record(ctx, domain.TaskChange{Type: domain.ChangeCreated, TaskID: task.ID, Task: *task})
+	return nil
+}
+
+func (s *RecordingStore) Update(ctx context.Context, task *domain.Task) error {
+	if err := s.store.Update(ctx, task); err != nil {
+		return err
+	}
+	s.record(ctx, domain.TaskChange{Type: domain.ChangeUpdated, TaskID: task.ID, Task: *task})
+	return nil
+}
+
+func (s *RecordingStore) Delete(ctx context.Context, id uint) error {
+	if err := s.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.record(ctx, domain.TaskChange{Type: domain.ChangeDeleted, TaskID: id})
+	return nil
+}
+
+func (s *RecordingStore) WithinTransaction(ctx context.Context, fn func(store repository.TaskStore) error) error {
+	var pending []domain.TaskChange
+	run := func(inner repository.TaskStore) error {
+		return fn(&RecordingStore{store: inner, changes: s.changes, clock: s.clock, pending: &pending})
+	}
+	if err := repository.WithinTransaction(ctx, s.store, run); err != nil {
+		return err
+	}
+	// Вложенная транзакция передаёт записи внешней: они пишутся только после
+	// её фиксации.
+	if s.pending != nil {
+		*s.pending = append(*s.pending, pending...)
+		return nil
+	}
+	s.append(ctx, pending)
+	return nil
+}
+
+func (s *RecordingStore) record(ctx context.Context, change domain.TaskChange) {
+	change.OccurredAt = s.clock.Now()
+	if s.pending != nil {
+		*s.pending = append(*s.pending, change)
+		return
+	}
+	s.append(ctx, []domain.TaskChange{change})
+}
+
+func (s *RecordingStore) append(ctx context.Context, changes []domain.TaskChange) {
+	if err := s.changes.Append(context.WithoutCancel(ctx), changes); err != nil {
+		log.Printf("не удалось записать историю задач: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package history
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// Источник значения точки ряда.
+const (
+	SourceSnapshot = "snapshot"
+	SourceBackfill = "backfill"
+	SourceLive     = "live"
+)
+
+const (
+	CodeInvalidDate     = "history_invalid_date"
+	CodeInvalidInterval = "history_invalid_interval"
+	CodeInvalidRange    = "history_invalid_range"
+	CodeConflictScope   = "history_conflicting_scope"
+)
+
+const (
+	// DefaultDays — длина ряда, если начало не указано.
+	DefaultDays = 30
+	// MaxDays — наибольшая длина ряда в днях.
+	MaxDays = 366
+)
+
+// Query описывает ряд: дни From..To включительно по UTC, шаг и сводку.
+type Query struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+	Scope    string
+	Key      string
+}
+
+// Point — значение сводки на конец периода, который начинается с Date.
+type Point struct {
+	Date     string           `json:"date"`
+	Source   string           `json:"source"`
+	Insights service.Insights `json:"insights"`
+}
+
+// ParseQuery проверяет параметры запроса ряда. Без to ряд заканчивается
+// сегодняшним днём, без from — охватывает DefaultDays дней.
+func ParseQuery(from, to, interval, owner, tag string, now time.Time) (Query, error) {
+	var errs service.ValidationErrors
+	query := Query{Interval: IntervalDay, Scope: repository.SnapshotScopeAll}
+	var err error
+	query.From, query.To, err = ParseRange(from, to, DefaultDays, now)
+	errs.Add(err)
+
+	if value := strings.ToLower(strings.TrimSpace(interval)); value != "" {
+		switch value {
+		case IntervalDay, IntervalWeek, IntervalMonth:
+			query.Interval = value
+		default:
+			errs.Add(service.NewFieldError(CodeInvalidInterval, "interval", map[string]any{"value": value}))
+		}
+	}
+
+	owner, tag = strings.TrimSpace(owner), strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case owner != "" && tag != "":
+		errs.Add(service.NewFieldError(CodeConflictScope, "owner", nil))
+	case owner != "":
+		query.Scope, query.Key = repository.SnapshotScopeOwner, owner
+	case tag != "":
+		query.Scope, query.Key = repository.SnapshotScopeTag, tag
+	}
+
+	if err := errs.Err(); err != nil {
+		return Query{}, err
+	}
+	return query, nil
+}
+
+// ParseRange проверяет дни from..to. Без to диапазон заканчивается
+// сегодняшним днём, без from — охватывает defaultDays дней.
+func ParseRange(from, to string, defaultDays int, now time.Time) (time.Time, time.Time, error) {
+	var errs service.ValidationErrors
+	last := startOfDay(now)
+	if value := strings.TrimSpace(to); value != "" {
+		day, err := parseDay("to", value)
+		errs.Add(err)
+		last = day
+	}
+	first := last.AddDate(0, 0, 1-defaultDays)
+	if value := strings.TrimSpace(from); value != "" {
+		day, err := parseDay("from", value)
+		errs.Add(err)
+		first = day
+	}
+	if err := errs.Err(); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if first.After(last) || last.Sub(first) >= MaxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, service.NewFieldError(CodeInvalidRange, "from", map[string]any{"max": MaxDays})
+	}
+	return first, last, nil
+}
+
+// Series строит ряд сводки. Сохранённые снимки используются как есть,
+// пропущенные дни восстанавливаются по истории изменений, а текущий день
+// всегда считается по текущему состоянию задач. Дни после текущего не
+// попадают в ряд.
+type Series struct {
+	tasks     repository.TaskStore
+	changes   repository.ChangeStore
+	snapshots repository.SnapshotStore
+	clock     service.Clock
+	policy    service.Policy
+}
+
+func NewSeries(tasks repository.TaskStore, changes repository.ChangeStore, snapshots repository.SnapshotStore, clock service.Clock, policy *service.Policy) *Series {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	return &Series{tasks: tasks, changes: changes, snapshots: snapshots, clock: clock, policy: policy.OrDefault()}
+}
+
+func (s *Series) Points(ctx context.Context, query Query) ([]Point, error) {
+	now := s.clock.Now()
+	today := startOfDay(now)
+	last := query.To
+	if last.After(today) {
+		last = today
+	}
+	points := []Point{}
+	if query.From.After(last) {
+		return points, nil
+	}
+
+	stored, err := s.snapshots.List(ctx, repository.SnapshotFilter{
+		Scope: query.Scope,
+		Key:   query.Key,
+		From:  Day(query.From),
+		To:    Day(last),
+	})
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string]service.Insights, len(stored))
+	for _, snapshot := range stored {
+		byDay[snapshot.Day] = snapshot.Insights
+	}
+
+	var timeline *Timeline
+	for day := query.From; !day.After(last); day = day.AddDate(0, 0, 1) {
+		point := Point{Date: Day(day)}
+		insights, ok := byDay[point.Date]
+		switch {
+		case day.Equal(today):
+			tasks, err := s.tasks.List(ctx, repository.TaskFilter{})
+			if err != nil {
+				return nil, err
+			}
+			point.Source = SourceLive
+			point.Insights = s.policy.Insights(now, Select(tasks, query.Scope, query.Key))
+		case ok:
+			point.Source = SourceSnapshot
+			point.Insights = insights
+		default:
+			if timeline == nil {
+				if timeline, err = LoadTimeline(ctx, s.tasks, s.changes, last.AddDate(0, 0, 1)); err != nil {
+					return nil, err
+				}
+			}
+			end := day.AddDate(0, 0, 1)
+			point.Source = SourceBackfill
+			point.Insights = s.policy.Insights(end, Select(timeline.At(end), query.Scope, query.Key))
+		}
+		points = appendPoint(points, point, query.Interval)
+	}
+	return points, nil
+}
+
+// LoadTimeline читает все задачи и записи истории до момента until.
+func LoadTimeline(ctx context.Context, tasks repository.TaskStore, changes repository.ChangeStore, until time.Time) (*Timeline, error) {
+	current, err := tasks.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := changes.List(ctx, repository.ChangeFilter{Until: until})
+	if err != nil {
+		return nil, err
+	}
+	return NewTimeline(current, recorded), nil
+}
+
+// appendPoint добавляет дневную точку в ряд: у недель и месяцев значением
+// периода становится его последний день.
+func appendPoint(points []Point, point Point, interval string) []Point {
+	day, _ := time.Parse(time.DateOnly, point.Date)
+	switch interval {
+	case IntervalWeek:
+		point.Date = Day(day.AddDate(0, 0, -(int(day.Weekday())+6)%7))
+	case IntervalMonth:
+		point.Date = Day(time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC))
+	}
+	if len(points) > 0 && points[len(points)-1].Date == point.Date {
+		points[len(points)-1] = point
+		return points
+	}
+	return append(points, point)
+}
+
+func parseDay(field string, value string) (time.Time, error) {
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, service.NewFieldError(CodeInvalidDate, field, map[string]any{"value": value})
+	}
+	return day, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
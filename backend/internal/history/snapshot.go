@@ -0,0 +1,142 @@
+package history
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+// DefaultSnapshotInterval — период снимков по умолчанию. Снимок за текущий
+// день перезаписывается, поэтому в хранилище остаётся последний за сутки.
+const DefaultSnapshotInterval = time.Hour
+
+// Day возвращает день момента t по UTC в виде YYYY-MM-DD.
+func Day(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// Select отбирает задачи сводки: все, одного владельца или с одним тегом.
+func Select(tasks []domain.Task, scope string, key string) []domain.Task {
+	if scope == repository.SnapshotScopeAll {
+		return tasks
+	}
+	selected := make([]domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if scope == repository.SnapshotScopeOwner && task.Owner == key ||
+			scope == repository.SnapshotScopeTag && hasTag(task, key) {
+			selected = append(selected, task)
+		}
+	}
+	return selected
+}
+
+// Snapshots строит снимки за день: общий, по каждому владельцу и по каждому
+// тегу.
+func Snapshots(now time.Time, tasks []domain.Task, policy service.Policy) []repository.InsightSnapshot {
+	owners := make(map[string][]domain.Task)
+	tags := make(map[string][]domain.Task)
+	for _, task := range tasks {
+		owners[task.Owner] = append(owners[task.Owner], task)
+		seen := make(map[string]bool, len(task.Tags))
+		for _, tag := range task.Tags {
+			tag = strings.ToLower(tag)
+			if !seen[tag] {
+				seen[tag] = true
+				tags[tag] = append(tags[tag], task)
+			}
+		}
+	}
+
+	day := Day(now)
+	snapshot := func(scope string, key string, tasks []domain.Task) repository.InsightSnapshot {
+		return repository.InsightSnapshot{
+			Day:        day,
+			Scope:      scope,
+			Key:        key,
+			Insights:   policy.Insights(now, tasks),
+			CapturedAt: now,
+		}
+	}
+
+	snapshots := []repository.InsightSnapshot{snapshot(repository.SnapshotScopeAll, "", tasks)}
+	for _, owner := range sortedKeys(owners) {
+		snapshots = append(snapshots, snapshot(repository.SnapshotScopeOwner, owner, owners[owner]))
+	}
+	for _, tag := range sortedKeys(tags) {
+		snapshots = append(snapshots, snapshot(repository.SnapshotScopeTag, tag, tags[tag]))
+	}
+	return snapshots
+}
+
+// SnapshotJob сохраняет снимки сводки за текущий день.
+type SnapshotJob struct {
+	tasks     repository.TaskStore
+	snapshots repository.SnapshotStore
+	clock     service.Clock
+	policy    service.Policy
+}
+
+func NewSnapshotJob(tasks repository.TaskStore, snapshots repository.SnapshotStore, clock service.Clock, policy *service.Policy) *SnapshotJob {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	return &SnapshotJob{tasks: tasks, snapshots: snapshots, clock: clock, policy: policy.OrDefault()}
+}
+
+// Capture сохраняет снимки и возвращает их количество.
+func (j *SnapshotJob) Capture(ctx context.Context) (int, error) {
+	tasks, err := j.tasks.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return 0, err
+	}
+	snapshots := Snapshots(j.clock.Now(), tasks, j.policy)
+	if err := j.snapshots.Save(ctx, snapshots); err != nil {
+		return 0, err
+	}
+	return len(snapshots), nil
+}
+
+// Run делает снимок сразу и затем каждые interval до отмены ctx. Ошибки
+// записываются в журнал и не останавливают задание.
+func (j *SnapshotJob) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.Capture(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("не удалось сохранить снимок сводки: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func hasTag(task domain.Task, tag string) bool {
+	for _, value := range task.Tags {
+		if strings.EqualFold(value, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(groups map[string][]domain.Task) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
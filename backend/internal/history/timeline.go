@@ -0,0 +1,164 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+// Timeline восстанавливает состояние задач на прошедший момент по записям
+// истории. Для задач без записей до этого момента состояние приближается по
+// текущим полям: CreatedAt, StartedAt и CompletedAt.
+type Timeline struct {
+	current map[uint]domain.Task
+	changes map[uint][]domain.TaskChange
+	ids     []uint
+}
+
+// NewTimeline ожидает записи истории в порядке изменений.
+func NewTimeline(current []domain.Task, changes []domain.TaskChange) *Timeline {
+	timeline := &Timeline{
+		current: make(map[uint]domain.Task, len(current)),
+		changes: make(map[uint][]domain.TaskChange),
+	}
+	for _, task := range current {
+		timeline.current[task.ID] = task
+		timeline.ids = append(timeline.ids, task.ID)
+	}
+	for _, change := range changes {
+		if _, ok := timeline.current[change.TaskID]; !ok && timeline.changes[change.TaskID] == nil {
+			timeline.ids = append(timeline.ids, change.TaskID)
+		}
+		timeline.changes[change.TaskID] = append(timeline.changes[change.TaskID], change)
+	}
+	sort.Slice(timeline.ids, func(i, j int) bool { return timeline.ids[i] < timeline.ids[j] })
+	return timeline
+}
+
+// At возвращает задачи, существовавшие до момента at, в их тогдашнем
+// состоянии.
+func (t *Timeline) At(at time.Time) []domain.Task {
+	tasks := make([]domain.Task, 0, len(t.ids))
+	for _, id := range t.ids {
+		if task, ok := t.taskAt(id, at); ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+func (t *Timeline) taskAt(id uint, at time.Time) (domain.Task, bool) {
+	changes := t.changes[id]
+	index := sort.Search(len(changes), func(i int) bool {
+		return !changes[i].OccurredAt.Before(at)
+	})
+	if index > 0 {
+		last := changes[index-1]
+		return last.Task, last.Type != domain.ChangeDeleted
+	}
+
+	// До момента at записей нет: берём самое раннее известное состояние.
+	base, ok := t.current[id]
+	if index < len(changes) {
+		first := changes[index]
+		switch first.Type {
+		case domain.ChangeCreated:
+			return domain.Task{}, false
+		case domain.ChangeUpdated:
+			base, ok = first.Task, true
+		}
+	}
+	if !ok {
+		return domain.Task{}, false
+	}
+	return approximate(base, at)
+}
+
+func approximate(task domain.Task, at time.Time) (domain.Task, bool) {
+	if !task.CreatedAt.IsZero() && !task.CreatedAt.Before(at) {
+		return domain.Task{}, false
+	}
+
+	switch {
+	case task.CompletedAt != nil && task.CompletedAt.Before(at):
+		task.Status = domain.StatusDone
+	case task.StartedAt != nil && task.StartedAt.Before(at):
+		task.CompletedAt = nil
+		if task.Status != domain.StatusBlocked {
+			task.Status = domain.StatusInProgress
+		}
+	default:
+		task.StartedAt = nil
+		task.CompletedAt = nil
+		task.Status = domain.StatusTodo
+	}
+	if task.UpdatedAt.After(at) {
+		task.UpdatedAt = at
+	}
+	return task, true
+}
+
+// Transition — переход задачи в статус.
+type Transition struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// Transitions возвращает переходы задачи до момента until по порядку. Без
+// записей истории переходы оцениваются по CreatedAt, StartedAt и CompletedAt,
+// а переход в текущий статус — по UpdatedAt.
+func (t *Timeline) Transitions(id uint, until time.Time) []Transition {
+	var changes []domain.TaskChange
+	for _, change := range t.changes[id] {
+		if change.OccurredAt.Before(until) {
+			changes = append(changes, change)
+		}
+	}
+
+	var transitions []Transition
+	add := func(status string, at time.Time) {
+		if len(transitions) > 0 && transitions[len(transitions)-1].Status == status {
+			return
+		}
+		transitions = append(transitions, Transition{Status: status, At: at})
+	}
+
+	if len(changes) == 0 || changes[0].Type != domain.ChangeCreated {
+		base, ok := t.current[id]
+		known := until
+		if len(changes) > 0 {
+			known = changes[0].OccurredAt
+			if changes[0].Type == domain.ChangeUpdated {
+				base, ok = changes[0].Task, true
+			}
+		}
+		if !ok {
+			return nil
+		}
+		if !base.CreatedAt.IsZero() {
+			add(domain.StatusTodo, base.CreatedAt)
+		}
+		if base.StartedAt != nil && base.StartedAt.Before(known) {
+			add(domain.StatusInProgress, *base.StartedAt)
+		}
+		if base.CompletedAt != nil && base.CompletedAt.Before(known) {
+			add(domain.StatusDone, *base.CompletedAt)
+		}
+		if len(changes) == 0 {
+			at := base.UpdatedAt
+			if len(transitions) > 0 && at.Before(transitions[len(transitions)-1].At) {
+				at = transitions[len(transitions)-1].At
+			}
+			add(base.Status, at)
+		}
+	}
+
+	for _, change := range changes {
+		if change.Type == domain.ChangeDeleted {
+			break
+		}
+		add(change.Task.Status, change.OccurredAt)
+	}
+	return transitions
+}
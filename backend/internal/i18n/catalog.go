@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LanguageRU      = "ru"
+	LanguageEN      = "en"
+	DefaultLanguage = LanguageRU
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var bundles = mustLoadBundles()
+
+func mustLoadBundles() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("не удалось прочитать каталог сообщений: %v", err))
+	}
+
+	result := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("не удалось прочитать %s: %v", entry.Name(), err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("некорректный каталог %s: %v", entry.Name(), err))
+		}
+		result[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return result
+}
+
+func Languages() []string {
+	languages := make([]string, 0, len(bundles))
+	for language := range bundles {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Translate возвращает сообщение по ключу на выбранном языке, подставляя
+// параметры вида {name}. Отсутствующий перевод берётся из языка по умолчанию,
+// а неизвестный ключ возвращается как есть.
+func Translate(language string, key string, params map[string]any) string {
+	template, ok := bundles[language][key]
+	if !ok {
+		template, ok = bundles[DefaultLanguage][key]
+	}
+	if !ok {
+		template = key
+	}
+
+	if len(params) == 0 {
+		return template
+	}
+
+	replacements := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// Negotiate выбирает поддерживаемый язык из заголовка Accept-Language
+// с учётом весов q; при отсутствии совпадений возвращается русский.
+func Negotiate(acceptLanguage string) string {
+	best := DefaultLanguage
+	bestWeight := 0.0
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			weight = parsed
+		}
+
+		base, _, _ := strings.Cut(tag, "-")
+		if _, ok := bundles[base]; !ok {
+			continue
+		}
+		if weight > bestWeight {
+			best = base
+			bestWeight = weight
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,37 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundlesHaveSameKeys(t *testing.T) {
+	require.Equal(t, []string{LanguageEN, LanguageRU}, Languages())
+
+	for key := range bundles[DefaultLanguage] {
+		require.Contains(t, bundles[LanguageEN], key)
+	}
+	for key := range bundles[LanguageEN] {
+		require.Contains(t, bundles[DefaultLanguage], key)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	require.Equal(t, "некорректный статус: weird", Translate(LanguageRU, "invalid_status", map[string]any{"value": "weird"}))
+	require.Equal(t, "invalid status: weird", Translate(LanguageEN, "invalid_status", map[string]any{"value": "weird"}))
+	require.Equal(t, "effortHours must be between 1 and 200", Translate(LanguageEN, "invalid_effort", map[string]any{"min": 1, "max": 200}))
+	require.Equal(t, "нужно указать название", Translate("de", "title_required", nil))
+	require.Equal(t, "unknown_key", Translate(LanguageEN, "unknown_key", nil))
+}
+
+func TestNegotiate(t *testing.T) {
+	require.Equal(t, LanguageRU, Negotiate(""))
+	require.Equal(t, LanguageEN, Negotiate("en"))
+	require.Equal(t, LanguageEN, Negotiate("en-US,en;q=0.9"))
+	require.Equal(t, LanguageRU, Negotiate("en;q=0.4, ru-RU;q=0.8"))
+	require.Equal(t, LanguageEN, Negotiate("de-DE, en;q=0.5"))
+	require.Equal(t, LanguageRU, Negotiate("de, fr;q=0.9"))
+	require.Equal(t, LanguageRU, Negotiate("en;q=0"))
+	require.Equal(t, LanguageRU, Negotiate("en;q=bad"))
+}
@@ -0,0 +1,100 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"devopslabs/internal/domain"
+)
+
+// gitHubIssue покрывает оба распространённых формата: ответ REST API
+// (html_url, assignee, due_on) и вывод gh issue list --json (url,
+// assignees, dueOn).
+type gitHubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"`
+	HTMLURL     string          `json:"html_url"`
+	URL         string          `json:"url"`
+	PullRequest json.RawMessage `json:"pull_request"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignee *struct {
+		Login string `json:"login"`
+	} `json:"assignee"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Milestone *struct {
+		DueOn    *string `json:"due_on"`
+		DueOnCLI *string `json:"dueOn"`
+	} `json:"milestone"`
+}
+
+var gitHubRepository = regexp.MustCompile(`github\.com/(?:repos/)?([^/]+/[^/]+)/(?:issues|pull)/\d+`)
+
+// readGitHub читает JSON-массив задач GitHub. Запросы на слияние
+// пропускаются. Закрытая задача выполнена, у открытой статус можно задать
+// меткой; приоритет берётся из меток вида P1 или priority: high, срок — из
+// вехи.
+func readGitHub(r io.Reader, mapping SourceMapping) ([]Record, error) {
+	var issues []gitHubIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, malformed(err)
+	}
+
+	records := make([]Record, 0, len(issues))
+	for i, issue := range issues {
+		if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+			continue
+		}
+
+		record := newRecord(i+1, gitHubExternalID(issue))
+		setValue(&record, FieldTitle, issue.Title)
+		setValue(&record, FieldDescription, issue.Body)
+		open := !strings.EqualFold(issue.State, "closed")
+		record.Values[FieldStatus] = domain.StatusTodo
+		if !open {
+			record.Values[FieldStatus] = domain.StatusDone
+		}
+
+		switch {
+		case issue.Assignee != nil:
+			setValue(&record, FieldOwner, issue.Assignee.Login)
+		case len(issue.Assignees) > 0:
+			setValue(&record, FieldOwner, issue.Assignees[0].Login)
+		}
+		if issue.Milestone != nil {
+			for _, due := range []*string{issue.Milestone.DueOn, issue.Milestone.DueOnCLI} {
+				if due != nil {
+					setValue(&record, FieldDueDate, *due)
+				}
+			}
+		}
+
+		labels := make([]string, 0, len(issue.Labels))
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		mapping.applyLabels(&record, labels, open)
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// gitHubExternalID строит идентификатор вида github:owner/repo#12; номер
+// задачи уникален только в пределах репозитория.
+func gitHubExternalID(issue gitHubIssue) string {
+	number := strconv.Itoa(issue.Number)
+	for _, link := range []string{issue.HTMLURL, issue.URL} {
+		if match := gitHubRepository.FindStringSubmatch(link); match != nil {
+			return SourceGitHub + ":" + match[1] + "#" + number
+		}
+	}
+	return SourceGitHub + ":#" + number
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+// DefaultBatchSize — число строк, записываемых в одной транзакции.
+const DefaultBatchSize = 100
+
+const (
+	ResultCreated = "created"
+	ResultUpdated = "updated"
+	ResultFailed  = "failed"
+)
+
+type Options struct {
+	// DryRun проверяет строки и определяет, какие задачи будут созданы или
+	// обновлены, но ничего не записывает.
+	DryRun    bool
+	BatchSize int
+	Now       time.Time
+}
+
+type RowResult struct {
+	Row        int
+	ExternalID string
+	Result     string
+	Task       *domain.Task
+	Err        error
+}
+
+type Report struct {
+	Created int
+	Updated int
+	Failed  int
+	Rows    []RowResult
+}
+
+type pendingRow struct {
+	index int
+	input service.TaskInput
+}
+
+// Run проверяет записи и сохраняет их порциями по BatchSize, каждую порцию
+// в своей транзакции. Задача с тем же внешним идентификатором обновляется,
+// остальные создаются. Статус переносится без проверки графа переходов:
+// импорт отражает состояние во внешней системе. Если порция не записалась,
+// все её строки помечаются ошибкой хранилища, а импорт продолжается.
+func Run(ctx context.Context, store repository.TaskStore, records []Record, options Options) Report {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.Now.IsZero() {
+		options.Now = time.Now().UTC()
+	}
+
+	report := Report{Rows: make([]RowResult, len(records))}
+	seen := make(map[string]int, len(records))
+	var pending []pendingRow
+	for i, record := range records {
+		externalID := record.ExternalID()
+		report.Rows[i] = RowResult{Row: record.Row, ExternalID: externalID}
+
+		input, err := Normalize(record)
+		if err == nil && externalID != "" {
+			if first, ok := seen[externalID]; ok {
+				err = service.NewFieldError(CodeDuplicateExternal, string(FieldExternalID), map[string]any{"value": externalID, "row": first})
+			} else {
+				seen[externalID] = record.Row
+			}
+		}
+		if err != nil {
+			report.Rows[i].Result = ResultFailed
+			report.Rows[i].Err = err
+			continue
+		}
+		pending = append(pending, pendingRow{index: i, input: input})
+	}
+
+	for start := 0; start < len(pending); start += options.BatchSize {
+		batch := pending[start:min(start+options.BatchSize, len(pending))]
+		results := make([]RowResult, len(batch))
+		for i, row := range batch {
+			results[i] = report.Rows[row.index]
+		}
+
+		var err error
+		if options.DryRun {
+			err = importBatch(ctx, store, batch, results, options)
+		} else {
+			err = repository.WithinTransaction(ctx, store, func(tx repository.TaskStore) error {
+				return importBatch(ctx, tx, batch, results, options)
+			})
+		}
+		for i, row := range batch {
+			if err != nil {
+				results[i].Result = ResultFailed
+				results[i].Task = nil
+				results[i].Err = err
+			}
+			report.Rows[row.index] = results[i]
+		}
+	}
+
+	for _, row := range report.Rows {
+		switch row.Result {
+		case ResultCreated:
+			report.Created++
+		case ResultUpdated:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+	}
+	return report
+}
+
+func importBatch(ctx context.Context, store repository.TaskStore, batch []pendingRow, results []RowResult, options Options) error {
+	var externalIDs []string
+	for i := range batch {
+		if id := results[i].ExternalID; id != "" {
+			externalIDs = append(externalIDs, id)
+		}
+	}
+	existing := map[string]domain.Task{}
+	if len(externalIDs) > 0 {
+		tasks, err := store.List(ctx, repository.TaskFilter{ExternalIDs: externalIDs})
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			existing[task.ExternalID] = task
+		}
+	}
+
+	for i, row := range batch {
+		task, update := existing[results[i].ExternalID]
+		if !update {
+			task = domain.Task{ExternalID: results[i].ExternalID}
+		}
+		if err := applyRow(&task, row.input, options); err != nil {
+			return err
+		}
+
+		if !options.DryRun {
+			var err error
+			if update {
+				err = store.Update(ctx, &task)
+			} else {
+				update, err = create(ctx, store, &task, row.input, options)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		results[i].Task = &task
+		results[i].Result = ResultCreated
+		if update {
+			results[i].Result = ResultUpdated
+		}
+	}
+	return nil
+}
+
+func applyRow(task *domain.Task, input service.TaskInput, options Options) error {
+	input.ApplyTo(task)
+	return service.ApplyStatusTransition(options.Now, task, input.Status, true)
+}
+
+// create вставляет задачу в точке сохранения. Если параллельный импорт уже
+// создал задачу с тем же ExternalID, уникальный индекс отклоняет вставку с
+// ErrConflict, и строка обновляет найденную задачу; update сообщает об этом.
+func create(ctx context.Context, store repository.TaskStore, task *domain.Task, input service.TaskInput, options Options) (update bool, err error) {
+	err = repository.WithinTransaction(ctx, store, func(tx repository.TaskStore) error {
+		return tx.Create(ctx, task)
+	})
+	if task.ExternalID == "" || !errors.Is(err, repository.ErrConflict) {
+		return false, err
+	}
+
+	tasks, err := store.List(ctx, repository.TaskFilter{ExternalIDs: []string{task.ExternalID}})
+	if err != nil {
+		return false, err
+	}
+	if len(tasks) == 0 {
+		return false, repository.ErrConflict
+	}
+	current := tasks[0]
+	if err := applyRow(&current, input, options); err != nil {
+		return false, err
+	}
+	if err := store.Update(ctx, &current); err != nil {
+		return false, err
+	}
+	*task = current
+	return true, nil
+}
@@ -0,0 +1,168 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	tasks        []domain.Task
+	failCreateOn string
+	// concurrent — задача, которую «параллельный» импорт вставляет сразу
+	// после первого поиска по ExternalID.
+	concurrent   *domain.Task
+	transactions int
+	depth        int
+}
+
+func (s *fakeStore) List(_ context.Context, filter repository.TaskFilter) ([]domain.Task, error) {
+	var result []domain.Task
+	for _, task := range s.tasks {
+		if filter.Matches(task) {
+			result = append(result, task)
+		}
+	}
+	if s.concurrent != nil {
+		s.concurrent.ID = uint(len(s.tasks) + 1)
+		s.tasks = append(s.tasks, *s.concurrent)
+		s.concurrent = nil
+	}
+	return result, nil
+}
+
+func (s *fakeStore) Get(context.Context, uint) (*domain.Task, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (s *fakeStore) Create(_ context.Context, task *domain.Task) error {
+	if task.Title == s.failCreateOn {
+		return errors.New("запись отклонена")
+	}
+	if task.ExternalID != "" && slices.ContainsFunc(s.tasks, func(existing domain.Task) bool { return existing.ExternalID == task.ExternalID }) {
+		return repository.ErrConflict
+	}
+	task.ID = uint(len(s.tasks) + 1)
+	s.tasks = append(s.tasks, *task)
+	return nil
+}
+
+func (s *fakeStore) Update(_ context.Context, task *domain.Task) error {
+	index := slices.IndexFunc(s.tasks, func(existing domain.Task) bool { return existing.ID == task.ID })
+	if index < 0 {
+		return repository.ErrNotFound
+	}
+	s.tasks[index] = *task
+	return nil
+}
+
+func (s *fakeStore) Delete(context.Context, uint) error {
+	return nil
+}
+
+func (s *fakeStore) WithinTransaction(_ context.Context, fn func(repository.TaskStore) error) error {
+	if s.depth == 0 {
+		s.transactions++
+	}
+	s.depth++
+	defer func() { s.depth-- }()
+	snapshot := slices.Clone(s.tasks)
+	if err := fn(s); err != nil {
+		s.tasks = snapshot
+		return err
+	}
+	return nil
+}
+
+func records(rows ...map[Field]string) []Record {
+	result := make([]Record, 0, len(rows))
+	for i, values := range rows {
+		result = append(result, Record{Row: i + 2, Values: values})
+	}
+	return result
+}
+
+func TestRunUpsertsByExternalIDInBatches(t *testing.T) {
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{tasks: []domain.Task{{ID: 1, ExternalID: "OPS-1", Title: "Old", Status: domain.StatusTodo, Priority: domain.PriorityLow}}}
+
+	report := Run(context.Background(), store, records(
+		map[Field]string{FieldExternalID: "OPS-1", FieldTitle: "Ship CI", FieldStatus: "done"},
+		map[Field]string{FieldExternalID: "OPS-2", FieldTitle: "Rotate keys"},
+		map[Field]string{FieldExternalID: "OPS-2", FieldTitle: "Duplicate"},
+		map[Field]string{FieldTitle: "", FieldPriority: "urgent"},
+		map[Field]string{FieldTitle: "No key"},
+	), Options{BatchSize: 2, Now: now})
+
+	require.Equal(t, 1, report.Updated)
+	require.Equal(t, 2, report.Created)
+	require.Equal(t, 2, report.Failed)
+	require.Equal(t, 2, store.transactions)
+
+	require.Equal(t, ResultUpdated, report.Rows[0].Result)
+	require.Equal(t, uint(1), report.Rows[0].Task.ID)
+	require.Equal(t, domain.StatusDone, store.tasks[0].Status)
+	require.Equal(t, now, *store.tasks[0].CompletedAt)
+	require.Equal(t, "OPS-2", store.tasks[1].ExternalID)
+	require.ErrorContains(t, report.Rows[2].Err, "OPS-2")
+	require.Equal(t, ResultFailed, report.Rows[3].Result)
+	require.Equal(t, ResultCreated, report.Rows[4].Result)
+}
+
+func TestRunUpdatesTaskCreatedByConcurrentImport(t *testing.T) {
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{concurrent: &domain.Task{ExternalID: "OPS-7", Title: "Parallel", Status: domain.StatusTodo, Priority: domain.PriorityLow}}
+
+	report := Run(context.Background(), store, records(
+		map[Field]string{FieldExternalID: "OPS-7", FieldTitle: "Ship CI", FieldStatus: "done"},
+		map[Field]string{FieldTitle: "Other"},
+	), Options{BatchSize: 2, Now: now})
+
+	require.Equal(t, 1, report.Updated)
+	require.Equal(t, 1, report.Created)
+	require.Equal(t, 0, report.Failed)
+	require.Equal(t, ResultUpdated, report.Rows[0].Result)
+	require.Len(t, store.tasks, 2)
+	require.Equal(t, "Ship CI", store.tasks[0].Title)
+	require.Equal(t, domain.StatusDone, store.tasks[0].Status)
+	require.Equal(t, store.tasks[0].ID, report.Rows[0].Task.ID)
+}
+
+func TestRunDryRunWritesNothing(t *testing.T) {
+	store := &fakeStore{tasks: []domain.Task{{ID: 1, ExternalID: "OPS-1", Title: "Old", Status: domain.StatusTodo}}}
+
+	report := Run(context.Background(), store, records(
+		map[Field]string{FieldExternalID: "OPS-1", FieldTitle: "Renamed"},
+		map[Field]string{FieldExternalID: "OPS-9", FieldTitle: "New"},
+	), Options{DryRun: true})
+
+	require.Equal(t, 1, report.Updated)
+	require.Equal(t, 1, report.Created)
+	require.Equal(t, 0, store.transactions)
+	require.Len(t, store.tasks, 1)
+	require.Equal(t, "Old", store.tasks[0].Title)
+	require.Equal(t, "Renamed", report.Rows[0].Task.Title)
+}
+
+func TestRunRollsBackFailedBatch(t *testing.T) {
+	store := &fakeStore{failCreateOn: "Boom"}
+
+	report := Run(context.Background(), store, records(
+		map[Field]string{FieldTitle: "First"},
+		map[Field]string{FieldTitle: "Boom"},
+		map[Field]string{FieldTitle: "Next batch"},
+	), Options{BatchSize: 2})
+
+	require.Equal(t, 1, report.Created)
+	require.Equal(t, 2, report.Failed)
+	require.Nil(t, report.Rows[0].Task)
+	require.Equal(t, ResultFailed, report.Rows[0].Result)
+	require.Len(t, store.tasks, 1)
+	require.Equal(t, "Next batch", store.tasks[0].Title)
+}
@@ -0,0 +1,125 @@
+package importer
+
+import (
+	"encoding/xml"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// jiraDateLayouts — форматы дат выгрузок Jira: RSS-выгрузка XML использует
+// RFC 1123, CSV — формат интерфейса с настройками по умолчанию.
+var jiraDateLayouts = []string{time.RFC1123Z, time.RFC1123, "02/Jan/06 3:04 PM", "02/Jan/06", time.RFC3339, time.DateOnly}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+type jiraRSS struct {
+	Items []jiraItem `xml:"channel>item"`
+}
+
+type jiraItem struct {
+	Key         string `xml:"key"`
+	Summary     string `xml:"summary"`
+	Description string `xml:"description"`
+	Status      string `xml:"status"`
+	Priority    string `xml:"priority"`
+	Assignee    struct {
+		Username string `xml:"username,attr"`
+		Name     string `xml:",chardata"`
+	} `xml:"assignee"`
+	Labels   []string `xml:"labels>label"`
+	Due      string   `xml:"due"`
+	Estimate struct {
+		Seconds string `xml:"seconds,attr"`
+	} `xml:"timeoriginalestimate"`
+}
+
+func readJiraXML(r io.Reader) ([]Record, error) {
+	var rss jiraRSS
+	if err := xml.NewDecoder(r).Decode(&rss); err != nil {
+		return nil, malformed(err)
+	}
+
+	records := make([]Record, 0, len(rss.Items))
+	for i, item := range rss.Items {
+		record := newRecord(i+1, jiraExternalID(item.Key))
+		setValue(&record, FieldTitle, item.Summary)
+		setValue(&record, FieldDescription, plainText(item.Description))
+		setValue(&record, FieldStatus, item.Status)
+		setValue(&record, FieldPriority, item.Priority)
+		setValue(&record, FieldOwner, jiraAssignee(item))
+		setValue(&record, FieldEffortHours, secondsToHours(item.Estimate.Seconds))
+		if item.Due != "" {
+			setValue(&record, FieldDueDate, normalizeDate(item.Due, jiraDateLayouts...))
+		}
+		record.Tags = append(record.Tags, item.Labels...)
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readJiraCSV читает CSV-выгрузку Jira. Оценка Original Estimate задана в
+// секундах и переводится в часы, если сопоставление не назначило трудоёмкости
+// другую колонку; каждая метка занимает отдельную колонку Labels.
+func readJiraCSV(r io.Reader, mapping SourceMapping) ([]Record, []string, error) {
+	columns, err := mapping.columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	estimateInSeconds := true
+	for _, field := range columns {
+		if field == FieldEffortHours {
+			estimateInSeconds = false
+		}
+	}
+	if estimateInSeconds {
+		columns[normalizeHeader("Original Estimate")] = FieldEffortHours
+	}
+
+	records, ignored, err := parseCSV(r, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range records {
+		record := &records[i]
+		record.Values[FieldExternalID] = jiraExternalID(record.ExternalID())
+		if value, ok := record.Values[FieldEffortHours]; ok && estimateInSeconds {
+			record.Values[FieldEffortHours] = secondsToHours(value)
+		}
+		if value, ok := record.Values[FieldDueDate]; ok && strings.TrimSpace(value) != "" {
+			record.Values[FieldDueDate] = normalizeDate(value, jiraDateLayouts...)
+		}
+	}
+	return records, ignored, nil
+}
+
+// jiraAssignee предпочитает имя пользователя отображаемому имени: оно
+// стабильнее. Незанятые задачи Jira выгружает с username="-1".
+func jiraAssignee(item jiraItem) string {
+	if item.Assignee.Username == "-1" {
+		return ""
+	}
+	if item.Assignee.Username != "" {
+		return item.Assignee.Username
+	}
+	if strings.EqualFold(strings.TrimSpace(item.Assignee.Name), "Unassigned") {
+		return ""
+	}
+	return item.Assignee.Name
+}
+
+func jiraExternalID(key string) string {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return ""
+	}
+	return SourceJira + ":" + key
+}
+
+// plainText убирает HTML-разметку из описаний Jira.
+func plainText(value string) string {
+	text := htmlTags.ReplaceAllString(value, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
@@ -0,0 +1,232 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+)
+
+const CodeInvalidMappingFile = "import_invalid_mapping_file"
+
+// SourceMapping — файл сопоставления значений внешнего трекера значениям
+// FlowBoard. Ключи сравниваются без учёта регистра и дополняют встроенные
+// сопоставления источника.
+//
+//	{
+//	  "statuses":   {"In Review": "in_progress", "Won't Do": "done"},
+//	  "priorities": {"P1": "high"},
+//	  "users":      {"alice.smith": "alice"},
+//	  "labels":     {"bug": "defect", "duplicate": ""},
+//	  "columns":    {"Story Points": "effortHours"}
+//	}
+//
+// Пустое значение в labels отбрасывает метку, columns задаёт колонки CSV
+// так же, как параметр map.
+type SourceMapping struct {
+	Statuses   map[string]string `json:"statuses"`
+	Priorities map[string]string `json:"priorities"`
+	Users      map[string]string `json:"users"`
+	Labels     map[string]string `json:"labels"`
+	Columns    map[string]string `json:"columns"`
+}
+
+// defaultStatuses и defaultPriorities покрывают названия, принятые в Jira,
+// Trello и GitHub по умолчанию.
+var defaultStatuses = map[string]string{
+	"to do":                    domain.StatusTodo,
+	"todo":                     domain.StatusTodo,
+	"open":                     domain.StatusTodo,
+	"new":                      domain.StatusTodo,
+	"backlog":                  domain.StatusTodo,
+	"selected for development": domain.StatusTodo,
+	"reopened":                 domain.StatusTodo,
+	"in progress":              domain.StatusInProgress,
+	"doing":                    domain.StatusInProgress,
+	"in review":                domain.StatusInProgress,
+	"review":                   domain.StatusInProgress,
+	"blocked":                  domain.StatusBlocked,
+	"on hold":                  domain.StatusBlocked,
+	"waiting":                  domain.StatusBlocked,
+	"done":                     domain.StatusDone,
+	"closed":                   domain.StatusDone,
+	"resolved":                 domain.StatusDone,
+	"complete":                 domain.StatusDone,
+	"completed":                domain.StatusDone,
+}
+
+var defaultPriorities = map[string]string{
+	"blocker":  domain.PriorityCritical,
+	"highest":  domain.PriorityCritical,
+	"critical": domain.PriorityCritical,
+	"urgent":   domain.PriorityCritical,
+	"p0":       domain.PriorityCritical,
+	"high":     domain.PriorityHigh,
+	"major":    domain.PriorityHigh,
+	"p1":       domain.PriorityHigh,
+	"medium":   domain.PriorityMedium,
+	"normal":   domain.PriorityMedium,
+	"p2":       domain.PriorityMedium,
+	"low":      domain.PriorityLow,
+	"lowest":   domain.PriorityLow,
+	"minor":    domain.PriorityLow,
+	"trivial":  domain.PriorityLow,
+	"p3":       domain.PriorityLow,
+}
+
+// LoadMapping читает файл сопоставления и проверяет, что статусы и
+// приоритеты переводятся в допустимые значения.
+func LoadMapping(r io.Reader) (SourceMapping, error) {
+	var mapping SourceMapping
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&mapping); err != nil {
+		return SourceMapping{}, service.NewFieldError(CodeInvalidMappingFile, "mapping", map[string]any{"reason": err.Error()})
+	}
+
+	var errs service.ValidationErrors
+	for source, target := range mapping.Statuses {
+		if !domain.AllowedStatuses[target] {
+			errs.Add(service.NewFieldError(CodeInvalidMappingFile, "mapping.statuses."+source, map[string]any{"reason": "unknown status " + target}))
+		}
+	}
+	for source, target := range mapping.Priorities {
+		if !domain.AllowedPriorities[target] {
+			errs.Add(service.NewFieldError(CodeInvalidMappingFile, "mapping.priorities."+source, map[string]any{"reason": "unknown priority " + target}))
+		}
+	}
+	if _, err := mapping.columns(); err != nil {
+		errs.Add(err)
+	}
+	if err := errs.Err(); err != nil {
+		return SourceMapping{}, err
+	}
+	return mapping, nil
+}
+
+// WithColumns возвращает копию сопоставления, дополненную колонками из
+// параметра map; они важнее колонок файла сопоставления.
+func (m SourceMapping) WithColumns(columns Mapping) SourceMapping {
+	merged := make(map[string]string, len(m.Columns)+len(columns))
+	for source, target := range m.Columns {
+		merged[normalizeHeader(source)] = target
+	}
+	for source, field := range columns {
+		merged[source] = string(field)
+	}
+	m.Columns = merged
+	return m
+}
+
+func (m SourceMapping) columns() (Mapping, error) {
+	columns := Mapping{}
+	for source, target := range m.Columns {
+		field, ok := lookupField(target)
+		if !ok {
+			return nil, service.NewFieldError(CodeUnknownTarget, "mapping.columns", map[string]any{"value": target, "available": fieldNames()})
+		}
+		columns[normalizeHeader(source)] = field
+	}
+	return columns, nil
+}
+
+func (m SourceMapping) status(value string) (string, bool) {
+	return lookup(m.Statuses, defaultStatuses, value)
+}
+
+// priority распознаёт и метки вида «priority: high».
+func (m SourceMapping) priority(value string) (string, bool) {
+	if mapped, ok := lookup(m.Priorities, defaultPriorities, value); ok {
+		return mapped, true
+	}
+	if _, rest, ok := strings.Cut(value, ":"); ok {
+		return lookup(m.Priorities, defaultPriorities, rest)
+	}
+	return "", false
+}
+
+func (m SourceMapping) user(value string) string {
+	if mapped, ok := lookup(m.Users, nil, value); ok {
+		return mapped
+	}
+	return strings.TrimSpace(value)
+}
+
+// label возвращает новое имя метки; false означает, что метку нужно
+// отбросить.
+func (m SourceMapping) label(value string) (string, bool) {
+	if mapped, ok := lookup(m.Labels, nil, value); ok {
+		return mapped, mapped != ""
+	}
+	value = strings.TrimSpace(value)
+	return value, value != ""
+}
+
+// translate переводит значения записи по сопоставлению. Встроенные
+// сопоставления трекеров применяются только к их выгрузкам: файл FlowBoard
+// должен содержать допустимые значения. Непереведённые статусы и приоритеты
+// остаются как есть, чтобы Normalize сообщил о них.
+func (m SourceMapping) translate(record *Record, withDefaults bool) {
+	statuses, priorities := defaultStatuses, defaultPriorities
+	if !withDefaults {
+		statuses, priorities = nil, nil
+	}
+	if value, ok := record.Values[FieldStatus]; ok {
+		if mapped, ok := lookup(m.Statuses, statuses, value); ok {
+			record.Values[FieldStatus] = mapped
+		}
+	}
+	if value, ok := record.Values[FieldPriority]; ok {
+		if mapped, ok := lookup(m.Priorities, priorities, value); ok {
+			record.Values[FieldPriority] = mapped
+		}
+	}
+	if value, ok := record.Values[FieldOwner]; ok {
+		record.Values[FieldOwner] = m.user(value)
+	}
+
+	tags := make([]string, 0, len(record.Tags))
+	for _, tag := range record.Tags {
+		if mapped, ok := m.label(tag); ok {
+			tags = append(tags, mapped)
+		}
+	}
+	record.Tags = tags
+}
+
+// applyLabels разбирает метки трекеров без отдельного поля приоритета:
+// метка-приоритет задаёт приоритет, метка-статус — статус открытой задачи,
+// остальные становятся тегами.
+func (m SourceMapping) applyLabels(record *Record, labels []string, statusFromLabels bool) {
+	for _, label := range labels {
+		if priority, ok := m.priority(label); ok {
+			if _, set := record.Values[FieldPriority]; !set {
+				record.Values[FieldPriority] = priority
+			}
+			continue
+		}
+		if statusFromLabels {
+			if status, ok := m.status(label); ok {
+				record.Values[FieldStatus] = status
+				continue
+			}
+		}
+		record.Tags = append(record.Tags, label)
+	}
+}
+
+func lookup(custom map[string]string, defaults map[string]string, value string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(value))
+	if key == "" {
+		return "", false
+	}
+	for source, target := range custom {
+		if strings.ToLower(strings.TrimSpace(source)) == key {
+			return target, true
+		}
+	}
+	target, ok := defaults[key]
+	return target, ok
+}
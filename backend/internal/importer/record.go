@@ -0,0 +1,362 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// MaxRows — предельное число строк в одном импорте.
+const MaxRows = 5000
+
+const (
+	CodeInvalidFormat      = "import_invalid_format"
+	CodeMalformed          = "import_malformed"
+	CodeEmpty              = "import_empty"
+	CodeTooManyRows        = "import_too_many_rows"
+	CodeUnknownTarget      = "import_unknown_target"
+	CodeDuplicateExternal  = "import_duplicate_external_id"
+	CodeExternalIDTooLong  = "import_external_id_too_long"
+	CodeInvalidMappingPair = "import_invalid_mapping"
+)
+
+// Field — поле задачи, в которое попадает колонка файла.
+type Field string
+
+const (
+	FieldExternalID  Field = "externalId"
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldStatus      Field = "status"
+	FieldPriority    Field = "priority"
+	FieldOwner       Field = "owner"
+	FieldEffortHours Field = "effortHours"
+	FieldDueDate     Field = "dueDate"
+	FieldTags        Field = "tags"
+)
+
+var Fields = []Field{FieldExternalID, FieldTitle, FieldDescription, FieldStatus, FieldPriority, FieldOwner, FieldEffortHours, FieldDueDate, FieldTags}
+
+// aliases сопоставляет распространённые заголовки таблиц полям задачи.
+// Заголовки сравниваются без учёта регистра, пробелов, дефисов и
+// подчёркиваний.
+var aliases = map[string]Field{
+	"externalid":  FieldExternalID,
+	"key":         FieldExternalID,
+	"issuekey":    FieldExternalID,
+	"title":       FieldTitle,
+	"name":        FieldTitle,
+	"summary":     FieldTitle,
+	"description": FieldDescription,
+	"details":     FieldDescription,
+	"status":      FieldStatus,
+	"state":       FieldStatus,
+	"priority":    FieldPriority,
+	"owner":       FieldOwner,
+	"assignee":    FieldOwner,
+	"effort":      FieldEffortHours,
+	"efforthours": FieldEffortHours,
+	"estimate":    FieldEffortHours,
+	"due":         FieldDueDate,
+	"duedate":     FieldDueDate,
+	"deadline":    FieldDueDate,
+	"tags":        FieldTags,
+	"labels":      FieldTags,
+}
+
+// Record — строка импорта до проверки. Row — номер строки в файле для
+// CSV (с учётом заголовка) или позиция элемента массива с единицы для JSON.
+type Record struct {
+	Row    int
+	Values map[Field]string
+	Tags   []string
+}
+
+func (r Record) ExternalID() string {
+	return strings.TrimSpace(r.Values[FieldExternalID])
+}
+
+// Mapping задаёт явное сопоставление заголовков полям и дополняет
+// встроенные синонимы.
+type Mapping map[string]Field
+
+// ParseMapping разбирает пары «заголовок:поле» через запятую, например
+// "Summary:title,Story Points:effortHours".
+func ParseMapping(raw string) (Mapping, error) {
+	mapping := Mapping{}
+	if strings.TrimSpace(raw) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		source, target, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(source) == "" {
+			return nil, service.NewFieldError(CodeInvalidMappingPair, "map", map[string]any{"value": strings.TrimSpace(pair)})
+		}
+		field, ok := lookupField(target)
+		if !ok {
+			return nil, service.NewFieldError(CodeUnknownTarget, "map", map[string]any{"value": strings.TrimSpace(target), "available": fieldNames()})
+		}
+		mapping[normalizeHeader(source)] = field
+	}
+	return mapping, nil
+}
+
+// ParseFormat разбирает формат импорта; без явного значения он
+// определяется по Content-Type.
+func ParseFormat(raw string, contentType string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(raw))
+	if format == "" {
+		if strings.Contains(strings.ToLower(contentType), "json") {
+			return FormatJSON, nil
+		}
+		return FormatCSV, nil
+	}
+	if format != FormatCSV && format != FormatJSON {
+		return "", service.NewFieldError(CodeInvalidFormat, "format", map[string]any{"value": format})
+	}
+	return format, nil
+}
+
+// Parse читает записи в формате format. Колонки, которые не удалось
+// сопоставить полям, возвращаются отдельно и не импортируются.
+func Parse(format string, r io.Reader, mapping Mapping) ([]Record, []string, error) {
+	if format == FormatJSON {
+		records, err := parseJSON(r)
+		return records, nil, err
+	}
+	return parseCSV(r, mapping)
+}
+
+func parseCSV(r io.Reader, mapping Mapping) ([]Record, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Табличные редакторы часто сохраняют CSV с BOM.
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, service.NewFieldError(CodeEmpty, "body", nil)
+	}
+	if err != nil {
+		return nil, nil, malformed(err)
+	}
+
+	columns := make([]Field, len(header))
+	var ignored []string
+	for i, name := range header {
+		key := normalizeHeader(name)
+		field, ok := mapping[key]
+		if !ok {
+			field, ok = aliases[key]
+		}
+		if !ok {
+			ignored = append(ignored, strings.TrimSpace(name))
+			continue
+		}
+		columns[i] = field
+	}
+
+	var records []Record
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, malformed(err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(values) {
+			continue
+		}
+		if len(records) == MaxRows {
+			return nil, nil, service.NewFieldError(CodeTooManyRows, "body", map[string]any{"max": MaxRows})
+		}
+
+		record := Record{Row: line, Values: map[Field]string{}}
+		for i, value := range values {
+			if i >= len(columns) || columns[i] == "" {
+				continue
+			}
+			if columns[i] == FieldTags {
+				record.Tags = append(record.Tags, splitTags(value)...)
+				continue
+			}
+			record.Values[columns[i]] = value
+		}
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		return nil, nil, service.NewFieldError(CodeEmpty, "body", nil)
+	}
+	return records, ignored, nil
+}
+
+func parseJSON(r io.Reader) ([]Record, error) {
+	var items []map[string]any
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&items); err != nil {
+		return nil, malformed(err)
+	}
+	if len(items) == 0 {
+		return nil, service.NewFieldError(CodeEmpty, "body", nil)
+	}
+	if len(items) > MaxRows {
+		return nil, service.NewFieldError(CodeTooManyRows, "body", map[string]any{"max": MaxRows})
+	}
+
+	records := make([]Record, 0, len(items))
+	for i, item := range items {
+		record := Record{Row: i + 1, Values: map[Field]string{}}
+		for key, raw := range item {
+			field, ok := lookupField(key)
+			if !ok || raw == nil {
+				continue
+			}
+			if field == FieldTags {
+				record.Tags = jsonTags(raw)
+				continue
+			}
+			record.Values[field] = jsonString(raw)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Normalize проверяет запись так же, как POST /api/tasks, и собирает
+// ошибки всех полей сразу.
+func Normalize(record Record) (service.TaskInput, error) {
+	var errs service.ValidationErrors
+	if len(record.ExternalID()) > domain.MaxExternalIDLength {
+		errs.Add(service.NewFieldError(CodeExternalIDTooLong, string(FieldExternalID), map[string]any{"max": domain.MaxExternalIDLength}))
+	}
+
+	effort := 0
+	if raw := strings.TrimSpace(record.Values[FieldEffortHours]); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value != float64(int(value)) {
+			errs.Add(service.NewFieldError(service.CodeInvalidEffort, string(FieldEffortHours), service.EffortRange()))
+		} else {
+			effort = int(value)
+		}
+	}
+
+	dueDate, err := parseDate(record.Values[FieldDueDate])
+	errs.Add(err)
+
+	input, err := service.NormalizeTaskInput(service.TaskInput{
+		Title:       record.Values[FieldTitle],
+		Description: record.Values[FieldDescription],
+		Status:      record.Values[FieldStatus],
+		Priority:    record.Values[FieldPriority],
+		Owner:       record.Values[FieldOwner],
+		EffortHours: effort,
+		DueDate:     dueDate,
+		Tags:        record.Tags,
+	})
+	errs.Add(err)
+	return input, errs.Err()
+}
+
+// parseDate принимает RFC3339 и календарную дату; дата без времени
+// означает конец дня по UTC.
+func parseDate(raw string) (*time.Time, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		endOfDay := parsed.Add(24*time.Hour - time.Second)
+		return &endOfDay, nil
+	}
+	return nil, service.NewFieldError(service.CodeInvalidDueDate, string(FieldDueDate), nil)
+}
+
+func lookupField(name string) (Field, bool) {
+	field, ok := aliases[normalizeHeader(name)]
+	return field, ok
+}
+
+func normalizeHeader(name string) string {
+	replacer := strings.NewReplacer(" ", "", "_", "", "-", "")
+	return strings.ToLower(replacer.Replace(strings.TrimSpace(name)))
+}
+
+func fieldNames() string {
+	names := make([]string, 0, len(Fields))
+	for _, field := range Fields {
+		names = append(names, string(field))
+	}
+	return strings.Join(names, ", ")
+}
+
+func splitTags(raw string) []string {
+	return strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	})
+}
+
+func jsonTags(raw any) []string {
+	switch typed := raw.(type) {
+	case []any:
+		tags := make([]string, 0, len(typed))
+		for _, tag := range typed {
+			tags = append(tags, jsonString(tag))
+		}
+		return tags
+	default:
+		return splitTags(jsonString(raw))
+	}
+}
+
+func jsonString(raw any) string {
+	switch typed := raw.(type) {
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		encoded, _ := json.Marshal(typed)
+		return string(encoded)
+	}
+}
+
+func isBlank(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func malformed(err error) error {
+	return service.NewFieldError(CodeMalformed, "body", map[string]any{"reason": fmt.Sprint(err)})
+}
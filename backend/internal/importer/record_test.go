@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSVMapsHeadersAndAliases(t *testing.T) {
+	mapping, err := ParseMapping("Story Points:effortHours")
+	require.NoError(t, err)
+
+	input := "\uFEFFIssue Key,Summary,Assignee,Labels,Story Points,Sprint\n" +
+		"OPS-1,Ship CI,alice,\"ci;release\",3,Sprint 4\n" +
+		",,,,,\n" +
+		"OPS-2,\"Rotate, keys\",bob,,,\n"
+	records, ignored, err := Parse(FormatCSV, strings.NewReader(input), mapping)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Sprint"}, ignored)
+	require.Len(t, records, 2)
+
+	require.Equal(t, 2, records[0].Row)
+	require.Equal(t, "OPS-1", records[0].ExternalID())
+	require.Equal(t, "3", records[0].Values[FieldEffortHours])
+	require.Equal(t, []string{"ci", "release"}, records[0].Tags)
+	require.Equal(t, 4, records[1].Row)
+	require.Equal(t, "Rotate, keys", records[1].Values[FieldTitle])
+}
+
+func TestParseJSONAndFileErrors(t *testing.T) {
+	records, _, err := Parse(FormatJSON, strings.NewReader(`[{"externalId":"A-1","title":"Ship","effortHours":5,"tags":["ci"],"unknown":true}]`), nil)
+	require.NoError(t, err)
+	require.Equal(t, "5", records[0].Values[FieldEffortHours])
+	require.Equal(t, []string{"ci"}, records[0].Tags)
+
+	for _, tc := range []struct {
+		format string
+		input  string
+		code   string
+	}{
+		{FormatJSON, `{"title":"x"}`, CodeMalformed},
+		{FormatJSON, `[]`, CodeEmpty},
+		{FormatCSV, "", CodeEmpty},
+		{FormatCSV, "title\n\"broken", CodeMalformed},
+	} {
+		_, _, err := Parse(tc.format, strings.NewReader(tc.input), nil)
+		var fieldErr *service.FieldError
+		require.ErrorAs(t, err, &fieldErr, tc.input)
+		require.Equal(t, tc.code, fieldErr.Code, tc.input)
+	}
+
+	_, err = ParseMapping("Summary:headline")
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeUnknownTarget, fieldErr.Code)
+
+	format, err := ParseFormat("", "application/json; charset=utf-8")
+	require.NoError(t, err)
+	require.Equal(t, FormatJSON, format)
+}
+
+func TestNormalizeCollectsAllFieldErrors(t *testing.T) {
+	input, err := Normalize(Record{Values: map[Field]string{
+		FieldTitle:       " Ship CI ",
+		FieldStatus:      "In_Progress",
+		FieldEffortHours: "4",
+		FieldDueDate:     "2026-02-10",
+	}, Tags: []string{"CI", "ci"}})
+	require.NoError(t, err)
+	require.Equal(t, "Ship CI", input.Title)
+	require.Equal(t, domain.StatusInProgress, input.Status)
+	require.Equal(t, 4, input.EffortHours)
+	require.Equal(t, time.Date(2026, 2, 10, 23, 59, 59, 0, time.UTC), *input.DueDate)
+	require.Equal(t, []string{"ci"}, input.Tags)
+
+	_, err = Normalize(Record{Values: map[Field]string{
+		FieldStatus:      "someday",
+		FieldPriority:    "urgent",
+		FieldEffortHours: "2.5",
+		FieldDueDate:     "next week",
+	}})
+	var list service.ValidationErrors
+	require.ErrorAs(t, err, &list)
+	codes := []string{}
+	for _, fieldErr := range list {
+		codes = append(codes, fieldErr.Code)
+	}
+	require.ElementsMatch(t, []string{service.CodeTitleRequired, service.CodeInvalidStatus, service.CodeInvalidPriority, service.CodeInvalidEffort, service.CodeInvalidDueDate}, codes)
+}
@@ -0,0 +1,120 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"devopslabs/internal/service"
+)
+
+const (
+	SourceFile   = "file"
+	SourceJira   = "jira"
+	SourceTrello = "trello"
+	SourceGitHub = "github"
+)
+
+var Sources = []string{SourceFile, SourceJira, SourceTrello, SourceGitHub}
+
+const CodeInvalidSource = "import_invalid_source"
+
+// ParseSource разбирает источник импорта; пустое значение означает файл
+// в формате FlowBoard.
+func ParseSource(raw string) (string, error) {
+	source := strings.ToLower(strings.TrimSpace(raw))
+	if source == "" {
+		return SourceFile, nil
+	}
+	for _, known := range Sources {
+		if source == known {
+			return source, nil
+		}
+	}
+	return "", service.NewFieldError(CodeInvalidSource, "source", map[string]any{"value": source})
+}
+
+// Read читает выгрузку источника и переводит её в записи импорта.
+// Исходные идентификаторы сохраняются во внешнем идентификаторе с
+// префиксом источника, например jira:OPS-12, поэтому повторный импорт той
+// же выгрузки обновляет задачи, а не создаёт копии. format учитывается
+// только для SourceFile; выгрузку Jira формат определяет сам.
+func Read(source string, format string, r io.Reader, mapping SourceMapping) ([]Record, []string, error) {
+	var records []Record
+	var ignored []string
+	var err error
+	switch source {
+	case SourceJira:
+		records, ignored, err = readJira(r, mapping)
+	case SourceTrello:
+		records, err = readTrello(r, mapping)
+	case SourceGitHub:
+		records, err = readGitHub(r, mapping)
+	default:
+		columns, columnsErr := mapping.columns()
+		if columnsErr != nil {
+			return nil, nil, columnsErr
+		}
+		records, ignored, err = Parse(format, r, columns)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, service.NewFieldError(CodeEmpty, "body", nil)
+	}
+	if len(records) > MaxRows {
+		return nil, nil, service.NewFieldError(CodeTooManyRows, "body", map[string]any{"max": MaxRows})
+	}
+
+	for i := range records {
+		mapping.translate(&records[i], source != SourceFile)
+	}
+	return records, ignored, nil
+}
+
+func readJira(r io.Reader, mapping SourceMapping) ([]Record, []string, error) {
+	reader := bufio.NewReader(r)
+	head, _ := reader.Peek(512)
+	if bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\uFEFF"))), []byte("<")) {
+		records, err := readJiraXML(reader)
+		return records, nil, err
+	}
+	return readJiraCSV(reader, mapping)
+}
+
+func newRecord(row int, externalID string) Record {
+	return Record{Row: row, Values: map[Field]string{FieldExternalID: externalID}}
+}
+
+func setValue(record *Record, field Field, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		record.Values[field] = value
+	}
+}
+
+// secondsToHours переводит оценку в секундах в целые часы с округлением
+// вверх, чтобы короткие задачи не получили нулевую оценку.
+func secondsToHours(raw string) string {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || seconds <= 0 {
+		return ""
+	}
+	return strconv.Itoa(int(math.Ceil(seconds / 3600)))
+}
+
+// normalizeDate переводит дату трекера в RFC3339; нераспознанное значение
+// возвращается как есть, чтобы Normalize сообщил об ошибке в строке.
+func normalizeDate(raw string, layouts ...string) string {
+	value := strings.TrimSpace(raw)
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC().Format(time.RFC3339)
+		}
+	}
+	return value
+}
@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+const jiraXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="0.92">
+  <channel>
+    <title>Jira</title>
+    <item>
+      <key id="10001">OPS-12</key>
+      <summary>Rotate TLS certificates</summary>
+      <description>&lt;p&gt;Expires &lt;b&gt;soon&lt;/b&gt; &amp;amp; blocks deploys&lt;/p&gt;</description>
+      <status id="3">In Review</status>
+      <priority id="2">Highest</priority>
+      <assignee username="alice.smith">Alice Smith</assignee>
+      <labels><label>security</label><label>duplicate</label></labels>
+      <due>Tue, 10 Feb 2026 00:00:00 +0000</due>
+      <timeoriginalestimate seconds="9000">2 hours, 30 minutes</timeoriginalestimate>
+    </item>
+    <item>
+      <key id="10002">OPS-13</key>
+      <summary>Clean up dashboards</summary>
+      <status>Won't Do</status>
+      <priority>Minor</priority>
+      <assignee username="-1">Unassigned</assignee>
+    </item>
+  </channel>
+</rss>`
+
+func testMapping(t *testing.T) SourceMapping {
+	mapping, err := LoadMapping(strings.NewReader(`{
+		"statuses": {"won't do": "done"},
+		"users": {"alice.smith": "alice"},
+		"labels": {"duplicate": ""}
+	}`))
+	require.NoError(t, err)
+	return mapping
+}
+
+func TestReadJiraXML(t *testing.T) {
+	records, _, err := Read(SourceJira, "", strings.NewReader(jiraXML), testMapping(t))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	input, err := Normalize(records[0])
+	require.NoError(t, err)
+	require.Equal(t, "jira:OPS-12", records[0].ExternalID())
+	require.Equal(t, "Rotate TLS certificates", input.Title)
+	require.Equal(t, "Expires soon & blocks deploys", input.Description)
+	require.Equal(t, domain.StatusInProgress, input.Status)
+	require.Equal(t, domain.PriorityCritical, input.Priority)
+	require.Equal(t, "alice", input.Owner)
+	require.Equal(t, 3, input.EffortHours)
+	require.Equal(t, "2026-02-10T00:00:00Z", input.DueDate.Format("2006-01-02T15:04:05Z07:00"))
+	require.Equal(t, []string{"security"}, input.Tags)
+
+	input, err = Normalize(records[1])
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusDone, input.Status)
+	require.Equal(t, domain.PriorityLow, input.Priority)
+	require.Equal(t, service.DefaultOwner, input.Owner)
+}
+
+func TestReadJiraCSV(t *testing.T) {
+	input := "Summary,Issue key,Issue id,Status,Priority,Assignee,Labels,Labels,Original Estimate,Due Date\n" +
+		"Ship CI,OPS-1,10001,To Do,Major,alice.smith,ci,release,3600,10/Feb/26 6:00 PM\n"
+	records, ignored, err := Read(SourceJira, "", strings.NewReader(input), testMapping(t))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Issue id"}, ignored)
+
+	task, err := Normalize(records[0])
+	require.NoError(t, err)
+	require.Equal(t, "jira:OPS-1", records[0].ExternalID())
+	require.Equal(t, domain.StatusTodo, task.Status)
+	require.Equal(t, domain.PriorityHigh, task.Priority)
+	require.Equal(t, "alice", task.Owner)
+	require.Equal(t, 1, task.EffortHours)
+	require.Equal(t, 18, task.DueDate.Hour())
+	require.Equal(t, []string{"ci", "release"}, task.Tags)
+
+	// Колонка трудоёмкости из сопоставления не переводится из секунд.
+	mapping := SourceMapping{Columns: map[string]string{"Story Points": "effortHours"}}
+	records, _, err = Read(SourceJira, "", strings.NewReader("Summary,Issue key,Story Points\nShip,OPS-2,5\n"), mapping)
+	require.NoError(t, err)
+	require.Equal(t, "5", records[0].Values[FieldEffortHours])
+}
+
+func TestReadTrelloBoard(t *testing.T) {
+	board := `{
+		"lists": [{"id": "l1", "name": "Doing"}, {"id": "l2", "name": "Ideas"}],
+		"labels": [{"id": "b1", "name": "High"}, {"id": "b2", "name": "frontend"}],
+		"members": [{"id": "m1", "username": "bob"}],
+		"cards": [
+			{"id": "c1", "name": "Build board", "desc": "Kanban", "idList": "l1", "idLabels": ["b1", "b2"], "idMembers": ["m1"], "due": "2026-02-20T12:00:00.000Z"},
+			{"id": "c2", "name": "Old idea", "idList": "l2", "closed": true, "due": null}
+		]
+	}`
+	records, _, err := Read(SourceTrello, "", strings.NewReader(board), SourceMapping{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	task, err := Normalize(records[0])
+	require.NoError(t, err)
+	require.Equal(t, "trello:c1", records[0].ExternalID())
+	require.Equal(t, domain.StatusInProgress, task.Status)
+	require.Equal(t, domain.PriorityHigh, task.Priority)
+	require.Equal(t, "bob", task.Owner)
+	require.Equal(t, "Kanban", task.Description)
+	require.Equal(t, []string{"frontend"}, task.Tags)
+	require.NotNil(t, task.DueDate)
+
+	task, err = Normalize(records[1])
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusDone, task.Status)
+	require.Nil(t, task.DueDate)
+}
+
+func TestReadGitHubIssues(t *testing.T) {
+	issues := `[
+		{"number": 7, "title": "Fix login", "body": "500 on submit", "state": "open",
+		 "html_url": "https://github.com/acme/web/issues/7",
+		 "labels": [{"name": "P1"}, {"name": "blocked"}, {"name": "bug"}],
+		 "assignee": {"login": "carol"},
+		 "milestone": {"due_on": "2026-03-01T08:00:00Z"}},
+		{"number": 8, "title": "Bump deps", "state": "open", "pull_request": {"url": "x"}},
+		{"number": 9, "title": "Write docs", "state": "CLOSED",
+		 "url": "https://github.com/acme/web/issues/9",
+		 "labels": [{"name": "blocked"}], "assignees": [{"login": "dave"}]}
+	]`
+	records, _, err := Read(SourceGitHub, "", strings.NewReader(issues), SourceMapping{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	task, err := Normalize(records[0])
+	require.NoError(t, err)
+	require.Equal(t, "github:acme/web#7", records[0].ExternalID())
+	require.Equal(t, domain.StatusBlocked, task.Status)
+	require.Equal(t, domain.PriorityHigh, task.Priority)
+	require.Equal(t, "carol", task.Owner)
+	require.Equal(t, []string{"bug"}, task.Tags)
+	require.Equal(t, 8, task.DueDate.Hour())
+
+	task, err = Normalize(records[1])
+	require.NoError(t, err)
+	require.Equal(t, "github:acme/web#9", records[1].ExternalID())
+	require.Equal(t, domain.StatusDone, task.Status)
+	require.Equal(t, "dave", task.Owner)
+	require.Equal(t, []string{"blocked"}, task.Tags)
+}
+
+func TestSourceErrors(t *testing.T) {
+	_, err := ParseSource("asana")
+	var fieldErr *service.FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidSource, fieldErr.Code)
+
+	_, err = LoadMapping(strings.NewReader(`{"statuses": {"Review": "reviewing"}, "colors": {}}`))
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidMappingFile, fieldErr.Code)
+
+	_, err = LoadMapping(strings.NewReader(`{"statuses": {"Review": "reviewing"}}`))
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, "mapping.statuses.Review", fieldErr.Field)
+
+	for _, source := range []string{SourceJira, SourceTrello, SourceGitHub} {
+		_, _, err := Read(source, "", strings.NewReader(`{"broken"`), SourceMapping{})
+		require.ErrorAs(t, err, &fieldErr, source)
+		require.Equal(t, CodeMalformed, fieldErr.Code, source)
+	}
+	_, _, err = Read(SourceGitHub, "", strings.NewReader(`[]`), SourceMapping{})
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeEmpty, fieldErr.Code)
+}
@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"devopslabs/internal/domain"
+)
+
+type trelloBoard struct {
+	Lists []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"lists"`
+	Labels []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"labels"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Cards []struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Desc        string   `json:"desc"`
+		IDList      string   `json:"idList"`
+		IDLabels    []string `json:"idLabels"`
+		IDMembers   []string `json:"idMembers"`
+		Due         *string  `json:"due"`
+		DueComplete bool     `json:"dueComplete"`
+		Closed      bool     `json:"closed"`
+	} `json:"cards"`
+}
+
+// readTrello читает JSON-выгрузку доски Trello. Статус берётся из названия
+// списка, приоритетов в Trello нет, поэтому их задают метки. Карточки в
+// архиве и с отмеченным сроком считаются выполненными.
+func readTrello(r io.Reader, mapping SourceMapping) ([]Record, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, malformed(err)
+	}
+
+	lists := make(map[string]string, len(board.Lists))
+	for _, list := range board.Lists {
+		lists[list.ID] = list.Name
+	}
+	labels := make(map[string]string, len(board.Labels))
+	for _, label := range board.Labels {
+		labels[label.ID] = label.Name
+	}
+	members := make(map[string]string, len(board.Members))
+	for _, member := range board.Members {
+		members[member.ID] = member.Username
+	}
+
+	records := make([]Record, 0, len(board.Cards))
+	for i, card := range board.Cards {
+		record := newRecord(i+1, SourceTrello+":"+card.ID)
+		setValue(&record, FieldTitle, card.Name)
+		setValue(&record, FieldDescription, card.Desc)
+		setValue(&record, FieldStatus, trelloStatus(lists[card.IDList], mapping))
+		if card.Closed || card.DueComplete {
+			record.Values[FieldStatus] = domain.StatusDone
+		}
+		if len(card.IDMembers) > 0 {
+			setValue(&record, FieldOwner, members[card.IDMembers[0]])
+		}
+		if card.Due != nil {
+			setValue(&record, FieldDueDate, *card.Due)
+		}
+
+		cardLabels := make([]string, 0, len(card.IDLabels))
+		for _, id := range card.IDLabels {
+			if name := strings.TrimSpace(labels[id]); name != "" {
+				cardLabels = append(cardLabels, name)
+			}
+		}
+		mapping.applyLabels(&record, cardLabels, false)
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// trelloStatus переводит название списка в статус: сначала по
+// сопоставлению, затем по ключевым словам, иначе задача считается новой.
+func trelloStatus(list string, mapping SourceMapping) string {
+	if status, ok := mapping.status(list); ok {
+		return status
+	}
+	name := strings.ToLower(list)
+	switch {
+	case strings.Contains(name, "done"), strings.Contains(name, "complete"):
+		return domain.StatusDone
+	case strings.Contains(name, "doing"), strings.Contains(name, "progress"), strings.Contains(name, "review"):
+		return domain.StatusInProgress
+	case strings.Contains(name, "block"), strings.Contains(name, "hold"):
+		return domain.StatusBlocked
+	}
+	return domain.StatusTodo
+}
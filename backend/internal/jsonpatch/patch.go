@@ -0,0 +1,379 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+var (
+	ErrInvalidPatch = errors.New("некорректный патч")
+	ErrTestFailed   = errors.New("проверка test в патче не пройдена")
+)
+
+// Operation — одна операция JSON Patch (RFC 6902).
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error описывает операцию, на которой остановилось применение патча.
+// Цепочка ошибок содержит ErrInvalidPatch или ErrTestFailed.
+type Error struct {
+	Index  int
+	Op     string
+	Path   string
+	Reason string
+	kind   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: операция %d (%s %s): %s", e.kind, e.Index, e.Op, e.Path, e.Reason)
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// MergePatch применяет JSON Merge Patch (RFC 7396) к документу.
+func MergePatch(document []byte, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, fmt.Errorf("%w: документ: %v", ErrInvalidPatch, err)
+	}
+	var changes any
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(mergeValue(target, changes))
+}
+
+func mergeValue(target any, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	result, ok := target.(map[string]any)
+	if !ok {
+		result = make(map[string]any, len(changes))
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = mergeValue(result[key], value)
+	}
+	return result
+}
+
+func DecodeOperations(data []byte) ([]Operation, error) {
+	var operations []Operation
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return nil, fmt.Errorf("%w: ожидается массив операций: %v", ErrInvalidPatch, err)
+	}
+	return operations, nil
+}
+
+// Apply применяет операции JSON Patch последовательно; при первой ошибке
+// документ не изменяется.
+func Apply(document []byte, operations []Operation) ([]byte, error) {
+	var doc any
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("%w: документ: %v", ErrInvalidPatch, err)
+	}
+
+	for index, operation := range operations {
+		next, err := applyOperation(doc, operation)
+		if err != nil {
+			kind := ErrInvalidPatch
+			if errors.Is(err, ErrTestFailed) {
+				kind = ErrTestFailed
+			}
+			return nil, &Error{Index: index, Op: operation.Op, Path: operation.Path, Reason: err.Error(), kind: kind}
+		}
+		doc = next
+	}
+
+	return json.Marshal(doc)
+}
+
+func applyOperation(doc any, operation Operation) (any, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case OpAdd:
+		value, err := decodeValue(operation.Value)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case OpRemove:
+		if len(path) == 0 {
+			return nil, errors.New("нельзя удалить корень документа")
+		}
+		return removeValue(doc, path)
+	case OpReplace:
+		value, err := decodeValue(operation.Value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := getValue(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		doc, err = removeValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case OpMove:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, errors.New("нельзя переместить значение внутрь самого себя")
+		}
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if len(from) == 0 {
+			return addValue(nil, path, value)
+		}
+		doc, err = removeValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, value)
+	case OpCopy:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, deepCopy(value))
+	case OpTest:
+		expected, err := decodeValue(operation.Value)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := getValue(doc, path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrTestFailed, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("неизвестная операция %q", operation.Op)
+	}
+}
+
+func decodeValue(raw json.RawMessage) (any, error) {
+	if len(raw) == 0 {
+		return nil, errors.New("не задано поле value")
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("путь %q должен начинаться с /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix []string, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("некорректный индекс %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("некорректный индекс %q", token)
+	}
+	limit := length - 1
+	if allowEnd {
+		limit = length
+	}
+	if index > limit {
+		return 0, fmt.Errorf("индекс %d вне массива", index)
+	}
+	return index, nil
+}
+
+func getValue(doc any, path []string) (any, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("путь /%s не найден", strings.Join(path, "/"))
+			}
+			current = value
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("путь /%s не найден", strings.Join(path, "/"))
+		}
+	}
+	return current, nil
+}
+
+func addValue(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token := path[0]
+	switch node := doc.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			node[token] = value
+			return node, nil
+		}
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("родительский путь для %q не найден", token)
+		}
+		updated, err := addValue(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = updated
+		return node, nil
+	case []any:
+		if len(path) == 1 {
+			index, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := addValue(node[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = updated
+		return node, nil
+	default:
+		return nil, fmt.Errorf("нельзя добавить значение по пути %q", token)
+	}
+}
+
+func removeValue(doc any, path []string) (any, error) {
+	token := path[0]
+	switch node := doc.(type) {
+	case map[string]any:
+		if _, ok := node[token]; !ok {
+			return nil, fmt.Errorf("путь %q не найден", token)
+		}
+		if len(path) == 1 {
+			delete(node, token)
+			return node, nil
+		}
+		updated, err := removeValue(node[token], path[1:])
+		if err != nil {
+			return nil, err
+		}
+		node[token] = updated
+		return node, nil
+	case []any:
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 1 {
+			return append(node[:index], node[index+1:]...), nil
+		}
+		updated, err := removeValue(node[index], path[1:])
+		if err != nil {
+			return nil, err
+		}
+		node[index] = updated
+		return node, nil
+	default:
+		return nil, fmt.Errorf("путь %q не найден", token)
+	}
+}
+
+func deepCopy(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(node))
+		for key, item := range node {
+			result[key] = deepCopy(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(node))
+		for i, item := range node {
+			result[i] = deepCopy(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
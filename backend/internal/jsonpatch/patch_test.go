@@ -0,0 +1,92 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	doc := []byte(`{"title":"A","owner":"anna","tags":["ci"],"meta":{"a":1,"b":2}}`)
+
+	result, err := MergePatch(doc, []byte(`{"title":"B","owner":null,"tags":["x","y"],"meta":{"a":null,"c":3}}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"title":"B","tags":["x","y"],"meta":{"b":2,"c":3}}`, string(result))
+
+	result, err = MergePatch(doc, []byte(`["replaced"]`))
+	require.NoError(t, err)
+	require.JSONEq(t, `["replaced"]`, string(result))
+
+	_, err = MergePatch(doc, []byte(`{`))
+	require.ErrorIs(t, err, ErrInvalidPatch)
+
+	_, err = MergePatch([]byte(`{`), []byte(`{}`))
+	require.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestApplyOperations(t *testing.T) {
+	doc := []byte(`{"title":"A","tags":["ci","infra"],"owner":"anna"}`)
+
+	operations, err := DecodeOperations([]byte(`[
+		{"op":"test","path":"/title","value":"A"},
+		{"op":"replace","path":"/title","value":"B"},
+		{"op":"add","path":"/tags/-","value":"release"},
+		{"op":"add","path":"/tags/0","value":"first"},
+		{"op":"remove","path":"/tags/1"},
+		{"op":"copy","from":"/owner","path":"/reviewer"},
+		{"op":"move","from":"/reviewer","path":"/lead"},
+		{"op":"add","path":"/a~1b","value":{"c~0":true}}
+	]`))
+	require.NoError(t, err)
+
+	result, err := Apply(doc, operations)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"title":"B","tags":["first","infra","release"],"owner":"anna","lead":"anna","a/b":{"c~0":true}}`, string(result))
+
+	result, err = Apply(doc, []Operation{{Op: OpReplace, Path: "", Value: []byte(`{"x":1}`)}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"x":1}`, string(result))
+
+	result, err = Apply(doc, []Operation{{Op: OpAdd, Path: "/dueDate", Value: []byte(`null`)}})
+	require.NoError(t, err)
+	require.Contains(t, string(result), `"dueDate":null`)
+}
+
+func TestApplyErrors(t *testing.T) {
+	doc := []byte(`{"title":"A","tags":["ci"]}`)
+
+	_, err := DecodeOperations([]byte(`{"op":"add"}`))
+	require.ErrorIs(t, err, ErrInvalidPatch)
+
+	_, err = Apply(doc, []Operation{{Op: OpTest, Path: "/title", Value: []byte(`"B"`)}})
+	require.ErrorIs(t, err, ErrTestFailed)
+
+	var patchErr *Error
+	require.ErrorAs(t, err, &patchErr)
+	require.Equal(t, 0, patchErr.Index)
+	require.Equal(t, "/title", patchErr.Path)
+
+	_, err = Apply(doc, []Operation{{Op: OpTest, Path: "/missing", Value: []byte(`1`)}})
+	require.ErrorIs(t, err, ErrTestFailed)
+
+	invalid := []Operation{
+		{Op: "bogus", Path: "/title"},
+		{Op: OpAdd, Path: "title", Value: []byte(`1`)},
+		{Op: OpAdd, Path: "/title"},
+		{Op: OpAdd, Path: "/missing/deep", Value: []byte(`1`)},
+		{Op: OpAdd, Path: "/tags/5", Value: []byte(`"x"`)},
+		{Op: OpAdd, Path: "/tags/01", Value: []byte(`"x"`)},
+		{Op: OpRemove, Path: ""},
+		{Op: OpRemove, Path: "/missing"},
+		{Op: OpRemove, Path: "/tags/3"},
+		{Op: OpReplace, Path: "/missing", Value: []byte(`1`)},
+		{Op: OpMove, From: "/tags", Path: "/tags/0"},
+		{Op: OpMove, From: "/missing", Path: "/x"},
+		{Op: OpCopy, From: "/missing", Path: "/x"},
+		{Op: OpAdd, Path: "/title/x", Value: []byte(`1`)},
+	}
+	for _, operation := range invalid {
+		_, err := Apply(doc, []Operation{operation})
+		require.ErrorIs(t, err, ErrInvalidPatch, operation)
+	}
+}
@@ -0,0 +1,125 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	zero           = 0.0
+)
+
+// Registry строит схемы по Go-типам через reflection. Именованные структуры
+// попадают в components.schemas и подключаются через $ref; поля читаются из
+// json-тегов, встроенные структуры раскрываются в родительскую схему.
+type Registry struct {
+	Schemas map[string]*Schema
+}
+
+func NewRegistry() *Registry {
+	return &Registry{Schemas: make(map[string]*Schema)}
+}
+
+// Register добавляет схему для значения sample и возвращает ссылку на неё.
+func (r *Registry) Register(sample any) *Schema {
+	return r.schemaFor(reflect.TypeOf(sample))
+}
+
+// Schema возвращает зарегистрированную схему для доработки вручную:
+// перечислений, обязательных полей и описаний.
+func (r *Registry) Schema(name string) *Schema {
+	return r.Schemas[name]
+}
+
+func (r *Registry) schemaFor(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := r.schemaFor(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		nullable := *schema
+		if name, ok := nullable.Type.(string); ok {
+			nullable.Type = []string{name, "null"}
+		}
+		return &nullable
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		if _, ok := r.Schemas[t.Name()]; !ok {
+			r.Schemas[t.Name()] = &Schema{}
+			*r.Schemas[t.Name()] = *r.structSchema(t)
+		}
+		return RefTo(t.Name())
+	default:
+		return &Schema{}
+	}
+}
+
+func (r *Registry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+	r.collectFields(t, schema)
+	return schema
+}
+
+func (r *Registry) collectFields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.collectFields(embedded, schema)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = r.schemaFor(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sampleBase struct {
+	ID int `json:"id"`
+}
+
+type sampleChild struct {
+	Name string `json:"name"`
+}
+
+type sample struct {
+	sampleBase
+	Title    string            `json:"title"`
+	Note     *string           `json:"note"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Child    *sampleChild      `json:"child,omitempty"`
+	Due      time.Time         `json:"due"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestRegistryBuildsStructSchema(t *testing.T) {
+	registry := NewRegistry()
+
+	ref := registry.Register(sample{})
+	require.Equal(t, "#/components/schemas/sample", ref.Ref)
+
+	schema := registry.Schema("sample")
+	require.NotNil(t, schema)
+	require.Equal(t, "object", schema.Type)
+	require.ElementsMatch(t, []string{"id", "title", "due"}, schema.Required)
+	require.Equal(t, "integer", schema.Properties["id"].Type)
+	require.Equal(t, []string{"string", "null"}, schema.Properties["note"].Type)
+	require.Equal(t, "string", schema.Properties["tags"].Items.Type)
+	require.Equal(t, false, schema.AdditionalProperties)
+	require.Equal(t, "string", schema.Properties["labels"].AdditionalProperties.(*Schema).Type)
+	require.Equal(t, "#/components/schemas/sampleChild", schema.Properties["child"].Ref)
+	require.Equal(t, "date-time", schema.Properties["due"].Format)
+	require.Equal(t, &Schema{}, schema.Properties["raw"])
+	require.NotContains(t, schema.Properties, "Ignored")
+	require.NotContains(t, schema.Properties, "internal")
+	require.NotNil(t, registry.Schema("sampleChild"))
+}
+
+func TestRegistryHandlesRecursiveTypes(t *testing.T) {
+	type node struct {
+		Children []node `json:"children"`
+	}
+
+	registry := NewRegistry()
+	registry.Register(node{})
+
+	require.Equal(t, "#/components/schemas/node", registry.Schema("node").Properties["children"].Items.Ref)
+}
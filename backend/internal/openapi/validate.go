@@ -0,0 +1,232 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	KeywordType                 = "type"
+	KeywordRequired             = "required"
+	KeywordAdditionalProperties = "additionalProperties"
+	KeywordEnum                 = "enum"
+	KeywordMaxLength            = "maxLength"
+	KeywordMaxItems             = "maxItems"
+	KeywordMinimum              = "minimum"
+	KeywordMaximum              = "maximum"
+	KeywordFormat               = "format"
+)
+
+// Violation описывает нарушение одного ключевого слова схемы. Pointer —
+// JSON Pointer на проверяемое значение, Params — значение и границы,
+// пригодные для подстановки в сообщение об ошибке.
+type Violation struct {
+	Pointer string
+	Keyword string
+	Params  map[string]any
+}
+
+// Field возвращает путь до значения через точку, например tags.0.
+func (v Violation) Field() string {
+	return strings.ReplaceAll(strings.TrimPrefix(v.Pointer, "/"), "/", ".")
+}
+
+// Validate проверяет значение, декодированное через json.Decoder с UseNumber,
+// по схеме; $ref разрешаются через schemas. Возвращаются все нарушения,
+// упорядоченные по пути.
+func Validate(schemas map[string]*Schema, schema *Schema, value any) []Violation {
+	v := validator{schemas: schemas}
+	v.validate(schema, value, "")
+	sort.SliceStable(v.violations, func(i, j int) bool {
+		return v.violations[i].Pointer < v.violations[j].Pointer
+	})
+	return v.violations
+}
+
+type validator struct {
+	schemas    map[string]*Schema
+	violations []Violation
+}
+
+func (v *validator) add(pointer string, keyword string, params map[string]any) {
+	v.violations = append(v.violations, Violation{Pointer: pointer, Keyword: keyword, Params: params})
+}
+
+func (v *validator) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = v.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+func (v *validator) validate(schema *Schema, value any, pointer string) {
+	schema = v.resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	types := schemaTypes(schema.Type)
+	if len(types) > 0 && !matchesAny(types, value) {
+		v.add(pointer, KeywordType, map[string]any{"expected": strings.Join(types, " | ")})
+		return
+	}
+	// Пустая строка в поле с перечислением означает значение по умолчанию.
+	if len(schema.Enum) > 0 && value != nil && value != "" && !inEnum(schema.Enum, value) {
+		v.add(pointer, KeywordEnum, map[string]any{"value": value, "allowed": schema.Enum})
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		v.validateObject(schema, typed, pointer)
+	case []any:
+		if schema.MaxItems != nil && len(typed) > *schema.MaxItems {
+			v.add(pointer, KeywordMaxItems, map[string]any{"count": len(typed), "max": *schema.MaxItems})
+		}
+		for i, item := range typed {
+			v.validate(schema.Items, item, fmt.Sprintf("%s/%d", pointer, i))
+		}
+	case string:
+		if schema.MaxLength != nil && utf8.RuneCountInString(typed) > *schema.MaxLength {
+			v.add(pointer, KeywordMaxLength, map[string]any{"value": typed, "max": *schema.MaxLength})
+		}
+		// Пустая строка в поле с форматом означает отсутствие значения.
+		if schema.Format == "date-time" && typed != "" {
+			if _, err := time.Parse(time.RFC3339, typed); err != nil {
+				v.add(pointer, KeywordFormat, map[string]any{"value": typed, "format": schema.Format})
+			}
+		}
+	case json.Number:
+		v.validateNumber(schema, typed, pointer)
+	}
+}
+
+func (v *validator) validateObject(schema *Schema, object map[string]any, pointer string) {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			v.add(pointer+"/"+escapePointer(name), KeywordRequired, map[string]any{"field": name})
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := pointer + "/" + escapePointer(name)
+		if property, ok := schema.Properties[name]; ok {
+			v.validate(property, object[name], child)
+			continue
+		}
+		switch extra := schema.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				v.add(child, KeywordAdditionalProperties, map[string]any{"field": name})
+			}
+		case *Schema:
+			v.validate(extra, object[name], child)
+		}
+	}
+}
+
+func (v *validator) validateNumber(schema *Schema, number json.Number, pointer string) {
+	value, ok := new(big.Float).SetString(number.String())
+	if !ok {
+		return
+	}
+	below := schema.Minimum != nil && value.Cmp(big.NewFloat(*schema.Minimum)) < 0
+	above := schema.Maximum != nil && value.Cmp(big.NewFloat(*schema.Maximum)) > 0
+	if !below && !above {
+		return
+	}
+
+	params := map[string]any{"value": number}
+	keyword := KeywordMaximum
+	if below {
+		keyword = KeywordMinimum
+	}
+	if schema.Minimum != nil {
+		params["min"] = *schema.Minimum
+	}
+	if schema.Maximum != nil {
+		params["max"] = *schema.Maximum
+	}
+	v.add(pointer, keyword, params)
+}
+
+func schemaTypes(value any) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []string:
+		return typed
+	default:
+		return nil
+	}
+}
+
+func matchesAny(types []string, value any) bool {
+	for _, name := range types {
+		if matchesType(name, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(name string, value any) bool {
+	switch name {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, ok = new(big.Int).SetString(number.String(), 10)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// inEnum сравнивает строки без учёта регистра и пробелов по краям — так же,
+// как их нормализуют доменные правила.
+func inEnum(enum []any, value any) bool {
+	text, isString := value.(string)
+	text = strings.TrimSpace(text)
+	for _, allowed := range enum {
+		if allowed == value {
+			return true
+		}
+		if name, ok := allowed.(string); ok && isString && strings.EqualFold(name, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
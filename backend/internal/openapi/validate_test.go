@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeValue(t *testing.T, raw string) any {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+	var value any
+	require.NoError(t, decoder.Decode(&value))
+	return value
+}
+
+func keywords(violations []Violation) map[string]string {
+	result := make(map[string]string, len(violations))
+	for _, violation := range violations {
+		result[violation.Field()] = violation.Keyword
+	}
+	return result
+}
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	maxLength := 3
+	maxItems := 2
+	minimum := 0.0
+	maximum := 10.0
+
+	schemas := map[string]*Schema{
+		"Item": {
+			Type:                 "object",
+			Required:             []string{"name"},
+			AdditionalProperties: false,
+			Properties: map[string]*Schema{
+				"name":  {Type: "string", MaxLength: &maxLength},
+				"kind":  Enum("a", "b"),
+				"count": {Type: "integer", Minimum: &minimum, Maximum: &maximum},
+				"when":  {Type: []string{"string", "null"}, Format: "date-time"},
+				"tags":  {Type: "array", MaxItems: &maxItems, Items: String()},
+				"meta":  {Type: "object", AdditionalProperties: Integer()},
+			},
+		},
+	}
+
+	value := decodeValue(t, `{"name":"long","kind":"c","count":11,"when":"later","tags":["x",2,"z"],"meta":{"a":1.5},"extra":true}`)
+	violations := Validate(schemas, RefTo("Item"), value)
+
+	require.Equal(t, map[string]string{
+		"name":   KeywordMaxLength,
+		"kind":   KeywordEnum,
+		"count":  KeywordMaximum,
+		"when":   KeywordFormat,
+		"tags":   KeywordMaxItems,
+		"tags.1": KeywordType,
+		"meta.a": KeywordType,
+		"extra":  KeywordAdditionalProperties,
+	}, keywords(violations))
+
+	for _, violation := range violations {
+		if violation.Keyword == KeywordMaximum {
+			require.Equal(t, 10.0, violation.Params["max"])
+			require.Equal(t, json.Number("11"), violation.Params["value"])
+		}
+	}
+
+	missing := Validate(schemas, RefTo("Item"), decodeValue(t, `{"when":null,"when2":1}`))
+	require.Equal(t, map[string]string{"name": KeywordRequired, "when2": KeywordAdditionalProperties}, keywords(missing))
+
+	require.Empty(t, Validate(schemas, RefTo("Item"), decodeValue(t, `{"name":"ok","when":"","count":0}`)))
+	// Перечисление сравнивается так же, как нормализуют доменные правила.
+	require.Empty(t, Validate(schemas, RefTo("Item"), decodeValue(t, `{"name":"ok","kind":" B "}`)))
+	require.Empty(t, Validate(schemas, RefTo("Item"), decodeValue(t, `{"name":"ok","kind":""}`)))
+}
+
+func TestValidateChecksRootType(t *testing.T) {
+	violations := Validate(nil, &Schema{Type: "object"}, decodeValue(t, `[1]`))
+	require.Len(t, violations, 1)
+	require.Equal(t, "", violations[0].Field())
+	require.Equal(t, KeywordType, violations[0].Keyword)
+	require.Equal(t, "object", violations[0].Params["expected"])
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"devopslabs/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CapacityStore interface {
+	// List возвращает настройки всех исполнителей, упорядоченные по имени.
+	List(ctx context.Context) ([]domain.OwnerCapacity, error)
+	Get(ctx context.Context, owner string) (*domain.OwnerCapacity, error)
+	// Save создаёт или заменяет настройки исполнителя.
+	Save(ctx context.Context, capacity *domain.OwnerCapacity) error
+	Delete(ctx context.Context, owner string) error
+}
+
+type GormCapacityStore struct {
+	db *gorm.DB
+}
+
+func NewGormCapacityStore(db *gorm.DB) *GormCapacityStore {
+	return &GormCapacityStore{db: db}
+}
+
+func (s *GormCapacityStore) List(ctx context.Context) ([]domain.OwnerCapacity, error) {
+	var capacities []domain.OwnerCapacity
+	if err := s.db.WithContext(ctx).Order("owner").Find(&capacities).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return capacities, nil
+}
+
+func (s *GormCapacityStore) Get(ctx context.Context, owner string) (*domain.OwnerCapacity, error) {
+	var capacity domain.OwnerCapacity
+	if err := s.db.WithContext(ctx).Where("owner = ?", owner).First(&capacity).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &capacity, nil
+}
+
+func (s *GormCapacityStore) Save(ctx context.Context, capacity *domain.OwnerCapacity) error {
+	return translateError(s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "owner"}},
+			DoUpdates: clause.AssignmentColumns([]string{"hours_per_week", "days_off", "updated_at"}),
+		}).
+		Create(capacity).Error)
+}
+
+func (s *GormCapacityStore) Delete(ctx context.Context, owner string) error {
+	result := s.db.WithContext(ctx).Where("owner = ?", owner).Delete(&domain.OwnerCapacity{})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MemoryCapacityStore — хранилище настроек ёмкости в памяти процесса.
+type MemoryCapacityStore struct {
+	mu         sync.Mutex
+	capacities map[string]domain.OwnerCapacity
+}
+
+func NewMemoryCapacityStore() *MemoryCapacityStore {
+	return &MemoryCapacityStore{capacities: make(map[string]domain.OwnerCapacity)}
+}
+
+func (s *MemoryCapacityStore) List(_ context.Context) ([]domain.OwnerCapacity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	capacities := make([]domain.OwnerCapacity, 0, len(s.capacities))
+	for _, capacity := range s.capacities {
+		capacities = append(capacities, cloneCapacity(capacity))
+	}
+	sort.Slice(capacities, func(i, j int) bool { return capacities[i].Owner < capacities[j].Owner })
+	return capacities, nil
+}
+
+func (s *MemoryCapacityStore) Get(_ context.Context, owner string) (*domain.OwnerCapacity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	capacity, ok := s.capacities[owner]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	capacity = cloneCapacity(capacity)
+	return &capacity, nil
+}
+
+func (s *MemoryCapacityStore) Save(_ context.Context, capacity *domain.OwnerCapacity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.capacities[capacity.Owner] = cloneCapacity(*capacity)
+	return nil
+}
+
+func (s *MemoryCapacityStore) Delete(_ context.Context, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.capacities[owner]; !ok {
+		return ErrNotFound
+	}
+	delete(s.capacities, owner)
+	return nil
+}
+
+func cloneCapacity(capacity domain.OwnerCapacity) domain.OwnerCapacity {
+	capacity.DaysOff = append(domain.StringList{}, capacity.DaysOff...)
+	return capacity
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGormCapacityStore(t *testing.T) {
+	db, mock := setupHistoryDB(t)
+	store := NewGormCapacityStore(db)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	columns := []string{"owner", "hours_per_week", "days_off", "updated_at"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "owner_capacities" \("owner","hours_per_week","days_off","updated_at"\) VALUES \(\$1,\$2,\$3,\$4\) ON CONFLICT \("owner"\) DO UPDATE SET "hours_per_week"="excluded"."hours_per_week","days_off"="excluded"."days_off","updated_at"="excluded"."updated_at"`).
+		WithArgs("anna", 30.0, `["2026-03-09"]`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.Save(ctx, &domain.OwnerCapacity{Owner: "anna", HoursPerWeek: 30, DaysOff: domain.StringList{"2026-03-09"}}))
+
+	mock.ExpectQuery(`SELECT \* FROM "owner_capacities" ORDER BY owner`).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("anna", 30.0, `["2026-03-09"]`, now))
+	capacities, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []domain.OwnerCapacity{{Owner: "anna", HoursPerWeek: 30, DaysOff: domain.StringList{"2026-03-09"}, UpdatedAt: now}}, capacities)
+
+	mock.ExpectQuery(`SELECT \* FROM "owner_capacities" WHERE owner = \$1`).
+		WithArgs("ivan", 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = store.Get(ctx, "ivan")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "owner_capacities" WHERE owner = \$1`).
+		WithArgs("ivan").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	require.ErrorIs(t, store.Delete(ctx, "ivan"), ErrNotFound)
+}
+
+func TestMemoryCapacityStore(t *testing.T) {
+	store := NewMemoryCapacityStore()
+	ctx := context.Background()
+
+	capacity := &domain.OwnerCapacity{Owner: "ivan", HoursPerWeek: 20, DaysOff: domain.StringList{"2026-03-10"}}
+	require.NoError(t, store.Save(ctx, capacity))
+	require.NoError(t, store.Save(ctx, &domain.OwnerCapacity{Owner: "anna", HoursPerWeek: 40}))
+	capacity.DaysOff[0] = "2026-03-11"
+
+	capacities, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, capacities, 2)
+	require.Equal(t, "anna", capacities[0].Owner)
+	require.Equal(t, domain.StringList{"2026-03-10"}, capacities[1].DaysOff)
+
+	require.NoError(t, store.Save(ctx, &domain.OwnerCapacity{Owner: "ivan", HoursPerWeek: 10}))
+	loaded, err := store.Get(ctx, "ivan")
+	require.NoError(t, err)
+	require.Equal(t, 10.0, loaded.HoursPerWeek)
+
+	require.NoError(t, store.Delete(ctx, "ivan"))
+	require.ErrorIs(t, store.Delete(ctx, "ivan"), ErrNotFound)
+	_, err = store.Get(ctx, "ivan")
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"devopslabs/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ChangeFilter отбирает записи истории; пустые поля не ограничивают выборку.
+// Until не включается.
+type ChangeFilter struct {
+	TaskIDs []uint
+	Until   time.Time
+}
+
+type ChangeStore interface {
+	Append(ctx context.Context, changes []domain.TaskChange) error
+	// List возвращает записи в порядке изменений.
+	List(ctx context.Context, filter ChangeFilter) ([]domain.TaskChange, error)
+}
+
+type GormChangeStore struct {
+	db *gorm.DB
+}
+
+func NewGormChangeStore(db *gorm.DB) *GormChangeStore {
+	return &GormChangeStore{db: db}
+}
+
+func (s *GormChangeStore) Append(ctx context.Context, changes []domain.TaskChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return translateError(s.db.WithContext(ctx).Create(&changes).Error)
+}
+
+func (s *GormChangeStore) List(ctx context.Context, filter ChangeFilter) ([]domain.TaskChange, error) {
+	query := s.db.WithContext(ctx).Model(&domain.TaskChange{})
+	if len(filter.TaskIDs) > 0 {
+		query = query.Where("task_id IN ?", filter.TaskIDs)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("occurred_at < ?", filter.Until)
+	}
+
+	var changes []domain.TaskChange
+	if err := query.Order("occurred_at ASC").Order("id ASC").Find(&changes).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return changes, nil
+}
+
+type MemoryChangeStore struct {
+	mu      sync.Mutex
+	changes []domain.TaskChange
+}
+
+func NewMemoryChangeStore() *MemoryChangeStore {
+	return &MemoryChangeStore{}
+}
+
+func (s *MemoryChangeStore) Append(_ context.Context, changes []domain.TaskChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, change := range changes {
+		change.ID = uint(len(s.changes) + 1)
+		s.changes = append(s.changes, change)
+	}
+	return nil
+}
+
+func (s *MemoryChangeStore) List(_ context.Context, filter ChangeFilter) ([]domain.TaskChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changes []domain.TaskChange
+	for _, change := range s.changes {
+		if len(filter.TaskIDs) > 0 && !slices.Contains(filter.TaskIDs, change.TaskID) {
+			continue
+		}
+		if !filter.Until.IsZero() && !change.OccurredAt.Before(filter.Until) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	slices.SortStableFunc(changes, func(a, b domain.TaskChange) int {
+		return a.OccurredAt.Compare(b.OccurredAt)
+	})
+	return changes, nil
+}
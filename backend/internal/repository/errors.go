@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotFound    = errors.New("запись не найдена")
+	ErrConflict    = errors.New("конфликт данных")
+	ErrValidation  = errors.New("данные не прошли проверку хранилища")
+	ErrUnavailable = errors.New("хранилище недоступно")
+)
+
+// translateError приводит ошибки GORM и драйвера PostgreSQL к ошибкам
+// пакета repository, сохраняя исходную ошибку в цепочке.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case errors.Is(err, gorm.ErrDuplicatedKey), errors.Is(err, gorm.ErrForeignKeyViolated):
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	case errors.Is(err, gorm.ErrCheckConstraintViolated), errors.Is(err, gorm.ErrInvalidField):
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, driver.ErrBadConn):
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505", pgErr.Code == "23503", pgErr.Code == "40001", pgErr.Code == "40P01":
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		case strings.HasPrefix(pgErr.Code, "22"), strings.HasPrefix(pgErr.Code, "23"):
+			return fmt.Errorf("%w: %w", ErrValidation, err)
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "53"), strings.HasPrefix(pgErr.Code, "57P"):
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return err
+}
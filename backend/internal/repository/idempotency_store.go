@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	IdempotencyPending   = "pending"
+	IdempotencyCompleted = "completed"
+)
+
+// IdempotencyRecord хранит ответ на запрос с заголовком Idempotency-Key.
+// Пока исходный запрос выполняется, запись находится в состоянии pending.
+type IdempotencyRecord struct {
+	Key         string    `gorm:"column:idempotency_key;primaryKey;size:320"`
+	Fingerprint string    `gorm:"size:64;not null"`
+	State       string    `gorm:"size:16;not null"`
+	StatusCode  int       `gorm:"not null;default:0"`
+	ContentType string    `gorm:"size:120"`
+	Body        []byte    `gorm:"type:bytea"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+type IdempotencyStore interface {
+	// Reserve атомарно создаёт запись; если действующая запись с тем же ключом
+	// уже есть, она возвращается, а created равно false.
+	Reserve(ctx context.Context, record *IdempotencyRecord, now time.Time) (existing *IdempotencyRecord, created bool, err error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type GormIdempotencyStore struct {
+	db *gorm.DB
+}
+
+func NewGormIdempotencyStore(db *gorm.DB) *GormIdempotencyStore {
+	return &GormIdempotencyStore{db: db}
+}
+
+func (s *GormIdempotencyStore) Reserve(ctx context.Context, record *IdempotencyRecord, now time.Time) (*IdempotencyRecord, bool, error) {
+	db := s.db.WithContext(ctx)
+	if err := db.Where("idempotency_key = ? AND expires_at <= ?", record.Key, now).Delete(&IdempotencyRecord{}).Error; err != nil {
+		return nil, false, translateError(err)
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, false, translateError(result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, true, nil
+	}
+
+	var existing IdempotencyRecord
+	if err := db.Where("idempotency_key = ?", record.Key).First(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Запись удалили между вставкой и чтением — клиент может повторить запрос.
+			return nil, false, ErrConflict
+		}
+		return nil, false, translateError(err)
+	}
+	return &existing, false, nil
+}
+
+func (s *GormIdempotencyStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	result := s.db.WithContext(ctx).Model(&IdempotencyRecord{}).
+		Where("idempotency_key = ?", key).
+		Updates(map[string]any{
+			"state":        IdempotencyCompleted,
+			"status_code":  statusCode,
+			"content_type": contentType,
+			"body":         body,
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *GormIdempotencyStore) Release(ctx context.Context, key string) error {
+	return translateError(s.db.WithContext(ctx).
+		Where("idempotency_key = ? AND state = ?", key, IdempotencyPending).
+		Delete(&IdempotencyRecord{}).Error)
+}
+
+func (s *GormIdempotencyStore) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&IdempotencyRecord{})
+	return result.RowsAffected, translateError(result.Error)
+}
+
+// MemoryIdempotencyStore — хранилище ключей в памяти процесса; подходит для
+// одного экземпляра сервиса и для тестов.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]IdempotencyRecord)}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(_ context.Context, record *IdempotencyRecord, now time.Time) (*IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[record.Key]; ok && existing.ExpiresAt.After(now) {
+		return &existing, false, nil
+	}
+	s.records[record.Key] = *record
+	return nil, true, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return ErrNotFound
+	}
+	record.State = IdempotencyCompleted
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = append([]byte(nil), body...)
+	s.records[key] = record
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[key]; ok && record.State == IdempotencyPending {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) PurgeExpired(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var idempotencyColumns = []string{
+	"idempotency_key",
+	"fingerprint",
+	"state",
+	"status_code",
+	"content_type",
+	"body",
+	"created_at",
+	"expires_at",
+}
+
+func setupIdempotencyDB(t *testing.T) (*GormIdempotencyStore, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	dialector := postgres.New(postgres.Config{Conn: sqlDB, PreferSimpleProtocol: true})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err)
+
+	return NewGormIdempotencyStore(db), mock
+}
+
+func TestGormIdempotencyStore(t *testing.T) {
+	store, mock := setupIdempotencyDB(t)
+	ctx := context.Background()
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	record := &IdempotencyRecord{
+		Key:         "POST /api/tasks key-1",
+		Fingerprint: "abc",
+		State:       IdempotencyPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "idempotency_records"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "idempotency_records" .* ON CONFLICT DO NOTHING`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	existing, created, err := store.Reserve(ctx, record, now)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Nil(t, existing)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "idempotency_records"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "idempotency_records"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT .* FROM "idempotency_records"`).WillReturnRows(
+		sqlmock.NewRows(idempotencyColumns).AddRow(record.Key, "abc", IdempotencyCompleted, 201, "application/json", []byte(`{"id":1}`), now, now.Add(time.Hour)),
+	)
+	existing, created, err = store.Reserve(ctx, record, now)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, 201, existing.StatusCode)
+	require.Equal(t, `{"id":1}`, string(existing.Body))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "idempotency_records"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.Complete(ctx, record.Key, 201, "application/json", []byte(`{}`)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "idempotency_records"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	require.ErrorIs(t, store.Complete(ctx, "missing", 201, "application/json", nil), ErrNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "idempotency_records"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.Release(ctx, record.Key))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "idempotency_records"`).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+	purged, err := store.PurgeExpired(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), purged)
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	ctx := context.Background()
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	record := &IdempotencyRecord{Key: "k", Fingerprint: "abc", State: IdempotencyPending, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	_, created, err := store.Reserve(ctx, record, now)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	existing, created, err := store.Reserve(ctx, record, now)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, IdempotencyPending, existing.State)
+
+	require.NoError(t, store.Complete(ctx, "k", 201, "application/json", []byte(`{}`)))
+	require.ErrorIs(t, store.Complete(ctx, "missing", 201, "", nil), ErrNotFound)
+
+	require.NoError(t, store.Release(ctx, "k"))
+	existing, _, err = store.Reserve(ctx, record, now)
+	require.NoError(t, err)
+	require.Equal(t, IdempotencyCompleted, existing.State)
+
+	_, created, err = store.Reserve(ctx, record, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.True(t, created)
+	require.NoError(t, store.Release(ctx, "k"))
+
+	_, _, err = store.Reserve(ctx, record, now)
+	require.NoError(t, err)
+	purged, err := store.PurgeExpired(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+}
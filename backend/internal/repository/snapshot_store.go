@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"devopslabs/internal/service"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	SnapshotScopeAll   = "all"
+	SnapshotScopeOwner = "owner"
+	SnapshotScopeTag   = "tag"
+)
+
+// InsightSnapshot хранит сводку по задачам за день. Day записывается в виде
+// YYYY-MM-DD по UTC, Key — владелец или тег, для общей сводки он пустой.
+// Повторный снимок за тот же день заменяет предыдущий.
+type InsightSnapshot struct {
+	ID         uint             `gorm:"primaryKey"`
+	Day        string           `gorm:"size:10;not null;uniqueIndex:idx_insight_snapshots_day_scope_key"`
+	Scope      string           `gorm:"size:16;not null;uniqueIndex:idx_insight_snapshots_day_scope_key"`
+	Key        string           `gorm:"column:scope_key;size:120;not null;uniqueIndex:idx_insight_snapshots_day_scope_key"`
+	Insights   service.Insights `gorm:"serializer:json;type:text"`
+	CapturedAt time.Time        `gorm:"not null"`
+}
+
+// SnapshotFilter отбирает снимки одной сводки за дни From..To включительно.
+type SnapshotFilter struct {
+	Scope string
+	Key   string
+	From  string
+	To    string
+}
+
+type SnapshotStore interface {
+	Save(ctx context.Context, snapshots []InsightSnapshot) error
+	// List возвращает снимки, упорядоченные по дню.
+	List(ctx context.Context, filter SnapshotFilter) ([]InsightSnapshot, error)
+}
+
+type GormSnapshotStore struct {
+	db *gorm.DB
+}
+
+func NewGormSnapshotStore(db *gorm.DB) *GormSnapshotStore {
+	return &GormSnapshotStore{db: db}
+}
+
+func (s *GormSnapshotStore) Save(ctx context.Context, snapshots []InsightSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return translateError(s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}, {Name: "scope"}, {Name: "scope_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"insights", "captured_at"}),
+		}).
+		Create(&snapshots).Error)
+}
+
+func (s *GormSnapshotStore) List(ctx context.Context, filter SnapshotFilter) ([]InsightSnapshot, error) {
+	var snapshots []InsightSnapshot
+	err := s.db.WithContext(ctx).
+		Where("scope = ? AND scope_key = ? AND day >= ? AND day <= ?", filter.Scope, filter.Key, filter.From, filter.To).
+		Order("day").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return snapshots, nil
+}
+
+type snapshotKey struct {
+	day   string
+	scope string
+	key   string
+}
+
+type MemorySnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[snapshotKey]InsightSnapshot
+	nextID    uint
+}
+
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{snapshots: make(map[snapshotKey]InsightSnapshot), nextID: 1}
+}
+
+func (s *MemorySnapshotStore) Save(_ context.Context, snapshots []InsightSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		key := snapshotKey{day: snapshot.Day, scope: snapshot.Scope, key: snapshot.Key}
+		if existing, ok := s.snapshots[key]; ok {
+			snapshot.ID = existing.ID
+		} else {
+			snapshot.ID = s.nextID
+			s.nextID++
+		}
+		s.snapshots[key] = snapshot
+	}
+	return nil
+}
+
+func (s *MemorySnapshotStore) List(_ context.Context, filter SnapshotFilter) ([]InsightSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var snapshots []InsightSnapshot
+	for key, snapshot := range s.snapshots {
+		if key.scope != filter.Scope || key.key != filter.Key || key.day < filter.From || key.day > filter.To {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Day < snapshots[j].Day
+	})
+	return snapshots, nil
+}
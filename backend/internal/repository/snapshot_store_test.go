@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func setupHistoryDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	dialector := postgres.New(postgres.Config{Conn: sqlDB, PreferSimpleProtocol: true})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err)
+	return db, mock
+}
+
+func TestGormChangeStore(t *testing.T) {
+	db, mock := setupHistoryDB(t)
+	store := NewGormChangeStore(db)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Append(ctx, nil))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "task_changes" \("task_id","type","occurred_at","task"\)`).
+		WithArgs(uint(3), domain.ChangeUpdated, at, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+	require.NoError(t, store.Append(ctx, []domain.TaskChange{
+		{TaskID: 3, Type: domain.ChangeUpdated, OccurredAt: at, Task: domain.Task{ID: 3, Status: domain.StatusBlocked}},
+	}))
+
+	mock.ExpectQuery(`SELECT \* FROM "task_changes" WHERE task_id IN \(\$1\) AND occurred_at < \$2 ORDER BY occurred_at ASC,id ASC`).
+		WithArgs(3, at).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_id", "type", "occurred_at", "task"}).
+			AddRow(1, 3, domain.ChangeUpdated, at, `{"id":3,"status":"blocked","tags":["ops"]}`))
+	changes, err := store.List(ctx, ChangeFilter{TaskIDs: []uint{3}, Until: at})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, domain.StatusBlocked, changes[0].Task.Status)
+	require.Equal(t, domain.StringList{"ops"}, changes[0].Task.Tags)
+}
+
+func TestGormSnapshotStore(t *testing.T) {
+	db, mock := setupHistoryDB(t)
+	store := NewGormSnapshotStore(db)
+	ctx := context.Background()
+	at := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "insight_snapshots" .* ON CONFLICT \("day","scope","scope_key"\) DO UPDATE SET "insights"="excluded"."insights","captured_at"="excluded"."captured_at"`).
+		WithArgs("2026-03-02", SnapshotScopeOwner, "anna", sqlmock.AnyArg(), at).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+	require.NoError(t, store.Save(ctx, []InsightSnapshot{
+		{Day: "2026-03-02", Scope: SnapshotScopeOwner, Key: "anna", Insights: service.Insights{Total: 2}, CapturedAt: at},
+	}))
+
+	mock.ExpectQuery(`SELECT \* FROM "insight_snapshots" WHERE scope = \$1 AND scope_key = \$2 AND day >= \$3 AND day <= \$4 ORDER BY day`).
+		WithArgs(SnapshotScopeOwner, "anna", "2026-03-01", "2026-03-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "day", "scope", "scope_key", "insights", "captured_at"}).
+			AddRow(5, "2026-03-02", SnapshotScopeOwner, "anna", `{"total":2,"overdue":1}`, at))
+	snapshots, err := store.List(ctx, SnapshotFilter{Scope: SnapshotScopeOwner, Key: "anna", From: "2026-03-01", To: "2026-03-31"})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, 1, snapshots[0].Insights.Overdue)
+}
+
+func TestMemoryHistoryStores(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	changes := NewMemoryChangeStore()
+	require.NoError(t, changes.Append(ctx, []domain.TaskChange{
+		{TaskID: 1, Type: domain.ChangeUpdated, OccurredAt: at.Add(time.Hour)},
+		{TaskID: 2, Type: domain.ChangeCreated, OccurredAt: at},
+		{TaskID: 1, Type: domain.ChangeCreated, OccurredAt: at.Add(-time.Hour)},
+	}))
+	listed, err := changes.List(ctx, ChangeFilter{Until: at.Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, uint(1), listed[0].TaskID)
+	require.Equal(t, uint(2), listed[1].TaskID)
+	listed, err = changes.List(ctx, ChangeFilter{TaskIDs: []uint{1}})
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+
+	snapshots := NewMemorySnapshotStore()
+	require.NoError(t, snapshots.Save(ctx, []InsightSnapshot{
+		{Day: "2026-03-02", Scope: SnapshotScopeAll, Insights: service.Insights{Total: 1}},
+		{Day: "2026-03-01", Scope: SnapshotScopeAll, Insights: service.Insights{Total: 4}},
+		{Day: "2026-03-02", Scope: SnapshotScopeTag, Key: "ops"},
+	}))
+	require.NoError(t, snapshots.Save(ctx, []InsightSnapshot{
+		{Day: "2026-03-02", Scope: SnapshotScopeAll, Insights: service.Insights{Total: 3}},
+	}))
+	stored, err := snapshots.List(ctx, SnapshotFilter{Scope: SnapshotScopeAll, From: "2026-03-01", To: "2026-03-02"})
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	require.Equal(t, "2026-03-01", stored[0].Day)
+	require.Equal(t, 3, stored[1].Insights.Total)
+	require.Equal(t, uint(1), stored[1].ID)
+}
@@ -2,18 +2,70 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
 	"gorm.io/gorm"
 )
 
 type TaskFilter struct {
-	Statuses   []string
-	Priorities []string
-	Owner      string
-	Query      string
-	Tag        string
+	IDs         []uint
+	ExternalIDs []string
+	Statuses    []string
+	Priorities  []string
+	Owner       string
+	Query       string
+	Tag         string
+	// Expression — выражение фильтра, которое сочетается с остальными полями
+	// через AND.
+	Expression taskquery.Expr
+	// Now — момент отсчёта для относительных дат в Expression; нулевое
+	// значение означает текущее время на момент проверки.
+	Now time.Time
+}
+
+// Matches проверяет задачу на соответствие фильтру так же, как List, но
+// в памяти; используется для отбора событий об изменениях.
+func (f TaskFilter) Matches(task domain.Task) bool {
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, task.ID) {
+		return false
+	}
+	if len(f.ExternalIDs) > 0 && !slices.Contains(f.ExternalIDs, task.ExternalID) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, task.Status) {
+		return false
+	}
+	if len(f.Priorities) > 0 && !slices.Contains(f.Priorities, task.Priority) {
+		return false
+	}
+	if f.Owner != "" && task.Owner != f.Owner {
+		return false
+	}
+	if f.Query != "" && !strings.Contains(task.Title, f.Query) && !strings.Contains(task.Description, f.Query) {
+		return false
+	}
+	if tag := strings.ToLower(strings.TrimSpace(f.Tag)); tag != "" && !containsTag(task.Tags, tag) {
+		return false
+	}
+	if f.Expression != nil && !f.Expression.Matches(task, f.now()) {
+		return false
+	}
+	return true
+}
+
+func (f TaskFilter) now() time.Time {
+	if f.Now.IsZero() {
+		return time.Now()
+	}
+	return f.Now
 }
 
 type TaskStore interface {
@@ -24,6 +76,65 @@ type TaskStore interface {
 	Delete(ctx context.Context, id uint) error
 }
 
+// TaskTransactor — необязательное расширение TaskStore: fn выполняется в одной
+// транзакции и получает хранилище, привязанное к ней. Вложенный вызов
+// открывает точку сохранения, поэтому откатывается только его часть.
+type TaskTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(store TaskStore) error) error
+}
+
+// WithinTransaction выполняет fn в транзакции, если хранилище это
+// поддерживает; иначе fn получает само хранилище.
+func WithinTransaction(ctx context.Context, store TaskStore, fn func(TaskStore) error) error {
+	if transactor, ok := store.(TaskTransactor); ok {
+		return transactor.WithinTransaction(ctx, fn)
+	}
+	return fn(store)
+}
+
+// StreamBatchSize — число задач, которое Stream загружает одним запросом.
+const StreamBatchSize = 500
+
+// TaskOrder — порядок выдачи Stream: сортировка Option по правилам Policy на
+// момент Now.
+type TaskOrder struct {
+	Option service.SortOption
+	Policy service.Policy
+	Now    time.Time
+}
+
+// Sort упорядочивает задачи в памяти.
+func (o TaskOrder) Sort(tasks []domain.Task) {
+	o.Policy.Sort(tasks, o.Option, o.Now)
+}
+
+// TaskStreamer — необязательное расширение TaskStore для выгрузок: fn
+// получает задачи фильтра по одной в порядке order, без загрузки всего
+// результата в память.
+type TaskStreamer interface {
+	Stream(ctx context.Context, filter TaskFilter, order TaskOrder, fn func(domain.Task) error) error
+}
+
+// StreamTasks передаёт задачи через TaskStreamer, если store его
+// поддерживает, и через List в остальных случаях.
+func StreamTasks(ctx context.Context, store TaskStore, filter TaskFilter, order TaskOrder, fn func(domain.Task) error) error {
+	if streamer, ok := store.(TaskStreamer); ok {
+		return streamer.Stream(ctx, filter, order, fn)
+	}
+
+	tasks, err := store.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+	order.Sort(tasks)
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type GormTaskStore struct {
 	db *gorm.DB
 }
@@ -33,7 +144,142 @@ func NewGormTaskStore(db *gorm.DB) *GormTaskStore {
 }
 
 func (s *GormTaskStore) List(ctx context.Context, filter TaskFilter) ([]domain.Task, error) {
+	var tasks []domain.Task
+	if err := s.query(ctx, filter).Find(&tasks).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return filterByTag(tasks, filter.Tag), nil
+}
+
+// Stream читает задачи в одной транзакции только для чтения, поэтому
+// выгрузка видит один снимок данных. Порядок, который умеет база, задаётся в
+// SQL, и задачи идут одним курсором. Для score в памяти держатся только ключи
+// оценки: они сортируются через order, а полные записи загружаются порциями
+// по StreamBatchSize.
+func (s *GormTaskStore) Stream(ctx context.Context, filter TaskFilter, order TaskOrder, fn func(domain.Task) error) error {
+	options := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		store := &GormTaskStore{db: tx}
+		if clause, ok := sqlOrder(order.Option); ok {
+			return eachTask(store.query(ctx, filter).Order(clause), filter.Tag, fn)
+		}
+		return store.streamByKeys(ctx, filter, order, fn)
+	}, options)
+}
+
+// scoreColumns — поля, от которых зависят оценка и её порядок.
+var scoreColumns = []string{"id", "priority", "status", "effort_hours", "owner", "tags", "due_date", "updated_at"}
+
+func (s *GormTaskStore) streamByKeys(ctx context.Context, filter TaskFilter, order TaskOrder, fn func(domain.Task) error) error {
+	var keys []domain.Task
+	err := eachTask(s.query(ctx, filter).Select(scoreColumns), filter.Tag, func(task domain.Task) error {
+		task.Tags = nil
+		keys = append(keys, task)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	order.Sort(keys)
+
+	for start := 0; start < len(keys); start += StreamBatchSize {
+		end := min(start+StreamBatchSize, len(keys))
+		ids := make([]uint, 0, end-start)
+		for _, key := range keys[start:end] {
+			ids = append(ids, key.ID)
+		}
+
+		var batch []domain.Task
+		if err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&batch).Error; err != nil {
+			return translateError(err)
+		}
+		byID := make(map[uint]domain.Task, len(batch))
+		for _, task := range batch {
+			byID[task.ID] = task
+		}
+		for _, id := range ids {
+			if task, ok := byID[id]; ok {
+				if err := fn(task); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// eachTask передаёт fn строки запроса по мере чтения курсора.
+func eachTask(query *gorm.DB, tag string, fn func(domain.Task) error) error {
+	rows, err := query.Model(&domain.Task{}).Rows()
+	if err != nil {
+		return translateError(err)
+	}
+	defer rows.Close()
+
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for rows.Next() {
+		var task domain.Task
+		if err := query.ScanRows(rows, &task); err != nil {
+			return translateError(err)
+		}
+		if tag != "" && !containsTag(task.Tags, tag) {
+			continue
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return translateError(rows.Err())
+}
+
+// sqlOrder повторяет в SQL порядок Policy.Sort, включая доразбор равных по
+// updated_at; id делает порядок однозначным. Оценку score база посчитать не
+// может.
+func sqlOrder(option service.SortOption) (string, bool) {
+	direction := " ASC"
+	if option.Order == "desc" {
+		direction = " DESC"
+	}
+	updated := "updated_at" + direction
+	switch option.By {
+	case "priority":
+		return priorityWeightSQL() + direction + ", " + updated + ", id", true
+	case "due_date":
+		return "due_date IS NULL, due_date" + direction + ", " + updated + ", id", true
+	case "created_at":
+		return "created_at" + direction + ", id", true
+	case "updated_at":
+		return updated + ", id", true
+	case "title":
+		return `LOWER(title) COLLATE "C"` + direction + ", " + updated + ", id", true
+	}
+	return "", false
+}
+
+func priorityWeightSQL() string {
+	priorities := make([]string, 0, len(domain.PriorityWeights))
+	for priority := range domain.PriorityWeights {
+		priorities = append(priorities, priority)
+	}
+	sort.Strings(priorities)
+
+	var builder strings.Builder
+	builder.WriteString("CASE priority")
+	for _, priority := range priorities {
+		builder.WriteString(" WHEN '" + priority + "' THEN " + strconv.Itoa(domain.PriorityWeights[priority]))
+	}
+	builder.WriteString(" ELSE 0 END")
+	return builder.String()
+}
+
+func (s *GormTaskStore) query(ctx context.Context, filter TaskFilter) *gorm.DB {
 	query := s.db.WithContext(ctx)
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if len(filter.ExternalIDs) > 0 {
+		query = query.Where("external_id IN ?", filter.ExternalIDs)
+	}
 	if len(filter.Statuses) > 0 {
 		query = query.Where("status IN ?", filter.Statuses)
 	}
@@ -47,19 +293,17 @@ func (s *GormTaskStore) List(ctx context.Context, filter TaskFilter) ([]domain.T
 		like := "%" + filter.Query + "%"
 		query = query.Where("title LIKE ? OR description LIKE ?", like, like)
 	}
-
-	var tasks []domain.Task
-	if err := query.Find(&tasks).Error; err != nil {
-		return nil, err
-	}
-
-	if filter.Tag == "" {
-		return tasks, nil
+	if filter.Expression != nil {
+		clause, args := taskquery.SQL(filter.Expression, filter.now())
+		query = query.Where(clause, args...)
 	}
+	return query
+}
 
-	tag := strings.ToLower(strings.TrimSpace(filter.Tag))
+func filterByTag(tasks []domain.Task, rawTag string) []domain.Task {
+	tag := strings.ToLower(strings.TrimSpace(rawTag))
 	if tag == "" {
-		return tasks, nil
+		return tasks
 	}
 
 	filtered := make([]domain.Task, 0, len(tasks))
@@ -68,28 +312,48 @@ func (s *GormTaskStore) List(ctx context.Context, filter TaskFilter) ([]domain.T
 			filtered = append(filtered, task)
 		}
 	}
-
-	return filtered, nil
+	return filtered
 }
 
 func (s *GormTaskStore) Get(ctx context.Context, id uint) (*domain.Task, error) {
 	var task domain.Task
 	if err := s.db.WithContext(ctx).First(&task, id).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &task, nil
 }
 
 func (s *GormTaskStore) Create(ctx context.Context, task *domain.Task) error {
-	return s.db.WithContext(ctx).Create(task).Error
+	return translateError(s.db.WithContext(ctx).Create(task).Error)
 }
 
 func (s *GormTaskStore) Update(ctx context.Context, task *domain.Task) error {
-	return s.db.WithContext(ctx).Save(task).Error
+	// Явный Select("*") не даёт Save превратиться во вставку удалённой записи.
+	result := s.db.WithContext(ctx).Select("*").Save(task)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
 func (s *GormTaskStore) Delete(ctx context.Context, id uint) error {
-	return s.db.WithContext(ctx).Delete(&domain.Task{}, id).Error
+	result := s.db.WithContext(ctx).Delete(&domain.Task{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *GormTaskStore) WithinTransaction(ctx context.Context, fn func(store TaskStore) error) error {
+	return translateError(s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormTaskStore{db: tx})
+	}))
 }
 
 func containsTag(tags domain.StringList, tag string) bool {
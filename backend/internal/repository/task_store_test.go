@@ -3,11 +3,15 @@ package repository
 import (
 	"context"
 	"errors"
+	"regexp"
 	"testing"
 	"time"
 
 	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -152,6 +156,92 @@ func TestRepositoryListWithFiltersAndTags(t *testing.T) {
 	require.Equal(t, "Prepare CI", tasks[0].Title)
 }
 
+func TestRepositoryListByIDs(t *testing.T) {
+	store, mock := setupStoreDB(t)
+
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(taskColumns).
+		AddRow(3, "Ship", "", domain.StatusTodo, domain.PriorityHigh, "anna", 1, `[]`, nil, nil, nil, now, now)
+
+	mock.ExpectQuery(`SELECT .* FROM "tasks" WHERE id IN \(\$1,\$2\)`).
+		WithArgs(3, 7).
+		WillReturnRows(rows)
+	tasks, err := store.List(context.Background(), TaskFilter{IDs: []uint{3, 7}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, uint(3), tasks[0].ID)
+}
+
+func TestRepositoryListByExpression(t *testing.T) {
+	store, mock := setupStoreDB(t)
+
+	expression, err := taskquery.Parse(`priority>=high AND (owner:anna OR tag:ci) AND due<7d`)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tasks" WHERE status IN ($1) AND (((priority IN ($2,$3) AND (COALESCE(owner, '') IN ($4) OR (LOWER(COALESCE(tags, '')) LIKE $5 ESCAPE '\'))) AND (due_date IS NOT NULL AND due_date < $6)))`)).
+		WithArgs(domain.StatusTodo, domain.PriorityHigh, domain.PriorityCritical, "anna", `%"ci"%`, now.Add(7*24*time.Hour)).
+		WillReturnRows(sqlmock.NewRows(taskColumns))
+
+	_, err = store.List(context.Background(), TaskFilter{Statuses: []string{domain.StatusTodo}, Expression: expression, Now: now})
+	require.NoError(t, err)
+}
+
+func TestRepositoryStreamOrdersInSQL(t *testing.T) {
+	store, mock := setupStoreDB(t)
+
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tasks" WHERE status IN ($1) ORDER BY due_date IS NULL, due_date DESC, updated_at DESC, id`)).
+		WithArgs(domain.StatusTodo).
+		WillReturnRows(sqlmock.NewRows(taskColumns).
+			AddRow(2, "Later", "", domain.StatusTodo, domain.PriorityLow, "anna", 1, `["ci"]`, now.Add(time.Hour), nil, nil, now, now).
+			AddRow(3, "Other tag", "", domain.StatusTodo, domain.PriorityLow, "anna", 1, `["docs"]`, now, nil, nil, now, now).
+			AddRow(1, "No due date", "", domain.StatusTodo, domain.PriorityLow, "anna", 1, `["CI"]`, nil, nil, nil, now, now))
+	mock.ExpectCommit()
+
+	order := TaskOrder{Option: service.SortOption{By: "due_date", Order: "desc"}, Policy: service.DefaultPolicy(), Now: now}
+	var streamed []uint
+	err := store.Stream(context.Background(), TaskFilter{Statuses: []string{domain.StatusTodo}, Tag: "ci"}, order, func(task domain.Task) error {
+		streamed = append(streamed, task.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint{2, 1}, streamed)
+}
+
+func TestRepositoryStreamLoadsScoreOrderInBatches(t *testing.T) {
+	store, mock := setupStoreDB(t)
+
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+	keys := sqlmock.NewRows(scoreColumns)
+	for id := 1; id <= StreamBatchSize+1; id++ {
+		// Оценки равны, и при сортировке по убыванию первыми идут задачи,
+		// обновлённые позже.
+		keys.AddRow(id, domain.PriorityLow, domain.StatusTodo, 1, "anna", `["ci"]`, nil, now.Add(time.Duration(id)*time.Minute))
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","priority","status","effort_hours","owner","tags","due_date","updated_at" FROM "tasks"`)).WillReturnRows(keys)
+	mock.ExpectQuery(`SELECT \* FROM "tasks" WHERE id IN \(\$1,\$2,`).
+		WillReturnRows(sqlmock.NewRows(taskColumns).
+			AddRow(StreamBatchSize+1, "Last", "full", domain.StatusTodo, domain.PriorityLow, "anna", 1, `["ci"]`, nil, nil, nil, now, now).
+			AddRow(StreamBatchSize, "Middle", "", domain.StatusTodo, domain.PriorityLow, "anna", 1, `["ci"]`, nil, nil, nil, now, now))
+	mock.ExpectQuery(`SELECT \* FROM "tasks" WHERE id IN \(\$1\)`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(taskColumns).
+			AddRow(1, "First", "", domain.StatusTodo, domain.PriorityLow, "anna", 1, `["ci"]`, nil, nil, nil, now, now))
+	mock.ExpectCommit()
+
+	order := TaskOrder{Option: service.SortOption{By: "score", Order: "desc"}, Policy: service.DefaultPolicy(), Now: now}
+	var streamed []uint
+	err := store.Stream(context.Background(), TaskFilter{Tag: "CI"}, order, func(task domain.Task) error {
+		streamed = append(streamed, task.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint{StreamBatchSize + 1, StreamBatchSize, 1}, streamed)
+}
+
 func TestRepositoryGetNotFound(t *testing.T) {
 	store, mock := setupStoreDB(t)
 
@@ -169,3 +259,103 @@ func TestRepositoryListReturnsErrorOnQueryFailure(t *testing.T) {
 	_, err := store.List(context.Background(), TaskFilter{})
 	require.Error(t, err)
 }
+
+func TestRepositoryUpdateAndDeleteNotFound(t *testing.T) {
+	store, mock := setupStoreDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "tasks"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	err := store.Update(context.Background(), &domain.Task{ID: 42, Title: "Missing"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "tasks"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	require.ErrorIs(t, store.Delete(context.Background(), 42), ErrNotFound)
+}
+
+func TestRepositoryGetNotFoundIsTyped(t *testing.T) {
+	store, mock := setupStoreDB(t)
+
+	mock.ExpectQuery(`SELECT .* FROM "tasks"`).WillReturnRows(sqlmock.NewRows(taskColumns))
+
+	_, err := store.Get(context.Background(), 999)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestTranslateError(t *testing.T) {
+	require.NoError(t, translateError(nil))
+
+	plain := errors.New("plain")
+	require.Equal(t, plain, translateError(plain))
+
+	require.ErrorIs(t, translateError(gorm.ErrDuplicatedKey), ErrConflict)
+	require.ErrorIs(t, translateError(gorm.ErrCheckConstraintViolated), ErrValidation)
+	require.ErrorIs(t, translateError(context.DeadlineExceeded), ErrUnavailable)
+
+	require.ErrorIs(t, translateError(&pgconn.PgError{Code: "23505"}), ErrConflict)
+	require.ErrorIs(t, translateError(&pgconn.PgError{Code: "22001"}), ErrValidation)
+	require.ErrorIs(t, translateError(&pgconn.PgError{Code: "08006"}), ErrUnavailable)
+
+	other := &pgconn.PgError{Code: "42601"}
+	require.Equal(t, error(other), translateError(other))
+}
+
+func TestRepositoryWithinTransaction(t *testing.T) {
+	store, mock := setupStoreDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "tasks"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := store.WithinTransaction(context.Background(), func(tx TaskStore) error {
+		return tx.Delete(context.Background(), 1)
+	})
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "tasks"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	err = store.WithinTransaction(context.Background(), func(tx TaskStore) error {
+		return tx.Delete(context.Background(), 2)
+	})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	var _ TaskTransactor = store
+}
+
+func TestTaskFilterMatches(t *testing.T) {
+	task := domain.Task{
+		Title:       "Deploy release",
+		Description: "Roll out to prod",
+		Status:      domain.StatusTodo,
+		Priority:    domain.PriorityHigh,
+		Owner:       "alice",
+		Tags:        domain.StringList{"ci", "ops"},
+	}
+
+	task.ID = 3
+	require.True(t, TaskFilter{}.Matches(task))
+	require.True(t, TaskFilter{IDs: []uint{1, 3}}.Matches(task))
+	require.False(t, TaskFilter{IDs: []uint{1}}.Matches(task))
+	require.True(t, TaskFilter{
+		Statuses:   []string{domain.StatusTodo, domain.StatusDone},
+		Priorities: []string{domain.PriorityHigh},
+		Owner:      "alice",
+		Query:      "prod",
+		Tag:        " OPS ",
+	}.Matches(task))
+
+	require.False(t, TaskFilter{Statuses: []string{domain.StatusDone}}.Matches(task))
+	require.False(t, TaskFilter{Priorities: []string{domain.PriorityLow}}.Matches(task))
+	require.False(t, TaskFilter{Owner: "bob"}.Matches(task))
+	require.False(t, TaskFilter{Query: "staging"}.Matches(task))
+	require.False(t, TaskFilter{Tag: "qa"}.Matches(task))
+
+	dueSoon, err := taskquery.Parse("tag:ci AND due<7d")
+	require.NoError(t, err)
+	due := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	task.DueDate = &due
+	require.True(t, TaskFilter{Expression: dueSoon, Now: due.Add(-24 * time.Hour)}.Matches(task))
+	require.False(t, TaskFilter{Expression: dueSoon, Now: due.Add(-30 * 24 * time.Hour)}.Matches(task))
+}
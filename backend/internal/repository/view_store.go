@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"devopslabs/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ViewStore interface {
+	// List возвращает общие представления и личные представления viewer,
+	// упорядоченные по имени.
+	List(ctx context.Context, viewer string) ([]domain.SavedView, error)
+	Get(ctx context.Context, id uint) (*domain.SavedView, error)
+	Create(ctx context.Context, view *domain.SavedView) error
+	Update(ctx context.Context, view *domain.SavedView) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type GormViewStore struct {
+	db *gorm.DB
+}
+
+func NewGormViewStore(db *gorm.DB) *GormViewStore {
+	return &GormViewStore{db: db}
+}
+
+func (s *GormViewStore) List(ctx context.Context, viewer string) ([]domain.SavedView, error) {
+	var views []domain.SavedView
+	err := s.db.WithContext(ctx).
+		Where("visibility = ? OR owner = ?", domain.VisibilityShared, viewer).
+		Order("name").Order("id").
+		Find(&views).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return views, nil
+}
+
+func (s *GormViewStore) Get(ctx context.Context, id uint) (*domain.SavedView, error) {
+	var view domain.SavedView
+	if err := s.db.WithContext(ctx).First(&view, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &view, nil
+}
+
+func (s *GormViewStore) Create(ctx context.Context, view *domain.SavedView) error {
+	return translateError(s.db.WithContext(ctx).Create(view).Error)
+}
+
+func (s *GormViewStore) Update(ctx context.Context, view *domain.SavedView) error {
+	result := s.db.WithContext(ctx).Select("*").Save(view)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *GormViewStore) Delete(ctx context.Context, id uint) error {
+	result := s.db.WithContext(ctx).Delete(&domain.SavedView{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MemoryViewStore — хранилище представлений в памяти процесса; подходит для
+// одного экземпляра сервиса и для тестов.
+type MemoryViewStore struct {
+	mu     sync.Mutex
+	views  map[uint]domain.SavedView
+	nextID uint
+}
+
+func NewMemoryViewStore() *MemoryViewStore {
+	return &MemoryViewStore{views: make(map[uint]domain.SavedView), nextID: 1}
+}
+
+func (s *MemoryViewStore) List(_ context.Context, viewer string) ([]domain.SavedView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views := make([]domain.SavedView, 0, len(s.views))
+	for _, view := range s.views {
+		if view.Visibility == domain.VisibilityShared || view.Owner == viewer {
+			views = append(views, cloneView(view))
+		}
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Name != views[j].Name {
+			return views[i].Name < views[j].Name
+		}
+		return views[i].ID < views[j].ID
+	})
+	return views, nil
+}
+
+func (s *MemoryViewStore) Get(_ context.Context, id uint) (*domain.SavedView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	view, ok := s.views[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	view = cloneView(view)
+	return &view, nil
+}
+
+func (s *MemoryViewStore) Create(_ context.Context, view *domain.SavedView) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	view.ID = s.nextID
+	s.nextID++
+	s.views[view.ID] = cloneView(*view)
+	return nil
+}
+
+func (s *MemoryViewStore) Update(_ context.Context, view *domain.SavedView) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.views[view.ID]; !ok {
+		return ErrNotFound
+	}
+	s.views[view.ID] = cloneView(*view)
+	return nil
+}
+
+func (s *MemoryViewStore) Delete(_ context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.views[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.views, id)
+	return nil
+}
+
+func cloneView(view domain.SavedView) domain.SavedView {
+	filter := make(domain.StringMap, len(view.Filter))
+	for key, value := range view.Filter {
+		filter[key] = value
+	}
+	view.Filter = filter
+	return view
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var viewColumns = []string{"id", "name", "owner", "visibility", "filter", "sort", "order", "created_at", "updated_at"}
+
+func setupViewDB(t *testing.T) (*GormViewStore, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	dialector := postgres.New(postgres.Config{Conn: sqlDB, PreferSimpleProtocol: true})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err)
+
+	return NewGormViewStore(db), mock
+}
+
+func TestGormViewStore(t *testing.T) {
+	store, mock := setupViewDB(t)
+	ctx := context.Background()
+	now := time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+
+	view := &domain.SavedView{
+		Name:       "My blockers",
+		Owner:      "anna",
+		Visibility: domain.VisibilityPrivate,
+		Filter:     domain.StringMap{"status": "blocked"},
+		Sort:       "score",
+		Order:      "desc",
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "saved_views"`).
+		WithArgs("My blockers", "anna", domain.VisibilityPrivate, `{"status":"blocked"}`, "score", "desc", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectCommit()
+	require.NoError(t, store.Create(ctx, view))
+	require.Equal(t, uint(4), view.ID)
+
+	mock.ExpectQuery(`SELECT \* FROM "saved_views" WHERE visibility = \$1 OR owner = \$2 ORDER BY name,id`).
+		WithArgs(domain.VisibilityShared, "anna").
+		WillReturnRows(sqlmock.NewRows(viewColumns).
+			AddRow(4, "My blockers", "anna", domain.VisibilityPrivate, `{"status":"blocked"}`, "score", "desc", now, now))
+	views, err := store.List(ctx, "anna")
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	require.Equal(t, domain.StringMap{"status": "blocked"}, views[0].Filter)
+
+	mock.ExpectQuery(`SELECT \* FROM "saved_views"`).WillReturnRows(sqlmock.NewRows(viewColumns))
+	_, err = store.Get(ctx, 9)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "saved_views"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	require.ErrorIs(t, store.Update(ctx, &domain.SavedView{ID: 9, Name: "x"}), ErrNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "saved_views"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.Delete(ctx, 4))
+}
+
+func TestMemoryViewStore(t *testing.T) {
+	store := NewMemoryViewStore()
+	ctx := context.Background()
+
+	shared := &domain.SavedView{Name: "Team board", Owner: "ivan", Visibility: domain.VisibilityShared, Filter: domain.StringMap{"tag": "infra"}}
+	private := &domain.SavedView{Name: "Mine", Owner: "anna", Visibility: domain.VisibilityPrivate}
+	require.NoError(t, store.Create(ctx, shared))
+	require.NoError(t, store.Create(ctx, private))
+
+	views, err := store.List(ctx, "anna")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Mine", "Team board"}, []string{views[0].Name, views[1].Name})
+
+	views, err = store.List(ctx, "ivan")
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+
+	loaded, err := store.Get(ctx, shared.ID)
+	require.NoError(t, err)
+	loaded.Filter["tag"] = "changed"
+	again, err := store.Get(ctx, shared.ID)
+	require.NoError(t, err)
+	require.Equal(t, "infra", again.Filter["tag"], "хранилище отдаёт копии")
+
+	require.NoError(t, store.Delete(ctx, private.ID))
+	require.ErrorIs(t, store.Delete(ctx, private.ID), ErrNotFound)
+	require.ErrorIs(t, store.Update(ctx, private), ErrNotFound)
+}
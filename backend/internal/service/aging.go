@@ -0,0 +1,159 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+// Полосы возраста задачи в работе относительно процентилей cycle time.
+const (
+	AgingBelowP50 = "below_p50"
+	AgingPastP50  = "past_p50"
+	AgingPastP85  = "past_p85"
+	AgingPastP95  = "past_p95"
+)
+
+// AgingStatuses — статусы незавершённой работы в порядке групп отчёта.
+// Задачи в todo ещё не начаты и в отчёт не входят.
+var AgingStatuses = []string{domain.StatusInProgress, domain.StatusBlocked}
+
+// AgingItem — задача в работе. AgeHours считается от начала работы,
+// StatusHours — от перехода в текущий статус. Band пуст, если завершённых
+// задач в окне нет; Flagged отмечает возраст выше p85.
+type AgingItem struct {
+	TaskID      uint      `json:"taskId"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	Priority    string    `json:"priority"`
+	Owner       string    `json:"owner"`
+	StartedAt   time.Time `json:"startedAt"`
+	AgeHours    float64   `json:"ageHours"`
+	StatusHours float64   `json:"statusHours"`
+	Band        string    `json:"band,omitempty"`
+	Flagged     bool      `json:"flagged"`
+}
+
+// AgingGroup собирает задачи одного статуса и исполнителя, старшие первыми.
+type AgingGroup struct {
+	Status      string      `json:"status"`
+	Owner       string      `json:"owner"`
+	Count       int         `json:"count"`
+	Flagged     int         `json:"flagged"`
+	OldestHours float64     `json:"oldestHours"`
+	Items       []AgingItem `json:"items"`
+}
+
+// AgingWIP сравнивает возраст задач в работе с cycle time задач,
+// завершённых в окне [Since, Until].
+type AgingWIP struct {
+	At        time.Time     `json:"at"`
+	Since     time.Time     `json:"since"`
+	Until     time.Time     `json:"until"`
+	CycleTime DurationStats `json:"cycleTime"`
+	Total     int           `json:"total"`
+	Flagged   int           `json:"flagged"`
+	Groups    []AgingGroup  `json:"groups"`
+}
+
+// StatusSince возвращает момент перехода задачи в текущий статус. Без
+// StatusChangedAt он оценивается по StartedAt, CompletedAt или CreatedAt.
+func StatusSince(task domain.Task) time.Time {
+	switch {
+	case task.StatusChangedAt != nil:
+		return *task.StatusChangedAt
+	case task.Status == domain.StatusInProgress && task.StartedAt != nil:
+		return *task.StartedAt
+	case task.Status == domain.StatusDone && task.CompletedAt != nil:
+		return *task.CompletedAt
+	}
+	return task.CreatedAt
+}
+
+// AgingWIP строит отчёт по задачам в статусах AgingStatuses; часы рабочие
+// по календарю исполнителя.
+func (p Policy) AgingWIP(now time.Time, tasks []domain.Task, since time.Time, until time.Time) AgingWIP {
+	report := AgingWIP{
+		At:        now,
+		Since:     since,
+		Until:     until,
+		CycleTime: p.FlowTimes(tasks, since, until).CycleTime.DurationStats,
+		Groups:    []AgingGroup{},
+	}
+
+	order := make(map[string]int, len(AgingStatuses))
+	for index, status := range AgingStatuses {
+		order[status] = index
+	}
+	type groupKey struct{ status, owner string }
+	groups := make(map[groupKey]*AgingGroup)
+	for _, task := range tasks {
+		if _, ok := order[task.Status]; !ok {
+			continue
+		}
+		started := StatusSince(task)
+		if task.StartedAt != nil {
+			started = *task.StartedAt
+		}
+		item := AgingItem{
+			TaskID:      task.ID,
+			Title:       task.Title,
+			Status:      task.Status,
+			Priority:    task.Priority,
+			Owner:       task.Owner,
+			StartedAt:   started,
+			AgeHours:    p.hoursBetween(task.Owner, started, now),
+			StatusHours: p.hoursBetween(task.Owner, StatusSince(task), now),
+		}
+		item.Band = agingBand(item.AgeHours, report.CycleTime)
+		item.Flagged = item.Band == AgingPastP85 || item.Band == AgingPastP95
+
+		key := groupKey{status: task.Status, owner: task.Owner}
+		group := groups[key]
+		if group == nil {
+			group = &AgingGroup{Status: task.Status, Owner: task.Owner}
+			groups[key] = group
+		}
+		group.Count++
+		group.Items = append(group.Items, item)
+		group.OldestHours = max(group.OldestHours, item.AgeHours)
+		report.Total++
+		if item.Flagged {
+			group.Flagged++
+			report.Flagged++
+		}
+	}
+
+	for _, group := range groups {
+		sort.Slice(group.Items, func(i, j int) bool {
+			if group.Items[i].AgeHours != group.Items[j].AgeHours {
+				return group.Items[i].AgeHours > group.Items[j].AgeHours
+			}
+			return group.Items[i].TaskID < group.Items[j].TaskID
+		})
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		left, right := report.Groups[i], report.Groups[j]
+		if left.Status != right.Status {
+			return order[left.Status] < order[right.Status]
+		}
+		return left.Owner < right.Owner
+	})
+	return report
+}
+
+func agingBand(hours float64, cycle DurationStats) string {
+	switch {
+	case cycle.Count == 0:
+		return ""
+	case hours > cycle.P95Hours:
+		return AgingPastP95
+	case hours > cycle.P85Hours:
+		return AgingPastP85
+	case hours > cycle.MedianHours:
+		return AgingPastP50
+	}
+	return AgingBelowP50
+}
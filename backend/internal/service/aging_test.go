@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgingWIPFlagsTasksPastP85(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(hours float64) *time.Time {
+		value := now.Add(-time.Duration(hours * float64(time.Hour)))
+		return &value
+	}
+
+	var tasks []domain.Task
+	// Cycle time истории: 10, 20, 30, 40 и 50 часов — медиана 30, p85 44, p95 48.
+	for index, hours := range []float64{10, 20, 30, 40, 50} {
+		tasks = append(tasks, domain.Task{
+			ID:          uint(index + 1),
+			Status:      domain.StatusDone,
+			StartedAt:   at(hours + 24),
+			CompletedAt: at(24),
+			CreatedAt:   *at(200),
+		})
+	}
+	tasks = append(tasks,
+		domain.Task{ID: 6, Status: domain.StatusInProgress, Owner: "alice", StartedAt: at(50), StatusChangedAt: at(50), CreatedAt: *at(60)},
+		domain.Task{ID: 7, Status: domain.StatusInProgress, Owner: "alice", StartedAt: at(45), CreatedAt: *at(60)},
+		domain.Task{ID: 8, Status: domain.StatusInProgress, Owner: "bob", StartedAt: at(35), CreatedAt: *at(60)},
+		domain.Task{ID: 9, Status: domain.StatusBlocked, Owner: "alice", StartedAt: at(100), StatusChangedAt: at(5), CreatedAt: *at(120)},
+		domain.Task{ID: 10, Status: domain.StatusTodo, Owner: "alice", CreatedAt: *at(500)},
+		domain.Task{ID: 11, Status: domain.StatusBlocked, Owner: "bob", StatusChangedAt: at(2), CreatedAt: *at(300)},
+	)
+
+	report := DefaultPolicy().AgingWIP(now, tasks, now.Add(-DefaultDurationWindow), now)
+	require.Equal(t, DurationStats{Count: 5, MeanHours: 30, MedianHours: 30, P85Hours: 44, P95Hours: 48}, report.CycleTime)
+	require.Equal(t, 5, report.Total)
+	require.Equal(t, 3, report.Flagged)
+
+	require.Len(t, report.Groups, 4)
+	inProgress := report.Groups[0]
+	require.Equal(t, domain.StatusInProgress, inProgress.Status)
+	require.Equal(t, "alice", inProgress.Owner)
+	require.Equal(t, 2, inProgress.Flagged)
+	require.Equal(t, 50.0, inProgress.OldestHours)
+	require.Equal(t, uint(6), inProgress.Items[0].TaskID)
+	require.Equal(t, AgingPastP95, inProgress.Items[0].Band)
+	require.Equal(t, AgingPastP85, inProgress.Items[1].Band)
+	require.True(t, inProgress.Items[1].Flagged)
+	require.Equal(t, 45.0, inProgress.Items[1].StatusHours)
+
+	require.Equal(t, "bob", report.Groups[1].Owner)
+	require.Equal(t, AgingPastP50, report.Groups[1].Items[0].Band)
+	require.False(t, report.Groups[1].Items[0].Flagged)
+
+	blocked := report.Groups[2].Items[0]
+	require.Equal(t, domain.StatusBlocked, blocked.Status)
+	require.Equal(t, 100.0, blocked.AgeHours)
+	require.Equal(t, 5.0, blocked.StatusHours)
+	require.True(t, blocked.Flagged)
+
+	// Без StartedAt возраст считается от перехода в статус.
+	require.Equal(t, "bob", report.Groups[3].Owner)
+	require.Equal(t, 2.0, report.Groups[3].Items[0].AgeHours)
+	require.Equal(t, AgingBelowP50, report.Groups[3].Items[0].Band)
+
+	empty := DefaultPolicy().AgingWIP(now, tasks[5:], now.Add(-DefaultDurationWindow), now)
+	require.Zero(t, empty.Flagged)
+	require.Empty(t, empty.Groups[0].Items[0].Band)
+}
+
+func TestStatusHoursUseStatusChange(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-300 * time.Hour)
+	started := now.Add(-200 * time.Hour)
+	completed := now.Add(-20 * time.Hour)
+	changed := now.Add(-3 * time.Hour)
+
+	metrics := ComputeMetrics(now, domain.Task{Status: domain.StatusInProgress, StartedAt: &started, StatusChangedAt: &changed, CreatedAt: created})
+	require.Equal(t, 300.0, metrics.AgeHours)
+	require.Equal(t, 3.0, metrics.StatusHours)
+
+	// Задачи без StatusChangedAt: оценка по отметкам начала и завершения.
+	require.Equal(t, started, StatusSince(domain.Task{Status: domain.StatusInProgress, StartedAt: &started, CreatedAt: created}))
+	require.Equal(t, completed, StatusSince(domain.Task{Status: domain.StatusDone, StartedAt: &started, CompletedAt: &completed, CreatedAt: created}))
+	require.Equal(t, created, StatusSince(domain.Task{Status: domain.StatusBlocked, StartedAt: &started, CreatedAt: created}))
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+const (
+	CodeInvalidWindowBound = "invalid_window_bound"
+	CodeInvalidWindow      = "invalid_window"
+)
+
+// DefaultDurationWindow — окно выборки завершённых задач, если since не указан.
+const DefaultDurationWindow = 90 * 24 * time.Hour
+
+// DurationBuckets — границы корзин гистограммы в часах: до 4 часов, до
+// рабочего дня, до суток, трёх дней, недели, двух и четырёх недель.
+// Постоянные границы позволяют сравнивать гистограммы разных окон.
+var DurationBuckets = []float64{4, 8, 24, 72, 168, 336, 672}
+
+// DurationStats — распределение длительностей в часах.
+type DurationStats struct {
+	Count       int     `json:"count"`
+	MeanHours   float64 `json:"meanHours"`
+	MedianHours float64 `json:"medianHours"`
+	P85Hours    float64 `json:"p85Hours"`
+	P95Hours    float64 `json:"p95Hours"`
+}
+
+// HistogramBucket считает длительности в диапазоне [FromHours, ToHours);
+// у последней корзины верхней границы нет.
+type HistogramBucket struct {
+	FromHours float64  `json:"fromHours"`
+	ToHours   *float64 `json:"toHours,omitempty"`
+	Count     int      `json:"count"`
+}
+
+// DurationDistribution описывает время выполнения задач. Выбросами считаются
+// значения выше OutlierHours: третий квартиль плюс полтора межквартильных
+// размаха.
+type DurationDistribution struct {
+	DurationStats
+	OutlierHours float64                  `json:"outlierHours"`
+	Histogram    []HistogramBucket        `json:"histogram"`
+	ByPriority   map[string]DurationStats `json:"byPriority"`
+	ByOwner      map[string]DurationStats `json:"byOwner"`
+}
+
+// DurationPoint — точка диаграммы рассеяния: завершённая задача с временем
+// выполнения от создания (lead) и от начала работы (cycle).
+type DurationPoint struct {
+	TaskID       uint      `json:"taskId"`
+	Title        string    `json:"title"`
+	Priority     string    `json:"priority"`
+	Owner        string    `json:"owner"`
+	CompletedAt  time.Time `json:"completedAt"`
+	LeadHours    float64   `json:"leadHours"`
+	CycleHours   *float64  `json:"cycleHours,omitempty"`
+	LeadOutlier  bool      `json:"leadOutlier"`
+	CycleOutlier bool      `json:"cycleOutlier"`
+}
+
+// FlowTimes — время выполнения задач, завершённых в окне [Since, Until].
+type FlowTimes struct {
+	Since     time.Time            `json:"since"`
+	Until     time.Time            `json:"until"`
+	LeadTime  DurationDistribution `json:"leadTime"`
+	CycleTime DurationDistribution `json:"cycleTime"`
+	Points    []DurationPoint      `json:"points"`
+}
+
+// ParseDurationWindow проверяет границы окна: RFC3339 или дату YYYY-MM-DD,
+// которая для until включает весь день. По умолчанию окно заканчивается в
+// now и охватывает DefaultDurationWindow.
+func ParseDurationWindow(since string, until string, now time.Time) (time.Time, time.Time, error) {
+	var errs ValidationErrors
+	end := now
+	if value := strings.TrimSpace(until); value != "" {
+		parsed, err := parseWindowBound("until", value, true)
+		errs.Add(err)
+		end = parsed
+	}
+	start := end.Add(-DefaultDurationWindow)
+	if value := strings.TrimSpace(since); value != "" {
+		parsed, err := parseWindowBound("since", value, false)
+		errs.Add(err)
+		start = parsed
+	}
+	if err := errs.Err(); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, NewFieldError(CodeInvalidWindow, "since", nil)
+	}
+	return start, end, nil
+}
+
+func parseWindowBound(field string, value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, NewFieldError(CodeInvalidWindowBound, field, map[string]any{"value": value})
+	}
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+// ComputeFlowTimes считает lead time и cycle time задач, завершённых в окне
+// [since, until]. Задачи без StartedAt учитываются только в lead time.
+func ComputeFlowTimes(tasks []domain.Task, since time.Time, until time.Time) FlowTimes {
+	return DefaultPolicy().FlowTimes(tasks, since, until)
+}
+
+// FlowTimes считает время выполнения в рабочих часах исполнителей.
+func (p Policy) FlowTimes(tasks []domain.Task, since time.Time, until time.Time) FlowTimes {
+	result := FlowTimes{Since: since, Until: until, Points: []DurationPoint{}}
+
+	var lead, cycle durationSample
+	for _, task := range tasks {
+		if task.Status != domain.StatusDone || task.CompletedAt == nil ||
+			task.CompletedAt.Before(since) || task.CompletedAt.After(until) {
+			continue
+		}
+		point := DurationPoint{
+			TaskID:      task.ID,
+			Title:       task.Title,
+			Priority:    task.Priority,
+			Owner:       task.Owner,
+			CompletedAt: *task.CompletedAt,
+			LeadHours:   p.hoursBetween(task.Owner, task.CreatedAt, *task.CompletedAt),
+		}
+		lead.add(task, point.LeadHours)
+		if task.StartedAt != nil {
+			hours := p.hoursBetween(task.Owner, *task.StartedAt, *task.CompletedAt)
+			point.CycleHours = &hours
+			cycle.add(task, hours)
+		}
+		result.Points = append(result.Points, point)
+	}
+
+	result.LeadTime = lead.distribution()
+	result.CycleTime = cycle.distribution()
+	for index := range result.Points {
+		point := &result.Points[index]
+		point.LeadOutlier = point.LeadHours > result.LeadTime.OutlierHours
+		point.CycleOutlier = point.CycleHours != nil && *point.CycleHours > result.CycleTime.OutlierHours
+	}
+	sort.Slice(result.Points, func(i, j int) bool {
+		if !result.Points[i].CompletedAt.Equal(result.Points[j].CompletedAt) {
+			return result.Points[i].CompletedAt.Before(result.Points[j].CompletedAt)
+		}
+		return result.Points[i].TaskID < result.Points[j].TaskID
+	})
+	return result
+}
+
+// SummarizeHours считает среднее и процентили с линейной интерполяцией
+// между соседними значениями.
+func SummarizeHours(hours []float64) DurationStats {
+	if len(hours) == 0 {
+		return DurationStats{}
+	}
+	sorted := append([]float64(nil), hours...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+	return DurationStats{
+		Count:       len(sorted),
+		MeanHours:   round2(sum / float64(len(sorted))),
+		MedianHours: round2(Percentile(sorted, 50)),
+		P85Hours:    round2(Percentile(sorted, 85)),
+		P95Hours:    round2(Percentile(sorted, 95)),
+	}
+}
+
+// Percentile возвращает p-й процентиль упорядоченных по возрастанию значений.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+type durationSample struct {
+	hours      []float64
+	byPriority map[string][]float64
+	byOwner    map[string][]float64
+}
+
+func (s *durationSample) add(task domain.Task, hours float64) {
+	if s.byPriority == nil {
+		s.byPriority = make(map[string][]float64)
+		s.byOwner = make(map[string][]float64)
+	}
+	s.hours = append(s.hours, hours)
+	s.byPriority[task.Priority] = append(s.byPriority[task.Priority], hours)
+	s.byOwner[task.Owner] = append(s.byOwner[task.Owner], hours)
+}
+
+func (s *durationSample) distribution() DurationDistribution {
+	distribution := DurationDistribution{
+		DurationStats: SummarizeHours(s.hours),
+		Histogram:     histogram(s.hours),
+		ByPriority:    make(map[string]DurationStats, len(s.byPriority)),
+		ByOwner:       make(map[string]DurationStats, len(s.byOwner)),
+	}
+	for priority, hours := range s.byPriority {
+		distribution.ByPriority[priority] = SummarizeHours(hours)
+	}
+	for owner, hours := range s.byOwner {
+		distribution.ByOwner[owner] = SummarizeHours(hours)
+	}
+	if len(s.hours) > 0 {
+		sorted := append([]float64(nil), s.hours...)
+		sort.Float64s(sorted)
+		q1, q3 := Percentile(sorted, 25), Percentile(sorted, 75)
+		distribution.OutlierHours = round2(q3 + 1.5*(q3-q1))
+	}
+	return distribution
+}
+
+func histogram(hours []float64) []HistogramBucket {
+	buckets := make([]HistogramBucket, len(DurationBuckets)+1)
+	from := 0.0
+	for index, bound := range DurationBuckets {
+		to := bound
+		buckets[index] = HistogramBucket{FromHours: from, ToHours: &to}
+		from = bound
+	}
+	buckets[len(DurationBuckets)] = HistogramBucket{FromHours: from}
+
+	for _, value := range hours {
+		index := sort.SearchFloat64s(DurationBuckets, value)
+		if index < len(DurationBuckets) && DurationBuckets[index] == value {
+			index++
+		}
+		buckets[index].Count++
+	}
+	return buckets
+}
+
+func (p Policy) hoursBetween(owner string, from time.Time, to time.Time) float64 {
+	hours := p.Hours(owner, from, to)
+	if hours < 0 {
+		return 0
+	}
+	return round2(hours)
+}
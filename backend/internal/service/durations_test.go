@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeHours(t *testing.T) {
+	require.Equal(t, DurationStats{}, SummarizeHours(nil))
+	require.Equal(t, DurationStats{Count: 1, MeanHours: 4, MedianHours: 4, P85Hours: 4, P95Hours: 4}, SummarizeHours([]float64{4}))
+	require.Equal(t, DurationStats{Count: 5, MeanHours: 22, MedianHours: 3, P85Hours: 42.4, P95Hours: 80.8}, SummarizeHours([]float64{100, 1, 3, 2, 4}))
+	require.Equal(t, 2.5, Percentile([]float64{1, 2, 3, 4}, 50))
+}
+
+func TestComputeFlowTimes(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		value := base.Add(time.Duration(hours) * time.Hour)
+		return &value
+	}
+	done := func(id uint, priority, owner string, created, started, completed int) domain.Task {
+		task := domain.Task{ID: id, Status: domain.StatusDone, Priority: priority, Owner: owner, CreatedAt: *at(created), CompletedAt: at(completed)}
+		if started >= 0 {
+			task.StartedAt = at(started)
+		}
+		return task
+	}
+	tasks := []domain.Task{
+		done(1, domain.PriorityHigh, "anna", 0, 2, 10),
+		done(2, domain.PriorityHigh, "anna", 0, 4, 12),
+		done(3, domain.PriorityLow, "ivan", 0, 6, 14),
+		done(4, domain.PriorityLow, "ivan", 0, -1, 16),
+		done(5, domain.PriorityLow, "ivan", 0, 18, 400),
+		// Завершена до начала окна.
+		done(6, domain.PriorityLow, "ivan", 0, 0, 1),
+		{ID: 7, Status: domain.StatusInProgress, CreatedAt: base, StartedAt: at(1)},
+	}
+
+	times := ComputeFlowTimes(tasks, base.Add(5*time.Hour), base.Add(500*time.Hour))
+	require.Len(t, times.Points, 5)
+	require.Equal(t, uint(1), times.Points[0].TaskID)
+	require.Equal(t, 10.0, times.Points[0].LeadHours)
+	require.Equal(t, 8.0, *times.Points[0].CycleHours)
+	require.Nil(t, times.Points[3].CycleHours)
+	require.False(t, times.Points[3].CycleOutlier)
+	require.True(t, times.Points[4].LeadOutlier)
+	require.True(t, times.Points[4].CycleOutlier)
+	require.False(t, times.Points[2].LeadOutlier)
+
+	require.Equal(t, 5, times.LeadTime.Count)
+	require.Equal(t, 14.0, times.LeadTime.MedianHours)
+	require.Equal(t, 22.0, times.LeadTime.OutlierHours)
+	require.Equal(t, 4, times.CycleTime.Count)
+	require.Equal(t, DurationStats{Count: 2, MeanHours: 11, MedianHours: 11, P85Hours: 11.7, P95Hours: 11.9}, times.LeadTime.ByPriority[domain.PriorityHigh])
+	require.Equal(t, 3, times.LeadTime.ByOwner["ivan"].Count)
+
+	counts := make([]int, 0, len(times.LeadTime.Histogram))
+	for _, bucket := range times.LeadTime.Histogram {
+		counts = append(counts, bucket.Count)
+	}
+	require.Equal(t, []int{0, 0, 4, 0, 0, 0, 1, 0}, counts)
+	require.Equal(t, 672.0, times.LeadTime.Histogram[7].FromHours)
+	require.Nil(t, times.LeadTime.Histogram[7].ToHours)
+	require.Equal(t, 8.0, *times.LeadTime.Histogram[1].ToHours)
+}
+
+func TestParseDurationWindow(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	since, until, err := ParseDurationWindow("", "", now)
+	require.NoError(t, err)
+	require.Equal(t, now, until)
+	require.Equal(t, now.Add(-DefaultDurationWindow), since)
+
+	since, until, err = ParseDurationWindow("2026-03-01", "2026-03-05", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), since)
+	require.Equal(t, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond), until)
+
+	since, _, err = ParseDurationWindow("2026-03-01T10:00:00+03:00", "", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC), since)
+
+	_, _, err = ParseDurationWindow("yesterday", "soon", now)
+	var list ValidationErrors
+	require.ErrorAs(t, err, &list)
+	require.Len(t, list, 2)
+	require.Equal(t, CodeInvalidWindowBound, list[0].Code)
+
+	_, _, err = ParseDurationWindow("2026-03-06", "2026-03-05", now)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidWindow, fieldErr.Code)
+}
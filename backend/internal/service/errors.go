@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"devopslabs/internal/i18n"
+)
+
+const (
+	CodeTitleRequired        = "title_required"
+	CodeTitleTooLong         = "title_too_long"
+	CodeInvalidStatus        = "invalid_status"
+	CodeInvalidPriority      = "invalid_priority"
+	CodeTagTooLong           = "tag_too_long"
+	CodeTooManyTags          = "too_many_tags"
+	CodeInvalidTags          = "invalid_tags"
+	CodeInvalidDueDate       = "invalid_due_date"
+	CodeInvalidEffort        = "invalid_effort"
+	CodeUnknownCurrentStatus = "unknown_current_status"
+	CodeInvalidTransition    = "invalid_transition"
+	CodeTaskRequired         = "task_required"
+	CodeInvalidValue         = "invalid_value"
+)
+
+// FieldError — ошибка проверки входных данных со стабильным кодом.
+// Текст ошибки строится по каталогу сообщений, поэтому его можно
+// локализовать на стороне транспорта через Localize.
+type FieldError struct {
+	Code    string
+	Field   string
+	Details map[string]any
+}
+
+func NewFieldError(code string, field string, details map[string]any) *FieldError {
+	return &FieldError{Code: code, Field: field, Details: details}
+}
+
+func (e *FieldError) Error() string {
+	return e.Localize(i18n.DefaultLanguage)
+}
+
+func (e *FieldError) Localize(language string) string {
+	return i18n.Translate(language, e.Code, e.Details)
+}
+
+// ValidationErrors собирает все ошибки проверки вместо первой найденной.
+// Повтор ошибки с тем же кодом для того же поля не добавляется.
+type ValidationErrors []*FieldError
+
+// Add добавляет ошибку; ошибки без кода оборачиваются в CodeInvalidValue.
+func (e *ValidationErrors) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	var list ValidationErrors
+	var fieldErr *FieldError
+	switch {
+	case errors.As(err, &list):
+		for _, item := range list {
+			e.Add(item)
+		}
+		return
+	case !errors.As(err, &fieldErr):
+		fieldErr = NewFieldError(CodeInvalidValue, "", map[string]any{"value": err.Error()})
+	}
+
+	for _, existing := range *e {
+		if existing.Code == fieldErr.Code && rootField(existing.Field) == rootField(fieldErr.Field) {
+			return
+		}
+	}
+	*e = append(*e, fieldErr)
+}
+
+// Err возвращает nil для пустого списка, единственную FieldError как есть,
+// а несколько ошибок — списком.
+func (e ValidationErrors) Err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	default:
+		return e
+	}
+}
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, item := range e {
+		messages = append(messages, item.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func rootField(field string) string {
+	root, _, _ := strings.Cut(field, ".")
+	return root
+}
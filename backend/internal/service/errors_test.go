@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrorsCollectsAndDeduplicates(t *testing.T) {
+	var errs ValidationErrors
+	require.NoError(t, errs.Err())
+
+	errs.Add(nil)
+	errs.Add(NewFieldError(CodeTitleRequired, "title", nil))
+	require.Equal(t, NewFieldError(CodeTitleRequired, "title", nil), errs.Err())
+
+	errs.Add(NewFieldError(CodeTagTooLong, "tags.0", map[string]any{"value": "long", "max": MaxTagLength}))
+	errs.Add(NewFieldError(CodeTagTooLong, "tags", nil))
+	errs.Add(ValidationErrors{NewFieldError(CodeTitleRequired, "title", nil), NewFieldError(CodeInvalidStatus, "status", map[string]any{"value": "x"})})
+	errs.Add(errors.New("boom"))
+
+	require.Len(t, errs, 4)
+	require.Equal(t, CodeInvalidValue, errs[3].Code)
+	require.Equal(t, "boom", errs[3].Details["value"])
+
+	var list ValidationErrors
+	require.ErrorAs(t, errs.Err(), &list)
+	require.Equal(t, "нужно указать название; слишком длинный тег: long; некорректный статус: x; недопустимое значение: boom", errs.Error())
+}
@@ -1,12 +1,12 @@
 package service
 
 import (
-	"errors"
-	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"devopslabs/internal/domain"
 )
@@ -25,6 +25,7 @@ const (
 	DefaultPriority = domain.PriorityMedium
 	MaxTags         = 8
 	MaxTagLength    = 24
+	MaxTitleLength  = 200
 )
 
 type Clock interface {
@@ -45,16 +46,22 @@ func (c FixedClock) Now() time.Time {
 	return c.NowValue
 }
 
+var SortFields = []string{"score", "priority", "due_date", "created_at", "updated_at", "title"}
+
 type SortOption struct {
 	By    string
 	Order string
 }
 
+// TaskMetrics — вычисляемые показатели задачи. AgeHours считается от
+// создания, StatusHours — от перехода в текущий статус.
 type TaskMetrics struct {
-	Risk       string   `json:"risk"`
-	Score      float64  `json:"score"`
-	AgeHours   float64  `json:"ageHours"`
-	CycleHours *float64 `json:"cycleHours,omitempty"`
+	Risk        string     `json:"risk"`
+	Score       float64    `json:"score"`
+	AgeHours    float64    `json:"ageHours"`
+	StatusHours float64    `json:"statusHours"`
+	CycleHours  *float64   `json:"cycleHours,omitempty"`
+	SLA         *SLAStatus `json:"sla,omitempty"`
 }
 
 type Insights struct {
@@ -69,6 +76,21 @@ type Insights struct {
 	AverageCycleHours float64        `json:"averageCycleHours"`
 	WorkloadHours     int            `json:"workloadHours"`
 	FocusIndex        float64        `json:"focusIndex"`
+	// SLABreached — задачи с нарушенной целью SLA, SLAImminent — задачи
+	// без нарушений, у которых нарушение близко.
+	SLABreached int `json:"slaBreached"`
+	SLAImminent int `json:"slaImminent"`
+}
+
+func NormalizeTitle(input string) (string, error) {
+	value := strings.TrimSpace(input)
+	if value == "" {
+		return "", NewFieldError(CodeTitleRequired, "title", nil)
+	}
+	if utf8.RuneCountInString(value) > MaxTitleLength {
+		return "", NewFieldError(CodeTitleTooLong, "title", map[string]any{"max": MaxTitleLength})
+	}
+	return value, nil
 }
 
 func NormalizeStatus(input string) (string, error) {
@@ -77,7 +99,7 @@ func NormalizeStatus(input string) (string, error) {
 		return DefaultStatus, nil
 	}
 	if !domain.AllowedStatuses[value] {
-		return "", fmt.Errorf("некорректный статус: %s", value)
+		return "", NewFieldError(CodeInvalidStatus, "status", map[string]any{"value": value})
 	}
 	return value, nil
 }
@@ -88,7 +110,7 @@ func NormalizePriority(input string) (string, error) {
 		return DefaultPriority, nil
 	}
 	if !domain.AllowedPriorities[value] {
-		return "", fmt.Errorf("некорректный приоритет: %s", value)
+		return "", NewFieldError(CodeInvalidPriority, "priority", map[string]any{"value": value})
 	}
 	return value, nil
 }
@@ -105,8 +127,8 @@ func NormalizeTags(tags []string) (domain.StringList, error) {
 		if value == "" {
 			continue
 		}
-		if len(value) > MaxTagLength {
-			return nil, fmt.Errorf("слишком длинный тег: %s", value)
+		if utf8.RuneCountInString(value) > MaxTagLength {
+			return nil, NewFieldError(CodeTagTooLong, "tags", map[string]any{"value": value, "max": MaxTagLength})
 		}
 		if !unique[value] {
 			unique[value] = true
@@ -115,7 +137,7 @@ func NormalizeTags(tags []string) (domain.StringList, error) {
 	}
 
 	if len(result) > MaxTags {
-		return nil, fmt.Errorf("слишком много тегов: %d", len(result))
+		return nil, NewFieldError(CodeTooManyTags, "tags", map[string]any{"count": len(result), "max": MaxTags})
 	}
 
 	return domain.StringList(result), nil
@@ -127,10 +149,7 @@ func NormalizeSort(by string, order string) SortOption {
 		value = "score"
 	}
 
-	switch value {
-	case "score", "priority", "due_date", "created_at", "updated_at", "title":
-		// allowed
-	default:
+	if !slices.Contains(SortFields, value) {
 		value = "score"
 	}
 
@@ -142,7 +161,12 @@ func NormalizeSort(by string, order string) SortOption {
 	return SortOption{By: value, Order: ord}
 }
 
+// SortTasks упорядочивает задачи по правилам DefaultPolicy.
 func SortTasks(tasks []domain.Task, option SortOption, now time.Time) {
+	DefaultPolicy().Sort(tasks, option, now)
+}
+
+func (p Policy) Sort(tasks []domain.Task, option SortOption, now time.Time) {
 	if len(tasks) < 2 {
 		return
 	}
@@ -153,7 +177,7 @@ func SortTasks(tasks []domain.Task, option SortOption, now time.Time) {
 	case "score":
 		scores := make(map[uint]float64, len(tasks))
 		for _, task := range tasks {
-			scores[task.ID] = ComputeScore(now, task)
+			scores[task.ID] = p.Score(now, task)
 		}
 		sort.SliceStable(tasks, func(i, j int) bool {
 			left := scores[tasks[i].ID]
@@ -233,17 +257,29 @@ func compareTime(left, right time.Time, desc bool) bool {
 }
 
 func ComputeMetrics(now time.Time, task domain.Task) TaskMetrics {
+	return DefaultPolicy().Metrics(now, task)
+}
+
+func (p Policy) Metrics(now time.Time, task domain.Task) TaskMetrics {
 	age := 0.0
 	if !task.CreatedAt.IsZero() {
-		age = now.Sub(task.CreatedAt).Hours()
+		age = p.Hours(task.Owner, task.CreatedAt, now)
 		if age < 0 {
 			age = 0
 		}
 	}
 
+	inStatus := 0.0
+	if since := StatusSince(task); !since.IsZero() {
+		inStatus = p.Hours(task.Owner, since, now)
+		if inStatus < 0 {
+			inStatus = 0
+		}
+	}
+
 	var cycle *float64
 	if task.StartedAt != nil && task.CompletedAt != nil {
-		value := task.CompletedAt.Sub(*task.StartedAt).Hours()
+		value := p.Hours(task.Owner, *task.StartedAt, *task.CompletedAt)
 		if value < 0 {
 			value = 0
 		}
@@ -251,64 +287,28 @@ func ComputeMetrics(now time.Time, task domain.Task) TaskMetrics {
 	}
 
 	return TaskMetrics{
-		Risk:       ComputeRisk(now, task),
-		Score:      ComputeScore(now, task),
-		AgeHours:   round2(age),
-		CycleHours: cycle,
+		Risk:        p.Risk(now, task),
+		Score:       p.Score(now, task),
+		AgeHours:    round2(age),
+		StatusHours: round2(inStatus),
+		CycleHours:  cycle,
+		SLA:         p.TaskSLA(now, task),
 	}
 }
 
 func ComputeRisk(now time.Time, task domain.Task) string {
-	switch task.Status {
-	case domain.StatusDone:
-		return RiskCompleted
-	case domain.StatusBlocked:
-		return RiskBlocked
-	}
-
-	if task.DueDate == nil {
-		return RiskUnassigned
-	}
-
-	if task.DueDate.Before(now) {
-		return RiskOverdue
-	}
-
-	if task.DueDate.Sub(now) <= 48*time.Hour {
-		return RiskAtRisk
-	}
-
-	return RiskOnTrack
+	return DefaultPolicy().Risk(now, task)
 }
 
 func ComputeScore(now time.Time, task domain.Task) float64 {
-	priorityWeight := domain.PriorityWeights[task.Priority]
-	score := float64(priorityWeight) * 10
-
-	if task.DueDate != nil {
-		hours := task.DueDate.Sub(now).Hours()
-		switch {
-		case hours <= 0:
-			score += 20
-		case hours <= 48:
-			score += 10
-		case hours <= 96:
-			score += 5
-		}
-	}
-
-	if task.Status == domain.StatusBlocked {
-		score += 7
-	}
-	if task.Status == domain.StatusDone {
-		score -= 5
-	}
-
-	score += float64(task.EffortHours) * 0.1
-	return round1(score)
+	return DefaultPolicy().Score(now, task)
 }
 
 func ComputeInsights(now time.Time, tasks []domain.Task) Insights {
+	return DefaultPolicy().Insights(now, tasks)
+}
+
+func (p Policy) Insights(now time.Time, tasks []domain.Task) Insights {
 	insights := Insights{
 		Total:      len(tasks),
 		ByStatus:   make(map[string]int),
@@ -323,7 +323,7 @@ func ComputeInsights(now time.Time, tasks []domain.Task) Insights {
 		insights.ByStatus[task.Status]++
 		insights.ByPriority[task.Priority]++
 
-		metrics := ComputeMetrics(now, task)
+		metrics := p.Metrics(now, task)
 		switch metrics.Risk {
 		case RiskOverdue:
 			insights.Overdue++
@@ -335,6 +335,14 @@ func ComputeInsights(now time.Time, tasks []domain.Task) Insights {
 			insights.Done++
 		}
 
+		switch {
+		case metrics.SLA == nil:
+		case metrics.SLA.Breached:
+			insights.SLABreached++
+		case metrics.SLA.Imminent:
+			insights.SLAImminent++
+		}
+
 		ageSum += metrics.AgeHours
 		if metrics.CycleHours != nil {
 			cycleSum += *metrics.CycleHours
@@ -386,17 +394,17 @@ func ValidateTransition(from string, to string, force bool) error {
 	}
 	allowed, ok := allowedTransitions[from]
 	if !ok {
-		return fmt.Errorf("неизвестный текущий статус: %s", from)
+		return NewFieldError(CodeUnknownCurrentStatus, "status", map[string]any{"from": from})
 	}
 	if !allowed[to] {
-		return fmt.Errorf("переход недопустим: %s -> %s", from, to)
+		return NewFieldError(CodeInvalidTransition, "status", map[string]any{"from": from, "to": to})
 	}
 	return nil
 }
 
 func ApplyStatusTransition(now time.Time, task *domain.Task, newStatus string, force bool) error {
 	if task == nil {
-		return errors.New("задача не задана")
+		return NewFieldError(CodeTaskRequired, "", nil)
 	}
 
 	status, err := NormalizeStatus(newStatus)
@@ -410,6 +418,10 @@ func ApplyStatusTransition(now time.Time, task *domain.Task, newStatus string, f
 		}
 	}
 
+	if task.Status != status {
+		stamp := now
+		task.StatusChangedAt = &stamp
+	}
 	task.Status = status
 
 	switch status {
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -225,6 +226,11 @@ func TestValidateAndApplyTransition(t *testing.T) {
 	require.Equal(t, domain.StatusInProgress, task.Status)
 	require.NotNil(t, task.StartedAt)
 	require.Nil(t, task.CompletedAt)
+	require.Equal(t, now, *task.StatusChangedAt)
+
+	// Тот же статус не сдвигает момент перехода.
+	require.NoError(t, ApplyStatusTransition(now.Add(time.Hour), task, domain.StatusInProgress, false))
+	require.Equal(t, now, *task.StatusChangedAt)
 
 	require.NoError(t, ApplyStatusTransition(now, task, domain.StatusDone, false))
 	require.Equal(t, domain.StatusDone, task.Status)
@@ -328,3 +334,78 @@ func TestSortTasks(t *testing.T) {
 func ptrTime(value time.Time) *time.Time {
 	return &value
 }
+
+func TestValidationErrorsCarryCodes(t *testing.T) {
+	_, err := NormalizeStatus("weird")
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidStatus, fieldErr.Code)
+	require.Equal(t, "status", fieldErr.Field)
+	require.Equal(t, "некорректный статус: weird", err.Error())
+	require.Equal(t, "invalid status: weird", fieldErr.Localize("en"))
+
+	_, err = NormalizeTitle("   ")
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeTitleRequired, fieldErr.Code)
+
+	_, err = NormalizeTitle(strings.Repeat("a", MaxTitleLength+1))
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeTitleTooLong, fieldErr.Code)
+
+	// Длина считается в символах, как maxLength в схеме API.
+	title, err := NormalizeTitle(strings.Repeat("я", MaxTitleLength))
+	require.NoError(t, err)
+	require.Len(t, title, 2*MaxTitleLength)
+	_, err = NormalizeTitle(strings.Repeat("я", MaxTitleLength+1))
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeTitleTooLong, fieldErr.Code)
+	tags, err := NormalizeTags([]string{strings.Repeat("ё", MaxTagLength)})
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+
+	title, err = NormalizeTitle("  Ship it ")
+	require.NoError(t, err)
+	require.Equal(t, "Ship it", title)
+
+	err = ValidateTransition(domain.StatusTodo, domain.StatusDone, false)
+	require.ErrorAs(t, err, &fieldErr)
+	require.Equal(t, CodeInvalidTransition, fieldErr.Code)
+	require.Equal(t, map[string]any{"from": domain.StatusTodo, "to": domain.StatusDone}, fieldErr.Details)
+}
+
+func TestNormalizeEffort(t *testing.T) {
+	_, err := NormalizeEffort(-1)
+	require.Error(t, err)
+
+	effort, err := NormalizeEffort(0)
+	require.NoError(t, err)
+	require.Equal(t, DefaultEffortHours, effort)
+
+	effort, err = NormalizeEffort(5)
+	require.NoError(t, err)
+	require.Equal(t, 5, effort)
+
+	_, err = NormalizeEffort(999)
+	require.Error(t, err)
+}
+
+func TestNormalizeTaskInputCollectsErrors(t *testing.T) {
+	input, err := NormalizeTaskInput(TaskInput{Title: "  Ship  ", Owner: " ", Tags: []string{"CI", "ci"}})
+	require.NoError(t, err)
+	require.Equal(t, "Ship", input.Title)
+	require.Equal(t, DefaultOwner, input.Owner)
+	require.Equal(t, DefaultStatus, input.Status)
+	require.Equal(t, DefaultEffortHours, input.EffortHours)
+	require.Equal(t, []string{"ci"}, input.Tags)
+
+	var task domain.Task
+	input.ApplyTo(&task)
+	require.Equal(t, "Ship", task.Title)
+	require.Empty(t, task.Status)
+	require.Equal(t, input, InputFromTask(domain.Task{Title: "Ship", Status: DefaultStatus, Priority: DefaultPriority, Owner: DefaultOwner, EffortHours: 1, Tags: domain.StringList{"ci"}}))
+
+	_, err = NormalizeTaskInput(TaskInput{Status: "weird", EffortHours: -1})
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 3)
+}
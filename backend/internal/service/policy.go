@@ -0,0 +1,244 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/worktime"
+)
+
+// Слагаемые оценки задачи.
+const (
+	TermPriority = "priority"
+	TermDue      = "due"
+	TermStatus   = "status"
+	TermEffort   = "effort"
+)
+
+// DueBand начисляет Points задаче, до срока которой осталось не больше
+// WithinHours рабочих часов. Полоса с WithinHours 0 достаётся только
+// просроченным задачам; задача, до срока которой остались одни нерабочие
+// часы, попадает в следующую полосу.
+type DueBand struct {
+	WithinHours float64 `json:"withinHours"`
+	Points      float64 `json:"points"`
+}
+
+// Policy задаёт оценку задачи и границу риска. Оценка складывается из очков
+// за приоритет, первой подходящей полосы срока, очков за статус и
+// трудоёмкости, умноженной на EffortWeight. Задача в статусе at_risk, если до
+// срока осталось не больше AtRiskHours рабочих часов.
+//
+// Часы до срока, возраст и время выполнения задачи считаются по рабочему
+// календарю её исполнителя из Calendars; без календарей — астрономические.
+type Policy struct {
+	PriorityPoints map[string]float64  `json:"priorityPoints"`
+	DueBands       []DueBand           `json:"dueBands"`
+	StatusPoints   map[string]float64  `json:"statusPoints"`
+	EffortWeight   float64             `json:"effortWeight"`
+	AtRiskHours    float64             `json:"atRiskHours"`
+	SLA            SLAPolicy           `json:"sla"`
+	Calendars      *worktime.Calendars `json:"-"`
+}
+
+// DefaultPolicy возвращает исходные правила сервиса.
+func DefaultPolicy() Policy {
+	return Policy{
+		PriorityPoints: map[string]float64{
+			domain.PriorityLow:      10,
+			domain.PriorityMedium:   20,
+			domain.PriorityHigh:     30,
+			domain.PriorityCritical: 40,
+		},
+		DueBands: []DueBand{
+			{WithinHours: 0, Points: 20},
+			{WithinHours: 48, Points: 10},
+			{WithinHours: 96, Points: 5},
+		},
+		StatusPoints: map[string]float64{
+			domain.StatusBlocked: 7,
+			domain.StatusDone:    -5,
+		},
+		EffortWeight: 0.1,
+		AtRiskHours:  48,
+		SLA:          SLAPolicy{Targets: map[string]SLATarget{}, WarnRatio: DefaultSLAWarnRatio},
+	}
+}
+
+// OrDefault возвращает DefaultPolicy для nil; так конструкторы принимают
+// необязательную политику, как и часы.
+func (p *Policy) OrDefault() Policy {
+	if p == nil {
+		return DefaultPolicy()
+	}
+	return *p
+}
+
+// ParsePolicy читает политику из JSON. Незаданные поля берутся из
+// DefaultPolicy, а ключи priorityPoints и statusPoints дополняют исходные.
+func ParsePolicy(data []byte) (Policy, error) {
+	policy := DefaultPolicy()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return Policy{}, fmt.Errorf("политика оценки: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return Policy{}, err
+	}
+	return policy, nil
+}
+
+// LoadPolicy читает политику из файла; пустой путь означает DefaultPolicy.
+func LoadPolicy(path string) (Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("политика оценки: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+func (p Policy) Validate() error {
+	for priority := range p.PriorityPoints {
+		if !domain.AllowedPriorities[priority] {
+			return fmt.Errorf("политика оценки: неизвестный приоритет %q", priority)
+		}
+	}
+	for status := range p.StatusPoints {
+		if !domain.AllowedStatuses[status] {
+			return fmt.Errorf("политика оценки: неизвестный статус %q", status)
+		}
+	}
+	for index, band := range p.DueBands {
+		if band.WithinHours < 0 {
+			return fmt.Errorf("политика оценки: граница полосы срока не может быть отрицательной")
+		}
+		if index > 0 && band.WithinHours <= p.DueBands[index-1].WithinHours {
+			return fmt.Errorf("политика оценки: полосы срока должны идти по возрастанию withinHours")
+		}
+	}
+	if p.AtRiskHours < 0 {
+		return fmt.Errorf("политика оценки: atRiskHours не может быть отрицательным")
+	}
+	return p.SLA.validate()
+}
+
+// ScoreTerm — слагаемое оценки: Input показывает значение задачи, за
+// которое начислены Points.
+type ScoreTerm struct {
+	Term   string  `json:"term"`
+	Input  string  `json:"input"`
+	Points float64 `json:"points"`
+}
+
+// ScoreBreakdown объясняет оценку и риск задачи на момент At.
+type ScoreBreakdown struct {
+	TaskID        uint        `json:"taskId"`
+	At            time.Time   `json:"at"`
+	Score         float64     `json:"score"`
+	Risk          string      `json:"risk"`
+	HoursUntilDue *float64    `json:"hoursUntilDue,omitempty"`
+	Terms         []ScoreTerm `json:"terms"`
+	Policy        Policy      `json:"policy"`
+}
+
+// Explain раскладывает оценку задачи на слагаемые; их сумма, округлённая до
+// десятых, равна Score.
+func (p Policy) Explain(now time.Time, task domain.Task) ScoreBreakdown {
+	breakdown := ScoreBreakdown{TaskID: task.ID, At: now, Risk: p.Risk(now, task), Policy: p}
+	breakdown.Terms = append(breakdown.Terms, ScoreTerm{Term: TermPriority, Input: task.Priority, Points: p.PriorityPoints[task.Priority]})
+
+	if task.DueDate != nil {
+		hours := p.hoursUntilDue(now, task)
+		rounded := round2(hours)
+		breakdown.HoursUntilDue = &rounded
+		if band, ok := p.dueBand(hours, task.DueDate.Before(now)); ok {
+			input := "<=" + strconv.FormatFloat(band.WithinHours, 'f', -1, 64) + "h"
+			if task.DueDate.Before(now) {
+				input = RiskOverdue
+			}
+			breakdown.Terms = append(breakdown.Terms, ScoreTerm{Term: TermDue, Input: input, Points: band.Points})
+		}
+	}
+
+	if points, ok := p.StatusPoints[task.Status]; ok {
+		breakdown.Terms = append(breakdown.Terms, ScoreTerm{Term: TermStatus, Input: task.Status, Points: points})
+	}
+	breakdown.Terms = append(breakdown.Terms, ScoreTerm{
+		Term:   TermEffort,
+		Input:  strconv.Itoa(task.EffortHours),
+		Points: float64(task.EffortHours) * p.EffortWeight,
+	})
+
+	score := 0.0
+	for index := range breakdown.Terms {
+		score += breakdown.Terms[index].Points
+		breakdown.Terms[index].Points = round2(breakdown.Terms[index].Points)
+	}
+	breakdown.Score = round1(score)
+	return breakdown
+}
+
+func (p Policy) Score(now time.Time, task domain.Task) float64 {
+	return p.Explain(now, task).Score
+}
+
+func (p Policy) Risk(now time.Time, task domain.Task) string {
+	switch task.Status {
+	case domain.StatusDone:
+		return RiskCompleted
+	case domain.StatusBlocked:
+		return RiskBlocked
+	}
+
+	if task.DueDate == nil {
+		return RiskUnassigned
+	}
+
+	if task.DueDate.Before(now) {
+		return RiskOverdue
+	}
+
+	// Если до срока остались только нерабочие часы, задача не считается
+	// близкой к сроку.
+	if hours := p.hoursUntilDue(now, task); hours > 0 && hours <= p.AtRiskHours {
+		return RiskAtRisk
+	}
+
+	return RiskOnTrack
+}
+
+// Hours возвращает рабочие часы исполнителя owner между from и to.
+func (p Policy) Hours(owner string, from time.Time, to time.Time) float64 {
+	return p.Calendars.For(owner).Hours(from, to)
+}
+
+// hoursUntilDue считает рабочие часы до срока; у просроченной задачи —
+// отрицательные астрономические часы, чтобы выходные не скрывали просрочку.
+func (p Policy) hoursUntilDue(now time.Time, task domain.Task) float64 {
+	if task.DueDate.Before(now) {
+		return task.DueDate.Sub(now).Hours()
+	}
+	return p.Hours(task.Owner, now, *task.DueDate)
+}
+
+// dueBand ищет первую полосу срока; Validate гарантирует их порядок.
+func (p Policy) dueBand(hours float64, overdue bool) (DueBand, bool) {
+	for _, band := range p.DueBands {
+		if band.WithinHours <= 0 && !overdue {
+			continue
+		}
+		if hours <= band.WithinHours {
+			return band, true
+		}
+	}
+	return DueBand{}, false
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/worktime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyExplainMatchesScore(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	dueDate := now.Add(30 * time.Hour)
+	task := domain.Task{ID: 7, Status: domain.StatusBlocked, Priority: domain.PriorityHigh, EffortHours: 12, DueDate: &dueDate}
+
+	breakdown := DefaultPolicy().Explain(now, task)
+	require.Equal(t, []ScoreTerm{
+		{Term: TermPriority, Input: domain.PriorityHigh, Points: 30},
+		{Term: TermDue, Input: "<=48h", Points: 10},
+		{Term: TermStatus, Input: domain.StatusBlocked, Points: 7},
+		{Term: TermEffort, Input: "12", Points: 1.2},
+	}, breakdown.Terms)
+	require.Equal(t, 48.2, breakdown.Score)
+	require.Equal(t, ComputeScore(now, task), breakdown.Score)
+	require.Equal(t, RiskBlocked, breakdown.Risk)
+	require.Equal(t, 30.0, *breakdown.HoursUntilDue)
+
+	overdue := now.Add(-time.Hour)
+	breakdown = DefaultPolicy().Explain(now, domain.Task{Status: domain.StatusTodo, Priority: domain.PriorityLow, DueDate: &overdue})
+	require.Equal(t, ScoreTerm{Term: TermDue, Input: RiskOverdue, Points: 20}, breakdown.Terms[1])
+	require.Equal(t, 30.0, breakdown.Score)
+}
+
+func TestParsePolicyOverridesDefaults(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	dueDate := now.Add(60 * time.Hour)
+	task := domain.Task{Status: domain.StatusTodo, Priority: domain.PriorityCritical, DueDate: &dueDate}
+
+	policy, err := ParsePolicy([]byte(`{"priorityPoints":{"critical":100},"dueBands":[{"withinHours":0,"points":50}],"atRiskHours":72}`))
+	require.NoError(t, err)
+	require.Equal(t, 20.0, policy.PriorityPoints[domain.PriorityMedium])
+	require.Equal(t, 0.1, policy.EffortWeight)
+	require.Equal(t, 100.0, policy.Score(now, task))
+	require.Equal(t, RiskAtRisk, policy.Risk(now, task))
+	require.Equal(t, RiskOnTrack, ComputeRisk(now, task))
+
+	for _, data := range []string{
+		`{"priorityPoints":{"urgent":1}}`,
+		`{"statusPoints":{"archived":1}}`,
+		`{"dueBands":[{"withinHours":48,"points":1},{"withinHours":24,"points":2}]}`,
+		`{"dueBands":[{"withinHours":-1,"points":1}]}`,
+		`{"atRiskHours":-1}`,
+		`{"weights":{}}`,
+		`not json`,
+	} {
+		_, err := ParsePolicy([]byte(data))
+		require.Error(t, err, data)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	policy, err := LoadPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultPolicy(), policy)
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"effortWeight":0.5}`), 0o600))
+	policy, err = LoadPolicy(path)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, policy.EffortWeight)
+
+	_, err = LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestPolicyUsesWorkingCalendars(t *testing.T) {
+	calendars, err := worktime.Parse([]byte(`{"owners": {"olga": {"weekdays": ["mon","tue","wed","thu","fri","sat","sun"]}}}`), "")
+	require.NoError(t, err)
+	policy := DefaultPolicy()
+	policy.Calendars = calendars
+	policy.AtRiskHours = 9
+
+	// Суббота; срок — вторник 12:00, до него рабочий день и три часа.
+	now := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+	dueDate := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	created := time.Date(2026, 3, 6, 17, 0, 0, 0, time.UTC)
+	task := domain.Task{Owner: "anna", Status: domain.StatusTodo, Priority: domain.PriorityLow, DueDate: &dueDate, CreatedAt: created}
+
+	breakdown := policy.Explain(now, task)
+	require.Equal(t, 12.0, *breakdown.HoursUntilDue)
+	require.Equal(t, RiskOnTrack, breakdown.Risk)
+	require.Equal(t, ScoreTerm{Term: TermDue, Input: "<=48h", Points: 10}, breakdown.Terms[1])
+	require.Equal(t, 1.0, policy.Metrics(now, task).AgeHours)
+	require.Equal(t, RiskAtRisk, policy.Risk(now.Add(72*time.Hour-time.Hour), task))
+
+	// Срок — понедельник 08:00, до начала рабочего дня: рабочих часов нет, но
+	// задача не просрочена.
+	monday := time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)
+	task.DueDate = &monday
+	breakdown = policy.Explain(now, task)
+	require.Equal(t, 0.0, *breakdown.HoursUntilDue)
+	require.Equal(t, RiskOnTrack, breakdown.Risk)
+	require.Equal(t, ScoreTerm{Term: TermDue, Input: "<=48h", Points: 10}, breakdown.Terms[1])
+	breakdown = policy.Explain(monday.Add(time.Hour), task)
+	require.Equal(t, RiskOverdue, breakdown.Risk)
+	require.Equal(t, ScoreTerm{Term: TermDue, Input: RiskOverdue, Points: 20}, breakdown.Terms[1])
+	task.DueDate = &dueDate
+
+	// У olga рабочие все дни недели.
+	task.Owner = "olga"
+	require.Equal(t, 6+9+9+3.0, *policy.Explain(now, task).HoursUntilDue)
+
+	// Просрочка видна и в выходные.
+	overdue := time.Date(2026, 3, 6, 20, 0, 0, 0, time.UTC)
+	task.DueDate = &overdue
+	breakdown = policy.Explain(now, task)
+	require.Equal(t, RiskOverdue, breakdown.Risk)
+	require.Equal(t, ScoreTerm{Term: TermDue, Input: RiskOverdue, Points: 20}, breakdown.Terms[1])
+
+	startedAt := time.Date(2026, 3, 6, 16, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, 3, 9, 11, 0, 0, 0, time.UTC)
+	done := domain.Task{ID: 1, Owner: "anna", Status: domain.StatusDone, CreatedAt: created, StartedAt: &startedAt, CompletedAt: &completedAt}
+	flow := policy.FlowTimes([]domain.Task{done}, created, now.Add(72*time.Hour))
+	require.Equal(t, 3.0, flow.Points[0].LeadHours)
+	require.Equal(t, 4.0, *flow.Points[0].CycleHours)
+}
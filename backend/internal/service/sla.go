@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+// Цели SLA.
+const (
+	SLAResponse = "response"
+	SLAResolve  = "resolve"
+)
+
+// Состояния часов SLA.
+const (
+	SLARunning  = "running"
+	SLAImminent = "imminent"
+	SLAMet      = "met"
+	SLABreached = "breached"
+)
+
+// DefaultSLAWarnRatio — доля цели, после которой нарушение считается близким.
+const DefaultSLAWarnRatio = 0.8
+
+// SLATarget — цели приоритета в рабочих часах от создания задачи:
+// ResponseHours до начала работы, ResolveHours до завершения. 0 — цели нет.
+type SLATarget struct {
+	ResponseHours float64 `json:"responseHours"`
+	ResolveHours  float64 `json:"resolveHours"`
+}
+
+// SLAPolicy задаёт цели по приоритетам. Часы, на которых прошло не меньше
+// WarnRatio цели, переходят в состояние imminent.
+type SLAPolicy struct {
+	Targets   map[string]SLATarget `json:"targets"`
+	WarnRatio float64              `json:"warnRatio"`
+}
+
+// SLAClock — часы одной цели. Часы останавливаются в StartedAt для response
+// (или в CompletedAt, если работа не начиналась) и в CompletedAt для
+// resolve; RemainingHours после нарушения отрицательные.
+type SLAClock struct {
+	TargetHours    float64 `json:"targetHours"`
+	ElapsedHours   float64 `json:"elapsedHours"`
+	RemainingHours float64 `json:"remainingHours"`
+	State          string  `json:"state"`
+	Breached       bool    `json:"breached"`
+}
+
+// SLAStatus — состояние SLA задачи; у приоритета без цели часов нет.
+type SLAStatus struct {
+	Response *SLAClock `json:"response,omitempty"`
+	Resolve  *SLAClock `json:"resolve,omitempty"`
+	Breached bool      `json:"breached"`
+	Imminent bool      `json:"imminent"`
+}
+
+// Clock возвращает часы цели target: SLAResponse или SLAResolve.
+func (s *SLAStatus) Clock(target string) *SLAClock {
+	if target == SLAResponse {
+		return s.Response
+	}
+	return s.Resolve
+}
+
+func (s SLAPolicy) validate() error {
+	for priority, target := range s.Targets {
+		if !domain.AllowedPriorities[priority] {
+			return fmt.Errorf("политика оценки: неизвестный приоритет SLA %q", priority)
+		}
+		if target.ResponseHours < 0 || target.ResolveHours < 0 {
+			return fmt.Errorf("политика оценки: цели SLA не могут быть отрицательными")
+		}
+	}
+	if s.WarnRatio <= 0 || s.WarnRatio > 1 {
+		return fmt.Errorf("политика оценки: sla.warnRatio должен быть больше 0 и не больше 1")
+	}
+	return nil
+}
+
+// TaskSLA считает состояние SLA задачи в рабочих часах исполнителя; nil,
+// если для приоритета нет целей.
+func (p Policy) TaskSLA(now time.Time, task domain.Task) *SLAStatus {
+	target, ok := p.SLA.Targets[task.Priority]
+	if !ok || task.CreatedAt.IsZero() || target.ResponseHours <= 0 && target.ResolveHours <= 0 {
+		return nil
+	}
+
+	status := &SLAStatus{}
+	if target.ResponseHours > 0 {
+		stop := task.StartedAt
+		if stop == nil {
+			stop = task.CompletedAt
+		}
+		status.Response = p.slaClock(now, task, target.ResponseHours, stop)
+	}
+	if target.ResolveHours > 0 {
+		status.Resolve = p.slaClock(now, task, target.ResolveHours, task.CompletedAt)
+	}
+	for _, clock := range []*SLAClock{status.Response, status.Resolve} {
+		if clock == nil {
+			continue
+		}
+		status.Breached = status.Breached || clock.Breached
+		status.Imminent = status.Imminent || clock.State == SLAImminent
+	}
+	return status
+}
+
+func (p Policy) slaClock(now time.Time, task domain.Task, targetHours float64, stop *time.Time) *SLAClock {
+	end := now
+	if stop != nil {
+		end = *stop
+	}
+	elapsed := p.Hours(task.Owner, task.CreatedAt, end)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	clock := &SLAClock{
+		TargetHours:    targetHours,
+		ElapsedHours:   round2(elapsed),
+		RemainingHours: round2(targetHours - elapsed),
+		Breached:       elapsed > targetHours,
+	}
+	switch {
+	case clock.Breached:
+		clock.State = SLABreached
+	case stop != nil:
+		clock.State = SLAMet
+	case elapsed >= targetHours*p.SLA.WarnRatio:
+		clock.State = SLAImminent
+	default:
+		clock.State = SLARunning
+	}
+	return clock
+}
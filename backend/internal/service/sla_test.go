@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func slaPolicy(t *testing.T) Policy {
+	t.Helper()
+	policy, err := ParsePolicy([]byte(`{"sla": {"targets": {"critical": {"responseHours": 1, "resolveHours": 8}, "high": {"resolveHours": 24}}}}`))
+	require.NoError(t, err)
+	return policy
+}
+
+func TestTaskSLAClocks(t *testing.T) {
+	policy := slaPolicy(t)
+	created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	task := domain.Task{Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: created}
+
+	sla := policy.TaskSLA(created.Add(30*time.Minute), task)
+	require.Equal(t, &SLAClock{TargetHours: 1, ElapsedHours: 0.5, RemainingHours: 0.5, State: SLARunning}, sla.Response)
+	require.Equal(t, SLARunning, sla.Resolve.State)
+	require.False(t, sla.Breached)
+
+	sla = policy.TaskSLA(created.Add(50*time.Minute), task)
+	require.Equal(t, SLAImminent, sla.Response.State)
+	require.True(t, sla.Imminent)
+
+	sla = policy.TaskSLA(created.Add(2*time.Hour), task)
+	require.Equal(t, &SLAClock{TargetHours: 1, ElapsedHours: 2, RemainingHours: -1, State: SLABreached, Breached: true}, sla.Response)
+	require.True(t, sla.Breached)
+
+	// Работа началась вовремя, а завершилась позже цели.
+	startedAt := created.Add(45 * time.Minute)
+	completedAt := created.Add(10 * time.Hour)
+	task.Status, task.StartedAt, task.CompletedAt = domain.StatusDone, &startedAt, &completedAt
+	sla = policy.TaskSLA(created.Add(48*time.Hour), task)
+	require.Equal(t, SLAMet, sla.Response.State)
+	require.Equal(t, 0.75, sla.Response.ElapsedHours)
+	require.Equal(t, SLABreached, sla.Resolve.State)
+	require.Equal(t, -2.0, sla.Resolve.RemainingHours)
+
+	// Без StartedAt реакцией считается завершение.
+	task.StartedAt = nil
+	task.CompletedAt = &startedAt
+	sla = policy.TaskSLA(created.Add(48*time.Hour), task)
+	require.Equal(t, SLAMet, sla.Response.State)
+	require.Equal(t, SLAMet, sla.Resolve.State)
+
+	high := policy.TaskSLA(created.Add(time.Hour), domain.Task{Priority: domain.PriorityHigh, CreatedAt: created})
+	require.Nil(t, high.Response)
+	require.Equal(t, 23.0, high.Resolve.RemainingHours)
+	require.Nil(t, policy.TaskSLA(created, domain.Task{Priority: domain.PriorityLow, CreatedAt: created}))
+	require.Nil(t, DefaultPolicy().TaskSLA(created, task))
+}
+
+func TestInsightsCountSLABreaches(t *testing.T) {
+	policy := slaPolicy(t)
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	tasks := []domain.Task{
+		{Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: now.Add(-2 * time.Hour)},
+		{Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: now.Add(-50 * time.Minute)},
+		{Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: now.Add(-10 * time.Minute)},
+		{Status: domain.StatusTodo, Priority: domain.PriorityLow, CreatedAt: now.Add(-100 * time.Hour)},
+	}
+
+	insights := policy.Insights(now, tasks)
+	require.Equal(t, 1, insights.SLABreached)
+	require.Equal(t, 1, insights.SLAImminent)
+	require.NotNil(t, policy.Metrics(now, tasks[0]).SLA)
+	require.Nil(t, policy.Metrics(now, tasks[3]).SLA)
+}
+
+func TestParsePolicyValidatesSLA(t *testing.T) {
+	policy := slaPolicy(t)
+	require.Equal(t, DefaultSLAWarnRatio, policy.SLA.WarnRatio)
+
+	for _, data := range []string{
+		`{"sla": {"targets": {"urgent": {"resolveHours": 1}}}}`,
+		`{"sla": {"targets": {"high": {"resolveHours": -1}}}}`,
+		`{"sla": {"warnRatio": 0}}`,
+		`{"sla": {"warnRatio": 1.5}}`,
+	} {
+		_, err := ParsePolicy([]byte(data))
+		require.Error(t, err, data)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+const (
+	DefaultOwner       = "unassigned"
+	DefaultEffortHours = 1
+	MinEffortHours     = 1
+	MaxEffortHours     = 200
+)
+
+// TaskInput — редактируемые поля задачи, общие для REST и gRPC.
+type TaskInput struct {
+	Title       string
+	Description string
+	Status      string
+	Priority    string
+	Owner       string
+	EffortHours int
+	DueDate     *time.Time
+	Tags        []string
+}
+
+// NormalizeEffort подставляет DefaultEffortHours вместо 0 и проверяет,
+// что трудоёмкость лежит в MinEffortHours..MaxEffortHours.
+func NormalizeEffort(value int) (int, error) {
+	if value == 0 {
+		return DefaultEffortHours, nil
+	}
+	if value < MinEffortHours || value > MaxEffortHours {
+		return 0, NewFieldError(CodeInvalidEffort, "effortHours", EffortRange())
+	}
+	return value, nil
+}
+
+// EffortRange — параметры сообщения CodeInvalidEffort.
+func EffortRange() map[string]any {
+	return map[string]any{"min": MinEffortHours, "max": MaxEffortHours}
+}
+
+// NormalizeTaskInput приводит поля к доменным значениям и подставляет
+// значения по умолчанию; ошибки собираются по всем полям сразу.
+func NormalizeTaskInput(input TaskInput) (TaskInput, error) {
+	var errs ValidationErrors
+	var err error
+	normalized := input
+
+	normalized.Title, err = NormalizeTitle(input.Title)
+	errs.Add(err)
+	normalized.Status, err = NormalizeStatus(input.Status)
+	errs.Add(err)
+	normalized.Priority, err = NormalizePriority(input.Priority)
+	errs.Add(err)
+	normalized.EffortHours, err = NormalizeEffort(input.EffortHours)
+	errs.Add(err)
+	tags, err := NormalizeTags(input.Tags)
+	errs.Add(err)
+	normalized.Tags = tags
+
+	normalized.Description = strings.TrimSpace(input.Description)
+	normalized.Owner = strings.TrimSpace(input.Owner)
+	if normalized.Owner == "" {
+		normalized.Owner = DefaultOwner
+	}
+	return normalized, errs.Err()
+}
+
+// ApplyTo переносит нормализованные поля в задачу. Статус не меняется:
+// его нужно менять через ApplyStatusTransition.
+func (input TaskInput) ApplyTo(task *domain.Task) {
+	task.Title = input.Title
+	task.Description = input.Description
+	task.Priority = input.Priority
+	task.Owner = input.Owner
+	task.EffortHours = input.EffortHours
+	task.Tags = domain.StringList(input.Tags)
+	task.DueDate = input.DueDate
+}
+
+// InputFromTask возвращает редактируемые поля существующей задачи.
+func InputFromTask(task domain.Task) TaskInput {
+	tags := []string(task.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	return TaskInput{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		Owner:       task.Owner,
+		EffortHours: task.EffortHours,
+		DueDate:     task.DueDate,
+		Tags:        tags,
+	}
+}
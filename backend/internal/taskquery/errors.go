@@ -0,0 +1,31 @@
+package taskquery
+
+import "devopslabs/internal/service"
+
+const (
+	CodeUnexpectedToken    = "filter_unexpected_token"
+	CodeUnterminatedString = "filter_unterminated_string"
+	CodeExpectedCondition  = "filter_expected_condition"
+	CodeExpectedOperator   = "filter_expected_operator"
+	CodeExpectedValue      = "filter_expected_value"
+	CodeUnclosedGroup      = "filter_unclosed_group"
+	CodeUnknownField       = "filter_unknown_field"
+	CodeInvalidOperator    = "filter_invalid_operator"
+	CodeInvalidValue       = "filter_invalid_value"
+	CodeSingleValue        = "filter_single_value"
+	CodeTooLong            = "filter_too_long"
+	CodeTooComplex         = "filter_too_complex"
+)
+
+// ErrorField — поле, к которому относятся ошибки разбора выражения.
+const ErrorField = "filter"
+
+// errorAt возвращает ошибку проверки с позицией символа, на котором
+// она обнаружена; позиции считаются с 1.
+func errorAt(code string, pos int, details map[string]any) *service.FieldError {
+	if details == nil {
+		details = map[string]any{}
+	}
+	details["position"] = pos
+	return service.NewFieldError(code, ErrorField, details)
+}
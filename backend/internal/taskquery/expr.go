@@ -0,0 +1,149 @@
+package taskquery
+
+import (
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+)
+
+// Expr — узел разобранного выражения. Matches и SQL дают одинаковый
+// результат: условия по пустым датам ложны и на стороне базы, поэтому NOT
+// работает одинаково в памяти и в SQL.
+type Expr interface {
+	// Matches проверяет задачу в памяти; now задаёт точку отсчёта для
+	// относительных дат вида 7d.
+	Matches(task domain.Task, now time.Time) bool
+	// String возвращает выражение в каноническом виде, пригодном для Parse.
+	String() string
+
+	sql(now time.Time) (string, []any)
+}
+
+type And struct {
+	Left, Right Expr
+}
+
+type Or struct {
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+// Condition — сравнение поля задачи со значениями, например owner:alice,bob.
+// Position указывает на первый символ имени поля.
+type Condition struct {
+	Field    string
+	Operator string
+	Values   []string
+	Position int
+
+	predicate predicate
+}
+
+// predicate — проверенное условие, готовое к вычислению.
+type predicate interface {
+	match(task domain.Task, now time.Time) bool
+	sql(now time.Time) (string, []any)
+}
+
+// SQL компилирует выражение в условие WHERE с параметрами вместо значений.
+func SQL(expr Expr, now time.Time) (string, []any) {
+	return expr.sql(now)
+}
+
+func (e *And) Matches(task domain.Task, now time.Time) bool {
+	return e.Left.Matches(task, now) && e.Right.Matches(task, now)
+}
+
+func (e *Or) Matches(task domain.Task, now time.Time) bool {
+	return e.Left.Matches(task, now) || e.Right.Matches(task, now)
+}
+
+func (e *Not) Matches(task domain.Task, now time.Time) bool {
+	return !e.Expr.Matches(task, now)
+}
+
+func (c *Condition) Matches(task domain.Task, now time.Time) bool {
+	return c.predicate.match(task, now)
+}
+
+func (e *And) sql(now time.Time) (string, []any) {
+	return joinSQL(" AND ", now, e.Left, e.Right)
+}
+
+func (e *Or) sql(now time.Time) (string, []any) {
+	return joinSQL(" OR ", now, e.Left, e.Right)
+}
+
+func (e *Not) sql(now time.Time) (string, []any) {
+	clause, args := e.Expr.sql(now)
+	return "NOT (" + clause + ")", args
+}
+
+func (c *Condition) sql(now time.Time) (string, []any) {
+	return c.predicate.sql(now)
+}
+
+func joinSQL(separator string, now time.Time, exprs ...Expr) (string, []any) {
+	clauses := make([]string, 0, len(exprs))
+	var args []any
+	for _, expr := range exprs {
+		clause, exprArgs := expr.sql(now)
+		clauses = append(clauses, clause)
+		args = append(args, exprArgs...)
+	}
+	return "(" + strings.Join(clauses, separator) + ")", args
+}
+
+func (e *And) String() string {
+	return group(e.Left, isOr) + " AND " + group(e.Right, isOr)
+}
+
+func (e *Or) String() string {
+	return e.Left.String() + " OR " + e.Right.String()
+}
+
+func (e *Not) String() string {
+	return "NOT " + group(e.Expr, func(expr Expr) bool { return !isCondition(expr) && !isNot(expr) })
+}
+
+func (c *Condition) String() string {
+	values := make([]string, 0, len(c.Values))
+	for _, value := range c.Values {
+		values = append(values, quoteValue(value))
+	}
+	return c.Field + c.Operator + strings.Join(values, ",")
+}
+
+func group(expr Expr, needsParens func(Expr) bool) string {
+	if needsParens(expr) {
+		return "(" + expr.String() + ")"
+	}
+	return expr.String()
+}
+
+func isOr(expr Expr) bool {
+	_, ok := expr.(*Or)
+	return ok
+}
+
+func isNot(expr Expr) bool {
+	_, ok := expr.(*Not)
+	return ok
+}
+
+func isCondition(expr Expr) bool {
+	_, ok := expr.(*Condition)
+	return ok
+}
+
+func quoteValue(value string) string {
+	if value != "" && !strings.ContainsFunc(value, isDelimiter) && !strings.ContainsRune(value, ',') {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,122 @@
+package taskquery
+
+import (
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)
+
+func at(value string) *time.Time {
+	parsed, _ := time.Parse(time.RFC3339, value)
+	return &parsed
+}
+
+func sampleTasks() []domain.Task {
+	created := now.Add(-72 * time.Hour)
+	return []domain.Task{
+		{ID: 1, Title: "Ship CI", Status: domain.StatusInProgress, Priority: domain.PriorityHigh, Owner: "alice", EffortHours: 5, Tags: domain.StringList{"CI"}, DueDate: at("2026-02-10T09:00:00Z"), CreatedAt: created},
+		{ID: 2, Title: "Rotate keys", Description: "Infra 100% secrets", Status: domain.StatusTodo, Priority: domain.PriorityCritical, Owner: "bob", EffortHours: 2, Tags: domain.StringList{"infra"}, CreatedAt: now.Add(-time.Hour)},
+		{ID: 3, Title: "Write docs", Status: domain.StatusDone, Priority: domain.PriorityHigh, Owner: "alice", EffortHours: 1, Tags: domain.StringList{"infra", "docs"}, DueDate: at("2026-02-20T09:00:00Z"), CreatedAt: created},
+		{ID: 4, Title: "Triage", Status: domain.StatusBlocked, Priority: domain.PriorityLow, Owner: "carol", EffortHours: 8, CreatedAt: created},
+	}
+}
+
+func matchingIDs(t *testing.T, input string) []uint {
+	t.Helper()
+	expr, err := Parse(input)
+	require.NoError(t, err)
+
+	ids := []uint{}
+	for _, task := range sampleTasks() {
+		if expr.Matches(task, now) {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids
+}
+
+func TestMatches(t *testing.T) {
+	cases := map[string][]uint{
+		`priority>=high AND (owner:alice OR tag:infra) AND due<7d AND NOT status:done`: {1},
+		`priority>=high AND (owner:alice OR tag:infra) AND NOT status:done`:            {1, 2},
+		`priority<high`:                       {4},
+		`priority!=high,critical`:             {4},
+		`priority=high`:                       {1, 3},
+		`tag:ci`:                              {1},
+		`tag!=infra`:                          {1, 4},
+		`text:"100%"`:                         {2},
+		`title:ship,docs`:                     {1, 3},
+		`effort>2 effort<=5`:                  {1},
+		`id!=1,2`:                             {3, 4},
+		`due:none`:                            {2, 4},
+		`due!=none`:                           {1, 3},
+		`due:2026-02-10`:                      {1},
+		`due<=2026-02-10`:                     {1},
+		`due>2026-02-10`:                      {3},
+		`NOT due<7d`:                          {2, 3, 4},
+		`due<"2026-02-10T09:00:00Z"`:          {},
+		`due="2026-02-10T09:00:00Z"`:          {1},
+		`created>-1d`:                         {2},
+		`created<now AND owner!=alice,bob`:    {4},
+		`NOT (status:done OR status:blocked)`: {1, 2},
+	}
+	for input, expected := range cases {
+		t.Run(input, func(t *testing.T) {
+			require.Equal(t, expected, matchingIDs(t, input))
+		})
+	}
+}
+
+func TestRelativeDatesUseEvaluationTime(t *testing.T) {
+	expr, err := Parse(`due<7d`)
+	require.NoError(t, err)
+	task := sampleTasks()[2]
+	require.False(t, expr.Matches(task, now))
+	require.True(t, expr.Matches(task, now.Add(7*24*time.Hour)))
+}
+
+func TestSQL(t *testing.T) {
+	expr, err := Parse(`priority>=high AND (owner:alice OR tag:"in_fra") AND due<7d AND NOT status:done`)
+	require.NoError(t, err)
+
+	clause, args := SQL(expr, now)
+	require.Equal(t, `(((priority IN ? AND (COALESCE(owner, '') IN ? OR (LOWER(COALESCE(tags, '')) LIKE ? ESCAPE '\'))) AND (due_date IS NOT NULL AND due_date < ?)) AND NOT (status IN ?))`, clause)
+	require.Equal(t, []any{
+		[]string{domain.PriorityHigh, domain.PriorityCritical},
+		[]string{"alice"},
+		`%"in\_fra"%`,
+		now.Add(7 * 24 * time.Hour),
+		[]string{domain.StatusDone},
+	}, args)
+}
+
+func TestSQLDates(t *testing.T) {
+	day := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input  string
+		clause string
+		args   []any
+	}{
+		{`due:none`, `due_date IS NULL`, nil},
+		{`completed!=none`, `completed_at IS NOT NULL`, nil},
+		{`due:2026-02-10`, `(due_date IS NOT NULL AND due_date >= ? AND due_date < ?)`, []any{day, day.AddDate(0, 0, 1)}},
+		{`due!=2026-02-10`, `NOT (due_date IS NOT NULL AND due_date >= ? AND due_date < ?)`, []any{day, day.AddDate(0, 0, 1)}},
+		{`due>2026-02-10`, `(due_date IS NOT NULL AND due_date >= ?)`, []any{day.AddDate(0, 0, 1)}},
+		{`created>=-2w`, `(created_at >= ?)`, []any{now.Add(-14 * 24 * time.Hour)}},
+		{`effort>3`, `effort_hours > ?`, []any{3}},
+		{`text:deploy`, `(LOWER(COALESCE(title, '')) LIKE ? ESCAPE '\' OR LOWER(COALESCE(description, '')) LIKE ? ESCAPE '\')`, []any{"%deploy%", "%deploy%"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			expr, err := Parse(tc.input)
+			require.NoError(t, err)
+			clause, args := SQL(expr, now)
+			require.Equal(t, tc.clause, clause)
+			require.Equal(t, tc.args, args)
+		})
+	}
+}
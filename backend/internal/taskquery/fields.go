@@ -0,0 +1,506 @@
+package taskquery
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+)
+
+var (
+	equalityOperators   = []string{":", "=", "!="}
+	comparisonOperators = []string{":", "=", "!=", "<", "<=", ">", ">="}
+	containsOperators   = []string{":"}
+)
+
+// field описывает поле задачи, доступное в выражении: допустимые операторы
+// и построение условия из значений.
+type field struct {
+	operators []string
+	resolve   func(c *Condition) (predicate, error)
+}
+
+var fields = map[string]field{
+	"id":          {comparisonOperators, numberField("id", func(t domain.Task) int { return int(t.ID) })},
+	"status":      {equalityOperators, statusField},
+	"priority":    {comparisonOperators, priorityField},
+	"owner":       {equalityOperators, ownerField},
+	"tag":         {equalityOperators, tagField},
+	"title":       {containsOperators, textField([]string{"title"}, func(t domain.Task) []string { return []string{t.Title} })},
+	"description": {containsOperators, textField([]string{"description"}, func(t domain.Task) []string { return []string{t.Description} })},
+	"text":        {containsOperators, textField([]string{"title", "description"}, func(t domain.Task) []string { return []string{t.Title, t.Description} })},
+	"effort":      {comparisonOperators, numberField("effort_hours", func(t domain.Task) int { return t.EffortHours })},
+	"due":         {comparisonOperators, dateField("due_date", true, func(t domain.Task) *time.Time { return t.DueDate })},
+	"started":     {comparisonOperators, dateField("started_at", true, func(t domain.Task) *time.Time { return t.StartedAt })},
+	"completed":   {comparisonOperators, dateField("completed_at", true, func(t domain.Task) *time.Time { return t.CompletedAt })},
+	"created":     {comparisonOperators, dateField("created_at", false, func(t domain.Task) *time.Time { return &t.CreatedAt })},
+	"updated":     {comparisonOperators, dateField("updated_at", false, func(t domain.Task) *time.Time { return &t.UpdatedAt })},
+}
+
+// Fields возвращает имена полей, доступных в выражениях.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Condition) resolve() error {
+	spec, ok := fields[c.Field]
+	if !ok {
+		return errorAt(CodeUnknownField, c.Position, map[string]any{"name": c.Field, "allowed": strings.Join(Fields(), ", ")})
+	}
+	if !slices.Contains(spec.operators, c.Operator) {
+		return errorAt(CodeInvalidOperator, c.Position, map[string]any{
+			"name":     c.Field,
+			"operator": c.Operator,
+			"allowed":  strings.Join(spec.operators, " "),
+		})
+	}
+	if len(c.Values) > 1 && c.Operator != ":" && c.Operator != "=" && c.Operator != "!=" {
+		return errorAt(CodeSingleValue, c.Position, map[string]any{"name": c.Field, "operator": c.Operator})
+	}
+
+	pred, err := spec.resolve(c)
+	if err != nil {
+		return err
+	}
+	c.predicate = pred
+	return nil
+}
+
+func (c *Condition) negated() bool {
+	return c.Operator == "!="
+}
+
+func (c *Condition) invalidValue(value string, expected string) error {
+	return errorAt(CodeInvalidValue, c.Position, map[string]any{"name": c.Field, "value": value, "expected": expected})
+}
+
+// setPredicate — принадлежность значения поля множеству; сравнения порядка
+// для приоритета тоже сводятся к множеству.
+type setPredicate struct {
+	column string
+	get    func(domain.Task) string
+	values []string
+	negate bool
+}
+
+func (p setPredicate) match(task domain.Task, _ time.Time) bool {
+	return slices.Contains(p.values, p.get(task)) != p.negate
+}
+
+func (p setPredicate) sql(time.Time) (string, []any) {
+	if p.negate {
+		return p.column + " NOT IN ?", []any{p.values}
+	}
+	return p.column + " IN ?", []any{p.values}
+}
+
+func statusField(c *Condition) (predicate, error) {
+	values := make([]string, 0, len(c.Values))
+	for _, raw := range c.Values {
+		value, err := service.NormalizeStatus(raw)
+		if err != nil || strings.TrimSpace(raw) == "" {
+			return nil, c.invalidValue(raw, strings.Join(sortedKeys(domain.AllowedStatuses), ", "))
+		}
+		values = append(values, value)
+	}
+	return setPredicate{column: "status", get: func(t domain.Task) string { return t.Status }, values: values, negate: c.negated()}, nil
+}
+
+func priorityField(c *Condition) (predicate, error) {
+	var weights []int
+	for _, raw := range c.Values {
+		value, err := service.NormalizePriority(raw)
+		if err != nil || strings.TrimSpace(raw) == "" {
+			return nil, c.invalidValue(raw, strings.Join(prioritiesByWeight(), ", "))
+		}
+		weights = append(weights, domain.PriorityWeights[value])
+	}
+
+	var values []string
+	for _, priority := range prioritiesByWeight() {
+		weight := domain.PriorityWeights[priority]
+		if compareInt(weight, c.Operator, weights) {
+			values = append(values, priority)
+		}
+	}
+	return setPredicate{column: "priority", get: func(t domain.Task) string { return t.Priority }, values: values}, nil
+}
+
+func ownerField(c *Condition) (predicate, error) {
+	values := make([]string, 0, len(c.Values))
+	for _, raw := range c.Values {
+		values = append(values, strings.TrimSpace(raw))
+	}
+	return setPredicate{column: "COALESCE(owner, '')", get: func(t domain.Task) string { return t.Owner }, values: values, negate: c.negated()}, nil
+}
+
+// tagPredicate ищет тег в JSON-массиве колонки tags по подстроке с
+// кавычками, поэтому tag:ci не совпадает с тегом cicd.
+type tagPredicate struct {
+	tags   []string
+	negate bool
+}
+
+func tagField(c *Condition) (predicate, error) {
+	tags := make([]string, 0, len(c.Values))
+	for _, raw := range c.Values {
+		tag := strings.ToLower(strings.TrimSpace(raw))
+		if tag == "" {
+			return nil, c.invalidValue(raw, "tag")
+		}
+		tags = append(tags, tag)
+	}
+	return tagPredicate{tags: tags, negate: c.negated()}, nil
+}
+
+func (p tagPredicate) match(task domain.Task, _ time.Time) bool {
+	found := slices.ContainsFunc(task.Tags, func(value string) bool {
+		return slices.Contains(p.tags, strings.ToLower(value))
+	})
+	return found != p.negate
+}
+
+func (p tagPredicate) sql(time.Time) (string, []any) {
+	clauses := make([]string, 0, len(p.tags))
+	args := make([]any, 0, len(p.tags))
+	for _, tag := range p.tags {
+		quoted, _ := json.Marshal(tag)
+		clauses = append(clauses, `LOWER(COALESCE(tags, '')) LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(string(quoted))+"%")
+	}
+	clause := "(" + strings.Join(clauses, " OR ") + ")"
+	if p.negate {
+		clause = "NOT " + clause
+	}
+	return clause, args
+}
+
+// textPredicate — поиск подстроки без учёта регистра в одном из полей.
+type textPredicate struct {
+	columns []string
+	get     func(domain.Task) []string
+	needles []string
+}
+
+func textField(columns []string, get func(domain.Task) []string) func(c *Condition) (predicate, error) {
+	return func(c *Condition) (predicate, error) {
+		needles := make([]string, 0, len(c.Values))
+		for _, raw := range c.Values {
+			needle := strings.ToLower(strings.TrimSpace(raw))
+			if needle == "" {
+				return nil, c.invalidValue(raw, "text")
+			}
+			needles = append(needles, needle)
+		}
+		return textPredicate{columns: columns, get: get, needles: needles}, nil
+	}
+}
+
+func (p textPredicate) match(task domain.Task, _ time.Time) bool {
+	for _, value := range p.get(task) {
+		value = strings.ToLower(value)
+		for _, needle := range p.needles {
+			if strings.Contains(value, needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p textPredicate) sql(time.Time) (string, []any) {
+	var clauses []string
+	var args []any
+	for _, column := range p.columns {
+		for _, needle := range p.needles {
+			clauses = append(clauses, "LOWER(COALESCE("+column+`, '')) LIKE ? ESCAPE '\'`)
+			args = append(args, "%"+escapeLike(needle)+"%")
+		}
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+type numberPredicate struct {
+	column   string
+	get      func(domain.Task) int
+	operator string
+	values   []int
+}
+
+func numberField(column string, get func(domain.Task) int) func(c *Condition) (predicate, error) {
+	return func(c *Condition) (predicate, error) {
+		values := make([]int, 0, len(c.Values))
+		for _, raw := range c.Values {
+			value, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil {
+				return nil, c.invalidValue(raw, "number")
+			}
+			values = append(values, value)
+		}
+		return numberPredicate{column: column, get: get, operator: c.Operator, values: values}, nil
+	}
+}
+
+func (p numberPredicate) match(task domain.Task, _ time.Time) bool {
+	return compareInt(p.get(task), p.operator, p.values)
+}
+
+func (p numberPredicate) sql(time.Time) (string, []any) {
+	switch p.operator {
+	case ":", "=":
+		return p.column + " IN ?", []any{p.values}
+	case "!=":
+		return p.column + " NOT IN ?", []any{p.values}
+	default:
+		return p.column + " " + p.operator + " ?", []any{p.values[0]}
+	}
+}
+
+// compareInt сравнивает value со значениями условия: для равенства — с
+// любым из них, для порядка — с единственным.
+func compareInt(value int, operator string, values []int) bool {
+	switch operator {
+	case ":", "=":
+		return slices.Contains(values, value)
+	case "!=":
+		return !slices.Contains(values, value)
+	case "<":
+		return value < values[0]
+	case "<=":
+		return value <= values[0]
+	case ">":
+		return value > values[0]
+	case ">=":
+		return value >= values[0]
+	}
+	return false
+}
+
+var relativePattern = regexp.MustCompile(`^([+-]?\d{1,5})([hdw])$`)
+
+var relativeUnits = map[string]time.Duration{
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+const dateExpectation = "2026-02-10, \"2026-02-10T12:00:00Z\", now, 7d, -12h, 2w"
+
+// MaxRelativeOffset ограничивает смещение от текущего момента сотней лет:
+// большие смещения не помещаются в time.Duration.
+const MaxRelativeOffset = 36500 * 24 * time.Hour
+
+var offsetExpectation = "смещение не больше ±" + strconv.Itoa(int(MaxRelativeOffset/relativeUnits["d"])) + "d"
+
+// dateValue — календарный день, момент времени или смещение от текущего
+// момента; смещение вычисляется при каждой проверке.
+type dateValue struct {
+	at       time.Time
+	offset   time.Duration
+	relative bool
+	day      bool
+}
+
+func (v dateValue) bounds(now time.Time) (time.Time, time.Time) {
+	if v.relative {
+		at := now.Add(v.offset)
+		return at, at
+	}
+	if v.day {
+		return v.at, v.at.AddDate(0, 0, 1)
+	}
+	return v.at, v.at
+}
+
+func parseDateValue(raw string) (dateValue, bool) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if value == "now" {
+		return dateValue{relative: true}, true
+	}
+	if match := relativePattern.FindStringSubmatch(value); match != nil {
+		amount, _ := strconv.Atoi(match[1])
+		unit := relativeUnits[match[2]]
+		if amount > int(MaxRelativeOffset/unit) || amount < -int(MaxRelativeOffset/unit) {
+			return dateValue{}, false
+		}
+		return dateValue{offset: time.Duration(amount) * unit, relative: true}, true
+	}
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return dateValue{at: day, day: true}, true
+	}
+	if at, err := time.Parse(time.RFC3339, strings.TrimSpace(raw)); err == nil {
+		return dateValue{at: at}, true
+	}
+	return dateValue{}, false
+}
+
+// datePredicate сравнивает дату с днём целиком: due<=2026-02-10 включает
+// весь день, а due=2026-02-10 — любой момент этого дня. Значение none
+// проверяет, что дата не задана.
+type datePredicate struct {
+	column   string
+	nullable bool
+	get      func(domain.Task) *time.Time
+	operator string
+	value    dateValue
+	none     bool
+}
+
+func dateField(column string, nullable bool, get func(domain.Task) *time.Time) func(c *Condition) (predicate, error) {
+	return func(c *Condition) (predicate, error) {
+		raw := c.Values[0]
+		if len(c.Values) > 1 {
+			return nil, errorAt(CodeSingleValue, c.Position, map[string]any{"name": c.Field, "operator": c.Operator})
+		}
+
+		pred := datePredicate{column: column, nullable: nullable, get: get, operator: c.Operator}
+		if strings.EqualFold(strings.TrimSpace(raw), "none") {
+			if !nullable || !slices.Contains(equalityOperators, c.Operator) {
+				return nil, c.invalidValue(raw, dateExpectation)
+			}
+			pred.none = true
+			return pred, nil
+		}
+
+		value, ok := parseDateValue(raw)
+		if !ok {
+			expected := dateExpectation
+			if nullable {
+				expected += ", none"
+			}
+			if relativePattern.MatchString(strings.ToLower(strings.TrimSpace(raw))) {
+				expected = offsetExpectation
+			}
+			return nil, c.invalidValue(raw, expected)
+		}
+		pred.value = value
+		return pred, nil
+	}
+}
+
+// comparison сводит оператор к сравнению с одной границей либо к
+// попаданию в полуинтервал [start, end) для равенства.
+func (p datePredicate) comparison(now time.Time) (string, time.Time) {
+	start, end := p.value.bounds(now)
+	switch p.operator {
+	case "<":
+		return "<", start
+	case "<=":
+		if p.value.day {
+			return "<", end
+		}
+		return "<=", start
+	case ">":
+		if p.value.day {
+			return ">=", end
+		}
+		return ">", start
+	case ">=":
+		return ">=", start
+	}
+	return "", time.Time{}
+}
+
+func (p datePredicate) match(task domain.Task, now time.Time) bool {
+	value := p.get(task)
+	if p.none {
+		return (value == nil) != p.negated()
+	}
+	if p.operator == ":" || p.operator == "=" || p.operator == "!=" {
+		return p.equal(value, now) != p.negated()
+	}
+	if value == nil {
+		return false
+	}
+
+	operator, bound := p.comparison(now)
+	switch operator {
+	case "<":
+		return value.Before(bound)
+	case "<=":
+		return !value.After(bound)
+	case ">":
+		return value.After(bound)
+	default:
+		return !value.Before(bound)
+	}
+}
+
+func (p datePredicate) equal(value *time.Time, now time.Time) bool {
+	if value == nil {
+		return false
+	}
+	start, end := p.value.bounds(now)
+	if p.value.day {
+		return !value.Before(start) && value.Before(end)
+	}
+	return value.Equal(start)
+}
+
+func (p datePredicate) negated() bool {
+	return p.operator == "!="
+}
+
+func (p datePredicate) sql(now time.Time) (string, []any) {
+	if p.none {
+		if p.negated() {
+			return p.column + " IS NOT NULL", nil
+		}
+		return p.column + " IS NULL", nil
+	}
+
+	var clause string
+	var args []any
+	if p.operator == ":" || p.operator == "=" || p.operator == "!=" {
+		start, end := p.value.bounds(now)
+		if p.value.day {
+			clause, args = p.column+" >= ? AND "+p.column+" < ?", []any{start, end}
+		} else {
+			clause, args = p.column+" = ?", []any{start}
+		}
+	} else {
+		operator, bound := p.comparison(now)
+		clause, args = p.column+" "+operator+" ?", []any{bound}
+	}
+
+	if p.nullable {
+		clause = p.column + " IS NOT NULL AND " + clause
+	}
+	clause = "(" + clause + ")"
+	if p.negated() {
+		clause = "NOT " + clause
+	}
+	return clause, args
+}
+
+func prioritiesByWeight() []string {
+	priorities := sortedKeys(domain.PriorityWeights)
+	sort.SliceStable(priorities, func(i, j int) bool {
+		return domain.PriorityWeights[priorities[i]] < domain.PriorityWeights[priorities[j]]
+	})
+	return priorities
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,106 @@
+package taskquery
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenComma
+	tokenLParen
+	tokenRParen
+)
+
+// token хранит позицию первого символа, считая с 1 в символах, а не байтах.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			i++
+		case r == '"':
+			text, next, ok := readString(runes, i)
+			if !ok {
+				return nil, errorAt(CodeUnterminatedString, pos, nil)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: pos})
+			i = next
+		case r == ':' || r == '=':
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: pos})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokenOperator, text: string(r) + "=", pos: pos})
+				i += 2
+				continue
+			}
+			if r == '!' {
+				return nil, errorAt(CodeUnexpectedToken, pos, map[string]any{"token": "!"})
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: pos})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !isDelimiter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: pos})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// readString читает строку в двойных кавычках; обратная косая черта
+// экранирует следующий символ.
+func readString(runes []rune, start int) (string, int, bool) {
+	var builder strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 == len(runes) {
+				return "", 0, false
+			}
+			i++
+			builder.WriteRune(runes[i])
+		case '"':
+			return builder.String(), i + 1, true
+		default:
+			builder.WriteRune(runes[i])
+		}
+	}
+	return "", 0, false
+}
+
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()",:=!<>`, r)
+}
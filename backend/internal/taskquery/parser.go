@@ -0,0 +1,170 @@
+package taskquery
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"devopslabs/internal/service"
+)
+
+const (
+	MaxLength     = 1000
+	MaxConditions = 50
+)
+
+// Parse разбирает выражение фильтра вида
+//
+//	priority>=high AND (owner:alice OR tag:infra) AND due<7d AND NOT status:done
+//
+// и проверяет поля, операторы и значения условий. Соседние условия без
+// связки объединяются через AND. Пустое выражение возвращает nil без ошибки.
+// Синтаксическая ошибка останавливает разбор, а ошибки отдельных условий
+// собираются все сразу.
+func Parse(input string) (Expr, error) {
+	if length := utf8.RuneCountInString(input); length > MaxLength {
+		return nil, service.NewFieldError(CodeTooLong, ErrorField, map[string]any{"max": MaxLength})
+	}
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errorAt(CodeUnexpectedToken, tok.pos, map[string]any{"token": tok.text})
+	}
+
+	var errs service.ValidationErrors
+	for _, condition := range p.conditions {
+		errs.Add(condition.resolve())
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+type parser struct {
+	tokens     []token
+	index      int
+	conditions []*Condition
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.index]
+	if tok.kind != tokenEOF {
+		p.index++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.isKeyword("AND"):
+			p.next()
+		case tok.kind == tokenLParen, tok.kind == tokenWord && !tok.isKeyword("OR"):
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.peek()
+	switch {
+	case tok.isKeyword("NOT"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: operand}, nil
+	case tok.kind == tokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, errorAt(CodeUnclosedGroup, tok.pos, nil)
+		}
+		p.next()
+		return inner, nil
+	case tok.kind == tokenWord && !tok.isKeyword("AND") && !tok.isKeyword("OR"):
+		return p.parseCondition()
+	default:
+		return nil, errorAt(CodeExpectedCondition, tok.pos, nil)
+	}
+}
+
+func (p *parser) parseCondition() (Expr, error) {
+	field := p.next()
+	operator := p.peek()
+	if operator.kind != tokenOperator {
+		return nil, errorAt(CodeExpectedOperator, operator.pos, map[string]any{"name": field.text})
+	}
+	p.next()
+
+	condition := &Condition{
+		Field:    strings.ToLower(field.text),
+		Operator: operator.text,
+		Position: field.pos,
+	}
+	for {
+		value := p.next()
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, errorAt(CodeExpectedValue, value.pos, nil)
+		}
+		condition.Values = append(condition.Values, value.text)
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+
+	p.conditions = append(p.conditions, condition)
+	if len(p.conditions) > MaxConditions {
+		return nil, errorAt(CodeTooComplex, field.pos, map[string]any{"max": MaxConditions})
+	}
+	return condition, nil
+}
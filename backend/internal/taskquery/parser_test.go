@@ -0,0 +1,138 @@
+package taskquery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"devopslabs/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func requireFieldError(t *testing.T, err error, code string, position int) *service.FieldError {
+	t.Helper()
+	var fieldErr *service.FieldError
+	require.True(t, errors.As(err, &fieldErr), "%v", err)
+	require.Equal(t, code, fieldErr.Code)
+	require.Equal(t, ErrorField, fieldErr.Field)
+	if position > 0 {
+		require.Equal(t, position, fieldErr.Details["position"])
+	}
+	return fieldErr
+}
+
+func TestParseBuildsTree(t *testing.T) {
+	expr, err := Parse(`priority>=high AND (owner:alice OR tag:infra) and due<7d AND NOT status:done`)
+	require.NoError(t, err)
+	require.Equal(t, "priority>=high AND (owner:alice OR tag:infra) AND due<7d AND NOT status:done", expr.String())
+
+	and, ok := expr.(*And)
+	require.True(t, ok)
+	not, ok := and.Right.(*Not)
+	require.True(t, ok)
+	require.Equal(t, &Condition{Field: "status", Operator: ":", Values: []string{"done"}, Position: 66}, withoutPredicate(not.Expr))
+}
+
+func TestParseImplicitAndPrecedence(t *testing.T) {
+	expr, err := Parse(`Owner:alice tag:ci OR tag:infra`)
+	require.NoError(t, err)
+	or, ok := expr.(*Or)
+	require.True(t, ok)
+	require.IsType(t, &And{}, or.Left)
+	require.Equal(t, "owner:alice AND tag:ci OR tag:infra", expr.String())
+
+	expr, err = Parse(`title:"release notes, v2" owner:alice,bob`)
+	require.NoError(t, err)
+	require.Equal(t, `title:"release notes, v2" AND owner:alice,bob`, expr.String())
+
+	reparsed, err := Parse(expr.String())
+	require.NoError(t, err)
+	require.Equal(t, expr.String(), reparsed.String())
+}
+
+func TestParseEmpty(t *testing.T) {
+	expr, err := Parse("   ")
+	require.NoError(t, err)
+	require.Nil(t, expr)
+}
+
+func TestParseSyntaxErrors(t *testing.T) {
+	cases := []struct {
+		input    string
+		code     string
+		position int
+	}{
+		{`status:todo AND`, CodeExpectedCondition, 16},
+		{`(status:todo OR owner:bob`, CodeUnclosedGroup, 1},
+		{`status:todo)`, CodeUnexpectedToken, 12},
+		{`status todo`, CodeExpectedOperator, 8},
+		{`owner:`, CodeExpectedValue, 7},
+		{`owner:alice,`, CodeExpectedValue, 13},
+		{`title:"unterminated`, CodeUnterminatedString, 7},
+		{`owner!alice`, CodeUnexpectedToken, 6},
+		{`ёлка:1 OR )`, CodeExpectedCondition, 11},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			requireFieldError(t, err, tc.code, tc.position)
+		})
+	}
+}
+
+func TestParseValidationErrors(t *testing.T) {
+	_, err := Parse(`priorty>=high`)
+	fieldErr := requireFieldError(t, err, CodeUnknownField, 1)
+	require.Contains(t, fieldErr.Details["allowed"], "priority")
+
+	_, err = Parse(`status>todo`)
+	requireFieldError(t, err, CodeInvalidOperator, 1)
+
+	_, err = Parse(`effort>1,2`)
+	requireFieldError(t, err, CodeSingleValue, 1)
+
+	_, err = Parse(`created:none`)
+	requireFieldError(t, err, CodeInvalidValue, 1)
+
+	_, err = Parse(`due<none`)
+	requireFieldError(t, err, CodeInvalidValue, 1)
+
+	// Ошибки с одинаковым кодом для одного поля схлопываются в первую.
+	_, err = Parse(`status:weird AND due<soon`)
+	requireFieldError(t, err, CodeInvalidValue, 1)
+
+	_, err = Parse(`status:weird AND effort<=x AND owner~bob:1`)
+	var list service.ValidationErrors
+	require.ErrorAs(t, err, &list)
+	require.Len(t, list, 2)
+	require.Equal(t, 1, list[0].Details["position"])
+	require.Equal(t, "blocked, done, in_progress, todo", list[0].Details["expected"])
+	require.Equal(t, CodeUnknownField, list[1].Code)
+	require.Equal(t, 32, list[1].Details["position"])
+}
+
+func TestParseRejectsOverflowingOffsets(t *testing.T) {
+	_, err := Parse(`status:todo AND due<99999w`)
+	fieldErr := requireFieldError(t, err, CodeInvalidValue, 17)
+	require.Equal(t, "смещение не больше ±36500d", fieldErr.Details["expected"])
+
+	_, err = Parse(`created>-36501d`)
+	requireFieldError(t, err, CodeInvalidValue, 1)
+
+	_, err = Parse(`due<5214w AND created>-36500d AND updated<99999h`)
+	require.NoError(t, err)
+}
+
+func TestParseLimits(t *testing.T) {
+	_, err := Parse(strings.Repeat("x", MaxLength+1))
+	requireFieldError(t, err, CodeTooLong, 0)
+
+	_, err = Parse(strings.TrimSuffix(strings.Repeat("effort>1 OR ", MaxConditions+1), " OR "))
+	requireFieldError(t, err, CodeTooComplex, 0)
+}
+
+func withoutPredicate(expr Expr) *Condition {
+	condition := *expr.(*Condition)
+	condition.predicate = nil
+	return &condition
+}
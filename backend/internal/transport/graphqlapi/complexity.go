@@ -0,0 +1,145 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 100
+	MaxComplexity   = 1000
+	MaxDepth        = 8
+)
+
+// pagedFields — поля со списками, стоимость элементов которых умножается
+// на размер страницы из аргумента first.
+var pagedFields = map[string]string{
+	"tasks": "nodes",
+}
+
+type queryCost struct {
+	Complexity int
+	Depth      int
+}
+
+// analyzeQuery оценивает выбранную операцию до выполнения: каждое поле
+// стоит 1, а элементы постраничных списков — 1 за поле на каждый элемент
+// страницы. Документ должен пройти проверку схемой, иначе циклы фрагментов
+// не исключены.
+func analyzeQuery(operation *ast.OperationDefinition, fragments map[string]*ast.FragmentDefinition, variables map[string]any) queryCost {
+	analyzer := costAnalyzer{fragments: fragments, variables: withDefaults(operation, variables)}
+	complexity, depth := analyzer.selections(operation.SelectionSet, "", 1)
+	return queryCost{Complexity: complexity, Depth: depth}
+}
+
+// withDefaults дополняет переданные переменные значениями по умолчанию из
+// объявления операции: без них $first: Int = 100000 обходил бы ограничение.
+func withDefaults(operation *ast.OperationDefinition, variables map[string]any) map[string]any {
+	resolved := make(map[string]any, len(variables))
+	for name, value := range variables {
+		resolved[name] = value
+	}
+	for _, definition := range operation.VariableDefinitions {
+		name := definition.Variable.Name.Value
+		if _, ok := resolved[name]; ok {
+			continue
+		}
+		if value, ok := definition.DefaultValue.(*ast.IntValue); ok {
+			if parsed, err := strconv.Atoi(value.Value); err == nil {
+				resolved[name] = parsed
+			}
+		}
+	}
+	return resolved
+}
+
+type costAnalyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// selections возвращает стоимость и глубину набора полей; стоимость поля
+// pagedChild умножается на размер страницы родителя.
+func (a costAnalyzer) selections(set *ast.SelectionSet, pagedChild string, pageSize int) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	complexity, depth := 0, 0
+	for _, selection := range set.Selections {
+		var cost, nested int
+		switch node := selection.(type) {
+		case *ast.Field:
+			// Интроспекция нужна инструментам вроде GraphiQL и не обращается
+			// к хранилищу, поэтому в оценку не входит.
+			if strings.HasPrefix(node.Name.Value, "__") {
+				continue
+			}
+			cost, nested = a.field(node)
+			if pagedChild != "" && node.Name.Value == pagedChild {
+				cost *= pageSize
+			}
+		case *ast.InlineFragment:
+			cost, nested = a.selections(node.SelectionSet, pagedChild, pageSize)
+		case *ast.FragmentSpread:
+			if fragment, ok := a.fragments[node.Name.Value]; ok {
+				cost, nested = a.selections(fragment.SelectionSet, pagedChild, pageSize)
+			}
+		}
+		complexity += cost
+		depth = max(depth, nested)
+	}
+	return complexity, depth
+}
+
+func (a costAnalyzer) field(node *ast.Field) (int, int) {
+	if node.SelectionSet == nil {
+		return 1, 1
+	}
+
+	pagedChild, paged := pagedFields[node.Name.Value]
+	pageSize := 1
+	if paged {
+		pageSize = a.pageSize(node)
+	} else {
+		pagedChild = ""
+	}
+	cost, depth := a.selections(node.SelectionSet, pagedChild, pageSize)
+	return 1 + cost, 1 + depth
+}
+
+func (a costAnalyzer) pageSize(node *ast.Field) int {
+	for _, argument := range node.Arguments {
+		if argument.Name.Value != "first" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if parsed, err := strconv.Atoi(value.Value); err == nil {
+				return max(parsed, 1)
+			}
+		case *ast.Variable:
+			if parsed, ok := intVariable(a.variables[value.Name.Value]); ok {
+				return max(parsed, 1)
+			}
+		}
+	}
+	return DefaultPageSize
+}
+
+func intVariable(value any) (int, bool) {
+	switch typed := value.(type) {
+	case int:
+		return typed, true
+	case float64:
+		return int(typed), true
+	case json.Number:
+		parsed, err := typed.Int64()
+		return int(parsed), err == nil
+	}
+	return 0, false
+}
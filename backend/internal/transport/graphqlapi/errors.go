@@ -0,0 +1,133 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+
+	"devopslabs/internal/i18n"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+)
+
+const (
+	CodeQueryRequired      = "graphql_query_required"
+	CodeMutationNotAllowed = "graphql_mutation_requires_post"
+	CodeTooComplex         = "query_too_complex"
+	CodeTooDeep            = "query_too_deep"
+	CodeInvalidCursor      = "invalid_cursor"
+	CodeInvalidID          = "invalid_id"
+	CodeOutOfRange         = "out_of_range"
+)
+
+type storeErrorMapping struct {
+	target error
+	code   string
+}
+
+var storeErrorMappings = []storeErrorMapping{
+	{target: repository.ErrNotFound, code: "not_found"},
+	{target: repository.ErrConflict, code: "conflict"},
+	{target: repository.ErrValidation, code: "validation_failed"},
+	{target: repository.ErrUnavailable, code: "unavailable"},
+}
+
+// Error — ошибка с кодом, полем и параметрами в extensions; поля совпадают
+// с ErrorResponse REST API, поэтому клиенты разбирают оба API одинаково.
+type Error struct {
+	Message string
+	Code    string
+	Field   string
+	Details map[string]any
+	Errors  []*Error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	extensions := e.extensions()
+	if len(e.Errors) > 0 {
+		items := make([]map[string]any, 0, len(e.Errors))
+		for _, item := range e.Errors {
+			entry := item.extensions()
+			entry["message"] = item.Message
+			items = append(items, entry)
+		}
+		extensions["errors"] = items
+	}
+	return extensions
+}
+
+func (e *Error) extensions() map[string]any {
+	extensions := map[string]any{"code": e.Code}
+	if e.Field != "" {
+		extensions["field"] = e.Field
+	}
+	if len(e.Details) > 0 {
+		extensions["details"] = e.Details
+	}
+	return extensions
+}
+
+type languageKey struct{}
+
+func withLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+func languageFrom(ctx context.Context) string {
+	if language, ok := ctx.Value(languageKey{}).(string); ok && language != "" {
+		return language
+	}
+	return i18n.DefaultLanguage
+}
+
+func newError(language string, code string, field string, details map[string]any) *Error {
+	return &Error{
+		Message: i18n.Translate(language, code, details),
+		Code:    code,
+		Field:   field,
+		Details: details,
+	}
+}
+
+// toError сопоставляет ошибки проверки и хранилища с кодами так же, как
+// respondStoreError в REST API; fallbackKey задаёт сообщение для прочих ошибок.
+func toError(ctx context.Context, err error, fallbackKey string) error {
+	language := languageFrom(ctx)
+
+	var list service.ValidationErrors
+	var fieldErr *service.FieldError
+	switch {
+	case errors.As(err, &list):
+	case errors.As(err, &fieldErr):
+		list = service.ValidationErrors{fieldErr}
+	}
+	if len(list) > 0 {
+		items := make([]*Error, 0, len(list))
+		for _, item := range list {
+			items = append(items, &Error{
+				Message: item.Localize(language),
+				Code:    item.Code,
+				Field:   item.Field,
+				Details: item.Details,
+			})
+		}
+		if len(items) == 1 {
+			return items[0]
+		}
+		result := newError(language, "validation_failed", "", nil)
+		result.Errors = items
+		return result
+	}
+
+	for _, mapping := range storeErrorMappings {
+		if errors.Is(err, mapping.target) {
+			return newError(language, mapping.code, "", nil)
+		}
+	}
+	result := newError(language, fallbackKey, "", nil)
+	result.Code = "internal"
+	return result
+}
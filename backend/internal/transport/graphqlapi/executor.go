@@ -0,0 +1,127 @@
+package graphqlapi
+
+import (
+	"context"
+	"strings"
+
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Options struct {
+	// Language задаёт язык сообщений об ошибках.
+	Language string
+	// ReadOnly запрещает мутации, например для запросов GET.
+	ReadOnly bool
+}
+
+type Executor struct {
+	schema graphql.Schema
+	store  repository.TaskStore
+}
+
+// NewExecutor строит схему поверх хранилища задач; без policy оценка и риск
+// считаются по DefaultPolicy. Схема статична, поэтому ошибка её построения —
+// ошибка программы, а не окружения.
+func NewExecutor(store repository.TaskStore, clock service.Clock, policy *service.Policy) *Executor {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	schema, err := newSchema(&resolver{store: store, clock: clock, policy: policy.OrDefault()})
+	if err != nil {
+		panic(err)
+	}
+	return &Executor{schema: schema, store: store}
+}
+
+// Execute разбирает и проверяет запрос, оценивает его сложность и только
+// затем выполняет. Результат без data означает, что запрос отклонён целиком.
+func (e *Executor) Execute(ctx context.Context, req Request, options Options) *graphql.Result {
+	if strings.TrimSpace(req.Query) == "" {
+		return Rejected(options.Language, CodeQueryRequired, "query")
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&e.schema, document, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	if operation, fragments := selectOperation(document, req.OperationName); operation != nil {
+		if options.ReadOnly && operation.Operation != ast.OperationTypeQuery {
+			return rejected(newError(options.Language, CodeMutationNotAllowed, "", nil))
+		}
+
+		cost := analyzeQuery(operation, fragments, req.Variables)
+		if cost.Depth > MaxDepth {
+			return rejected(newError(options.Language, CodeTooDeep, "", map[string]any{"depth": cost.Depth, "max": MaxDepth}))
+		}
+		if cost.Complexity > MaxComplexity {
+			return rejected(newError(options.Language, CodeTooComplex, "", map[string]any{"complexity": cost.Complexity, "max": MaxComplexity}))
+		}
+	}
+
+	ctx = withLanguage(withLoader(ctx, newTaskLoader(e.store)), options.Language)
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           document,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+// selectOperation находит операцию так же, как graphql.Execute; если её нет,
+// ошибку вернёт сам Execute.
+func selectOperation(document *ast.Document, name string) (*ast.OperationDefinition, map[string]*ast.FragmentDefinition) {
+	var operations []*ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, definition := range document.Definitions {
+		switch node := definition.(type) {
+		case *ast.OperationDefinition:
+			operations = append(operations, node)
+		case *ast.FragmentDefinition:
+			fragments[node.Name.Value] = node
+		}
+	}
+
+	for _, operation := range operations {
+		if name == "" && len(operations) == 1 {
+			return operation, fragments
+		}
+		if name != "" && operation.Name != nil && operation.Name.Value == name {
+			return operation, fragments
+		}
+	}
+	return nil, fragments
+}
+
+// Rejected возвращает ответ на запрос, который нельзя выполнить, например
+// из-за некорректного тела.
+func Rejected(language string, code string, field string) *graphql.Result {
+	return rejected(newError(language, code, field, nil))
+}
+
+func rejected(err *Error) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+		Message:    err.Message,
+		Locations:  []location.SourceLocation{},
+		Extensions: err.Extensions(),
+	}}}
+}
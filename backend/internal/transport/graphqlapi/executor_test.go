@@ -0,0 +1,273 @@
+package graphqlapi
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/require"
+)
+
+type countingStore struct {
+	tasks     []domain.Task
+	listCalls []repository.TaskFilter
+}
+
+func (s *countingStore) List(_ context.Context, filter repository.TaskFilter) ([]domain.Task, error) {
+	s.listCalls = append(s.listCalls, filter)
+	var result []domain.Task
+	for _, task := range s.tasks {
+		if filter.Matches(task) {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+func (s *countingStore) Get(_ context.Context, id uint) (*domain.Task, error) {
+	for _, task := range s.tasks {
+		if task.ID == id {
+			return &task, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (s *countingStore) Create(_ context.Context, task *domain.Task) error {
+	task.ID = uint(len(s.tasks) + 1)
+	s.tasks = append(s.tasks, *task)
+	return nil
+}
+
+func (s *countingStore) Update(_ context.Context, task *domain.Task) error {
+	for i := range s.tasks {
+		if s.tasks[i].ID == task.ID {
+			s.tasks[i] = *task
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (s *countingStore) Delete(context.Context, uint) error {
+	return nil
+}
+
+var testClock = service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+
+func newTestExecutor(tasks ...domain.Task) (*Executor, *countingStore) {
+	store := &countingStore{tasks: tasks}
+	return NewExecutor(store, testClock, nil), store
+}
+
+func sampleTasks() []domain.Task {
+	created := testClock.NowValue.Add(-48 * time.Hour)
+	return []domain.Task{
+		{ID: 1, Title: "Plan", Status: domain.StatusTodo, Priority: domain.PriorityLow, Owner: "anna", EffortHours: 2, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, Title: "Build", Status: domain.StatusInProgress, Priority: domain.PriorityHigh, Owner: "ivan", EffortHours: 5, CreatedAt: created, UpdatedAt: created},
+		{ID: 3, Title: "Ship", Status: domain.StatusBlocked, Priority: domain.PriorityCritical, Owner: "anna", EffortHours: 3, CreatedAt: created, UpdatedAt: created},
+	}
+}
+
+func errorCode(t *testing.T, result *graphql.Result) string {
+	t.Helper()
+	require.NotEmpty(t, result.Errors)
+	code, _ := result.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+func mustOperation(t *testing.T, query string) *ast.OperationDefinition {
+	t.Helper()
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	require.NoError(t, err)
+	operation, _ := selectOperation(document, "")
+	require.NotNil(t, operation)
+	return operation
+}
+
+func mustFragments(t *testing.T, query string) map[string]*ast.FragmentDefinition {
+	t.Helper()
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	require.NoError(t, err)
+	_, fragments := selectOperation(document, "")
+	return fragments
+}
+
+func TestExecutorBatchesTaskLookups(t *testing.T) {
+	executor, store := newTestExecutor(sampleTasks()...)
+
+	result := executor.Execute(context.Background(), Request{
+		Query: `{ a: task(id: "1") { title } b: task(id: "3") { title risk } missing: task(id: "42") { title } }`,
+	}, Options{})
+	require.False(t, result.HasErrors(), "%v", result.Errors)
+
+	data := result.Data.(map[string]any)
+	require.Equal(t, "Plan", data["a"].(map[string]any)["title"])
+	require.Equal(t, "Ship", data["b"].(map[string]any)["title"])
+	require.Equal(t, service.RiskBlocked, data["b"].(map[string]any)["risk"])
+	require.Nil(t, data["missing"])
+
+	require.Len(t, store.listCalls, 1)
+	ids := store.listCalls[0].IDs
+	slices.Sort(ids)
+	require.Equal(t, []uint{1, 3, 42}, ids)
+}
+
+func TestExecutorReusesListedTasks(t *testing.T) {
+	executor, store := newTestExecutor(sampleTasks()...)
+
+	result := executor.Execute(context.Background(), Request{
+		Query: `{ tasks(first: 2, sort: "title", order: "asc") { totalCount nodes { id } pageInfo { hasNextPage endCursor } } task(id: "2") { title } }`,
+	}, Options{})
+	require.False(t, result.HasErrors(), "%v", result.Errors)
+	require.Len(t, store.listCalls, 1)
+
+	data := result.Data.(map[string]any)
+	connection := data["tasks"].(map[string]any)
+	require.Equal(t, 3, connection["totalCount"])
+	require.Equal(t, []any{map[string]any{"id": "2"}, map[string]any{"id": "1"}}, connection["nodes"])
+	pageInfo := connection["pageInfo"].(map[string]any)
+	require.Equal(t, true, pageInfo["hasNextPage"])
+	require.Equal(t, "Build", data["task"].(map[string]any)["title"])
+
+	next := executor.Execute(context.Background(), Request{
+		Query:     `query Next($after: String) { tasks(first: 2, sort: "title", order: "asc", after: $after) { nodes { id } pageInfo { hasNextPage endCursor } } }`,
+		Variables: map[string]any{"after": pageInfo["endCursor"]},
+	}, Options{})
+	require.False(t, next.HasErrors(), "%v", next.Errors)
+	connection = next.Data.(map[string]any)["tasks"].(map[string]any)
+	require.Equal(t, []any{map[string]any{"id": "3"}}, connection["nodes"])
+	require.Equal(t, map[string]any{"hasNextPage": false, "endCursor": encodeCursor(3)}, connection["pageInfo"])
+}
+
+func TestExecutorRejectsExpensiveQueries(t *testing.T) {
+	executor, store := newTestExecutor(sampleTasks()...)
+
+	tooComplex := executor.Execute(context.Background(), Request{
+		Query:     `query($first: Int) { tasks(first: $first) { nodes { id title description status priority owner effortHours tags risk score ageHours } } }`,
+		Variables: map[string]any{"first": float64(MaxPageSize)},
+	}, Options{Language: "en"})
+	require.Nil(t, tooComplex.Data)
+	require.Equal(t, CodeTooComplex, errorCode(t, tooComplex))
+	require.Equal(t, "query complexity 1201 exceeds the limit of 1000", tooComplex.Errors[0].Message)
+
+	// Значение по умолчанию у переменной учитывается так же, как переданное.
+	byDefault := executor.Execute(context.Background(), Request{
+		Query: `query($first: Int = 100000) { tasks(first: $first) { nodes { id title } } }`,
+	}, Options{Language: "en"})
+	require.Nil(t, byDefault.Data)
+	require.Equal(t, CodeTooComplex, errorCode(t, byDefault))
+	// Переданное значение важнее значения по умолчанию.
+	explicit := analyzeQuery(mustOperation(t, `query($first: Int = 100000) { tasks(first: $first) { nodes { id title } } }`), nil, map[string]any{"first": float64(3)})
+	require.Equal(t, 1+(1+2)*3, explicit.Complexity)
+
+	tooDeep := analyzeQuery(mustOperation(t, `{ a { b { c { d { e { f { g { h { i } } } } } } } } }`), nil, nil)
+	require.Equal(t, 9, tooDeep.Depth)
+
+	require.Empty(t, store.listCalls)
+}
+
+func TestAnalyzeQueryFollowsFragments(t *testing.T) {
+	document := `
+		query { tasks(first: 10) { totalCount nodes { ...fields ... on Task { owner } } } __schema { types { name } } }
+		fragment fields on Task { id title }`
+	cost := analyzeQuery(mustOperation(t, document), mustFragments(t, document), nil)
+	require.Equal(t, 1+1+(1+3)*10, cost.Complexity)
+	require.Equal(t, 3, cost.Depth)
+}
+
+func TestExecutorReadOnlyRejectsMutations(t *testing.T) {
+	executor, store := newTestExecutor()
+
+	result := executor.Execute(context.Background(), Request{
+		Query: `mutation { createTask(input: {title: "Ship"}) { id } }`,
+	}, Options{ReadOnly: true})
+	require.Equal(t, CodeMutationNotAllowed, errorCode(t, result))
+	require.Empty(t, store.tasks)
+
+	empty := executor.Execute(context.Background(), Request{Query: "  "}, Options{})
+	require.Equal(t, CodeQueryRequired, errorCode(t, empty))
+
+	invalid := executor.Execute(context.Background(), Request{Query: `{ tasks { unknown } }`}, Options{})
+	require.Nil(t, invalid.Data)
+	require.NotEmpty(t, invalid.Errors)
+}
+
+func TestExecutorMutationsReportFieldErrors(t *testing.T) {
+	executor, _ := newTestExecutor(sampleTasks()...)
+
+	result := executor.Execute(context.Background(), Request{
+		Query: `mutation { createTask(input: {title: " ", status: "weird", dueDate: "tomorrow"}) { id } }`,
+	}, Options{Language: "en"})
+	require.Equal(t, "validation_failed", errorCode(t, result))
+
+	items := result.Errors[0].Extensions["errors"].([]map[string]any)
+	codes := make(map[string]string, len(items))
+	for _, item := range items {
+		codes[item["field"].(string)] = item["code"].(string)
+	}
+	require.Equal(t, map[string]string{
+		"dueDate": service.CodeInvalidDueDate,
+		"title":   service.CodeTitleRequired,
+		"status":  service.CodeInvalidStatus,
+	}, codes)
+
+	transition := executor.Execute(context.Background(), Request{
+		Query: `mutation { transitionTask(id: "1", status: "done") { status } }`,
+	}, Options{})
+	require.Equal(t, service.CodeInvalidTransition, errorCode(t, transition))
+	require.Equal(t, "status", transition.Errors[0].Extensions["field"])
+
+	missing := executor.Execute(context.Background(), Request{
+		Query: `mutation { updateTask(id: "42", input: {owner: "bob"}) { id } }`,
+	}, Options{})
+	require.Equal(t, "not_found", errorCode(t, missing))
+
+	badID := executor.Execute(context.Background(), Request{Query: `{ task(id: "x") { id } }`}, Options{})
+	require.Equal(t, CodeInvalidID, errorCode(t, badID))
+}
+
+func TestExecutorFiltersByExpression(t *testing.T) {
+	executor, _ := newTestExecutor(sampleTasks()...)
+
+	result := executor.Execute(context.Background(), Request{
+		Query: `{ tasks(filter: {expression: "owner:anna AND priority>=high"}) { nodes { title } } }`,
+	}, Options{})
+	require.False(t, result.HasErrors(), "%v", result.Errors)
+	connection := result.Data.(map[string]any)["tasks"].(map[string]any)
+	require.Equal(t, []any{map[string]any{"title": "Ship"}}, connection["nodes"])
+
+	invalid := executor.Execute(context.Background(), Request{
+		Query: `{ insights(filter: {expression: "owner:anna AND"}) { total } }`,
+	}, Options{Language: "en"})
+	require.Equal(t, taskquery.CodeExpectedCondition, errorCode(t, invalid))
+	require.Equal(t, 15, invalid.Errors[0].Extensions["details"].(map[string]any)["position"])
+}
+
+func TestExecutorResolvesSLA(t *testing.T) {
+	policy, err := service.ParsePolicy([]byte(`{"sla": {"targets": {"critical": {"resolveHours": 24}}}}`))
+	require.NoError(t, err)
+	executor := NewExecutor(&countingStore{tasks: sampleTasks()}, testClock, &policy)
+
+	result := executor.Execute(context.Background(), Request{
+		Query: `{ a: task(id: "1") { sla { breached } } b: task(id: "3") { sla { breached response { state } resolve { state remainingHours } } } insights { slaBreached slaImminent } }`,
+	}, Options{})
+	require.False(t, result.HasErrors(), "%v", result.Errors)
+
+	data := result.Data.(map[string]any)
+	require.Nil(t, data["a"].(map[string]any)["sla"])
+	require.Equal(t, map[string]any{
+		"breached": true,
+		"response": nil,
+		"resolve":  map[string]any{"state": service.SLABreached, "remainingHours": -24.0},
+	}, data["b"].(map[string]any)["sla"])
+	require.Equal(t, map[string]any{"slaBreached": 1, "slaImminent": 0}, data["insights"])
+}
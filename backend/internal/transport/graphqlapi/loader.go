@@ -0,0 +1,105 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+)
+
+type loaderKey struct{}
+
+// taskLoader откладывает загрузку задач по идентификатору до разрешения
+// отложенных значений: все task(id) одного уровня запроса загружаются одним
+// вызовом List. Загрузчик живёт в пределах одного запроса и кэширует задачи,
+// уже полученные другими полями.
+type taskLoader struct {
+	store repository.TaskStore
+
+	mu      sync.Mutex
+	pending []uint
+	tasks   map[uint]domain.Task
+	errs    map[uint]error
+	done    map[uint]bool
+}
+
+func newTaskLoader(store repository.TaskStore) *taskLoader {
+	return &taskLoader{
+		store: store,
+		tasks: make(map[uint]domain.Task),
+		errs:  make(map[uint]error),
+		done:  make(map[uint]bool),
+	}
+}
+
+func withLoader(ctx context.Context, loader *taskLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+func loaderFrom(ctx context.Context) *taskLoader {
+	return ctx.Value(loaderKey{}).(*taskLoader)
+}
+
+// Load ставит id в очередь и возвращает функцию, которая загружает всю
+// очередь при первом вызове. Отсутствующая задача даёт nil без ошибки.
+func (l *taskLoader) Load(ctx context.Context, id uint) func() (*domain.Task, error) {
+	l.mu.Lock()
+	if !l.done[id] {
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (*domain.Task, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if !l.done[id] {
+			l.flushLocked(ctx)
+		}
+		if err := l.errs[id]; err != nil {
+			return nil, err
+		}
+		task, ok := l.tasks[id]
+		if !ok {
+			return nil, nil
+		}
+		return &task, nil
+	}
+}
+
+// Prime кэширует уже загруженные задачи, например результаты списка.
+func (l *taskLoader) Prime(tasks ...domain.Task) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, task := range tasks {
+		l.tasks[task.ID] = task
+		l.done[task.ID] = true
+		delete(l.errs, task.ID)
+	}
+}
+
+func (l *taskLoader) flushLocked(ctx context.Context) {
+	ids := make([]uint, 0, len(l.pending))
+	for _, id := range l.pending {
+		if !l.done[id] {
+			ids = append(ids, id)
+			l.done[id] = true
+		}
+	}
+	l.pending = nil
+	if len(ids) == 0 {
+		return
+	}
+
+	tasks, err := l.store.List(ctx, repository.TaskFilter{IDs: ids})
+	if err != nil {
+		for _, id := range ids {
+			l.errs[id] = err
+		}
+		return
+	}
+	for _, task := range tasks {
+		l.tasks[task.ID] = task
+	}
+}
@@ -0,0 +1,413 @@
+package graphqlapi
+
+import (
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
+	"github.com/graphql-go/graphql"
+)
+
+var applyStatusTransition = service.ApplyStatusTransition
+
+type resolver struct {
+	store  repository.TaskStore
+	clock  service.Clock
+	policy service.Policy
+}
+
+// taskNode — задача с вычисляемыми полями; метрики считаются один раз и
+// только если запрос выбрал risk, score, ageHours, statusHours, cycleHours
+// или sla.
+type taskNode struct {
+	task   domain.Task
+	now    time.Time
+	policy service.Policy
+
+	once   sync.Once
+	values service.TaskMetrics
+}
+
+func newTaskNode(task domain.Task, now time.Time, policy service.Policy) *taskNode {
+	return &taskNode{task: task, now: now, policy: policy}
+}
+
+func (n *taskNode) metrics() service.TaskMetrics {
+	n.once.Do(func() {
+		n.values = n.policy.Metrics(n.now, n.task)
+	})
+	return n.values
+}
+
+func (n *taskNode) cycleHours() any {
+	if hours := n.metrics().CycleHours; hours != nil {
+		return *hours
+	}
+	return nil
+}
+
+func (n *taskNode) sla() any {
+	if sla := n.metrics().SLA; sla != nil {
+		return sla
+	}
+	return nil
+}
+
+func (n *taskNode) tags() []string {
+	if n.task.Tags == nil {
+		return []string{}
+	}
+	return []string(n.task.Tags)
+}
+
+func (r *resolver) tasks(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+
+	var errs service.ValidationErrors
+	now := r.clock.Now()
+	filter, err := filterFromArgs(p.Args["filter"], now)
+	errs.Add(err)
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > MaxPageSize {
+		errs.Add(service.NewFieldError(CodeOutOfRange, "first", map[string]any{"min": 1, "max": MaxPageSize}))
+	}
+	after, _ := p.Args["after"].(string)
+	offset, err := decodeCursor(after)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		return nil, toError(ctx, err, "task_list_failed")
+	}
+
+	tasks, err := r.store.List(ctx, filter)
+	if err != nil {
+		return nil, toError(ctx, err, "task_list_failed")
+	}
+	loaderFrom(ctx).Prime(tasks...)
+
+	sortArg, _ := p.Args["sort"].(string)
+	orderArg, _ := p.Args["order"].(string)
+	r.policy.Sort(tasks, service.NormalizeSort(sortArg, orderArg), now)
+
+	start := min(offset, len(tasks))
+	end := min(start+first, len(tasks))
+	nodes := make([]*taskNode, 0, end-start)
+	for _, task := range tasks[start:end] {
+		nodes = append(nodes, newTaskNode(task, now, r.policy))
+	}
+
+	var endCursor any
+	if end > start {
+		endCursor = encodeCursor(end)
+	}
+	return map[string]any{
+		"totalCount": len(tasks),
+		"nodes":      nodes,
+		"pageInfo":   map[string]any{"hasNextPage": end < len(tasks), "endCursor": endCursor},
+	}, nil
+}
+
+func (r *resolver) task(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, toError(ctx, err, "task_get_failed")
+	}
+
+	load := loaderFrom(ctx).Load(ctx, id)
+	return func() (any, error) {
+		task, err := load()
+		if err != nil {
+			return nil, toError(ctx, err, "task_get_failed")
+		}
+		if task == nil {
+			return nil, nil
+		}
+		return newTaskNode(*task, r.clock.Now(), r.policy), nil
+	}, nil
+}
+
+func (r *resolver) insights(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	now := r.clock.Now()
+	filter, err := filterFromArgs(p.Args["filter"], now)
+	if err != nil {
+		return nil, toError(ctx, err, "insights_failed")
+	}
+
+	tasks, err := r.store.List(ctx, filter)
+	if err != nil {
+		return nil, toError(ctx, err, "insights_failed")
+	}
+
+	insights := r.policy.Insights(now, tasks)
+	return map[string]any{
+		"total":             insights.Total,
+		"byStatus":          counts(insights.ByStatus),
+		"byPriority":        counts(insights.ByPriority),
+		"overdue":           insights.Overdue,
+		"atRisk":            insights.AtRisk,
+		"blocked":           insights.Blocked,
+		"done":              insights.Done,
+		"averageAgeHours":   insights.AverageAgeHours,
+		"averageCycleHours": insights.AverageCycleHours,
+		"workloadHours":     insights.WorkloadHours,
+		"focusIndex":        insights.FocusIndex,
+		"slaBreached":       insights.SLABreached,
+		"slaImminent":       insights.SLAImminent,
+	}, nil
+}
+
+func (r *resolver) createTask(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	input, err := normalizeInput(service.TaskInput{}, p.Args["input"])
+	if err != nil {
+		return nil, toError(ctx, err, "task_create_failed")
+	}
+
+	now := r.clock.Now()
+	var task domain.Task
+	if err := applyInput(now, &task, input, true); err != nil {
+		return nil, toError(ctx, err, "task_create_failed")
+	}
+	if err := r.store.Create(ctx, &task); err != nil {
+		return nil, toError(ctx, err, "task_create_failed")
+	}
+	loaderFrom(ctx).Prime(task)
+	return newTaskNode(task, now, r.policy), nil
+}
+
+func (r *resolver) updateTask(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, toError(ctx, err, "task_update_failed")
+	}
+
+	task, err := r.store.Get(ctx, id)
+	if err != nil {
+		return nil, toError(ctx, err, "task_load_failed")
+	}
+
+	input, err := normalizeInput(service.InputFromTask(*task), p.Args["input"])
+	if err != nil {
+		return nil, toError(ctx, err, "task_update_failed")
+	}
+
+	force, _ := p.Args["force"].(bool)
+	now := r.clock.Now()
+	if err := applyInput(now, task, input, force); err != nil {
+		return nil, toError(ctx, err, "task_update_failed")
+	}
+	return r.save(p, task, now)
+}
+
+func (r *resolver) transitionTask(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, toError(ctx, err, "task_update_failed")
+	}
+
+	task, err := r.store.Get(ctx, id)
+	if err != nil {
+		return nil, toError(ctx, err, "task_load_failed")
+	}
+
+	status, _ := p.Args["status"].(string)
+	force, _ := p.Args["force"].(bool)
+	now := r.clock.Now()
+	if err := applyStatusTransition(now, task, status, force); err != nil {
+		return nil, toError(ctx, err, "task_update_failed")
+	}
+	return r.save(p, task, now)
+}
+
+func (r *resolver) save(p graphql.ResolveParams, task *domain.Task, now time.Time) (any, error) {
+	if err := r.store.Update(p.Context, task); err != nil {
+		return nil, toError(p.Context, err, "task_update_failed")
+	}
+	loaderFrom(p.Context).Prime(*task)
+	return newTaskNode(*task, now, r.policy), nil
+}
+
+// applyInput переносит нормализованные поля в задачу; статус меняется по
+// графу переходов.
+func applyInput(now time.Time, task *domain.Task, input service.TaskInput, force bool) error {
+	updated := *task
+	input.ApplyTo(&updated)
+	if err := applyStatusTransition(now, &updated, input.Status, force); err != nil {
+		return err
+	}
+
+	*task = updated
+	return nil
+}
+
+// normalizeInput переносит в base только поля, переданные в аргументе input,
+// и нормализует результат; ошибки собираются по всем полям сразу.
+func normalizeInput(base service.TaskInput, raw any) (service.TaskInput, error) {
+	fields, _ := raw.(map[string]any)
+	var errs service.ValidationErrors
+
+	if value, ok := fields["title"].(string); ok {
+		base.Title = value
+	}
+	if value, ok := fields["description"].(string); ok {
+		base.Description = value
+	}
+	if value, ok := fields["status"].(string); ok {
+		base.Status = value
+	}
+	if value, ok := fields["priority"].(string); ok {
+		base.Priority = value
+	}
+	if value, ok := fields["owner"].(string); ok {
+		base.Owner = value
+	}
+	if value, ok := fields["effortHours"].(int); ok {
+		base.EffortHours = value
+	}
+	if value, ok := fields["dueDate"].(string); ok {
+		dueDate, err := parseDueDate(value)
+		errs.Add(err)
+		base.DueDate = dueDate
+	}
+	if value, ok := fields["tags"]; ok {
+		base.Tags = stringsFromArg(value)
+	}
+
+	normalized, err := service.NormalizeTaskInput(base)
+	errs.Add(err)
+	return normalized, errs.Err()
+}
+
+func filterFromArgs(raw any, now time.Time) (repository.TaskFilter, error) {
+	fields, _ := raw.(map[string]any)
+	var errs service.ValidationErrors
+
+	var ids []uint
+	for _, value := range listArg(fields["ids"]) {
+		id, err := parseID(value, "filter.ids")
+		errs.Add(err)
+		ids = append(ids, id)
+	}
+	statuses, err := normalizeAll(stringsFromArg(fields["statuses"]), service.NormalizeStatus)
+	errs.Add(err)
+	priorities, err := normalizeAll(stringsFromArg(fields["priorities"]), service.NormalizePriority)
+	errs.Add(err)
+	source, _ := fields["expression"].(string)
+	expression, err := taskquery.Parse(source)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		return repository.TaskFilter{}, err
+	}
+
+	owner, _ := fields["owner"].(string)
+	tag, _ := fields["tag"].(string)
+	query, _ := fields["q"].(string)
+	return repository.TaskFilter{
+		IDs:        ids,
+		Statuses:   statuses,
+		Priorities: priorities,
+		Owner:      strings.TrimSpace(owner),
+		Tag:        strings.TrimSpace(tag),
+		Query:      strings.TrimSpace(query),
+		Expression: expression,
+		Now:        now,
+	}, nil
+}
+
+func normalizeAll(values []string, normalize func(string) (string, error)) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		normalized, err := normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, normalized)
+	}
+	return result, nil
+}
+
+func parseID(raw any, field string) (uint, error) {
+	value, _ := raw.(string)
+	id, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+	if err != nil || id == 0 {
+		return 0, service.NewFieldError(CodeInvalidID, field, nil)
+	}
+	return uint(id), nil
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func parseDueDate(raw string) (*time.Time, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, service.NewFieldError(service.CodeInvalidDueDate, "dueDate", nil)
+	}
+	return &parsed, nil
+}
+
+// Курсор — смещение в отсортированном списке; клиенты должны считать его
+// непрозрачной строкой.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, service.NewFieldError(CodeInvalidCursor, "after", nil)
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, service.NewFieldError(CodeInvalidCursor, "after", nil)
+	}
+	return offset, nil
+}
+
+func listArg(raw any) []any {
+	values, _ := raw.([]any)
+	return values
+}
+
+func stringsFromArg(raw any) []string {
+	values := listArg(raw)
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if text, ok := value.(string); ok {
+			result = append(result, text)
+		}
+	}
+	return result
+}
+
+func counts(values map[string]int) []map[string]any {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]map[string]any, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, map[string]any{"key": key, "count": values[key]})
+	}
+	return result
+}
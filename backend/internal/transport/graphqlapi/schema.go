@@ -0,0 +1,204 @@
+package graphqlapi
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+func newSchema(r *resolver) (graphql.Schema, error) {
+	slaClockType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "SlaClock",
+		Description: "Часы цели SLA в рабочих часах; state: running, imminent, met или breached.",
+		Fields: graphql.Fields{
+			"targetHours":    {Type: graphql.NewNonNull(graphql.Float)},
+			"elapsedHours":   {Type: graphql.NewNonNull(graphql.Float)},
+			"remainingHours": {Type: graphql.NewNonNull(graphql.Float)},
+			"state":          {Type: graphql.NewNonNull(graphql.String)},
+			"breached":       {Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+
+	slaType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SlaStatus",
+		Fields: graphql.Fields{
+			"response": {Type: slaClockType},
+			"resolve":  {Type: slaClockType},
+			"breached": {Type: graphql.NewNonNull(graphql.Boolean)},
+			"imminent": {Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+
+	taskType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Task",
+		Fields: graphql.Fields{
+			"id":              {Type: graphql.NewNonNull(graphql.ID), Resolve: taskField(func(n *taskNode) any { return formatID(n.task.ID) })},
+			"title":           {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(n *taskNode) any { return n.task.Title })},
+			"description":     {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(n *taskNode) any { return n.task.Description })},
+			"status":          {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(n *taskNode) any { return n.task.Status })},
+			"priority":        {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(n *taskNode) any { return n.task.Priority })},
+			"owner":           {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(n *taskNode) any { return n.task.Owner })},
+			"effortHours":     {Type: graphql.NewNonNull(graphql.Int), Resolve: taskField(func(n *taskNode) any { return n.task.EffortHours })},
+			"tags":            {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))), Resolve: taskField(func(n *taskNode) any { return n.tags() })},
+			"dueDate":         {Type: graphql.DateTime, Resolve: taskField(func(n *taskNode) any { return n.task.DueDate })},
+			"startedAt":       {Type: graphql.DateTime, Resolve: taskField(func(n *taskNode) any { return n.task.StartedAt })},
+			"completedAt":     {Type: graphql.DateTime, Resolve: taskField(func(n *taskNode) any { return n.task.CompletedAt })},
+			"statusChangedAt": {Type: graphql.DateTime, Resolve: taskField(func(n *taskNode) any { return n.task.StatusChangedAt })},
+			"createdAt":       {Type: graphql.NewNonNull(graphql.DateTime), Resolve: taskField(func(n *taskNode) any { return n.task.CreatedAt })},
+			"updatedAt":       {Type: graphql.NewNonNull(graphql.DateTime), Resolve: taskField(func(n *taskNode) any { return n.task.UpdatedAt })},
+			"risk":            {Type: graphql.NewNonNull(graphql.String), Resolve: taskField(func(n *taskNode) any { return n.metrics().Risk })},
+			"score":           {Type: graphql.NewNonNull(graphql.Float), Resolve: taskField(func(n *taskNode) any { return n.metrics().Score })},
+			"ageHours":        {Type: graphql.NewNonNull(graphql.Float), Resolve: taskField(func(n *taskNode) any { return n.metrics().AgeHours })},
+			"statusHours":     {Type: graphql.NewNonNull(graphql.Float), Resolve: taskField(func(n *taskNode) any { return n.metrics().StatusHours })},
+			"cycleHours":      {Type: graphql.Float, Resolve: taskField(func(n *taskNode) any { return n.cycleHours() })},
+			"sla":             {Type: slaType, Resolve: taskField(func(n *taskNode) any { return n.sla() })},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": {Type: graphql.NewNonNull(graphql.Boolean)},
+			"endCursor":   {Type: graphql.String},
+		},
+	})
+
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TaskConnection",
+		Fields: graphql.Fields{
+			"totalCount": {Type: graphql.NewNonNull(graphql.Int)},
+			"nodes":      {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(taskType)))},
+			"pageInfo":   {Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	countType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Count",
+		Fields: graphql.Fields{
+			"key":   {Type: graphql.NewNonNull(graphql.String)},
+			"count": {Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	insightsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Insights",
+		Fields: graphql.Fields{
+			"total":             {Type: graphql.NewNonNull(graphql.Int)},
+			"byStatus":          {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(countType)))},
+			"byPriority":        {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(countType)))},
+			"overdue":           {Type: graphql.NewNonNull(graphql.Int)},
+			"atRisk":            {Type: graphql.NewNonNull(graphql.Int)},
+			"blocked":           {Type: graphql.NewNonNull(graphql.Int)},
+			"done":              {Type: graphql.NewNonNull(graphql.Int)},
+			"averageAgeHours":   {Type: graphql.NewNonNull(graphql.Float)},
+			"averageCycleHours": {Type: graphql.NewNonNull(graphql.Float)},
+			"workloadHours":     {Type: graphql.NewNonNull(graphql.Int)},
+			"focusIndex":        {Type: graphql.NewNonNull(graphql.Float)},
+			"slaBreached":       {Type: graphql.NewNonNull(graphql.Int)},
+			"slaImminent":       {Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	stringList := graphql.NewList(graphql.NewNonNull(graphql.String))
+
+	filterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TaskFilter",
+		Description: "Те же условия, что и у параметров GET /api/tasks.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"ids":        {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+			"statuses":   {Type: stringList},
+			"priorities": {Type: stringList},
+			"owner":      {Type: graphql.String},
+			"tag":        {Type: graphql.String},
+			"q":          {Type: graphql.String},
+			"expression": {Type: graphql.String, Description: "Выражение фильтра, как в параметре filter REST API."},
+		},
+	})
+
+	inputFields := func(titleType graphql.Input) graphql.InputObjectConfigFieldMap {
+		return graphql.InputObjectConfigFieldMap{
+			"title":       {Type: titleType},
+			"description": {Type: graphql.String},
+			"status":      {Type: graphql.String},
+			"priority":    {Type: graphql.String},
+			"owner":       {Type: graphql.String},
+			"effortHours": {Type: graphql.Int},
+			"dueDate":     {Type: graphql.String, Description: "RFC 3339; пустая строка убирает срок."},
+			"tags":        {Type: stringList},
+		}
+	}
+	createInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   "TaskInput",
+		Fields: inputFields(graphql.NewNonNull(graphql.String)),
+	})
+	updateInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TaskUpdateInput",
+		Description: "Меняются только переданные поля.",
+		Fields:      inputFields(graphql.String),
+	})
+
+	listArgs := graphql.FieldConfigArgument{
+		"filter": {Type: filterType},
+		"sort":   {Type: graphql.String, Description: "Поле сортировки как в параметре sort REST API."},
+		"order":  {Type: graphql.String},
+		"first":  {Type: graphql.Int, DefaultValue: DefaultPageSize},
+		"after":  {Type: graphql.String},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"tasks": {
+				Type:    graphql.NewNonNull(connectionType),
+				Args:    listArgs,
+				Resolve: r.tasks,
+			},
+			"task": {
+				Type:    taskType,
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: r.task,
+			},
+			"insights": {
+				Type:    graphql.NewNonNull(insightsType),
+				Args:    graphql.FieldConfigArgument{"filter": {Type: filterType}},
+				Resolve: r.insights,
+			},
+		},
+	})
+
+	forceArg := &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false}
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTask": {
+				Type:    graphql.NewNonNull(taskType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createInputType)}},
+				Resolve: r.createTask,
+			},
+			"updateTask": {
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(updateInputType)},
+					"force": forceArg,
+				},
+				Resolve: r.updateTask,
+			},
+			"transitionTask": {
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"id":     {Type: graphql.NewNonNull(graphql.ID)},
+					"status": {Type: graphql.NewNonNull(graphql.String)},
+					"force":  forceArg,
+				},
+				Resolve: r.transitionTask,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func taskField(value func(*taskNode) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return value(p.Source.(*taskNode)), nil
+	}
+}
@@ -0,0 +1,183 @@
+package grpcapi
+
+import (
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/events"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
+	"devopslabs/internal/transport/grpcapi/taskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventTypes = map[string]taskpb.TaskEvent_Type{
+	events.TaskCreated:     taskpb.TaskEvent_TYPE_CREATED,
+	events.TaskUpdated:     taskpb.TaskEvent_TYPE_UPDATED,
+	events.TaskDeleted:     taskpb.TaskEvent_TYPE_DELETED,
+	events.TaskSLAImminent: taskpb.TaskEvent_TYPE_SLA_IMMINENT,
+	events.TaskSLABreached: taskpb.TaskEvent_TYPE_SLA_BREACHED,
+}
+
+func toProtoTask(task domain.Task, now time.Time, policy service.Policy) *taskpb.Task {
+	metrics := policy.Metrics(now, task)
+	tags := []string(task.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	return &taskpb.Task{
+		Id:              uint32(task.ID),
+		Title:           task.Title,
+		Description:     task.Description,
+		Status:          task.Status,
+		Priority:        task.Priority,
+		Owner:           task.Owner,
+		EffortHours:     int32(task.EffortHours),
+		Tags:            tags,
+		DueDate:         toTimestamp(task.DueDate),
+		StartedAt:       toTimestamp(task.StartedAt),
+		CompletedAt:     toTimestamp(task.CompletedAt),
+		CreatedAt:       timestamppb.New(task.CreatedAt),
+		UpdatedAt:       timestamppb.New(task.UpdatedAt),
+		Risk:            metrics.Risk,
+		Score:           metrics.Score,
+		AgeHours:        metrics.AgeHours,
+		CycleHours:      metrics.CycleHours,
+		Sla:             toProtoSLA(metrics.SLA),
+		StatusChangedAt: toTimestamp(task.StatusChangedAt),
+		StatusHours:     metrics.StatusHours,
+	}
+}
+
+func toProtoSLA(sla *service.SLAStatus) *taskpb.SlaStatus {
+	if sla == nil {
+		return nil
+	}
+	return &taskpb.SlaStatus{
+		Response: toProtoSLAClock(sla.Response),
+		Resolve:  toProtoSLAClock(sla.Resolve),
+		Breached: sla.Breached,
+		Imminent: sla.Imminent,
+	}
+}
+
+func toProtoSLAClock(clock *service.SLAClock) *taskpb.SlaClock {
+	if clock == nil {
+		return nil
+	}
+	return &taskpb.SlaClock{
+		TargetHours:    clock.TargetHours,
+		ElapsedHours:   clock.ElapsedHours,
+		RemainingHours: clock.RemainingHours,
+		State:          clock.State,
+		Breached:       clock.Breached,
+	}
+}
+
+func toProtoInsights(insights service.Insights) *taskpb.Insights {
+	return &taskpb.Insights{
+		Total:             int32(insights.Total),
+		ByStatus:          toInt32Map(insights.ByStatus),
+		ByPriority:        toInt32Map(insights.ByPriority),
+		Overdue:           int32(insights.Overdue),
+		AtRisk:            int32(insights.AtRisk),
+		Blocked:           int32(insights.Blocked),
+		Done:              int32(insights.Done),
+		AverageAgeHours:   insights.AverageAgeHours,
+		AverageCycleHours: insights.AverageCycleHours,
+		WorkloadHours:     int32(insights.WorkloadHours),
+		FocusIndex:        insights.FocusIndex,
+		SlaBreached:       int32(insights.SLABreached),
+		SlaImminent:       int32(insights.SLAImminent),
+	}
+}
+
+func toProtoEvent(event events.TaskEvent, now time.Time, policy service.Policy) *taskpb.TaskEvent {
+	message := &taskpb.TaskEvent{
+		Type:       eventTypes[event.Type],
+		TaskId:     uint32(event.TaskID),
+		OccurredAt: timestamppb.New(event.OccurredAt),
+		SlaTarget:  event.SLA,
+	}
+	if event.Type != events.TaskDeleted {
+		message.Task = toProtoTask(event.Task, now, policy)
+	}
+	return message
+}
+
+func inputFromProto(input *taskpb.TaskInput) service.TaskInput {
+	if input == nil {
+		return service.TaskInput{}
+	}
+	return service.TaskInput{
+		Title:       input.GetTitle(),
+		Description: input.GetDescription(),
+		Status:      input.GetStatus(),
+		Priority:    input.GetPriority(),
+		Owner:       input.GetOwner(),
+		EffortHours: int(input.GetEffortHours()),
+		DueDate:     fromTimestamp(input.GetDueDate()),
+		Tags:        input.GetTags(),
+	}
+}
+
+// filterFromProto нормализует статусы и приоритеты фильтра и разбирает
+// выражение так же, как параметры status, priority и filter REST API.
+func filterFromProto(filter *taskpb.TaskFilter, now time.Time) (repository.TaskFilter, error) {
+	var errs service.ValidationErrors
+	statuses, err := normalizeAll(filter.GetStatuses(), service.NormalizeStatus)
+	errs.Add(err)
+	priorities, err := normalizeAll(filter.GetPriorities(), service.NormalizePriority)
+	errs.Add(err)
+	expression, err := taskquery.Parse(filter.GetExpression())
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		return repository.TaskFilter{}, err
+	}
+
+	return repository.TaskFilter{
+		Statuses:   statuses,
+		Priorities: priorities,
+		Owner:      filter.GetOwner(),
+		Tag:        filter.GetTag(),
+		Query:      filter.GetQuery(),
+		Expression: expression,
+		Now:        now,
+	}, nil
+}
+
+func normalizeAll(values []string, normalize func(string) (string, error)) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		normalized, err := normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, normalized)
+	}
+	return result, nil
+}
+
+func toTimestamp(value *time.Time) *timestamppb.Timestamp {
+	if value == nil {
+		return nil
+	}
+	return timestamppb.New(*value)
+}
+
+func fromTimestamp(value *timestamppb.Timestamp) *time.Time {
+	if value == nil {
+		return nil
+	}
+	converted := value.AsTime()
+	return &converted
+}
+
+func toInt32Map(values map[string]int) map[string]int32 {
+	result := make(map[string]int32, len(values))
+	for key, value := range values {
+		result[key] = int32(value)
+	}
+	return result
+}
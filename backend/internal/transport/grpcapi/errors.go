@@ -0,0 +1,80 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"devopslabs/internal/i18n"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type storeErrorMapping struct {
+	target error
+	code   codes.Code
+	key    string
+}
+
+var storeErrorMappings = []storeErrorMapping{
+	{target: repository.ErrNotFound, code: codes.NotFound, key: "not_found"},
+	{target: repository.ErrConflict, code: codes.Aborted, key: "conflict"},
+	{target: repository.ErrValidation, code: codes.InvalidArgument, key: "validation_failed"},
+	{target: repository.ErrUnavailable, code: codes.Unavailable, key: "unavailable"},
+}
+
+// requestLanguage выбирает язык сообщений по метаданным accept-language.
+func requestLanguage(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("accept-language")
+	if len(values) == 0 {
+		return i18n.DefaultLanguage
+	}
+	return i18n.Negotiate(values[0])
+}
+
+// toStatus сопоставляет ошибку с gRPC-статусом так же, как respondStoreError
+// в REST API: ошибки проверки возвращаются с INVALID_ARGUMENT и перечнем
+// полей в google.rpc.BadRequest.
+func toStatus(ctx context.Context, err error, fallbackKey string) error {
+	language := requestLanguage(ctx)
+
+	var list service.ValidationErrors
+	var fieldErr *service.FieldError
+	switch {
+	case errors.As(err, &list):
+	case errors.As(err, &fieldErr):
+		list = service.ValidationErrors{fieldErr}
+	}
+	if len(list) > 0 {
+		message := i18n.Translate(language, "validation_failed", nil)
+		if len(list) == 1 {
+			message = list[0].Localize(language)
+		}
+
+		badRequest := &errdetails.BadRequest{}
+		for _, item := range list {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       item.Field,
+				Description: item.Localize(language),
+				Reason:      item.Code,
+			})
+		}
+
+		st := status.New(codes.InvalidArgument, message)
+		if detailed, detailErr := st.WithDetails(badRequest); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	}
+
+	for _, mapping := range storeErrorMappings {
+		if errors.Is(err, mapping.target) {
+			return status.Error(mapping.code, i18n.Translate(language, mapping.key, nil))
+		}
+	}
+	return status.Error(codes.Internal, i18n.Translate(language, fallbackKey, nil))
+}
@@ -0,0 +1,33 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"devopslabs/internal/repository"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusMapsStoreErrors(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "en"))
+
+	cases := map[error]codes.Code{
+		repository.ErrNotFound:                         codes.NotFound,
+		fmt.Errorf("wrap: %w", repository.ErrConflict): codes.Aborted,
+		repository.ErrValidation:                       codes.InvalidArgument,
+		repository.ErrUnavailable:                      codes.Unavailable,
+		errors.New("boom"):                             codes.Internal,
+	}
+	for err, code := range cases {
+		require.Equal(t, code, status.Code(toStatus(ctx, err, "task_list_failed")), err.Error())
+	}
+
+	st, _ := status.FromError(toStatus(context.Background(), errors.New("boom"), "task_list_failed"))
+	require.NotEqual(t, "task_list_failed", st.Message())
+	require.NotContains(t, st.Message(), "boom")
+}
@@ -0,0 +1,237 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/events"
+	"devopslabs/internal/i18n"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/grpcapi/taskpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var applyStatusTransition = service.ApplyStatusTransition
+
+// TaskServer реализует taskpb.TaskService поверх того же хранилища и пакета
+// service, что и REST-обработчики.
+type TaskServer struct {
+	taskpb.UnimplementedTaskServiceServer
+
+	store  repository.TaskStore
+	bus    *events.Bus
+	clock  service.Clock
+	policy service.Policy
+}
+
+// NewTaskServer без policy считает оценку и риск по DefaultPolicy.
+func NewTaskServer(store repository.TaskStore, bus *events.Bus, clock service.Clock, policy *service.Policy) *TaskServer {
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	if bus == nil {
+		bus = events.NewBus()
+	}
+	return &TaskServer{store: store, bus: bus, clock: clock, policy: policy.OrDefault()}
+}
+
+// NewServer создаёт gRPC-сервер с зарегистрированным TaskService. Чтобы
+// WatchTasks получал изменения, store должен публиковать события в bus,
+// например через events.NotifyingStore.
+func NewServer(store repository.TaskStore, bus *events.Bus, clock service.Clock, policy *service.Policy, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	taskpb.RegisterTaskServiceServer(server, NewTaskServer(store, bus, clock, policy))
+	return server
+}
+
+func (s *TaskServer) ListTasks(ctx context.Context, req *taskpb.ListTasksRequest) (*taskpb.ListTasksResponse, error) {
+	now := s.clock.Now()
+	filter, err := filterFromProto(req.GetFilter(), now)
+	if err != nil {
+		return nil, toStatus(ctx, err, "task_list_failed")
+	}
+
+	tasks, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, toStatus(ctx, err, "task_list_failed")
+	}
+
+	s.policy.Sort(tasks, service.NormalizeSort(req.GetSort(), req.GetOrder()), now)
+
+	response := &taskpb.ListTasksResponse{Tasks: make([]*taskpb.Task, 0, len(tasks))}
+	for _, task := range tasks {
+		response.Tasks = append(response.Tasks, toProtoTask(task, now, s.policy))
+	}
+	return response, nil
+}
+
+func (s *TaskServer) GetTask(ctx context.Context, req *taskpb.GetTaskRequest) (*taskpb.Task, error) {
+	task, err := s.store.Get(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(ctx, err, "task_get_failed")
+	}
+	return toProtoTask(*task, s.clock.Now(), s.policy), nil
+}
+
+func (s *TaskServer) CreateTask(ctx context.Context, req *taskpb.CreateTaskRequest) (*taskpb.Task, error) {
+	now := s.clock.Now()
+	var task domain.Task
+	if err := applyInput(now, &task, inputFromProto(req.GetTask()), true); err != nil {
+		return nil, toStatus(ctx, err, "task_create_failed")
+	}
+
+	if err := s.store.Create(ctx, &task); err != nil {
+		return nil, toStatus(ctx, err, "task_create_failed")
+	}
+	return toProtoTask(task, now, s.policy), nil
+}
+
+func (s *TaskServer) UpdateTask(ctx context.Context, req *taskpb.UpdateTaskRequest) (*taskpb.Task, error) {
+	task, err := s.store.Get(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(ctx, err, "task_load_failed")
+	}
+
+	input := inputFromProto(req.GetTask())
+	if mask := req.GetUpdateMask(); mask != nil {
+		input, err = mergeMasked(service.InputFromTask(*task), input, mask.GetPaths())
+		if err != nil {
+			return nil, toStatus(ctx, err, "task_update_failed")
+		}
+	}
+
+	now := s.clock.Now()
+	if err := applyInput(now, task, input, req.GetForce()); err != nil {
+		return nil, toStatus(ctx, err, "task_update_failed")
+	}
+
+	if err := s.store.Update(ctx, task); err != nil {
+		return nil, toStatus(ctx, err, "task_update_failed")
+	}
+	return toProtoTask(*task, now, s.policy), nil
+}
+
+func (s *TaskServer) DeleteTask(ctx context.Context, req *taskpb.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if err := s.store.Delete(ctx, uint(req.GetId())); err != nil {
+		return nil, toStatus(ctx, err, "task_delete_failed")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TaskServer) GetInsights(ctx context.Context, req *taskpb.GetInsightsRequest) (*taskpb.Insights, error) {
+	now := s.clock.Now()
+	filter, err := filterFromProto(req.GetFilter(), now)
+	if err != nil {
+		return nil, toStatus(ctx, err, "insights_failed")
+	}
+
+	tasks, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, toStatus(ctx, err, "insights_failed")
+	}
+	return toProtoInsights(s.policy.Insights(now, tasks)), nil
+}
+
+// WatchTasks подписывается на события до чтения снимка, поэтому изменения,
+// сделанные во время его отправки, не теряются. Удаления передаются без
+// проверки фильтра: после удаления задачу уже нельзя сопоставить с ним.
+func (s *TaskServer) WatchTasks(req *taskpb.WatchTasksRequest, stream taskpb.TaskService_WatchTasksServer) error {
+	ctx := stream.Context()
+	filter, err := filterFromProto(req.GetFilter(), s.clock.Now())
+	if err != nil {
+		return toStatus(ctx, err, "task_list_failed")
+	}
+
+	subscription := s.bus.Subscribe(events.DefaultSubscriberBuffer)
+	defer subscription.Close()
+
+	if req.GetIncludeSnapshot() {
+		tasks, err := s.store.List(ctx, filter)
+		if err != nil {
+			return toStatus(ctx, err, "task_list_failed")
+		}
+		now := s.clock.Now()
+		for _, task := range tasks {
+			event := &taskpb.TaskEvent{
+				Type:   taskpb.TaskEvent_TYPE_SNAPSHOT,
+				TaskId: uint32(task.ID),
+				Task:   toProtoTask(task, now, s.policy),
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-subscription.C:
+			if !ok {
+				if subscription.Lagged() {
+					return status.Error(codes.ResourceExhausted, i18n.Translate(requestLanguage(ctx), "watch_lagged", nil))
+				}
+				return nil
+			}
+			// Относительные даты выражения отсчитываются от момента события,
+			// а не от начала подписки.
+			filter.Now = s.clock.Now()
+			if event.Type != events.TaskDeleted && !filter.Matches(event.Task) {
+				continue
+			}
+			if err := stream.Send(toProtoEvent(event, s.clock.Now(), s.policy)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func applyInput(now time.Time, task *domain.Task, input service.TaskInput, force bool) error {
+	normalized, err := service.NormalizeTaskInput(input)
+	if err != nil {
+		return err
+	}
+
+	updated := *task
+	normalized.ApplyTo(&updated)
+	if err := applyStatusTransition(now, &updated, normalized.Status, force); err != nil {
+		return err
+	}
+
+	*task = updated
+	return nil
+}
+
+// mergeMasked переносит в current только поля TaskInput, перечисленные в маске.
+func mergeMasked(current service.TaskInput, patch service.TaskInput, paths []string) (service.TaskInput, error) {
+	var errs service.ValidationErrors
+	for _, path := range paths {
+		switch path {
+		case "title":
+			current.Title = patch.Title
+		case "description":
+			current.Description = patch.Description
+		case "status":
+			current.Status = patch.Status
+		case "priority":
+			current.Priority = patch.Priority
+		case "owner":
+			current.Owner = patch.Owner
+		case "effort_hours":
+			current.EffortHours = patch.EffortHours
+		case "due_date":
+			current.DueDate = patch.DueDate
+		case "tags":
+			current.Tags = patch.Tags
+		default:
+			errs.Add(service.NewFieldError(service.CodeInvalidValue, "update_mask", map[string]any{"value": path}))
+		}
+	}
+	return current, errs.Err()
+}
@@ -0,0 +1,1487 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: flowboard/task/v1/task.proto
+
+package taskpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TaskEvent_Type int32
+
+const (
+	TaskEvent_TYPE_UNSPECIFIED  TaskEvent_Type = 0
+	TaskEvent_TYPE_SNAPSHOT     TaskEvent_Type = 1
+	TaskEvent_TYPE_CREATED      TaskEvent_Type = 2
+	TaskEvent_TYPE_UPDATED      TaskEvent_Type = 3
+	TaskEvent_TYPE_DELETED      TaskEvent_Type = 4
+	TaskEvent_TYPE_SLA_IMMINENT TaskEvent_Type = 5
+	TaskEvent_TYPE_SLA_BREACHED TaskEvent_Type = 6
+)
+
+// Enum value maps for TaskEvent_Type.
+var (
+	TaskEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_SNAPSHOT",
+		2: "TYPE_CREATED",
+		3: "TYPE_UPDATED",
+		4: "TYPE_DELETED",
+		5: "TYPE_SLA_IMMINENT",
+		6: "TYPE_SLA_BREACHED",
+	}
+	TaskEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED":  0,
+		"TYPE_SNAPSHOT":     1,
+		"TYPE_CREATED":      2,
+		"TYPE_UPDATED":      3,
+		"TYPE_DELETED":      4,
+		"TYPE_SLA_IMMINENT": 5,
+		"TYPE_SLA_BREACHED": 6,
+	}
+)
+
+func (x TaskEvent_Type) Enum() *TaskEvent_Type {
+	p := new(TaskEvent_Type)
+	*p = x
+	return p
+}
+
+func (x TaskEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_flowboard_task_v1_task_proto_enumTypes[0].Descriptor()
+}
+
+func (TaskEvent_Type) Type() protoreflect.EnumType {
+	return &file_flowboard_task_v1_task_proto_enumTypes[0]
+}
+
+func (x TaskEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskEvent_Type.Descriptor instead.
+func (TaskEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{14, 0}
+}
+
+type Task struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status      string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Priority    string                 `protobuf:"bytes,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	Owner       string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	EffortHours int32                  `protobuf:"varint,7,opt,name=effort_hours,json=effortHours,proto3" json:"effort_hours,omitempty"`
+	Tags        []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	DueDate     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	StartedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Risk        string                 `protobuf:"bytes,14,opt,name=risk,proto3" json:"risk,omitempty"`
+	Score       float64                `protobuf:"fixed64,15,opt,name=score,proto3" json:"score,omitempty"`
+	AgeHours    float64                `protobuf:"fixed64,16,opt,name=age_hours,json=ageHours,proto3" json:"age_hours,omitempty"`
+	CycleHours  *float64               `protobuf:"fixed64,17,opt,name=cycle_hours,json=cycleHours,proto3,oneof" json:"cycle_hours,omitempty"`
+	// Не заполняется, если для приоритета нет целей SLA.
+	Sla *SlaStatus `protobuf:"bytes,18,opt,name=sla,proto3" json:"sla,omitempty"`
+	// Не заполняется у задач, созданных до появления поля.
+	StatusChangedAt *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=status_changed_at,json=statusChangedAt,proto3" json:"status_changed_at,omitempty"`
+	// Рабочие часы в текущем статусе.
+	StatusHours   float64 `protobuf:"fixed64,20,opt,name=status_hours,json=statusHours,proto3" json:"status_hours,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Task) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Task) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Task) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *Task) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Task) GetEffortHours() int32 {
+	if x != nil {
+		return x.EffortHours
+	}
+	return 0
+}
+
+func (x *Task) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Task) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *Task) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetRisk() string {
+	if x != nil {
+		return x.Risk
+	}
+	return ""
+}
+
+func (x *Task) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Task) GetAgeHours() float64 {
+	if x != nil {
+		return x.AgeHours
+	}
+	return 0
+}
+
+func (x *Task) GetCycleHours() float64 {
+	if x != nil && x.CycleHours != nil {
+		return *x.CycleHours
+	}
+	return 0
+}
+
+func (x *Task) GetSla() *SlaStatus {
+	if x != nil {
+		return x.Sla
+	}
+	return nil
+}
+
+func (x *Task) GetStatusChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StatusChangedAt
+	}
+	return nil
+}
+
+func (x *Task) GetStatusHours() float64 {
+	if x != nil {
+		return x.StatusHours
+	}
+	return 0
+}
+
+// SlaClock — часы одной цели SLA в рабочих часах; state: running, imminent,
+// met или breached.
+type SlaClock struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TargetHours    float64                `protobuf:"fixed64,1,opt,name=target_hours,json=targetHours,proto3" json:"target_hours,omitempty"`
+	ElapsedHours   float64                `protobuf:"fixed64,2,opt,name=elapsed_hours,json=elapsedHours,proto3" json:"elapsed_hours,omitempty"`
+	RemainingHours float64                `protobuf:"fixed64,3,opt,name=remaining_hours,json=remainingHours,proto3" json:"remaining_hours,omitempty"`
+	State          string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Breached       bool                   `protobuf:"varint,5,opt,name=breached,proto3" json:"breached,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SlaClock) Reset() {
+	*x = SlaClock{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlaClock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlaClock) ProtoMessage() {}
+
+func (x *SlaClock) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlaClock.ProtoReflect.Descriptor instead.
+func (*SlaClock) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SlaClock) GetTargetHours() float64 {
+	if x != nil {
+		return x.TargetHours
+	}
+	return 0
+}
+
+func (x *SlaClock) GetElapsedHours() float64 {
+	if x != nil {
+		return x.ElapsedHours
+	}
+	return 0
+}
+
+func (x *SlaClock) GetRemainingHours() float64 {
+	if x != nil {
+		return x.RemainingHours
+	}
+	return 0
+}
+
+func (x *SlaClock) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *SlaClock) GetBreached() bool {
+	if x != nil {
+		return x.Breached
+	}
+	return false
+}
+
+type SlaStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Response      *SlaClock              `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	Resolve       *SlaClock              `protobuf:"bytes,2,opt,name=resolve,proto3" json:"resolve,omitempty"`
+	Breached      bool                   `protobuf:"varint,3,opt,name=breached,proto3" json:"breached,omitempty"`
+	Imminent      bool                   `protobuf:"varint,4,opt,name=imminent,proto3" json:"imminent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlaStatus) Reset() {
+	*x = SlaStatus{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlaStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlaStatus) ProtoMessage() {}
+
+func (x *SlaStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlaStatus.ProtoReflect.Descriptor instead.
+func (*SlaStatus) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SlaStatus) GetResponse() *SlaClock {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *SlaStatus) GetResolve() *SlaClock {
+	if x != nil {
+		return x.Resolve
+	}
+	return nil
+}
+
+func (x *SlaStatus) GetBreached() bool {
+	if x != nil {
+		return x.Breached
+	}
+	return false
+}
+
+func (x *SlaStatus) GetImminent() bool {
+	if x != nil {
+		return x.Imminent
+	}
+	return false
+}
+
+// TaskInput — редактируемые поля задачи, как в TaskCreateRequest REST API.
+type TaskInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Priority      string                 `protobuf:"bytes,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	Owner         string                 `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
+	EffortHours   int32                  `protobuf:"varint,6,opt,name=effort_hours,json=effortHours,proto3" json:"effort_hours,omitempty"`
+	DueDate       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	Tags          []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskInput) Reset() {
+	*x = TaskInput{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskInput) ProtoMessage() {}
+
+func (x *TaskInput) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskInput.ProtoReflect.Descriptor instead.
+func (*TaskInput) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TaskInput) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TaskInput) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TaskInput) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TaskInput) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *TaskInput) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *TaskInput) GetEffortHours() int32 {
+	if x != nil {
+		return x.EffortHours
+	}
+	return 0
+}
+
+func (x *TaskInput) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *TaskInput) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type TaskFilter struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Statuses   []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Priorities []string               `protobuf:"bytes,2,rep,name=priorities,proto3" json:"priorities,omitempty"`
+	Owner      string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Tag        string                 `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+	Query      string                 `protobuf:"bytes,5,opt,name=query,proto3" json:"query,omitempty"`
+	// Выражение фильтра, как в параметре filter REST API, например
+	// "priority>=high AND NOT status:done".
+	Expression    string `protobuf:"bytes,6,opt,name=expression,proto3" json:"expression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskFilter) Reset() {
+	*x = TaskFilter{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskFilter) ProtoMessage() {}
+
+func (x *TaskFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskFilter.ProtoReflect.Descriptor instead.
+func (*TaskFilter) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TaskFilter) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *TaskFilter) GetPriorities() []string {
+	if x != nil {
+		return x.Priorities
+	}
+	return nil
+}
+
+func (x *TaskFilter) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *TaskFilter) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *TaskFilter) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *TaskFilter) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+type ListTasksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *TaskFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Поле сортировки как в параметре sort REST API; по умолчанию score.
+	Sort string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	// asc или desc; по умолчанию desc.
+	Order         string `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTasksRequest) GetFilter() *TaskFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+type ListTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type GetTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTaskRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *TaskInput             `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateTaskRequest) GetTask() *TaskInput {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// UpdateTaskRequest без update_mask заменяет задачу целиком, как PUT;
+// с маской меняются только перечисленные поля TaskInput, как PATCH.
+type UpdateTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Task          *TaskInput             `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	Force         bool                   `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateTaskRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateTaskRequest) GetTask() *TaskInput {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type DeleteTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteTaskRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetInsightsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *TaskFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInsightsRequest) Reset() {
+	*x = GetInsightsRequest{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInsightsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInsightsRequest) ProtoMessage() {}
+
+func (x *GetInsightsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInsightsRequest.ProtoReflect.Descriptor instead.
+func (*GetInsightsRequest) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetInsightsRequest) GetFilter() *TaskFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type Insights struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Total             int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	ByStatus          map[string]int32       `protobuf:"bytes,2,rep,name=by_status,json=byStatus,proto3" json:"by_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ByPriority        map[string]int32       `protobuf:"bytes,3,rep,name=by_priority,json=byPriority,proto3" json:"by_priority,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Overdue           int32                  `protobuf:"varint,4,opt,name=overdue,proto3" json:"overdue,omitempty"`
+	AtRisk            int32                  `protobuf:"varint,5,opt,name=at_risk,json=atRisk,proto3" json:"at_risk,omitempty"`
+	Blocked           int32                  `protobuf:"varint,6,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	Done              int32                  `protobuf:"varint,7,opt,name=done,proto3" json:"done,omitempty"`
+	AverageAgeHours   float64                `protobuf:"fixed64,8,opt,name=average_age_hours,json=averageAgeHours,proto3" json:"average_age_hours,omitempty"`
+	AverageCycleHours float64                `protobuf:"fixed64,9,opt,name=average_cycle_hours,json=averageCycleHours,proto3" json:"average_cycle_hours,omitempty"`
+	WorkloadHours     int32                  `protobuf:"varint,10,opt,name=workload_hours,json=workloadHours,proto3" json:"workload_hours,omitempty"`
+	FocusIndex        float64                `protobuf:"fixed64,11,opt,name=focus_index,json=focusIndex,proto3" json:"focus_index,omitempty"`
+	SlaBreached       int32                  `protobuf:"varint,12,opt,name=sla_breached,json=slaBreached,proto3" json:"sla_breached,omitempty"`
+	SlaImminent       int32                  `protobuf:"varint,13,opt,name=sla_imminent,json=slaImminent,proto3" json:"sla_imminent,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Insights) Reset() {
+	*x = Insights{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Insights) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Insights) ProtoMessage() {}
+
+func (x *Insights) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Insights.ProtoReflect.Descriptor instead.
+func (*Insights) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Insights) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Insights) GetByStatus() map[string]int32 {
+	if x != nil {
+		return x.ByStatus
+	}
+	return nil
+}
+
+func (x *Insights) GetByPriority() map[string]int32 {
+	if x != nil {
+		return x.ByPriority
+	}
+	return nil
+}
+
+func (x *Insights) GetOverdue() int32 {
+	if x != nil {
+		return x.Overdue
+	}
+	return 0
+}
+
+func (x *Insights) GetAtRisk() int32 {
+	if x != nil {
+		return x.AtRisk
+	}
+	return 0
+}
+
+func (x *Insights) GetBlocked() int32 {
+	if x != nil {
+		return x.Blocked
+	}
+	return 0
+}
+
+func (x *Insights) GetDone() int32 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *Insights) GetAverageAgeHours() float64 {
+	if x != nil {
+		return x.AverageAgeHours
+	}
+	return 0
+}
+
+func (x *Insights) GetAverageCycleHours() float64 {
+	if x != nil {
+		return x.AverageCycleHours
+	}
+	return 0
+}
+
+func (x *Insights) GetWorkloadHours() int32 {
+	if x != nil {
+		return x.WorkloadHours
+	}
+	return 0
+}
+
+func (x *Insights) GetFocusIndex() float64 {
+	if x != nil {
+		return x.FocusIndex
+	}
+	return 0
+}
+
+func (x *Insights) GetSlaBreached() int32 {
+	if x != nil {
+		return x.SlaBreached
+	}
+	return 0
+}
+
+func (x *Insights) GetSlaImminent() int32 {
+	if x != nil {
+		return x.SlaImminent
+	}
+	return 0
+}
+
+type WatchTasksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *TaskFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Перед изменениями отправить текущие задачи как события SNAPSHOT.
+	IncludeSnapshot bool `protobuf:"varint,2,opt,name=include_snapshot,json=includeSnapshot,proto3" json:"include_snapshot,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchTasksRequest) GetFilter() *TaskFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *WatchTasksRequest) GetIncludeSnapshot() bool {
+	if x != nil {
+		return x.IncludeSnapshot
+	}
+	return false
+}
+
+type TaskEvent struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Type   TaskEvent_Type         `protobuf:"varint,1,opt,name=type,proto3,enum=flowboard.task.v1.TaskEvent_Type" json:"type,omitempty"`
+	TaskId uint32                 `protobuf:"varint,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Не заполняется для TYPE_DELETED.
+	Task       *Task                  `protobuf:"bytes,3,opt,name=task,proto3" json:"task,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// Цель SLA для TYPE_SLA_IMMINENT и TYPE_SLA_BREACHED: response или resolve.
+	SlaTarget     string `protobuf:"bytes,5,opt,name=sla_target,json=slaTarget,proto3" json:"sla_target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_flowboard_task_v1_task_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_flowboard_task_v1_task_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TaskEvent) GetType() TaskEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return TaskEvent_TYPE_UNSPECIFIED
+}
+
+func (x *TaskEvent) GetTaskId() uint32 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *TaskEvent) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetSlaTarget() string {
+	if x != nil {
+		return x.SlaTarget
+	}
+	return ""
+}
+
+var File_flowboard_task_v1_task_proto protoreflect.FileDescriptor
+
+const file_flowboard_task_v1_task_proto_rawDesc = "" +
+	"\n" +
+	"\x1cflowboard/task/v1/task.proto\x12\x11flowboard.task.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8e\x06\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\tR\bpriority\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12!\n" +
+	"\feffort_hours\x18\a \x01(\x05R\veffortHours\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x125\n" +
+	"\bdue_date\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x129\n" +
+	"\n" +
+	"started_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
+	"\x04risk\x18\x0e \x01(\tR\x04risk\x12\x14\n" +
+	"\x05score\x18\x0f \x01(\x01R\x05score\x12\x1b\n" +
+	"\tage_hours\x18\x10 \x01(\x01R\bageHours\x12$\n" +
+	"\vcycle_hours\x18\x11 \x01(\x01H\x00R\n" +
+	"cycleHours\x88\x01\x01\x12.\n" +
+	"\x03sla\x18\x12 \x01(\v2\x1c.flowboard.task.v1.SlaStatusR\x03sla\x12F\n" +
+	"\x11status_changed_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\x0fstatusChangedAt\x12!\n" +
+	"\fstatus_hours\x18\x14 \x01(\x01R\vstatusHoursB\x0e\n" +
+	"\f_cycle_hours\"\xad\x01\n" +
+	"\bSlaClock\x12!\n" +
+	"\ftarget_hours\x18\x01 \x01(\x01R\vtargetHours\x12#\n" +
+	"\relapsed_hours\x18\x02 \x01(\x01R\felapsedHours\x12'\n" +
+	"\x0fremaining_hours\x18\x03 \x01(\x01R\x0eremainingHours\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12\x1a\n" +
+	"\bbreached\x18\x05 \x01(\bR\bbreached\"\xb3\x01\n" +
+	"\tSlaStatus\x127\n" +
+	"\bresponse\x18\x01 \x01(\v2\x1b.flowboard.task.v1.SlaClockR\bresponse\x125\n" +
+	"\aresolve\x18\x02 \x01(\v2\x1b.flowboard.task.v1.SlaClockR\aresolve\x12\x1a\n" +
+	"\bbreached\x18\x03 \x01(\bR\bbreached\x12\x1a\n" +
+	"\bimminent\x18\x04 \x01(\bR\bimminent\"\xfb\x01\n" +
+	"\tTaskInput\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\tR\bpriority\x12\x14\n" +
+	"\x05owner\x18\x05 \x01(\tR\x05owner\x12!\n" +
+	"\feffort_hours\x18\x06 \x01(\x05R\veffortHours\x125\n" +
+	"\bdue_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\"\xa6\x01\n" +
+	"\n" +
+	"TaskFilter\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x1e\n" +
+	"\n" +
+	"priorities\x18\x02 \x03(\tR\n" +
+	"priorities\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x10\n" +
+	"\x03tag\x18\x04 \x01(\tR\x03tag\x12\x14\n" +
+	"\x05query\x18\x05 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x06 \x01(\tR\n" +
+	"expression\"s\n" +
+	"\x10ListTasksRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.flowboard.task.v1.TaskFilterR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x03 \x01(\tR\x05order\"B\n" +
+	"\x11ListTasksResponse\x12-\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x17.flowboard.task.v1.TaskR\x05tasks\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"E\n" +
+	"\x11CreateTaskRequest\x120\n" +
+	"\x04task\x18\x01 \x01(\v2\x1c.flowboard.task.v1.TaskInputR\x04task\"\xa8\x01\n" +
+	"\x11UpdateTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x120\n" +
+	"\x04task\x18\x02 \x01(\v2\x1c.flowboard.task.v1.TaskInputR\x04task\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12\x14\n" +
+	"\x05force\x18\x04 \x01(\bR\x05force\"#\n" +
+	"\x11DeleteTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"K\n" +
+	"\x12GetInsightsRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.flowboard.task.v1.TaskFilterR\x06filter\"\xfd\x04\n" +
+	"\bInsights\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12F\n" +
+	"\tby_status\x18\x02 \x03(\v2).flowboard.task.v1.Insights.ByStatusEntryR\bbyStatus\x12L\n" +
+	"\vby_priority\x18\x03 \x03(\v2+.flowboard.task.v1.Insights.ByPriorityEntryR\n" +
+	"byPriority\x12\x18\n" +
+	"\aoverdue\x18\x04 \x01(\x05R\aoverdue\x12\x17\n" +
+	"\aat_risk\x18\x05 \x01(\x05R\x06atRisk\x12\x18\n" +
+	"\ablocked\x18\x06 \x01(\x05R\ablocked\x12\x12\n" +
+	"\x04done\x18\a \x01(\x05R\x04done\x12*\n" +
+	"\x11average_age_hours\x18\b \x01(\x01R\x0faverageAgeHours\x12.\n" +
+	"\x13average_cycle_hours\x18\t \x01(\x01R\x11averageCycleHours\x12%\n" +
+	"\x0eworkload_hours\x18\n" +
+	" \x01(\x05R\rworkloadHours\x12\x1f\n" +
+	"\vfocus_index\x18\v \x01(\x01R\n" +
+	"focusIndex\x12!\n" +
+	"\fsla_breached\x18\f \x01(\x05R\vslaBreached\x12!\n" +
+	"\fsla_imminent\x18\r \x01(\x05R\vslaImminent\x1a;\n" +
+	"\rByStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a=\n" +
+	"\x0fByPriorityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"u\n" +
+	"\x11WatchTasksRequest\x125\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1d.flowboard.task.v1.TaskFilterR\x06filter\x12)\n" +
+	"\x10include_snapshot\x18\x02 \x01(\bR\x0fincludeSnapshot\"\xfa\x02\n" +
+	"\tTaskEvent\x125\n" +
+	"\x04type\x18\x01 \x01(\x0e2!.flowboard.task.v1.TaskEvent.TypeR\x04type\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\rR\x06taskId\x12+\n" +
+	"\x04task\x18\x03 \x01(\v2\x17.flowboard.task.v1.TaskR\x04task\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x1d\n" +
+	"\n" +
+	"sla_target\x18\x05 \x01(\tR\tslaTarget\"\x93\x01\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rTYPE_SNAPSHOT\x10\x01\x12\x10\n" +
+	"\fTYPE_CREATED\x10\x02\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x03\x12\x10\n" +
+	"\fTYPE_DELETED\x10\x04\x12\x15\n" +
+	"\x11TYPE_SLA_IMMINENT\x10\x05\x12\x15\n" +
+	"\x11TYPE_SLA_BREACHED\x10\x062\xb9\x04\n" +
+	"\vTaskService\x12V\n" +
+	"\tListTasks\x12#.flowboard.task.v1.ListTasksRequest\x1a$.flowboard.task.v1.ListTasksResponse\x12E\n" +
+	"\aGetTask\x12!.flowboard.task.v1.GetTaskRequest\x1a\x17.flowboard.task.v1.Task\x12K\n" +
+	"\n" +
+	"CreateTask\x12$.flowboard.task.v1.CreateTaskRequest\x1a\x17.flowboard.task.v1.Task\x12K\n" +
+	"\n" +
+	"UpdateTask\x12$.flowboard.task.v1.UpdateTaskRequest\x1a\x17.flowboard.task.v1.Task\x12J\n" +
+	"\n" +
+	"DeleteTask\x12$.flowboard.task.v1.DeleteTaskRequest\x1a\x16.google.protobuf.Empty\x12Q\n" +
+	"\vGetInsights\x12%.flowboard.task.v1.GetInsightsRequest\x1a\x1b.flowboard.task.v1.Insights\x12R\n" +
+	"\n" +
+	"WatchTasks\x12$.flowboard.task.v1.WatchTasksRequest\x1a\x1c.flowboard.task.v1.TaskEvent0\x01B5Z3devopslabs/internal/transport/grpcapi/taskpb;taskpbb\x06proto3"
+
+var (
+	file_flowboard_task_v1_task_proto_rawDescOnce sync.Once
+	file_flowboard_task_v1_task_proto_rawDescData []byte
+)
+
+func file_flowboard_task_v1_task_proto_rawDescGZIP() []byte {
+	file_flowboard_task_v1_task_proto_rawDescOnce.Do(func() {
+		file_flowboard_task_v1_task_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_flowboard_task_v1_task_proto_rawDesc), len(file_flowboard_task_v1_task_proto_rawDesc)))
+	})
+	return file_flowboard_task_v1_task_proto_rawDescData
+}
+
+var file_flowboard_task_v1_task_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_flowboard_task_v1_task_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_flowboard_task_v1_task_proto_goTypes = []any{
+	(TaskEvent_Type)(0),           // 0: flowboard.task.v1.TaskEvent.Type
+	(*Task)(nil),                  // 1: flowboard.task.v1.Task
+	(*SlaClock)(nil),              // 2: flowboard.task.v1.SlaClock
+	(*SlaStatus)(nil),             // 3: flowboard.task.v1.SlaStatus
+	(*TaskInput)(nil),             // 4: flowboard.task.v1.TaskInput
+	(*TaskFilter)(nil),            // 5: flowboard.task.v1.TaskFilter
+	(*ListTasksRequest)(nil),      // 6: flowboard.task.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),     // 7: flowboard.task.v1.ListTasksResponse
+	(*GetTaskRequest)(nil),        // 8: flowboard.task.v1.GetTaskRequest
+	(*CreateTaskRequest)(nil),     // 9: flowboard.task.v1.CreateTaskRequest
+	(*UpdateTaskRequest)(nil),     // 10: flowboard.task.v1.UpdateTaskRequest
+	(*DeleteTaskRequest)(nil),     // 11: flowboard.task.v1.DeleteTaskRequest
+	(*GetInsightsRequest)(nil),    // 12: flowboard.task.v1.GetInsightsRequest
+	(*Insights)(nil),              // 13: flowboard.task.v1.Insights
+	(*WatchTasksRequest)(nil),     // 14: flowboard.task.v1.WatchTasksRequest
+	(*TaskEvent)(nil),             // 15: flowboard.task.v1.TaskEvent
+	nil,                           // 16: flowboard.task.v1.Insights.ByStatusEntry
+	nil,                           // 17: flowboard.task.v1.Insights.ByPriorityEntry
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil), // 19: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),         // 20: google.protobuf.Empty
+}
+var file_flowboard_task_v1_task_proto_depIdxs = []int32{
+	18, // 0: flowboard.task.v1.Task.due_date:type_name -> google.protobuf.Timestamp
+	18, // 1: flowboard.task.v1.Task.started_at:type_name -> google.protobuf.Timestamp
+	18, // 2: flowboard.task.v1.Task.completed_at:type_name -> google.protobuf.Timestamp
+	18, // 3: flowboard.task.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	18, // 4: flowboard.task.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 5: flowboard.task.v1.Task.sla:type_name -> flowboard.task.v1.SlaStatus
+	18, // 6: flowboard.task.v1.Task.status_changed_at:type_name -> google.protobuf.Timestamp
+	2,  // 7: flowboard.task.v1.SlaStatus.response:type_name -> flowboard.task.v1.SlaClock
+	2,  // 8: flowboard.task.v1.SlaStatus.resolve:type_name -> flowboard.task.v1.SlaClock
+	18, // 9: flowboard.task.v1.TaskInput.due_date:type_name -> google.protobuf.Timestamp
+	5,  // 10: flowboard.task.v1.ListTasksRequest.filter:type_name -> flowboard.task.v1.TaskFilter
+	1,  // 11: flowboard.task.v1.ListTasksResponse.tasks:type_name -> flowboard.task.v1.Task
+	4,  // 12: flowboard.task.v1.CreateTaskRequest.task:type_name -> flowboard.task.v1.TaskInput
+	4,  // 13: flowboard.task.v1.UpdateTaskRequest.task:type_name -> flowboard.task.v1.TaskInput
+	19, // 14: flowboard.task.v1.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
+	5,  // 15: flowboard.task.v1.GetInsightsRequest.filter:type_name -> flowboard.task.v1.TaskFilter
+	16, // 16: flowboard.task.v1.Insights.by_status:type_name -> flowboard.task.v1.Insights.ByStatusEntry
+	17, // 17: flowboard.task.v1.Insights.by_priority:type_name -> flowboard.task.v1.Insights.ByPriorityEntry
+	5,  // 18: flowboard.task.v1.WatchTasksRequest.filter:type_name -> flowboard.task.v1.TaskFilter
+	0,  // 19: flowboard.task.v1.TaskEvent.type:type_name -> flowboard.task.v1.TaskEvent.Type
+	1,  // 20: flowboard.task.v1.TaskEvent.task:type_name -> flowboard.task.v1.Task
+	18, // 21: flowboard.task.v1.TaskEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	6,  // 22: flowboard.task.v1.TaskService.ListTasks:input_type -> flowboard.task.v1.ListTasksRequest
+	8,  // 23: flowboard.task.v1.TaskService.GetTask:input_type -> flowboard.task.v1.GetTaskRequest
+	9,  // 24: flowboard.task.v1.TaskService.CreateTask:input_type -> flowboard.task.v1.CreateTaskRequest
+	10, // 25: flowboard.task.v1.TaskService.UpdateTask:input_type -> flowboard.task.v1.UpdateTaskRequest
+	11, // 26: flowboard.task.v1.TaskService.DeleteTask:input_type -> flowboard.task.v1.DeleteTaskRequest
+	12, // 27: flowboard.task.v1.TaskService.GetInsights:input_type -> flowboard.task.v1.GetInsightsRequest
+	14, // 28: flowboard.task.v1.TaskService.WatchTasks:input_type -> flowboard.task.v1.WatchTasksRequest
+	7,  // 29: flowboard.task.v1.TaskService.ListTasks:output_type -> flowboard.task.v1.ListTasksResponse
+	1,  // 30: flowboard.task.v1.TaskService.GetTask:output_type -> flowboard.task.v1.Task
+	1,  // 31: flowboard.task.v1.TaskService.CreateTask:output_type -> flowboard.task.v1.Task
+	1,  // 32: flowboard.task.v1.TaskService.UpdateTask:output_type -> flowboard.task.v1.Task
+	20, // 33: flowboard.task.v1.TaskService.DeleteTask:output_type -> google.protobuf.Empty
+	13, // 34: flowboard.task.v1.TaskService.GetInsights:output_type -> flowboard.task.v1.Insights
+	15, // 35: flowboard.task.v1.TaskService.WatchTasks:output_type -> flowboard.task.v1.TaskEvent
+	29, // [29:36] is the sub-list for method output_type
+	22, // [22:29] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_flowboard_task_v1_task_proto_init() }
+func file_flowboard_task_v1_task_proto_init() {
+	if File_flowboard_task_v1_task_proto != nil {
+		return
+	}
+	file_flowboard_task_v1_task_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_flowboard_task_v1_task_proto_rawDesc), len(file_flowboard_task_v1_task_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_flowboard_task_v1_task_proto_goTypes,
+		DependencyIndexes: file_flowboard_task_v1_task_proto_depIdxs,
+		EnumInfos:         file_flowboard_task_v1_task_proto_enumTypes,
+		MessageInfos:      file_flowboard_task_v1_task_proto_msgTypes,
+	}.Build()
+	File_flowboard_task_v1_task_proto = out.File
+	file_flowboard_task_v1_task_proto_goTypes = nil
+	file_flowboard_task_v1_task_proto_depIdxs = nil
+}
@@ -0,0 +1,321 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	BulkSetStatus   = "set_status"
+	BulkSetPriority = "set_priority"
+	BulkSetOwner    = "set_owner"
+	BulkAddTags     = "add_tags"
+	BulkRemoveTags  = "remove_tags"
+	BulkDelete      = "delete"
+
+	maxBulkItems = 500
+)
+
+const (
+	BulkResultUpdated = "updated"
+	BulkResultDeleted = "deleted"
+	BulkResultFailed  = "failed"
+	BulkResultSkipped = "skipped"
+)
+
+const (
+	CodeBulkInvalidOperation = "bulk_invalid_operation"
+	CodeBulkTargetRequired   = "bulk_target_required"
+	CodeBulkTooMany          = "bulk_too_many"
+	CodeBulkValueRequired    = "bulk_value_required"
+)
+
+var errBulkAborted = errors.New("массовая операция отменена")
+
+type BulkRequest struct {
+	IDs       []uint            `json:"ids"`
+	Filter    map[string]string `json:"filter"`
+	Operation string            `json:"operation"`
+	Status    *string           `json:"status"`
+	Force     bool              `json:"force"`
+	Priority  *string           `json:"priority"`
+	Owner     *string           `json:"owner"`
+	Tags      []string          `json:"tags"`
+	Atomic    bool              `json:"atomic"`
+}
+
+type BulkItemResult struct {
+	ID     uint           `json:"id"`
+	Result string         `json:"result"`
+	Error  *ErrorResponse `json:"error,omitempty"`
+	Task   *TaskResponse  `json:"task,omitempty"`
+}
+
+type BulkResponse struct {
+	Operation  string           `json:"operation"`
+	Atomic     bool             `json:"atomic"`
+	Applied    int              `json:"applied"`
+	Failed     int              `json:"failed"`
+	RolledBack bool             `json:"rolledBack"`
+	Results    []BulkItemResult `json:"results"`
+}
+
+type bulkItem struct {
+	id     uint
+	task   *domain.Task
+	result BulkItemResult
+}
+
+func (h *TaskHandler) Bulk(c *gin.Context) {
+	req, ok := validatedBody(c, "BulkRequest", bulkRequestRules)
+	if !ok {
+		return
+	}
+
+	operation := bulkOperation(req)
+	ctx := c.Request.Context()
+	now := h.clock.Now()
+	response := BulkResponse{Operation: operation, Atomic: req.Atomic}
+
+	err := repository.WithinTransaction(ctx, h.store, func(store repository.TaskStore) error {
+		items, err := resolveBulkItems(c, store, req, now)
+		if err != nil {
+			return err
+		}
+
+		for i := range items {
+			if items[i].task == nil {
+				continue
+			}
+			if err := applyBulkOperation(now, items[i].task, operation, req); err != nil {
+				items[i].fail(c, err)
+			}
+		}
+
+		if req.Atomic && countFailed(items) > 0 {
+			response.abort(items)
+			return errBulkAborted
+		}
+
+		for i := range items {
+			if items[i].task == nil {
+				continue
+			}
+			write := func(target repository.TaskStore) error {
+				if operation == BulkDelete {
+					return target.Delete(ctx, items[i].id)
+				}
+				return target.Update(ctx, items[i].task)
+			}
+
+			if req.Atomic {
+				if err := write(store); err != nil {
+					items[i].fail(c, err)
+					response.abort(items)
+					return errBulkAborted
+				}
+			} else if err := repository.WithinTransaction(ctx, store, write); err != nil {
+				items[i].fail(c, err)
+				continue
+			}
+
+			if operation == BulkDelete {
+				items[i].result.Result = BulkResultDeleted
+			} else {
+				items[i].result.Result = BulkResultUpdated
+				task := h.toTaskResponse(*items[i].task, now)
+				items[i].result.Task = &task
+			}
+			response.Applied++
+		}
+
+		response.Results = make([]BulkItemResult, 0, len(items))
+		for _, item := range items {
+			response.Results = append(response.Results, item.result)
+		}
+		response.Failed = countFailed(items)
+		return nil
+	})
+
+	if errors.Is(err, errBulkAborted) {
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+	if err != nil {
+		respondStoreError(c, err, "bulk_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func bulkOperation(req BulkRequest) string {
+	return strings.TrimSpace(strings.ToLower(req.Operation))
+}
+
+// bulkRequestRules проверяет, что задан набор задач и значение для операции.
+func bulkRequestRules(req BulkRequest) error {
+	var errs service.ValidationErrors
+	if len(req.IDs) == 0 && len(req.Filter) == 0 {
+		errs.Add(service.NewFieldError(CodeBulkTargetRequired, "ids", nil))
+	}
+	if len(req.IDs) > maxBulkItems {
+		errs.Add(service.NewFieldError(CodeBulkTooMany, "ids", map[string]any{"max": maxBulkItems}))
+	}
+	if len(req.Filter) > 0 {
+		_, _, err := parseListValues(listValues(req.Filter))
+		errs.Add(err)
+	}
+
+	switch operation := bulkOperation(req); operation {
+	case BulkSetStatus:
+		if req.Status == nil || strings.TrimSpace(*req.Status) == "" {
+			errs.Add(service.NewFieldError(CodeBulkValueRequired, "status", nil))
+		}
+	case BulkSetPriority:
+		if req.Priority == nil || strings.TrimSpace(*req.Priority) == "" {
+			errs.Add(service.NewFieldError(CodeBulkValueRequired, "priority", nil))
+		}
+	case BulkSetOwner:
+		if req.Owner == nil {
+			errs.Add(service.NewFieldError(CodeBulkValueRequired, "owner", nil))
+		}
+	case BulkAddTags, BulkRemoveTags:
+		if len(req.Tags) == 0 {
+			errs.Add(service.NewFieldError(CodeBulkValueRequired, "tags", nil))
+		}
+	case BulkDelete:
+	default:
+		errs.Add(service.NewFieldError(CodeBulkInvalidOperation, "operation", map[string]any{"value": operation}))
+	}
+
+	return errs.Err()
+}
+
+func resolveBulkItems(c *gin.Context, store repository.TaskStore, req BulkRequest, now time.Time) ([]bulkItem, error) {
+	if len(req.IDs) > 0 {
+		seen := make(map[uint]bool, len(req.IDs))
+		items := make([]bulkItem, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			item := bulkItem{id: id, result: BulkItemResult{ID: id}}
+			task, err := store.Get(c.Request.Context(), id)
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				item.fail(c, err)
+			case err != nil:
+				return nil, err
+			default:
+				item.task = task
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	filter, _, err := parseListValues(listValues(req.Filter))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := store.List(c.Request.Context(), filter.TaskFilter(now))
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > maxBulkItems {
+		return nil, service.NewFieldError(CodeBulkTooMany, "filter", map[string]any{"max": maxBulkItems})
+	}
+
+	items := make([]bulkItem, 0, len(tasks))
+	for i := range tasks {
+		items = append(items, bulkItem{id: tasks[i].ID, task: &tasks[i], result: BulkItemResult{ID: tasks[i].ID}})
+	}
+	return items, nil
+}
+
+func applyBulkOperation(now time.Time, task *domain.Task, operation string, req BulkRequest) error {
+	switch operation {
+	case BulkSetStatus:
+		return applyStatusTransition(now, task, *req.Status, req.Force)
+	case BulkSetPriority:
+		value, err := service.NormalizePriority(*req.Priority)
+		if err != nil {
+			return err
+		}
+		task.Priority = value
+	case BulkSetOwner:
+		owner := strings.TrimSpace(*req.Owner)
+		if owner == "" {
+			owner = service.DefaultOwner
+		}
+		task.Owner = owner
+	case BulkAddTags:
+		tags, err := service.NormalizeTags(append(append([]string{}, task.Tags...), req.Tags...))
+		if err != nil {
+			return err
+		}
+		task.Tags = tags
+	case BulkRemoveTags:
+		remove, err := service.NormalizeTags(req.Tags)
+		if err != nil {
+			return err
+		}
+		drop := make(map[string]bool, len(remove))
+		for _, tag := range remove {
+			drop[tag] = true
+		}
+		kept := domain.StringList{}
+		for _, tag := range task.Tags {
+			if !drop[strings.ToLower(tag)] {
+				kept = append(kept, tag)
+			}
+		}
+		task.Tags = kept
+	}
+	return nil
+}
+
+func (item *bulkItem) fail(c *gin.Context, err error) {
+	_, body := describeError(c, err, "bulk_failed")
+	item.task = nil
+	item.result.Result = BulkResultFailed
+	item.result.Error = &body
+}
+
+// abort помечает все неупавшие элементы пропущенными: при откате
+// транзакции ни одно изменение не сохраняется.
+func (r *BulkResponse) abort(items []bulkItem) {
+	r.Results = make([]BulkItemResult, 0, len(items))
+	for _, item := range items {
+		result := item.result
+		if result.Result != BulkResultFailed {
+			result.Result = BulkResultSkipped
+			result.Task = nil
+		}
+		r.Results = append(r.Results, result)
+	}
+	r.Applied = 0
+	r.Failed = countFailed(items)
+	r.RolledBack = true
+}
+
+func countFailed(items []bulkItem) int {
+	failed := 0
+	for _, item := range items {
+		if item.result.Result == BulkResultFailed {
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"devopslabs/internal/calendar"
+	"devopslabs/internal/domain"
+	"devopslabs/internal/export"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	CalendarFeedOwner = "owner"
+	CalendarFeedView  = "view"
+)
+
+// CalendarFeed — ссылка на личную ленту календаря с токеном доступа.
+type CalendarFeed struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	ViewID uint   `json:"viewId,omitempty"`
+	URL    string `json:"url"`
+}
+
+type CalendarHandler struct {
+	tasks  *TaskHandler
+	views  repository.ViewStore
+	signer *calendar.Signer
+}
+
+func NewCalendarHandler(tasks *TaskHandler, views repository.ViewStore, signer *calendar.Signer) *CalendarHandler {
+	return &CalendarHandler{tasks: tasks, views: views, signer: signer}
+}
+
+// Feed отдаёт сроки задач с теми же фильтрами, что и GET /api/tasks.
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	h.respond(c, "FlowBoard", c.Request.URL.Query())
+}
+
+// OwnerFeed отдаёт сроки задач исполнителя по ссылке с токеном; путь может
+// оканчиваться на .ics, как ждут календарные приложения.
+func (h *CalendarHandler) OwnerFeed(c *gin.Context) {
+	owner := strings.TrimSuffix(c.Param("owner"), ".ics")
+	if !h.authorize(c, calendar.OwnerSubject(owner)) {
+		return
+	}
+	values := feedValues(c, url.Values{})
+	values.Set("owner", owner)
+	h.respond(c, "FlowBoard: "+owner, values)
+}
+
+// ViewFeed отдаёт сроки задач сохранённого представления по ссылке с
+// токеном. Фильтр читается при каждом обновлении, поэтому изменения
+// представления сразу попадают в ленту. Отсутствующее представление
+// неотличимо от неверного токена.
+func (h *CalendarHandler) ViewFeed(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	view, err := h.views.Get(c.Request.Context(), id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		respondStoreError(c, err, "view_load_failed")
+		return
+	}
+	subject := ""
+	if view != nil {
+		subject = calendar.ViewSubject(view.ID, view.Visibility)
+	}
+	if !h.authorize(c, subject) {
+		return
+	}
+	h.respond(c, "FlowBoard: "+view.Name, feedValues(c, viewValues(*view)))
+}
+
+// Feeds выдаёт текущему пользователю ссылки на его ленту и ленты видимых
+// ему представлений.
+func (h *CalendarHandler) Feeds(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	views, err := h.views.List(c.Request.Context(), user)
+	if err != nil {
+		respondStoreError(c, err, "view_list_failed")
+		return
+	}
+
+	base := baseURL(c)
+	feeds := make([]CalendarFeed, 0, len(views)+1)
+	feeds = append(feeds, CalendarFeed{
+		Kind: CalendarFeedOwner,
+		Name: user,
+		URL:  h.feedURL(base, "/api/calendar/owners/"+url.PathEscape(user)+".ics", calendar.OwnerSubject(user)),
+	})
+	for _, view := range views {
+		feeds = append(feeds, CalendarFeed{
+			Kind:   CalendarFeedView,
+			Name:   view.Name,
+			ViewID: view.ID,
+			URL:    h.feedURL(base, fmt.Sprintf("/api/views/%d/calendar.ics", view.ID), calendar.ViewSubject(view.ID, view.Visibility)),
+		})
+	}
+	c.JSON(http.StatusOK, feeds)
+}
+
+// respond строит календарь и отдаёт его с ETag: календарь зависит только от
+// задач, поэтому клиент, обновляющий подписку, получает 304, пока задачи не
+// изменились.
+func (h *CalendarHandler) respond(c *gin.Context, name string, values url.Values) {
+	filter, _, err := parseListValues(values)
+	var errs service.ValidationErrors
+	errs.Add(err)
+	component, err := calendar.ParseComponent(values.Get("component"))
+	errs.Add(err)
+	location, err := export.ParseLocation(values.Get("tz"))
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	tasks, err := h.tasks.store.List(c.Request.Context(), filter.TaskFilter(h.tasks.clock.Now()))
+	if err != nil {
+		respondStoreError(c, err, "calendar_failed")
+		return
+	}
+	slices.SortFunc(tasks, func(a, b domain.Task) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	var body bytes.Buffer
+	if err := calendar.Write(&body, calendar.Feed{Name: name, Component: component, Location: location}, tasks); err != nil {
+		respondStoreError(c, err, "calendar_failed")
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	if matchesETag(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, calendar.ContentType, body.Bytes())
+}
+
+// authorize проверяет токен ленты. Неверный токен не отличается от
+// отсутствующего.
+func (h *CalendarHandler) authorize(c *gin.Context, subject string) bool {
+	if subject == "" || !h.signer.Valid(subject, c.Query("token")) {
+		respondError(c, http.StatusForbidden, CodeForbidden, "token")
+		return false
+	}
+	return true
+}
+
+func (h *CalendarHandler) feedURL(base string, path string, subject string) string {
+	return base + path + "?token=" + url.QueryEscape(h.signer.Token(subject))
+}
+
+// feedValues переносит в параметры ленты только настройки вывода: фильтр
+// личной ленты задаётся ссылкой, а не строкой запроса.
+func feedValues(c *gin.Context, values url.Values) url.Values {
+	for _, key := range []string{"component", "tz"} {
+		if value := c.Query(key); value != "" {
+			values.Set(key, value)
+		}
+	}
+	return values
+}
+
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+func matchesETag(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
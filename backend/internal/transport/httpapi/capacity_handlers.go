@@ -0,0 +1,136 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+
+	"devopslabs/internal/capacity"
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CapacityRequest struct {
+	HoursPerWeek float64  `json:"hoursPerWeek"`
+	DaysOff      []string `json:"daysOff"`
+}
+
+type CapacityHandler struct {
+	capacities repository.CapacityStore
+	tasks      *TaskHandler
+}
+
+func NewCapacityHandler(capacities repository.CapacityStore, tasks *TaskHandler) *CapacityHandler {
+	return &CapacityHandler{capacities: capacities, tasks: tasks}
+}
+
+func (h *CapacityHandler) List(c *gin.Context) {
+	capacities, err := h.capacities.List(c.Request.Context())
+	if err != nil {
+		respondStoreError(c, err, "capacity_failed")
+		return
+	}
+	if capacities == nil {
+		capacities = []domain.OwnerCapacity{}
+	}
+	c.JSON(http.StatusOK, capacities)
+}
+
+func (h *CapacityHandler) Get(c *gin.Context) {
+	owner, ok := capacityOwner(c)
+	if !ok {
+		return
+	}
+	capacity, err := h.capacities.Get(c.Request.Context(), owner)
+	if err != nil {
+		respondStoreError(c, err, "capacity_failed")
+		return
+	}
+	c.JSON(http.StatusOK, capacity)
+}
+
+// Put создаёт или заменяет настройки исполнителя.
+func (h *CapacityHandler) Put(c *gin.Context) {
+	owner, ok := capacityOwner(c)
+	if !ok {
+		return
+	}
+	req, ok := validatedBody(c, "CapacityRequest", capacityRequestRules)
+	if !ok {
+		return
+	}
+
+	daysOff, _ := capacity.NormalizeDaysOff(req.DaysOff)
+	saved := domain.OwnerCapacity{
+		Owner:        owner,
+		HoursPerWeek: req.HoursPerWeek,
+		DaysOff:      daysOff,
+		UpdatedAt:    h.tasks.clock.Now(),
+	}
+	if err := h.capacities.Save(c.Request.Context(), &saved); err != nil {
+		respondStoreError(c, err, "capacity_failed")
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+func (h *CapacityHandler) Delete(c *gin.Context) {
+	owner, ok := capacityOwner(c)
+	if !ok {
+		return
+	}
+	if err := h.capacities.Delete(c.Request.Context(), owner); err != nil {
+		respondStoreError(c, err, "capacity_failed")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Workload сравнивает открытые задачи под фильтрами GET /api/tasks с
+// ёмкостью исполнителей на ближайшие недели.
+func (h *CapacityHandler) Workload(c *gin.Context) {
+	values := c.Request.URL.Query()
+	now := h.tasks.clock.Now()
+	var errs service.ValidationErrors
+	filter, _, err := parseListValues(values)
+	errs.Add(err)
+	weeks, err := capacity.ParseWeeks(values.Get("weeks"))
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	tasks, err := h.tasks.store.List(c.Request.Context(), filter.TaskFilter(now))
+	if err != nil {
+		respondStoreError(c, err, "capacity_failed")
+		return
+	}
+	capacities, err := h.capacities.List(c.Request.Context())
+	if err != nil {
+		respondStoreError(c, err, "capacity_failed")
+		return
+	}
+	c.JSON(http.StatusOK, capacity.Plan(tasks, capacities, weeks, now))
+}
+
+func capacityOwner(c *gin.Context) (string, bool) {
+	owner := strings.TrimSpace(c.Param("owner"))
+	if owner == "" {
+		respondInvalid(c, service.NewFieldError(CodeFieldRequired, "owner", map[string]any{"field": "owner"}))
+		return "", false
+	}
+	if len(owner) > maxUserLength {
+		respondInvalid(c, service.NewFieldError(CodeTooLong, "owner", map[string]any{"max": maxUserLength}))
+		return "", false
+	}
+	return owner, true
+}
+
+// capacityRequestRules дополняет схему CapacityRequest проверкой дат
+// нерабочих дней.
+func capacityRequestRules(req CapacityRequest) error {
+	_, err := capacity.NormalizeDaysOff(req.DaysOff)
+	return err
+}
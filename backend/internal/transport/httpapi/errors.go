@@ -0,0 +1,122 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"devopslabs/internal/i18n"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	CodeBadRequest  = "bad_request"
+	CodeInvalidBody = "invalid_body"
+	CodeInvalidID   = "invalid_id"
+	CodeValidation  = "validation_failed"
+	CodeNotFound    = "not_found"
+	CodeConflict    = "conflict"
+	CodeUnavailable = "unavailable"
+	CodeInternal    = "internal"
+)
+
+type ErrorResponse struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Field   string          `json:"field,omitempty"`
+	Details map[string]any  `json:"details,omitempty"`
+	Errors  []ErrorResponse `json:"errors,omitempty"`
+}
+
+type storeErrorMapping struct {
+	target error
+	status int
+	code   string
+}
+
+var storeErrorMappings = []storeErrorMapping{
+	{target: repository.ErrNotFound, status: http.StatusNotFound, code: CodeNotFound},
+	{target: repository.ErrConflict, status: http.StatusConflict, code: CodeConflict},
+	{target: repository.ErrValidation, status: http.StatusBadRequest, code: CodeValidation},
+	{target: repository.ErrUnavailable, status: http.StatusServiceUnavailable, code: CodeUnavailable},
+}
+
+func requestLanguage(c *gin.Context) string {
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
+}
+
+func newErrorResponse(c *gin.Context, code string, messageKey string, field string, details map[string]any) ErrorResponse {
+	return ErrorResponse{
+		Code:    code,
+		Message: i18n.Translate(requestLanguage(c), messageKey, details),
+		Field:   field,
+		Details: details,
+	}
+}
+
+func respondError(c *gin.Context, status int, code string, field string) {
+	c.JSON(status, newErrorResponse(c, code, code, field, nil))
+}
+
+// respondInvalid отвечает 400 на ошибку проверки данных; ошибки FieldError
+// сохраняют свой код, поле и параметры.
+func respondInvalid(c *gin.Context, err error) {
+	if body, ok := describeValidationError(c, err); ok {
+		c.JSON(http.StatusBadRequest, body)
+		return
+	}
+	respondError(c, http.StatusBadRequest, CodeValidation, "")
+}
+
+// respondStoreError — единая точка сопоставления ошибок хранилища с HTTP-ответом;
+// fallbackKey задаёт сообщение для ошибок, не относящихся ни к одной известной категории.
+func respondStoreError(c *gin.Context, err error, fallbackKey string) {
+	status, body := describeError(c, err, fallbackKey)
+	c.JSON(status, body)
+}
+
+// describeError строит тело ошибки без отправки ответа; используется там,
+// где ошибки собираются поэлементно, как в массовых операциях.
+func describeError(c *gin.Context, err error, fallbackKey string) (int, ErrorResponse) {
+	if body, ok := describeValidationError(c, err); ok {
+		return http.StatusBadRequest, body
+	}
+	for _, mapping := range storeErrorMappings {
+		if errors.Is(err, mapping.target) {
+			return mapping.status, newErrorResponse(c, mapping.code, mapping.code, "", nil)
+		}
+	}
+	return http.StatusInternalServerError, newErrorResponse(c, CodeInternal, fallbackKey, "", nil)
+}
+
+// describeValidationError перечисляет все ошибки проверки в поле errors.
+// Единственная ошибка дополнительно выносится на верхний уровень, поэтому
+// клиенты, читающие только code и field, видят её как раньше.
+func describeValidationError(c *gin.Context, err error) (ErrorResponse, bool) {
+	var list service.ValidationErrors
+	var fieldErr *service.FieldError
+	switch {
+	case errors.As(err, &list):
+	case errors.As(err, &fieldErr):
+		list = service.ValidationErrors{fieldErr}
+	default:
+		return ErrorResponse{}, false
+	}
+
+	items := make([]ErrorResponse, 0, len(list))
+	for _, item := range list {
+		items = append(items, describeFieldError(c, item))
+	}
+
+	body := newErrorResponse(c, CodeValidation, CodeValidation, "", nil)
+	if len(items) == 1 {
+		body = items[0]
+	}
+	body.Errors = items
+	return body, true
+}
+
+func describeFieldError(c *gin.Context, err *service.FieldError) ErrorResponse {
+	return newErrorResponse(c, err.Code, err.Code, err.Field, err.Details)
+}
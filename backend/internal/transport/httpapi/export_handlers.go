@@ -0,0 +1,122 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/export"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type exportOptions struct {
+	format   string
+	layout   export.TaskLayout
+	location *time.Location
+}
+
+// Export выгружает задачи с теми же фильтрами и сортировкой, что и List.
+// Строки пишутся в ответ по мере чтения из хранилища; ошибку после начала
+// выгрузки можно только записать в журнал, поэтому ответ обрывается.
+func (h *TaskHandler) Export(c *gin.Context) {
+	values := c.Request.URL.Query()
+	filter, sortOption, err := parseListValues(values)
+	var errs service.ValidationErrors
+	errs.Add(err)
+	options, err := parseExportOptions(values)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	now := h.clock.Now()
+	var writer export.RowWriter
+	begin := func() error {
+		writer = startExport(c, options, "tasks", now)
+		return writer.Write(options.layout.Header())
+	}
+	order := repository.TaskOrder{Option: sortOption, Policy: h.policy, Now: now}
+	err = repository.StreamTasks(c.Request.Context(), h.store, filter.TaskFilter(now), order, func(task domain.Task) error {
+		if writer == nil {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(options.layout.Row(task, h.policy.Metrics(now, task)))
+	})
+	if err == nil && writer == nil {
+		err = begin()
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if writer == nil {
+			respondStoreError(c, err, "export_failed")
+			return
+		}
+		_ = c.Error(err)
+	}
+}
+
+// ExportInsights выгружает сводные метрики парами «метрика — значение».
+func (h *TaskHandler) ExportInsights(c *gin.Context) {
+	values := c.Request.URL.Query()
+	filter, _, err := parseListValues(values)
+	var errs service.ValidationErrors
+	errs.Add(err)
+	options, err := parseExportOptions(values)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	now := h.clock.Now()
+	tasks, err := h.store.List(c.Request.Context(), filter.TaskFilter(now))
+	if err != nil {
+		respondStoreError(c, err, "insights_failed")
+		return
+	}
+
+	writer := startExport(c, options, "insights", now)
+	for _, row := range export.InsightRows(h.policy.Insights(now, tasks)) {
+		if err := writer.Write(row); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func parseExportOptions(values url.Values) (exportOptions, error) {
+	var errs service.ValidationErrors
+	format, err := export.ParseFormat(values.Get("format"))
+	errs.Add(err)
+	location, err := export.ParseLocation(values.Get("tz"))
+	errs.Add(err)
+	if location == nil {
+		location = time.UTC
+	}
+	layout, err := export.ParseLayout(values.Get("columns"), location)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		return exportOptions{}, err
+	}
+	return exportOptions{format: format, layout: layout, location: location}, nil
+}
+
+func startExport(c *gin.Context, options exportOptions, name string, now time.Time) export.RowWriter {
+	filename := fmt.Sprintf("%s-%s.%s", name, now.In(options.location).Format("20060102"), options.format)
+	c.Header("Content-Type", export.ContentType(options.format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+	return export.NewWriter(options.format, c.Writer)
+}
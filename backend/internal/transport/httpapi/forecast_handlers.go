@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"devopslabs/internal/forecast"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Forecast моделирует сроки методом Монте-Карло по дневной пропускной
+// способности задач, которые подходят под фильтры GET /api/tasks.
+func (h *TaskHandler) Forecast(c *gin.Context) {
+	values := c.Request.URL.Query()
+	now := h.clock.Now()
+	var errs service.ValidationErrors
+	filter, _, err := parseListValues(values)
+	errs.Add(err)
+	query, err := forecast.ParseQuery(
+		values.Get("target"), values.Get("items"), values.Get("history"), values.Get("trials"), values.Get("seed"), now,
+	)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	tasks, err := h.store.List(c.Request.Context(), filter.TaskFilter(now))
+	if err != nil {
+		respondStoreError(c, err, "forecast_failed")
+		return
+	}
+	result, err := forecast.Build(tasks, query, now)
+	if errors.Is(err, forecast.ErrNoThroughput) {
+		respondError(c, http.StatusUnprocessableEntity, forecast.CodeNoThroughput, "history")
+		return
+	}
+	if err != nil {
+		respondStoreError(c, err, "forecast_failed")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
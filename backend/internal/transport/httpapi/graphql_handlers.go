@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"devopslabs/internal/transport/graphqlapi"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+)
+
+// serveGraphQL принимает запросы GraphQL через POST с JSON-телом и через GET
+// с параметрами query, operationName и variables; GET допускает только
+// чтение. Запрос, не дошедший до выполнения полей, получает 400, остальные —
+// 200 с ошибками отдельных полей в errors.
+func serveGraphQL(executor *graphqlapi.Executor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := parseGraphQLRequest(c)
+		if !ok {
+			return
+		}
+
+		result := executor.Execute(c.Request.Context(), req, graphqlapi.Options{
+			Language: requestLanguage(c),
+			ReadOnly: c.Request.Method == http.MethodGet,
+		})
+
+		status := http.StatusOK
+		if result.Data == nil && !hasFieldErrors(result) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, result)
+	}
+}
+
+// hasFieldErrors отличает ошибки выполнения полей, у которых есть path, от
+// ошибок разбора, проверки и лимитов, при которых поля не выполнялись.
+func hasFieldErrors(result *graphql.Result) bool {
+	for _, err := range result.Errors {
+		if len(err.Path) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func parseGraphQLRequest(c *gin.Context) (graphqlapi.Request, bool) {
+	var req graphqlapi.Request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if raw := c.Query("variables"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, graphqlapi.Rejected(requestLanguage(c), CodeInvalidBody, "variables"))
+				return req, false
+			}
+		}
+		return req, true
+	}
+
+	body, ok := readBody(c)
+	if !ok {
+		return req, false
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		c.JSON(http.StatusBadRequest, graphqlapi.Rejected(requestLanguage(c), CodeInvalidBody, ""))
+		return req, false
+	}
+	return req, true
+}
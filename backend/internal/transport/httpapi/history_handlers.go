@@ -0,0 +1,117 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"devopslabs/internal/history"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// InsightsHistory — ряд сводки по дням, неделям или месяцам.
+type InsightsHistory struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Interval string          `json:"interval"`
+	Scope    string          `json:"scope"`
+	Key      string          `json:"key,omitempty"`
+	Points   []history.Point `json:"points"`
+}
+
+type HistoryHandler struct {
+	series   *history.Series
+	burndown *history.BurndownBuilder
+	flow     *history.FlowBuilder
+	clock    service.Clock
+}
+
+func NewHistoryHandler(series *history.Series, burndown *history.BurndownBuilder, flow *history.FlowBuilder, clock service.Clock) *HistoryHandler {
+	return &HistoryHandler{series: series, burndown: burndown, flow: flow, clock: clock}
+}
+
+func (h *HistoryHandler) Insights(c *gin.Context) {
+	query, err := history.ParseQuery(
+		c.Query("from"), c.Query("to"), c.Query("interval"), c.Query("owner"), c.Query("tag"), h.clock.Now(),
+	)
+	if err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	points, err := h.series.Points(c.Request.Context(), query)
+	if err != nil {
+		respondStoreError(c, err, "history_failed")
+		return
+	}
+	c.JSON(http.StatusOK, InsightsHistory{
+		From:     history.Day(query.From),
+		To:       history.Day(query.To),
+		Interval: query.Interval,
+		Scope:    query.Scope,
+		Key:      query.Key,
+		Points:   points,
+	})
+}
+
+// Flow отдаёт накопительную диаграмму потока по задачам, которые подходят
+// под фильтры GET /api/tasks.
+func (h *HistoryHandler) Flow(c *gin.Context) {
+	values := c.Request.URL.Query()
+	now := h.clock.Now()
+	var errs service.ValidationErrors
+	filter, _, err := parseListValues(values)
+	errs.Add(err)
+	from, to, err := history.ParseRange(values.Get("from"), values.Get("to"), history.DefaultDays, now)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	flow, err := h.flow.CumulativeFlow(c.Request.Context(), from, to, filter.TaskFilter(now))
+	if err != nil {
+		respondStoreError(c, err, "history_failed")
+		return
+	}
+	c.JSON(http.StatusOK, flow)
+}
+
+// TimeInStatus отдаёт время в статусах по задачам и сводку по статусам.
+func (h *HistoryHandler) TimeInStatus(c *gin.Context) {
+	now := h.clock.Now()
+	filter, _, err := parseListValues(c.Request.URL.Query())
+	if err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	stats, err := h.flow.TimeInStatus(c.Request.Context(), filter.TaskFilter(now))
+	if err != nil {
+		respondStoreError(c, err, "history_failed")
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// Burndown отдаёт данные диаграмм сгорания и выполнения по задачам, которые
+// подходят под фильтры GET /api/tasks.
+func (h *HistoryHandler) Burndown(c *gin.Context) {
+	values := c.Request.URL.Query()
+	now := h.clock.Now()
+	var errs service.ValidationErrors
+	filter, _, err := parseListValues(values)
+	errs.Add(err)
+	from, to, err := history.ParseRange(values.Get("from"), values.Get("to"), history.DefaultBurndownDays, now)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	burndown, err := h.burndown.Build(c.Request.Context(), from, to, filter.TaskFilter(now))
+	if err != nil {
+		respondStoreError(c, err, "history_failed")
+		return
+	}
+	c.JSON(http.StatusOK, burndown)
+}
@@ -0,0 +1,191 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	DefaultIdempotencyTTL    = 24 * time.Hour
+	maxIdempotencyKeyLength  = 255
+	// maxIdempotentRequestBytes не меньше самого большого тела, которое
+	// принимают маршруты с Idempotency-Key, — файла импорта.
+	maxIdempotentRequestBytes = maxImportBodyBytes
+)
+
+const (
+	CodeInvalidIdempotencyKey = "invalid_idempotency_key"
+	CodeIdempotencyKeyReused  = "idempotency_key_reused"
+	CodeIdempotencyInProgress = "idempotency_in_progress"
+)
+
+type idempotencyGuard struct {
+	store repository.IdempotencyStore
+	ttl   time.Duration
+	clock service.Clock
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+// Idempotency сохраняет ответ на запрос с заголовком Idempotency-Key и
+// повторяет его при повторной отправке того же тела. Тот же ключ с другим
+// телом отклоняется с 422, а дубликат, пришедший во время обработки
+// оригинала, получает 409.
+func Idempotency(store repository.IdempotencyStore, ttl time.Duration, clock service.Clock) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	guard := &idempotencyGuard{store: store, ttl: ttl, clock: clock}
+	return guard.handle
+}
+
+func (g *idempotencyGuard) handle(c *gin.Context) {
+	key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+	if key == "" {
+		c.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		respondError(c, http.StatusBadRequest, CodeInvalidIdempotencyKey, IdempotencyKeyHeader)
+		c.Abort()
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentRequestBytes+1))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+		c.Abort()
+		return
+	}
+	if len(body) > maxIdempotentRequestBytes {
+		// Обрезанное тело нельзя ни обработать, ни запомнить.
+		c.JSON(http.StatusRequestEntityTooLarge, newErrorResponse(c, CodeBodyTooLarge, CodeBodyTooLarge, "", map[string]any{"max": maxIdempotentRequestBytes}))
+		c.Abort()
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	ctx := c.Request.Context()
+	now := g.clock.Now()
+	g.purgeIfDue(c, now)
+
+	record := &repository.IdempotencyRecord{
+		Key:         c.Request.Method + " " + c.FullPath() + " " + key,
+		Fingerprint: requestFingerprint(c.Request.Method, c.FullPath(), body),
+		State:       repository.IdempotencyPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(g.ttl),
+	}
+
+	existing, created, err := g.store.Reserve(ctx, record, now)
+	if err != nil {
+		respondStoreError(c, err, "internal")
+		c.Abort()
+		return
+	}
+
+	if !created {
+		switch {
+		case existing.Fingerprint != record.Fingerprint:
+			respondError(c, http.StatusUnprocessableEntity, CodeIdempotencyKeyReused, IdempotencyKeyHeader)
+		case existing.State != repository.IdempotencyCompleted:
+			c.Header("Retry-After", "1")
+			respondError(c, http.StatusConflict, CodeIdempotencyInProgress, IdempotencyKeyHeader)
+		default:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+		}
+		c.Abort()
+		return
+	}
+
+	// Запись завершается и после отключения клиента, иначе ключ оставался бы
+	// в pending до истечения срока. Серверные ошибки, паника обработчика и
+	// несохранённый ответ освобождают ключ, чтобы клиент мог повторить запрос.
+	finalizeCtx := context.WithoutCancel(ctx)
+	completed := false
+	defer func() {
+		if !completed {
+			if err := g.store.Release(finalizeCtx, record.Key); err != nil {
+				log.Printf("не удалось освободить Idempotency-Key: %v", err)
+			}
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+
+	status := recorder.Status()
+	if status >= http.StatusInternalServerError {
+		return
+	}
+	if err := g.store.Complete(finalizeCtx, record.Key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+		log.Printf("не удалось сохранить ответ для Idempotency-Key: %v", err)
+		return
+	}
+	completed = true
+}
+
+func (g *idempotencyGuard) purgeIfDue(c *gin.Context, now time.Time) {
+	g.mu.Lock()
+	due := now.Sub(g.lastPurge) >= g.ttl
+	if due {
+		g.lastPurge = now
+	}
+	g.mu.Unlock()
+
+	if due {
+		_, _ = g.store.PurgeExpired(c.Request.Context(), now)
+	}
+}
+
+// requestFingerprint не зависит от порядка ключей и пробелов в JSON-теле.
+func requestFingerprint(method string, route string, body []byte) string {
+	canonical := body
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		if data, err := json.Marshal(decoded); err == nil {
+			canonical = data
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + route + "\n"))
+	hash.Write(canonical)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
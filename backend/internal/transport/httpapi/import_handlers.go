@@ -0,0 +1,175 @@
+package httpapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"devopslabs/internal/importer"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBodyBytes ограничивает файл импорта; он больше общего
+// ограничения тела, потому что таблицы содержат тысячи строк.
+const maxImportBodyBytes = 10 << 20
+
+type ImportRowResult struct {
+	Row        int            `json:"row"`
+	ExternalID string         `json:"externalId,omitempty"`
+	Result     string         `json:"result"`
+	Task       *TaskResponse  `json:"task,omitempty"`
+	Error      *ErrorResponse `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	Source         string            `json:"source"`
+	Format         string            `json:"format,omitempty"`
+	DryRun         bool              `json:"dryRun"`
+	Created        int               `json:"created"`
+	Updated        int               `json:"updated"`
+	Failed         int               `json:"failed"`
+	IgnoredColumns []string          `json:"ignoredColumns,omitempty"`
+	Rows           []ImportRowResult `json:"rows"`
+}
+
+// Import создаёт и обновляет задачи из CSV или JSON либо из выгрузки Jira,
+// Trello или GitHub. Ошибки отдельных строк не прерывают импорт и
+// перечисляются в отчёте.
+func (h *TaskHandler) Import(c *gin.Context) {
+	upload, ok := readImportUpload(c)
+	if !ok {
+		return
+	}
+
+	var errs service.ValidationErrors
+	source, err := importer.ParseSource(c.Query("source"))
+	errs.Add(err)
+	format, err := importer.ParseFormat(c.Query("format"), upload.contentType)
+	errs.Add(err)
+	columns, err := importer.ParseMapping(c.Query("map"))
+	errs.Add(err)
+	mapping := importer.SourceMapping{}
+	if upload.mapping != nil {
+		mapping, err = importer.LoadMapping(bytes.NewReader(upload.mapping))
+		errs.Add(err)
+	}
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+	if source != importer.SourceFile {
+		format = ""
+	}
+
+	records, ignored, err := importer.Read(source, format, bytes.NewReader(upload.data), mapping.WithColumns(columns))
+	if err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	now := h.clock.Now()
+	dryRun := isTruthy(c.Query("dryRun"))
+	report := importer.Run(c.Request.Context(), h.store, records, importer.Options{DryRun: dryRun, Now: now})
+
+	response := ImportResponse{
+		Source:         source,
+		Format:         format,
+		DryRun:         dryRun,
+		Created:        report.Created,
+		Updated:        report.Updated,
+		Failed:         report.Failed,
+		IgnoredColumns: ignored,
+		Rows:           make([]ImportRowResult, 0, len(report.Rows)),
+	}
+	for _, row := range report.Rows {
+		result := ImportRowResult{Row: row.Row, ExternalID: row.ExternalID, Result: row.Result}
+		if row.Task != nil {
+			task := h.toTaskResponse(*row.Task, now)
+			result.Task = &task
+		}
+		if row.Err != nil {
+			_, body := describeError(c, row.Err, "import_failed")
+			result.Error = &body
+		}
+		response.Rows = append(response.Rows, result)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// importUpload — файл импорта и необязательный файл сопоставления.
+type importUpload struct {
+	data        []byte
+	contentType string
+	mapping     []byte
+}
+
+// readImportUpload читает файл из тела запроса либо из частей file и
+// mapping формы multipart/form-data.
+func readImportUpload(c *gin.Context) (importUpload, bool) {
+	body, ok := readImportBody(c)
+	if !ok {
+		return importUpload{}, false
+	}
+	mediaType, params, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return importUpload{data: body, contentType: c.ContentType()}, true
+	}
+
+	var upload importUpload
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+			return importUpload{}, false
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+			return importUpload{}, false
+		}
+		switch part.FormName() {
+		case "file":
+			upload.data = data
+			upload.contentType = part.Header.Get("Content-Type")
+		case "mapping":
+			upload.mapping = data
+		}
+	}
+	if upload.data == nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidBody, "file")
+		return importUpload{}, false
+	}
+	return upload, true
+}
+
+func readImportBody(c *gin.Context) ([]byte, bool) {
+	if c.Request.Body == nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxImportBodyBytes+1))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+		return nil, false
+	}
+	if len(body) > maxImportBodyBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, newErrorResponse(c, CodeBodyTooLarge, CodeBodyTooLarge, "", map[string]any{"max": maxImportBodyBytes}))
+		return nil, false
+	}
+	return body, true
+}
+
+func isTruthy(raw string) bool {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	return value == "true" || value == "1" || value == "yes"
+}
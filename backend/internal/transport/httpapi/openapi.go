@@ -0,0 +1,816 @@
+package httpapi
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"devopslabs/internal/calendar"
+	"devopslabs/internal/capacity"
+	"devopslabs/internal/domain"
+	"devopslabs/internal/export"
+	"devopslabs/internal/forecast"
+	"devopslabs/internal/history"
+	"devopslabs/internal/importer"
+	"devopslabs/internal/jsonpatch"
+	"devopslabs/internal/openapi"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs/index.html
+var docsPage []byte
+
+var (
+	openAPIOnce     sync.Once
+	openAPIDocument *openapi.Document
+)
+
+// OpenAPIDocument возвращает описание всех маршрутов NewRouter. Схемы тел
+// строятся по Go-типам обработчиков, перечисления — по справочникам domain.
+func OpenAPIDocument() *openapi.Document {
+	openAPIOnce.Do(func() {
+		openAPIDocument = buildOpenAPIDocument()
+	})
+	return openAPIDocument
+}
+
+func serveOpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, OpenAPIDocument())
+}
+
+func serveDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
+
+func buildOpenAPIDocument() *openapi.Document {
+	registry := openapi.NewRegistry()
+
+	task := registry.Register(TaskResponse{})
+	createRequest := registry.Register(TaskCreateRequest{})
+	insights := registry.Register(service.Insights{})
+	errorBody := registry.Register(ErrorResponse{})
+	bulkRequest := registry.Register(BulkRequest{})
+	bulkResponse := registry.Register(BulkResponse{})
+	patchOperation := registry.Register(jsonpatch.Operation{})
+	importResponse := registry.Register(ImportResponse{})
+	view := registry.Register(domain.SavedView{})
+	viewRequest := registry.Register(ViewRequest{})
+	calendarFeed := registry.Register(CalendarFeed{})
+	insightsHistory := registry.Register(InsightsHistory{})
+	burndown := registry.Register(history.Burndown{})
+	cumulativeFlow := registry.Register(history.CumulativeFlow{})
+	timeInStatus := registry.Register(history.TimeInStatus{})
+	flowTimes := registry.Register(service.FlowTimes{})
+	agingWIP := registry.Register(service.AgingWIP{})
+	deliveryForecast := registry.Register(forecast.Forecast{})
+	scoreBreakdown := registry.Register(service.ScoreBreakdown{})
+	scoringPolicy := openapi.RefTo("Policy")
+	ownerCapacity := registry.Register(domain.OwnerCapacity{})
+	capacityRequest := registry.Register(CapacityRequest{})
+	workload := registry.Register(capacity.Workload{})
+
+	statuses := sortedKeys(domain.AllowedStatuses)
+	priorities := sortedKeys(domain.AllowedPriorities)
+	risks := []string{service.RiskOnTrack, service.RiskAtRisk, service.RiskOverdue, service.RiskUnassigned, service.RiskBlocked, service.RiskCompleted}
+
+	taskSchema := registry.Schema("TaskResponse")
+	setEnum(taskSchema, "status", statuses)
+	setEnum(taskSchema, "priority", priorities)
+	setEnum(taskSchema, "risk", risks)
+
+	createSchema := registry.Schema("TaskCreateRequest")
+	createSchema.Required = []string{"title"}
+	setEnum(createSchema, "status", statuses)
+	setEnum(createSchema, "priority", priorities)
+	createSchema.Properties["title"].MaxLength = intPtr(service.MaxTitleLength)
+	// Трудоёмкость 0 или null означает значение по умолчанию, как и в
+	// service.NormalizeEffort.
+	createSchema.Properties["effortHours"].Type = []string{"integer", "null"}
+	createSchema.Properties["effortHours"].Description = fmt.Sprintf("Часы от %d до %d; 0 или null — %d", service.MinEffortHours, service.MaxEffortHours, service.DefaultEffortHours)
+	createSchema.Properties["effortHours"].Minimum = floatPtr(0)
+	createSchema.Properties["effortHours"].Maximum = floatPtr(service.MaxEffortHours)
+	createSchema.Properties["dueDate"].Format = "date-time"
+	createSchema.Properties["tags"].MaxItems = intPtr(service.MaxTags)
+	createSchema.Properties["tags"].Items.MaxLength = intPtr(service.MaxTagLength)
+
+	mergePatch := *createSchema
+	mergePatch.Required = nil
+	mergePatch.Description = "JSON Merge Patch (RFC 7396): null удаляет значение поля."
+	registry.Schemas["TaskMergePatch"] = &mergePatch
+
+	bulkSchema := registry.Schema("BulkRequest")
+	bulkSchema.Required = []string{"operation"}
+	setEnum(bulkSchema, "operation", []string{BulkSetStatus, BulkSetPriority, BulkSetOwner, BulkAddTags, BulkRemoveTags, BulkDelete})
+	bulkSchema.Properties["ids"].MaxItems = intPtr(maxBulkItems)
+	listFilter := func() *openapi.Schema {
+		return &openapi.Schema{
+			Type:        "object",
+			Description: "Фильтр с теми же ключами, что и в GET /api/tasks.",
+			Properties: map[string]*openapi.Schema{
+				"status":   {Type: "string", Description: "Статусы через запятую"},
+				"priority": {Type: "string", Description: "Приоритеты через запятую"},
+				"owner":    openapi.String(),
+				"tag":      openapi.String(),
+				"q":        openapi.String(),
+				"filter":   {Type: "string", Description: "Выражение фильтра, как в параметре filter", MaxLength: intPtr(taskquery.MaxLength)},
+			},
+			AdditionalProperties: false,
+		}
+	}
+	bulkSchema.Properties["filter"] = listFilter()
+	setEnum(registry.Schema("BulkItemResult"), "result", []string{BulkResultUpdated, BulkResultDeleted, BulkResultFailed, BulkResultSkipped})
+
+	setEnum(registry.Schema("ImportRowResult"), "result", []string{importer.ResultCreated, importer.ResultUpdated, importer.ResultFailed})
+	setEnum(registry.Schema("Forecast"), "mode", []string{forecast.ModeHowMany, forecast.ModeWhen})
+	setEnum(registry.Schema("ScoreBreakdown"), "risk", risks)
+	setEnum(registry.Schema("SLAClock"), "state", []string{service.SLARunning, service.SLAImminent, service.SLAMet, service.SLABreached})
+	setEnum(registry.Schema("ScoreTerm"), "term", []string{service.TermPriority, service.TermDue, service.TermStatus, service.TermEffort})
+	setEnum(registry.Schema("ImportResponse"), "format", []string{importer.FormatCSV, importer.FormatJSON})
+	setEnum(registry.Schema("ImportResponse"), "source", importer.Sources)
+	importFields := make([]string, 0, len(importer.Fields))
+	importRecord := &openapi.Schema{Type: "object", Description: "Строка импорта; поля как в TaskCreateRequest, tags — массив или строка через запятую.", Properties: map[string]*openapi.Schema{}}
+	for _, field := range importer.Fields {
+		importFields = append(importFields, string(field))
+		importRecord.Properties[string(field)] = &openapi.Schema{}
+	}
+	registry.Schemas["ImportRecord"] = importRecord
+
+	setEnum(registry.Schema("CalendarFeed"), "kind", []string{CalendarFeedOwner, CalendarFeedView})
+
+	intervals := []string{history.IntervalDay, history.IntervalWeek, history.IntervalMonth}
+	historySchema := registry.Schema("InsightsHistory")
+	setEnum(historySchema, "interval", intervals)
+	setEnum(historySchema, "scope", []string{repository.SnapshotScopeAll, repository.SnapshotScopeOwner, repository.SnapshotScopeTag})
+	setEnum(registry.Schema("Point"), "source", []string{history.SourceSnapshot, history.SourceBackfill, history.SourceLive})
+	setEnum(registry.Schema("ScopeChange"), "kind", []string{history.ScopeAdded, history.ScopeRemoved, history.ScopeResized})
+	setEnum(registry.Schema("CumulativeFlow"), "statuses", history.FlowStatuses)
+	setEnum(registry.Schema("Transition"), "status", statuses)
+	setEnum(registry.Schema("TaskStatusTime"), "status", statuses)
+	setEnum(registry.Schema("StatusTime"), "status", statuses)
+	setEnum(registry.Schema("DurationPoint"), "priority", priorities)
+	setEnum(registry.Schema("AgingGroup"), "status", service.AgingStatuses)
+	setEnum(registry.Schema("AgingItem"), "status", service.AgingStatuses)
+	setEnum(registry.Schema("AgingItem"), "priority", priorities)
+	setEnum(registry.Schema("AgingItem"), "band", []string{service.AgingBelowP50, service.AgingPastP50, service.AgingPastP85, service.AgingPastP95})
+
+	capacityRequestSchema := registry.Schema("CapacityRequest")
+	capacityRequestSchema.Required = []string{"hoursPerWeek"}
+	capacityRequestSchema.Properties["hoursPerWeek"].Minimum = floatPtr(0)
+	capacityRequestSchema.Properties["hoursPerWeek"].Maximum = floatPtr(domain.MaxHoursPerWeek)
+	capacityRequestSchema.Properties["daysOff"].MaxItems = intPtr(capacity.MaxDaysOff)
+	capacityRequestSchema.Properties["daysOff"].Items.Description = "YYYY-MM-DD"
+	setEnum(registry.Schema("Suggestion"), "priority", priorities)
+
+	visibilities := sortedKeys(domain.AllowedVisibilities)
+	viewSchema := registry.Schema("SavedView")
+	viewSchema.Properties["filter"] = listFilter()
+	setEnum(viewSchema, "visibility", visibilities)
+	setEnum(viewSchema, "sort", service.SortFields)
+	setEnum(viewSchema, "order", []string{"asc", "desc"})
+
+	viewRequestSchema := registry.Schema("ViewRequest")
+	viewRequestSchema.Required = []string{"name"}
+	viewRequestSchema.Properties["name"].MaxLength = intPtr(maxViewNameLength)
+	viewRequestSchema.Properties["filter"] = listFilter()
+	viewRequestSchema.Properties["visibility"] = withDefault(openapi.Enum(visibilities...), domain.VisibilityPrivate)
+	viewRequestSchema.Properties["sort"] = withDefault(openapi.Enum(service.SortFields...), "score")
+	viewRequestSchema.Properties["order"] = withDefault(openapi.Enum("asc", "desc"), "desc")
+
+	patchSchema := registry.Schema("Operation")
+	patchSchema.Required = []string{"op", "path"}
+	setEnum(patchSchema, "op", []string{jsonpatch.OpAdd, jsonpatch.OpRemove, jsonpatch.OpReplace, jsonpatch.OpMove, jsonpatch.OpCopy, jsonpatch.OpTest})
+
+	registry.Schemas["GraphQLRequest"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"query"},
+		Properties: map[string]*openapi.Schema{
+			"query":         openapi.String(),
+			"operationName": openapi.String(),
+			"variables":     {Type: "object"},
+		},
+	}
+	registry.Schemas["GraphQLResponse"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"data": {Description: "Результат запроса; null, если запрос отклонён целиком."},
+			"errors": openapi.ArrayOf(&openapi.Schema{
+				Type: "object",
+				Properties: map[string]*openapi.Schema{
+					"message":    openapi.String(),
+					"path":       {Type: "array"},
+					"extensions": {Type: "object", Description: "code, field, details и errors как в ErrorResponse"},
+				},
+			}),
+		},
+	}
+	graphQLResponses := func() map[string]openapi.Response {
+		return map[string]openapi.Response{
+			"200": {Description: "Результат; ошибки отдельных полей перечислены в errors", Content: openapi.JSONContent(openapi.RefTo("GraphQLResponse"))},
+			"400": {Description: "Запрос отклонён: некорректное тело, синтаксис, схема или превышение лимитов сложности", Content: openapi.JSONContent(openapi.RefTo("GraphQLResponse"))},
+		}
+	}
+
+	listParams := []openapi.Parameter{
+		csvEnumParam("status", "Статусы через запятую", statuses),
+		csvEnumParam("priority", "Приоритеты через запятую", priorities),
+		{Name: "owner", In: "query", Description: "Точное совпадение исполнителя", Schema: openapi.String()},
+		{Name: "tag", In: "query", Description: "Тег без учёта регистра", Schema: openapi.String()},
+		{Name: "q", In: "query", Description: "Поиск по названию и описанию", Schema: openapi.String()},
+		{Name: "filter", In: "query", Description: "Выражение фильтра, например priority>=high AND (owner:alice OR tag:infra) AND due<7d", Schema: &openapi.Schema{Type: "string", MaxLength: intPtr(taskquery.MaxLength)}},
+	}
+	sortParams := []openapi.Parameter{
+		{Name: "sort", In: "query", Schema: withDefault(openapi.Enum(service.SortFields...), "score")},
+		{Name: "order", In: "query", Schema: withDefault(openapi.Enum("asc", "desc"), "desc")},
+	}
+	exportParams := []openapi.Parameter{
+		{Name: "format", In: "query", Schema: withDefault(openapi.Enum(export.Formats...), export.FormatCSV)},
+		{Name: "tz", In: "query", Description: "Часовой пояс IANA для дат, по умолчанию UTC", Schema: openapi.String()},
+	}
+	columnsParam := csvEnumParam("columns", "Колонки через запятую в нужном порядке", export.Columns())
+	exportContent := func(schema *openapi.Schema) map[string]openapi.MediaType {
+		return map[string]openapi.MediaType{
+			export.ContentType(export.FormatCSV):  {Schema: schema},
+			export.ContentType(export.FormatXLSX): {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+		}
+	}
+	calendarParams := []openapi.Parameter{
+		{Name: "component", In: "query", Description: "VTODO или событие на весь день для календарей без задач", Schema: withDefault(openapi.Enum(calendar.Components...), calendar.ComponentTodo)},
+		{Name: "tz", In: "query", Description: "Часовой пояс IANA для даты событий, по умолчанию UTC", Schema: openapi.String()},
+		{Name: "If-None-Match", In: "header", Description: "ETag прошлого ответа", Schema: openapi.String()},
+	}
+	tokenParam := openapi.Parameter{Name: "token", In: "query", Required: true, Description: "Токен из GET /api/calendar/feeds", Schema: openapi.String()}
+	idParam := openapi.Parameter{Name: "id", In: "path", Required: true, Schema: openapi.Integer()}
+	ownerParam := openapi.Parameter{Name: "owner", In: "path", Required: true, Description: "Исполнитель", Schema: &openapi.Schema{Type: "string", MaxLength: intPtr(maxUserLength)}}
+	forceParam := openapi.Parameter{Name: "force", In: "query", Description: "Разрешить переход статуса вне графа переходов", Schema: openapi.Enum("true", "1", "yes")}
+	idempotencyParam := openapi.Parameter{Name: IdempotencyKeyHeader, In: "header", Description: "Ключ для безопасного повтора запроса", Schema: &openapi.Schema{Type: "string", MaxLength: intPtr(maxIdempotencyKeyLength)}}
+	userParam := openapi.Parameter{Name: UserHeader, In: "header", Description: "Пользователь, от имени которого выполняется запрос", Schema: &openapi.Schema{Type: "string", MaxLength: intPtr(maxUserLength)}}
+	languageParam := openapi.Parameter{Name: "Accept-Language", In: "header", Description: "Язык сообщений об ошибках (ru по умолчанию, en)", Schema: openapi.String()}
+
+	errorResponses := func(statuses ...int) map[string]openapi.Response {
+		responses := make(map[string]openapi.Response, len(statuses))
+		for _, status := range statuses {
+			responses[strconv.Itoa(status)] = openapi.Response{Description: http.StatusText(status), Content: openapi.JSONContent(errorBody)}
+		}
+		return responses
+	}
+	with := func(base map[string]openapi.Response, status int, response openapi.Response) map[string]openapi.Response {
+		base[strconv.Itoa(status)] = response
+		return base
+	}
+	params := func(groups ...[]openapi.Parameter) []openapi.Parameter {
+		var result []openapi.Parameter
+		for _, group := range groups {
+			result = append(result, group...)
+		}
+		return append(result, languageParam)
+	}
+
+	calendarResponses := func(statuses ...int) map[string]openapi.Response {
+		responses := with(errorResponses(statuses...), http.StatusOK, openapi.Response{
+			Description: "Календарь iCalendar",
+			Headers:     map[string]openapi.Header{"ETag": {Description: "Меняется только при изменении календаря", Schema: openapi.String()}},
+			Content:     map[string]openapi.MediaType{calendar.ContentType: {Schema: openapi.String()}},
+		})
+		responses[strconv.Itoa(http.StatusNotModified)] = openapi.Response{Description: "Календарь не изменился"}
+		return responses
+	}
+
+	replayedHeader := map[string]openapi.Header{
+		IdempotentReplayedHeader: {Description: "true, если ответ повторён по Idempotency-Key", Schema: openapi.String()},
+	}
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "FlowBoard API",
+			Version:     "1.0.0",
+			Description: "REST API учебного приложения FlowBoard для управления задачами.",
+		},
+		Tags: []openapi.Tag{
+			{Name: "tasks", Description: "Задачи"},
+			{Name: "insights", Description: "Метрики"},
+			{Name: "views", Description: "Сохранённые представления"},
+			{Name: "capacity", Description: "Ёмкость и нагрузка исполнителей"},
+			{Name: "calendar", Description: "Календарь сроков в формате iCalendar"},
+			{Name: "graphql", Description: "GraphQL API"},
+			{Name: "system", Description: "Служебные маршруты"},
+		},
+		Paths: map[string]map[string]openapi.Operation{
+			"/health": {
+				"get": {
+					OperationID: "health",
+					Summary:     "Проверка работоспособности",
+					Tags:        []string{"system"},
+					Responses: map[string]openapi.Response{
+						"200": {Description: "OK", Content: openapi.JSONContent(&openapi.Schema{
+							Type:       "object",
+							Properties: map[string]*openapi.Schema{"status": openapi.Enum("ok")},
+						})},
+					},
+				},
+			},
+			"/api/openapi.json": {
+				"get": {
+					OperationID: "getOpenAPI",
+					Summary:     "Спецификация OpenAPI",
+					Tags:        []string{"system"},
+					Responses:   map[string]openapi.Response{"200": {Description: "Документ OpenAPI 3.1", Content: openapi.JSONContent(&openapi.Schema{Type: "object"})}},
+				},
+			},
+			"/api/docs": {
+				"get": {
+					OperationID: "getDocs",
+					Summary:     "Документация API",
+					Tags:        []string{"system"},
+					Responses: map[string]openapi.Response{"200": {
+						Description: "HTML-страница документации",
+						Content:     map[string]openapi.MediaType{"text/html": {Schema: openapi.String()}},
+					}},
+				},
+			},
+			"/api/tasks": {
+				"get": {
+					OperationID: "listTasks",
+					Summary:     "Список задач",
+					Tags:        []string{"tasks"},
+					Parameters:  params(listParams, sortParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Задачи", Content: openapi.JSONContent(openapi.ArrayOf(task))}),
+				},
+				"post": {
+					OperationID: "createTask",
+					Summary:     "Создать задачу",
+					Tags:        []string{"tasks"},
+					Parameters:  params([]openapi.Parameter{idempotencyParam}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSONContent(createRequest)},
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusCreated, openapi.Response{Description: "Созданная задача", Headers: replayedHeader, Content: openapi.JSONContent(task)}),
+				},
+			},
+			"/api/tasks/bulk": {
+				"post": {
+					OperationID: "bulkTasks",
+					Summary:     "Массовая операция над задачами",
+					Tags:        []string{"tasks"},
+					Parameters:  params([]openapi.Parameter{idempotencyParam}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSONContent(bulkRequest)},
+					Responses: with(with(errorResponses(http.StatusBadRequest, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Результаты по задачам", Headers: replayedHeader, Content: openapi.JSONContent(bulkResponse)}),
+						http.StatusUnprocessableEntity, openapi.Response{Description: "Атомарная операция отменена или ключ идемпотентности использован повторно", Content: openapi.JSONContent(bulkResponse)}),
+				},
+			},
+			"/api/tasks/export": {
+				"get": {
+					OperationID: "exportTasks",
+					Summary:     "Выгрузить задачи в CSV или XLSX",
+					Description: "Принимает те же фильтры и сортировку, что и список задач. Строки передаются потоком.",
+					Tags:        []string{"tasks"},
+					Parameters:  params(listParams, sortParams, exportParams, []openapi.Parameter{columnsParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Файл выгрузки", Content: exportContent(openapi.String())}),
+				},
+			},
+			"/api/tasks/import": {
+				"post": {
+					OperationID: "importTasks",
+					Summary:     "Импортировать задачи из CSV, JSON или выгрузки трекера",
+					Description: "Каждая строка проверяется как при создании задачи. Задачи с совпадающим externalId обновляются, остальные создаются; запись идёт порциями в транзакциях. " +
+						"Для source=jira принимается XML или CSV выгрузка Jira, для trello — JSON доски, для github — JSON-массив задач; исходные идентификаторы сохраняются в externalId с префиксом источника.",
+					Tags: []string{"tasks"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "source", In: "query", Description: "Источник выгрузки", Schema: withDefault(openapi.Enum(importer.Sources...), importer.SourceFile)},
+						{Name: "format", In: "query", Description: "Только для source=file; по умолчанию определяется по Content-Type", Schema: openapi.Enum(importer.FormatCSV, importer.FormatJSON)},
+						{Name: "dryRun", In: "query", Description: "Только проверить и показать отчёт", Schema: openapi.Enum("true", "1", "yes")},
+						{Name: "map", In: "query", Description: "Сопоставление заголовков CSV полям: Summary:title,Story Points:effortHours. Поля: " + strings.Join(importFields, ", "), Schema: openapi.String()},
+						idempotencyParam,
+					}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+						"text/csv":         {Schema: openapi.String()},
+						"application/json": {Schema: openapi.ArrayOf(openapi.RefTo("ImportRecord"))},
+						"application/xml":  {Schema: openapi.String()},
+						"multipart/form-data": {Schema: &openapi.Schema{Type: "object", Required: []string{"file"}, Properties: map[string]*openapi.Schema{
+							"file":    {Type: "string", Format: "binary", Description: "Файл импорта"},
+							"mapping": {Type: "string", Format: "binary", Description: "JSON-файл сопоставления: statuses, priorities, users, labels, columns"},
+						}}},
+					}},
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Отчёт по строкам", Headers: replayedHeader, Content: openapi.JSONContent(importResponse)}),
+				},
+			},
+			"/api/tasks/{id}": {
+				"get": {
+					OperationID: "getTask",
+					Summary:     "Получить задачу",
+					Tags:        []string{"tasks"},
+					Parameters:  params([]openapi.Parameter{idParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Задача", Content: openapi.JSONContent(task)}),
+				},
+				"put": {
+					OperationID: "replaceTask",
+					Summary:     "Полностью заменить задачу",
+					Description: "Не переданные поля получают значения по умолчанию, как при создании.",
+					Tags:        []string{"tasks"},
+					Parameters:  params([]openapi.Parameter{idParam, forceParam}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSONContent(createRequest)},
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Обновлённая задача", Content: openapi.JSONContent(task)}),
+				},
+				"patch": {
+					OperationID: "patchTask",
+					Summary:     "Частично изменить задачу",
+					Tags:        []string{"tasks"},
+					Parameters:  params([]openapi.Parameter{idParam, forceParam}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+						MediaTypeMergePatch: {Schema: openapi.RefTo("TaskMergePatch")},
+						MediaTypeJSONPatch:  {Schema: openapi.ArrayOf(patchOperation)},
+					}},
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Обновлённая задача", Content: openapi.JSONContent(task)}),
+				},
+				"delete": {
+					OperationID: "deleteTask",
+					Summary:     "Удалить задачу",
+					Tags:        []string{"tasks"},
+					Parameters:  params([]openapi.Parameter{idParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusNoContent, openapi.Response{Description: "Задача удалена"}),
+				},
+			},
+			"/api/tasks/{id}/score": {
+				"get": {
+					OperationID: "explainTaskScore",
+					Summary:     "Разложить оценку задачи на слагаемые",
+					Description: "Слагаемые действующей политики: очки за приоритет, полосу срока, статус и трудоёмкость. Сумма слагаемых, округлённая до десятых, равна score в GET /api/tasks/{id}. hoursUntilDue — рабочие часы по календарю исполнителя из WORK_CALENDAR.",
+					Tags:        []string{"tasks"},
+					Parameters:  params([]openapi.Parameter{idParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Разложение оценки", Content: openapi.JSONContent(scoreBreakdown)}),
+				},
+			},
+			"/api/policy": {
+				"get": {
+					OperationID: "getScoringPolicy",
+					Summary:     "Действующая политика оценки и риска",
+					Description: "Задаётся файлом из переменной SCORING_POLICY; без неё действуют исходные правила.",
+					Tags:        []string{"insights"},
+					Responses: with(errorResponses(http.StatusInternalServerError),
+						http.StatusOK, openapi.Response{Description: "Политика", Content: openapi.JSONContent(scoringPolicy)}),
+				},
+			},
+			"/api/graphql": {
+				"get": {
+					OperationID: "graphqlQuery",
+					Summary:     "Запрос GraphQL только для чтения",
+					Tags:        []string{"graphql"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "query", In: "query", Required: true, Schema: openapi.String()},
+						{Name: "operationName", In: "query", Schema: openapi.String()},
+						{Name: "variables", In: "query", Description: "Переменные в виде JSON-объекта", Schema: openapi.String()},
+					}),
+					Responses: graphQLResponses(),
+				},
+				"post": {
+					OperationID: "graphql",
+					Summary:     "Запрос или мутация GraphQL",
+					Tags:        []string{"graphql"},
+					Parameters:  params(),
+					RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSONContent(openapi.RefTo("GraphQLRequest"))},
+					Responses: with(graphQLResponses(),
+						http.StatusRequestEntityTooLarge, openapi.Response{Description: http.StatusText(http.StatusRequestEntityTooLarge), Content: openapi.JSONContent(errorBody)}),
+				},
+			},
+			"/api/insights/export": {
+				"get": {
+					OperationID: "exportInsights",
+					Summary:     "Выгрузить сводные метрики в CSV или XLSX",
+					Tags:        []string{"insights"},
+					Parameters:  params(listParams, exportParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Пары «метрика — значение»", Content: exportContent(openapi.String())}),
+				},
+			},
+			"/api/insights/history": {
+				"get": {
+					OperationID: "getInsightsHistory",
+					Summary:     "Ряд сводных метрик по дням, неделям или месяцам",
+					Description: "Дни без сохранённого снимка восстанавливаются по истории изменений задач, текущий день считается по текущему состоянию. Значение недели или месяца — значение его последнего дня.",
+					Tags:        []string{"insights"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "from", In: "query", Description: "Первый день (YYYY-MM-DD, UTC), по умолчанию 29 дней до to", Schema: openapi.String()},
+						{Name: "to", In: "query", Description: "Последний день (YYYY-MM-DD, UTC), по умолчанию сегодня", Schema: openapi.String()},
+						{Name: "interval", In: "query", Schema: withDefault(openapi.Enum(intervals...), history.IntervalDay)},
+						{Name: "owner", In: "query", Description: "Сводка по владельцу; нельзя сочетать с tag", Schema: openapi.String()},
+						{Name: "tag", In: "query", Description: "Сводка по тегу", Schema: openapi.String()},
+					}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Ряд метрик", Content: openapi.JSONContent(insightsHistory)}),
+				},
+			},
+			"/api/insights/burndown": {
+				"get": {
+					OperationID: "getBurndown",
+					Summary:     "Данные диаграмм сгорания и выполнения за итерацию",
+					Description: "Объём работ в EffortHours и задачах на конец каждого дня до текущего, идеальная линия на весь диапазон и отметки изменения объёма. Фильтры применяются к состоянию задачи на каждый день; задачи, завершённые до начала итерации, не учитываются.",
+					Tags:        []string{"insights"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "from", In: "query", Description: "Первый день итерации (YYYY-MM-DD, UTC), по умолчанию 13 дней до to", Schema: openapi.String()},
+						{Name: "to", In: "query", Description: "Последний день итерации (YYYY-MM-DD, UTC), по умолчанию сегодня", Schema: openapi.String()},
+					}, listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Данные диаграмм", Content: openapi.JSONContent(burndown)}),
+				},
+			},
+			"/api/insights/cycle-time": {
+				"get": {
+					OperationID: "getFlowTimes",
+					Summary:     "Распределения lead time и cycle time",
+					Description: "Lead time — от создания до завершения, cycle time — от начала работы до завершения, для задач под фильтрами списка, завершённых в окне since..until. Процентили, гистограмма с постоянными корзинами, разбивка по приоритетам и владельцам и точки диаграммы рассеяния; выбросы — значения выше Q3 + 1,5·IQR. Часы рабочие по календарю исполнителя из WORK_CALENDAR.",
+					Tags:        []string{"insights"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "since", In: "query", Description: "Начало окна: RFC3339 или YYYY-MM-DD, по умолчанию за 90 дней до until", Schema: openapi.String()},
+						{Name: "until", In: "query", Description: "Конец окна: RFC3339 или YYYY-MM-DD включительно, по умолчанию сейчас", Schema: openapi.String()},
+					}, listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Распределения", Content: openapi.JSONContent(flowTimes)}),
+				},
+			},
+			"/api/insights/aging-wip": {
+				"get": {
+					OperationID: "getAgingWIP",
+					Summary:     "Возраст незавершённой работы",
+					Description: "Задачи в статусах in_progress и blocked под фильтрами списка, сгруппированные по статусу и исполнителю. Возраст считается от начала работы и сравнивается с процентилями cycle time задач под теми же фильтрами, завершённых в окне since..until; flagged отмечает задачи старше p85. Часы рабочие по календарю исполнителя из WORK_CALENDAR.",
+					Tags:        []string{"insights"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "since", In: "query", Description: "Начало окна истории: RFC3339 или YYYY-MM-DD, по умолчанию за 90 дней до until", Schema: openapi.String()},
+						{Name: "until", In: "query", Description: "Конец окна истории: RFC3339 или YYYY-MM-DD включительно, по умолчанию сейчас", Schema: openapi.String()},
+					}, listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Отчёт по группам", Content: openapi.JSONContent(agingWIP)}),
+				},
+			},
+			"/api/insights/flow": {
+				"get": {
+					OperationID: "getCumulativeFlow",
+					Summary:     "Накопительная диаграмма потока",
+					Description: "Число задач в каждом статусе на конец каждого дня до текущего. Фильтры применяются к состоянию задачи на конец дня.",
+					Tags:        []string{"insights"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "from", In: "query", Description: "Первый день (YYYY-MM-DD, UTC), по умолчанию 29 дней до to", Schema: openapi.String()},
+						{Name: "to", In: "query", Description: "Последний день (YYYY-MM-DD, UTC), по умолчанию сегодня", Schema: openapi.String()},
+					}, listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Ряд по статусам", Content: openapi.JSONContent(cumulativeFlow)}),
+				},
+			},
+			"/api/insights/time-in-status": {
+				"get": {
+					OperationID: "getTimeInStatus",
+					Summary:     "Время в статусах",
+					Description: "Переходы и часы в каждом статусе, кроме done, для задач под фильтром, и среднее, медиана, p85 и p95 по статусам. Время в текущем статусе идёт до текущего момента.",
+					Tags:        []string{"insights"},
+					Parameters:  params(listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Время в статусах", Content: openapi.JSONContent(timeInStatus)}),
+				},
+			},
+			"/api/insights/workload": {
+				"get": {
+					OperationID: "getWorkload",
+					Summary:     "Нагрузка исполнителей по неделям",
+					Description: "Часы незавершённых задач под фильтрами списка распределяются по рабочим дням исполнителя от сегодняшнего дня до срока пропорционально ёмкости и сравниваются с ёмкостью на каждую неделю с понедельника текущей. Просроченные задачи ложатся на текущую неделю. suggestions предлагает передать задачи с низким приоритетом из перегруженных недель тем, у кого хватает свободных часов.",
+					Tags:        []string{"insights", "capacity"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "weeks", In: "query", Description: "Число недель", Schema: &openapi.Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(capacity.MaxWeeks), Default: capacity.DefaultWeeks}},
+					}, listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Нагрузка", Content: openapi.JSONContent(workload)}),
+				},
+			},
+			"/api/capacity": {
+				"get": {
+					OperationID: "listCapacities",
+					Summary:     "Настройки ёмкости исполнителей",
+					Tags:        []string{"capacity"},
+					Parameters:  params(),
+					Responses: with(errorResponses(http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Настройки", Content: openapi.JSONContent(openapi.ArrayOf(ownerCapacity))}),
+				},
+			},
+			"/api/capacity/{owner}": {
+				"get": {
+					OperationID: "getCapacity",
+					Summary:     "Настройки ёмкости исполнителя",
+					Tags:        []string{"capacity"},
+					Parameters:  params([]openapi.Parameter{ownerParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Настройки", Content: openapi.JSONContent(ownerCapacity)}),
+				},
+				"put": {
+					OperationID: "putCapacity",
+					Summary:     "Задать ёмкость исполнителя",
+					Description: "Часы в неделю делятся поровну между буднями; нерабочие дни исключаются. Без настроек исполнитель работает 40 часов в неделю.",
+					Tags:        []string{"capacity"},
+					Parameters:  params([]openapi.Parameter{ownerParam}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSONContent(capacityRequest)},
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Сохранённые настройки", Content: openapi.JSONContent(ownerCapacity)}),
+				},
+				"delete": {
+					OperationID: "deleteCapacity",
+					Summary:     "Удалить настройки ёмкости исполнителя",
+					Tags:        []string{"capacity"},
+					Parameters:  params([]openapi.Parameter{ownerParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusNoContent, openapi.Response{Description: "Настройки удалены"}),
+				},
+			},
+			"/api/forecast": {
+				"get": {
+					OperationID: "getForecast",
+					Summary:     "Прогноз сроков методом Монте-Карло",
+					Description: "Моделирование по дневной пропускной способности: числу задач под фильтрами списка, завершённых в каждый из последних history полных дней по CompletedAt. С target — сколько задач будет завершено к этому дню, иначе — через сколько дней будут завершены items задач. Результаты даются с уверенностью 50, 85 и 95%; при одинаковом seed они повторяются. Если за окно не завершено ни одной задачи, возвращается 422.",
+					Tags:        []string{"insights"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "target", In: "query", Description: "День (YYYY-MM-DD, UTC) позже сегодняшнего; нельзя сочетать с items", Schema: openapi.String()},
+						{Name: "items", In: "query", Description: "Число задач, по умолчанию — незавершённые задачи под фильтрами", Schema: &openapi.Schema{Type: "integer", Minimum: floatPtr(0)}},
+						{Name: "history", In: "query", Description: "Окно истории в днях", Schema: &openapi.Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(forecast.MaxHistoryDays), Default: forecast.DefaultHistoryDays}},
+						{Name: "trials", In: "query", Description: "Число испытаний", Schema: &openapi.Schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(forecast.MaxTrials), Default: forecast.DefaultTrials}},
+						{Name: "seed", In: "query", Description: "Зерно генератора, по умолчанию — текущее время", Schema: &openapi.Schema{Type: "integer", Minimum: floatPtr(0)}},
+					}, listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Прогноз", Content: openapi.JSONContent(deliveryForecast)}),
+				},
+			},
+			"/api/views": {
+				"get": {
+					OperationID: "listViews",
+					Summary:     "Общие представления и личные представления пользователя",
+					Tags:        []string{"views"},
+					Parameters:  params([]openapi.Parameter{userParam}),
+					Responses: with(errorResponses(http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Представления", Content: openapi.JSONContent(openapi.ArrayOf(view))}),
+				},
+				"post": {
+					OperationID: "createView",
+					Summary:     "Сохранить представление",
+					Tags:        []string{"views"},
+					Parameters:  params([]openapi.Parameter{userParam}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSONContent(viewRequest)},
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusRequestEntityTooLarge, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusCreated, openapi.Response{Description: "Созданное представление", Content: openapi.JSONContent(view)}),
+				},
+			},
+			"/api/views/{id}": {
+				"get": {
+					OperationID: "getView",
+					Summary:     "Получить представление",
+					Tags:        []string{"views"},
+					Parameters:  params([]openapi.Parameter{idParam, userParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Представление", Content: openapi.JSONContent(view)}),
+				},
+				"put": {
+					OperationID: "replaceView",
+					Summary:     "Заменить представление",
+					Description: "Доступно только владельцу представления.",
+					Tags:        []string{"views"},
+					Parameters:  params([]openapi.Parameter{idParam, userParam}),
+					RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSONContent(viewRequest)},
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Обновлённое представление", Content: openapi.JSONContent(view)}),
+				},
+				"delete": {
+					OperationID: "deleteView",
+					Summary:     "Удалить представление",
+					Description: "Доступно только владельцу представления.",
+					Tags:        []string{"views"},
+					Parameters:  params([]openapi.Parameter{idParam, userParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusNoContent, openapi.Response{Description: "Представление удалено"}),
+				},
+			},
+			"/api/views/{id}/tasks": {
+				"get": {
+					OperationID: "listViewTasks",
+					Summary:     "Задачи, отобранные представлением",
+					Tags:        []string{"views"},
+					Parameters:  params([]openapi.Parameter{idParam, userParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Задачи", Content: openapi.JSONContent(openapi.ArrayOf(task))}),
+				},
+			},
+			"/api/views/{id}/insights": {
+				"get": {
+					OperationID: "getViewInsights",
+					Summary:     "Метрики по задачам представления",
+					Tags:        []string{"views"},
+					Parameters:  params([]openapi.Parameter{idParam, userParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Метрики", Content: openapi.JSONContent(insights)}),
+				},
+			},
+			"/api/views/{id}/calendar.ics": {
+				"get": {
+					OperationID: "getViewCalendar",
+					Summary:     "Календарь сроков задач представления",
+					Description: "Ссылка с токеном перестаёт работать, если представление удалено или изменилась его видимость.",
+					Tags:        []string{"calendar"},
+					Parameters:  params([]openapi.Parameter{idParam, tokenParam}, calendarParams),
+					Responses:   calendarResponses(http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError, http.StatusServiceUnavailable),
+				},
+			},
+			"/api/calendar.ics": {
+				"get": {
+					OperationID: "getCalendar",
+					Summary:     "Календарь сроков задач",
+					Description: "Задачи со сроком в формате iCalendar с теми же фильтрами, что и список задач. UID записи постоянен, удалённые задачи пропадают из календаря при обновлении.",
+					Tags:        []string{"calendar"},
+					Parameters:  params(listParams, calendarParams),
+					Responses:   calendarResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+				},
+			},
+			"/api/calendar/feeds": {
+				"get": {
+					OperationID: "listCalendarFeeds",
+					Summary:     "Ссылки на личные календари пользователя",
+					Description: "Лента исполнителя и ленты видимых представлений со ссылками для подписки.",
+					Tags:        []string{"calendar"},
+					Parameters:  params([]openapi.Parameter{userParam}),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Ссылки", Content: openapi.JSONContent(openapi.ArrayOf(calendarFeed))}),
+				},
+			},
+			"/api/calendar/owners/{owner}": {
+				"get": {
+					OperationID: "getOwnerCalendar",
+					Summary:     "Календарь сроков задач исполнителя",
+					Tags:        []string{"calendar"},
+					Parameters: params([]openapi.Parameter{
+						{Name: "owner", In: "path", Required: true, Description: "Исполнитель; допускается окончание .ics", Schema: openapi.String()},
+						tokenParam,
+					}, calendarParams),
+					Responses: calendarResponses(http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError, http.StatusServiceUnavailable),
+				},
+			},
+			"/api/insights": {
+				"get": {
+					OperationID: "getInsights",
+					Summary:     "Сводные метрики",
+					Tags:        []string{"insights"},
+					Parameters:  params(listParams),
+					Responses: with(errorResponses(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable),
+						http.StatusOK, openapi.Response{Description: "Метрики", Content: openapi.JSONContent(insights)}),
+				},
+			},
+		},
+	}
+
+	doc.Components.Schemas = registry.Schemas
+	return doc
+}
+
+func setEnum(schema *openapi.Schema, property string, values []string) {
+	target := schema.Properties[property]
+	if target.Items != nil {
+		target = target.Items
+	}
+	target.Enum = nil
+	for _, value := range values {
+		target.Enum = append(target.Enum, value)
+	}
+}
+
+func csvEnumParam(name string, description string, values []string) openapi.Parameter {
+	explode := false
+	return openapi.Parameter{Name: name, In: "query", Description: description, Explode: &explode, Schema: openapi.ArrayOf(openapi.Enum(values...))}
+}
+
+func withDefault(schema *openapi.Schema, value string) *openapi.Schema {
+	schema.Default = value
+	return schema
+}
+
+func sortedKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,197 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/jsonpatch"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MediaTypeJSON       = "application/json"
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+const (
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeInvalidPatch         = "invalid_patch"
+	CodePatchTestFailed      = "patch_test_failed"
+	CodePatchUnknownField    = "patch_unknown_field"
+)
+
+// Patch частично изменяет задачу. Патч применяется к представлению задачи
+// в формате TaskCreateRequest, после чего результат проходит те же проверки
+// по схеме и доменным правилам, что и тело PUT.
+func (h *TaskHandler) Patch(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || (mediaType != MediaTypeMergePatch && mediaType != MediaTypeJSONPatch && mediaType != MediaTypeJSON) {
+		c.Header("Accept-Patch", MediaTypeMergePatch+", "+MediaTypeJSONPatch)
+		respondError(c, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "")
+		return
+	}
+
+	body, ok := readBody(c)
+	if !ok {
+		return
+	}
+
+	task, err := h.store.Get(c.Request.Context(), id)
+	if err != nil {
+		respondStoreError(c, err, "task_load_failed")
+		return
+	}
+
+	document, err := json.Marshal(taskDocument(*task))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, CodeInternal, "")
+		return
+	}
+
+	var patched []byte
+	if mediaType == MediaTypeJSONPatch {
+		var operations []jsonpatch.Operation
+		operations, err = jsonpatch.DecodeOperations(body)
+		if err == nil {
+			patched, err = jsonpatch.Apply(document, operations)
+		}
+	} else {
+		patched, err = jsonpatch.MergePatch(document, body)
+	}
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	req, err := decodeDocument(patched, "TaskCreateRequest", taskDocumentRules)
+	if err != nil {
+		respondPatchDocumentError(c, err)
+		return
+	}
+
+	h.saveTaskDocument(c, task, req)
+}
+
+func (h *TaskHandler) saveTaskDocument(c *gin.Context, task *domain.Task, req TaskCreateRequest) {
+	now := h.clock.Now()
+	if err := applyTaskDocument(now, task, req, parseForce(c)); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	if err := h.store.Update(c.Request.Context(), task); err != nil {
+		respondStoreError(c, err, "task_update_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toTaskResponse(*task, now))
+}
+
+// normalizeTaskDocument переводит представление задачи в service.TaskInput;
+// ошибка в дате дополняет ошибки остальных полей.
+func normalizeTaskDocument(req TaskCreateRequest) (service.TaskInput, error) {
+	var errs service.ValidationErrors
+	dueDate, err := parseDueDate(req.DueDate)
+	errs.Add(err)
+
+	input, err := service.NormalizeTaskInput(service.TaskInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+		Priority:    req.Priority,
+		Owner:       req.Owner,
+		EffortHours: req.EffortHours,
+		DueDate:     dueDate,
+		Tags:        req.Tags,
+	})
+	errs.Add(err)
+	return input, errs.Err()
+}
+
+func taskDocumentRules(req TaskCreateRequest) error {
+	_, err := normalizeTaskDocument(req)
+	return err
+}
+
+// applyTaskDocument проверяет представление задачи и переносит его в task.
+// Статус меняется через переход, чтобы обновить отметки начала и завершения.
+func applyTaskDocument(now time.Time, task *domain.Task, req TaskCreateRequest, force bool) error {
+	input, err := normalizeTaskDocument(req)
+	if err != nil {
+		return err
+	}
+
+	updated := *task
+	input.ApplyTo(&updated)
+	if err := applyStatusTransition(now, &updated, input.Status, force); err != nil {
+		return err
+	}
+
+	*task = updated
+	return nil
+}
+
+func taskDocument(task domain.Task) TaskCreateRequest {
+	input := service.InputFromTask(task)
+	document := TaskCreateRequest{
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      input.Status,
+		Priority:    input.Priority,
+		Owner:       input.Owner,
+		EffortHours: input.EffortHours,
+		Tags:        input.Tags,
+	}
+	if task.DueDate != nil {
+		value := task.DueDate.UTC().Format(time.RFC3339)
+		document.DueDate = &value
+	}
+	return document
+}
+
+func respondPatchError(c *gin.Context, err error) {
+	var patchErr *jsonpatch.Error
+	details := map[string]any{}
+	if errors.As(err, &patchErr) {
+		details["operation"] = patchErr.Index
+		details["path"] = patchErr.Path
+	}
+
+	code := CodeInvalidPatch
+	status := http.StatusBadRequest
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		code = CodePatchTestFailed
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, newErrorResponse(c, code, code, "", details))
+}
+
+// respondPatchDocumentError сообщает об ошибках в результате применения
+// патча; лишнее поле в нём означает попытку изменить нередактируемое поле.
+func respondPatchDocumentError(c *gin.Context, err error) {
+	if errors.Is(err, errMalformedBody) {
+		respondError(c, http.StatusBadRequest, CodeInvalidPatch, "")
+		return
+	}
+
+	var errs service.ValidationErrors
+	errs.Add(err)
+	for i, item := range errs {
+		if item.Code == CodeUnknownField {
+			errs[i] = service.NewFieldError(CodePatchUnknownField, item.Field, item.Details)
+		}
+	}
+	respondInvalid(c, errs.Err())
+}
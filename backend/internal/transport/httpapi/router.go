@@ -1,14 +1,125 @@
 package httpapi
 
 import (
+	"crypto/rand"
 	"net/http"
+	"time"
 
+	"devopslabs/internal/calendar"
+	"devopslabs/internal/history"
 	"devopslabs/internal/repository"
 	"devopslabs/internal/service"
+	"devopslabs/internal/transport/graphqlapi"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(taskStore repository.TaskStore) *gin.Engine {
+type RouterOption func(*routerOptions)
+
+type routerOptions struct {
+	idempotencyStore repository.IdempotencyStore
+	idempotencyTTL   time.Duration
+	viewStore        repository.ViewStore
+	capacityStore    repository.CapacityStore
+	calendarSecret   []byte
+	changeStore      repository.ChangeStore
+	snapshotStore    repository.SnapshotStore
+	clock            service.Clock
+	policy           *service.Policy
+}
+
+// WithIdempotency задаёт хранилище ключей идемпотентности и время их жизни.
+// Без этой опции ключи хранятся в памяти процесса.
+func WithIdempotency(store repository.IdempotencyStore, ttl time.Duration) RouterOption {
+	return func(o *routerOptions) {
+		o.idempotencyStore = store
+		o.idempotencyTTL = ttl
+	}
+}
+
+// WithViews задаёт хранилище сохранённых представлений. Без этой опции
+// представления хранятся в памяти процесса.
+func WithViews(store repository.ViewStore) RouterOption {
+	return func(o *routerOptions) {
+		o.viewStore = store
+	}
+}
+
+// WithCapacity задаёт хранилище настроек ёмкости исполнителей. Без этой
+// опции настройки хранятся в памяти процесса.
+func WithCapacity(store repository.CapacityStore) RouterOption {
+	return func(o *routerOptions) {
+		o.capacityStore = store
+	}
+}
+
+// WithCalendarSecret задаёт секрет токенов ссылок на календарь. Без этой
+// опции секрет создаётся при запуске, и выданные ссылки перестают работать
+// после перезапуска.
+func WithCalendarSecret(secret string) RouterOption {
+	return func(o *routerOptions) {
+		if secret != "" {
+			o.calendarSecret = []byte(secret)
+		}
+	}
+}
+
+// WithHistory задаёт хранилища истории изменений и снимков сводки. Запись
+// истории в этом случае ведёт переданное хранилище задач, например
+// history.RecordingStore. Без этой опции история хранится в памяти процесса,
+// а запись ведёт сам маршрутизатор.
+func WithHistory(changes repository.ChangeStore, snapshots repository.SnapshotStore) RouterOption {
+	return func(o *routerOptions) {
+		o.changeStore = changes
+		o.snapshotStore = snapshots
+	}
+}
+
+// WithClock задаёт часы обработчиков; используется в тестах для
+// воспроизводимых сроков и рядов метрик.
+func WithClock(clock service.Clock) RouterOption {
+	return func(o *routerOptions) {
+		o.clock = clock
+	}
+}
+
+// WithPolicy задаёт политику оценки и риска задач. Без этой опции действует
+// service.DefaultPolicy.
+func WithPolicy(policy service.Policy) RouterOption {
+	return func(o *routerOptions) {
+		o.policy = &policy
+	}
+}
+
+func NewRouter(taskStore repository.TaskStore, opts ...RouterOption) *gin.Engine {
+	options := routerOptions{idempotencyTTL: DefaultIdempotencyTTL}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.idempotencyStore == nil {
+		options.idempotencyStore = repository.NewMemoryIdempotencyStore()
+	}
+	if options.viewStore == nil {
+		options.viewStore = repository.NewMemoryViewStore()
+	}
+	if options.capacityStore == nil {
+		options.capacityStore = repository.NewMemoryCapacityStore()
+	}
+	if options.calendarSecret == nil {
+		options.calendarSecret = make([]byte, 32)
+		_, _ = rand.Read(options.calendarSecret)
+	}
+	clock := options.clock
+	if clock == nil {
+		clock = service.RealClock{}
+	}
+	if options.changeStore == nil {
+		options.changeStore = repository.NewMemoryChangeStore()
+		taskStore = history.NewRecordingStore(taskStore, options.changeStore, clock)
+	}
+	if options.snapshotStore == nil {
+		options.snapshotStore = repository.NewMemorySnapshotStore()
+	}
+
 	r := gin.New()
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
@@ -18,16 +129,63 @@ func NewRouter(taskStore repository.TaskStore) *gin.Engine {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
-	h := NewTaskHandler(taskStore, service.RealClock{})
+	h := NewTaskHandler(taskStore, clock)
+	h.policy = options.policy.OrDefault()
+	idempotent := Idempotency(options.idempotencyStore, options.idempotencyTTL, clock)
+	validateTask := ValidateTaskRequest()
+	views := NewViewHandler(options.viewStore, h)
+	capacities := NewCapacityHandler(options.capacityStore, h)
+	calendarFeeds := NewCalendarHandler(h, options.viewStore, calendar.NewSigner(options.calendarSecret))
+	insightsHistory := NewHistoryHandler(
+		history.NewSeries(taskStore, options.changeStore, options.snapshotStore, clock, options.policy),
+		history.NewBurndownBuilder(taskStore, options.changeStore, clock),
+		history.NewFlowBuilder(taskStore, options.changeStore, clock),
+		clock,
+	)
+	graphQL := serveGraphQL(graphqlapi.NewExecutor(taskStore, clock, options.policy))
 
 	api := r.Group("/api")
 	{
 		api.GET("/tasks", h.List)
+		api.GET("/tasks/export", h.Export)
 		api.GET("/tasks/:id", h.Get)
-		api.POST("/tasks", h.Create)
-		api.PUT("/tasks/:id", h.Update)
+		api.GET("/tasks/:id/score", h.Score)
+		api.POST("/tasks", idempotent, validateTask, h.Create)
+		api.POST("/tasks/bulk", idempotent, ValidateBulkRequest(), h.Bulk)
+		api.POST("/tasks/import", idempotent, h.Import)
+		api.PUT("/tasks/:id", validateTask, h.Update)
+		api.PATCH("/tasks/:id", h.Patch)
 		api.DELETE("/tasks/:id", h.Delete)
 		api.GET("/insights", h.Insights)
+		api.GET("/insights/export", h.ExportInsights)
+		api.GET("/insights/cycle-time", h.FlowTimes)
+		api.GET("/insights/aging-wip", h.AgingWIP)
+		api.GET("/insights/history", insightsHistory.Insights)
+		api.GET("/insights/burndown", insightsHistory.Burndown)
+		api.GET("/insights/flow", insightsHistory.Flow)
+		api.GET("/insights/time-in-status", insightsHistory.TimeInStatus)
+		api.GET("/insights/workload", capacities.Workload)
+		api.GET("/forecast", h.Forecast)
+		api.GET("/policy", h.Policy)
+		api.GET("/capacity", capacities.List)
+		api.GET("/capacity/:owner", capacities.Get)
+		api.PUT("/capacity/:owner", capacities.Put)
+		api.DELETE("/capacity/:owner", capacities.Delete)
+		api.GET("/views", views.List)
+		api.POST("/views", views.Create)
+		api.GET("/views/:id", views.Get)
+		api.PUT("/views/:id", views.Update)
+		api.DELETE("/views/:id", views.Delete)
+		api.GET("/views/:id/tasks", views.Tasks)
+		api.GET("/views/:id/insights", views.Insights)
+		api.GET("/views/:id/calendar.ics", calendarFeeds.ViewFeed)
+		api.GET("/calendar.ics", calendarFeeds.Feed)
+		api.GET("/calendar/feeds", calendarFeeds.Feeds)
+		api.GET("/calendar/owners/:owner", calendarFeeds.OwnerFeed)
+		api.GET("/graphql", graphQL)
+		api.POST("/graphql", graphQL)
+		api.GET("/openapi.json", serveOpenAPI)
+		api.GET("/docs", serveDocs)
 	}
 
 	return r
@@ -36,8 +194,9 @@ func NewRouter(taskStore repository.TaskStore) *gin.Engine {
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept-Language, Idempotency-Key, X-User, If-None-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Idempotent-Replayed, Content-Disposition, ETag")
 
 		if c.Request.Method == http.MethodOptions {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -0,0 +1,27 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Score раскладывает оценку задачи на слагаемые действующей политики.
+func (h *TaskHandler) Score(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	task, err := h.store.Get(c.Request.Context(), id)
+	if err != nil {
+		respondStoreError(c, err, "task_get_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.policy.Explain(h.clock.Now(), *task))
+}
+
+func (h *TaskHandler) Policy(c *gin.Context) {
+	c.JSON(http.StatusOK, h.policy)
+}
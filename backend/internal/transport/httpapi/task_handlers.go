@@ -1,9 +1,8 @@
 package httpapi
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -11,30 +10,26 @@ import (
 	"devopslabs/internal/domain"
 	"devopslabs/internal/repository"
 	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
-)
-
-const (
-	defaultOwner     = "unassigned"
-	maxTitleLength   = 200
-	maxEffortHours   = 200
-	defaultEffortVal = 1
 )
 
 var applyStatusTransition = service.ApplyStatusTransition
 
 type TaskHandler struct {
-	store repository.TaskStore
-	clock service.Clock
+	store  repository.TaskStore
+	clock  service.Clock
+	policy service.Policy
 }
 
 type TaskResponse struct {
 	domain.Task
-	Risk       string   `json:"risk"`
-	Score      float64  `json:"score"`
-	AgeHours   float64  `json:"ageHours"`
-	CycleHours *float64 `json:"cycleHours,omitempty"`
+	Risk        string             `json:"risk"`
+	Score       float64            `json:"score"`
+	AgeHours    float64            `json:"ageHours"`
+	StatusHours float64            `json:"statusHours"`
+	CycleHours  *float64           `json:"cycleHours,omitempty"`
+	SLA         *service.SLAStatus `json:"sla,omitempty"`
 }
 
 type TaskCreateRequest struct {
@@ -48,23 +43,13 @@ type TaskCreateRequest struct {
 	Tags        []string `json:"tags"`
 }
 
-type TaskUpdateRequest struct {
-	Title       *string         `json:"title"`
-	Description *string         `json:"description"`
-	Status      *string         `json:"status"`
-	Priority    *string         `json:"priority"`
-	Owner       *string         `json:"owner"`
-	EffortHours *int            `json:"effortHours"`
-	DueDate     json.RawMessage `json:"dueDate"`
-	Tags        json.RawMessage `json:"tags"`
-}
-
 type ListQuery struct {
 	Statuses   []string
 	Priorities []string
 	Owner      string
 	Tag        string
 	Search     string
+	Expression taskquery.Expr
 	Sort       service.SortOption
 }
 
@@ -72,34 +57,34 @@ func NewTaskHandler(store repository.TaskStore, clock service.Clock) *TaskHandle
 	if clock == nil {
 		clock = service.RealClock{}
 	}
-	return &TaskHandler{store: store, clock: clock}
+	return &TaskHandler{store: store, clock: clock, policy: service.DefaultPolicy()}
 }
 
 func (h *TaskHandler) List(c *gin.Context) {
-	filter, sortOption, err := parseListQuery(c)
+	h.respondList(c, c.Request.URL.Query())
+}
+
+// respondList отвечает списком задач по параметрам строки запроса; через него
+// же выполняются сохранённые представления.
+func (h *TaskHandler) respondList(c *gin.Context, values url.Values) {
+	filter, sortOption, err := parseListValues(values)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, err.Error())
+		respondInvalid(c, err)
 		return
 	}
 
-	tasks, err := h.store.List(c.Request.Context(), repository.TaskFilter{
-		Statuses:   filter.Statuses,
-		Priorities: filter.Priorities,
-		Owner:      filter.Owner,
-		Query:      filter.Search,
-		Tag:        filter.Tag,
-	})
+	now := h.clock.Now()
+	tasks, err := h.store.List(c.Request.Context(), filter.TaskFilter(now))
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "не удалось получить список задач")
+		respondStoreError(c, err, "task_list_failed")
 		return
 	}
 
-	now := h.clock.Now()
-	service.SortTasks(tasks, sortOption, now)
+	h.policy.Sort(tasks, sortOption, now)
 
 	response := make([]TaskResponse, 0, len(tasks))
 	for _, task := range tasks {
-		response = append(response, toTaskResponse(task, now))
+		response = append(response, h.toTaskResponse(task, now))
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -113,195 +98,54 @@ func (h *TaskHandler) Get(c *gin.Context) {
 
 	task, err := h.store.Get(c.Request.Context(), id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			respondError(c, http.StatusNotFound, "задача не найдена")
-			return
-		}
-		respondError(c, http.StatusInternalServerError, "не удалось получить задачу")
+		respondStoreError(c, err, "task_get_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, toTaskResponse(*task, h.clock.Now()))
+	c.JSON(http.StatusOK, h.toTaskResponse(*task, h.clock.Now()))
 }
 
 func (h *TaskHandler) Create(c *gin.Context) {
-	var req TaskCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "некорректное тело запроса")
-		return
-	}
-
-	title := strings.TrimSpace(req.Title)
-	if title == "" {
-		respondError(c, http.StatusBadRequest, "нужно указать название")
-		return
-	}
-	if len(title) > maxTitleLength {
-		respondError(c, http.StatusBadRequest, "слишком длинное название")
-		return
-	}
-
-	status, err := service.NormalizeStatus(req.Status)
-	if err != nil {
-		respondError(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	priority, err := service.NormalizePriority(req.Priority)
-	if err != nil {
-		respondError(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	effortHours, err := normalizeEffort(req.EffortHours)
-	if err != nil {
-		respondError(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	tags, err := service.NormalizeTags(req.Tags)
-	if err != nil {
-		respondError(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	down := strings.TrimSpace(req.Owner)
-	if down == "" {
-		down = defaultOwner
-	}
-
-	dueDate, err := parseDueDate(req.DueDate)
-	if err != nil {
-		respondError(c, http.StatusBadRequest, "некорректная дата; используйте RFC3339")
+	req, ok := validatedBody(c, "TaskCreateRequest", taskDocumentRules)
+	if !ok {
 		return
 	}
 
 	now := h.clock.Now()
-	task := domain.Task{
-		Title:       title,
-		Description: strings.TrimSpace(req.Description),
-		Status:      status,
-		Priority:    priority,
-		Owner:       down,
-		EffortHours: effortHours,
-		Tags:        tags,
-		DueDate:     dueDate,
-	}
-
-	if err := applyStatusTransition(now, &task, status, true); err != nil {
-		respondError(c, http.StatusBadRequest, err.Error())
+	var task domain.Task
+	if err := applyTaskDocument(now, &task, req, true); err != nil {
+		respondInvalid(c, err)
 		return
 	}
 
 	if err := h.store.Create(c.Request.Context(), &task); err != nil {
-		respondError(c, http.StatusInternalServerError, "не удалось создать задачу")
+		respondStoreError(c, err, "task_create_failed")
 		return
 	}
 
-	c.JSON(http.StatusCreated, toTaskResponse(task, now))
+	c.JSON(http.StatusCreated, h.toTaskResponse(task, now))
 }
 
+// Update полностью заменяет редактируемые поля задачи: не переданные поля
+// получают значения по умолчанию, как при создании.
 func (h *TaskHandler) Update(c *gin.Context) {
 	id, ok := parseID(c)
 	if !ok {
 		return
 	}
 
-	var req TaskUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "некорректное тело запроса")
+	req, ok := validatedBody(c, "TaskCreateRequest", taskDocumentRules)
+	if !ok {
 		return
 	}
 
 	task, err := h.store.Get(c.Request.Context(), id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			respondError(c, http.StatusNotFound, "задача не найдена")
-			return
-		}
-		respondError(c, http.StatusInternalServerError, "не удалось загрузить задачу")
-		return
-	}
-
-	if req.Title != nil {
-		value := strings.TrimSpace(*req.Title)
-		if value == "" {
-			respondError(c, http.StatusBadRequest, "название не может быть пустым")
-			return
-		}
-		if len(value) > maxTitleLength {
-			respondError(c, http.StatusBadRequest, "слишком длинное название")
-			return
-		}
-		task.Title = value
-	}
-
-	if req.Description != nil {
-		task.Description = strings.TrimSpace(*req.Description)
-	}
-
-	if req.Priority != nil {
-		value, err := service.NormalizePriority(*req.Priority)
-		if err != nil {
-			respondError(c, http.StatusBadRequest, err.Error())
-			return
-		}
-		task.Priority = value
-	}
-
-	if req.Owner != nil {
-		owner := strings.TrimSpace(*req.Owner)
-		if owner == "" {
-			owner = defaultOwner
-		}
-		task.Owner = owner
-	}
-
-	if req.EffortHours != nil {
-		value, err := normalizeEffort(*req.EffortHours)
-		if err != nil {
-			respondError(c, http.StatusBadRequest, err.Error())
-			return
-		}
-		task.EffortHours = value
-	}
-
-	if req.Status != nil {
-		force := parseForce(c)
-		if err := applyStatusTransition(h.clock.Now(), task, *req.Status, force); err != nil {
-			respondError(c, http.StatusBadRequest, err.Error())
-			return
-		}
-	}
-
-	if len(req.DueDate) > 0 {
-		set, value, err := parseDueDatePatch(req.DueDate)
-		if err != nil {
-			respondError(c, http.StatusBadRequest, "некорректная дата; используйте RFC3339 или null")
-			return
-		}
-		if set {
-			task.DueDate = value
-		}
-	}
-
-	if len(req.Tags) > 0 {
-		set, value, err := parseTagsPatch(req.Tags)
-		if err != nil {
-			respondError(c, http.StatusBadRequest, err.Error())
-			return
-		}
-		if set {
-			task.Tags = value
-		}
-	}
-
-	if err := h.store.Update(c.Request.Context(), task); err != nil {
-		respondError(c, http.StatusInternalServerError, "не удалось обновить задачу")
+		respondStoreError(c, err, "task_load_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, toTaskResponse(*task, h.clock.Now()))
+	h.saveTaskDocument(c, task, req)
 }
 
 func (h *TaskHandler) Delete(c *gin.Context) {
@@ -311,7 +155,7 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.store.Delete(c.Request.Context(), id); err != nil {
-		respondError(c, http.StatusInternalServerError, "не удалось удалить задачу")
+		respondStoreError(c, err, "task_delete_failed")
 		return
 	}
 
@@ -319,50 +163,140 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 }
 
 func (h *TaskHandler) Insights(c *gin.Context) {
-	filter, _, err := parseListQuery(c)
+	h.respondInsights(c, c.Request.URL.Query())
+}
+
+func (h *TaskHandler) respondInsights(c *gin.Context, values url.Values) {
+	filter, _, err := parseListValues(values)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, err.Error())
+		respondInvalid(c, err)
 		return
 	}
 
-	tasks, err := h.store.List(c.Request.Context(), repository.TaskFilter{
-		Statuses:   filter.Statuses,
-		Priorities: filter.Priorities,
-		Owner:      filter.Owner,
-		Query:      filter.Search,
-		Tag:        filter.Tag,
-	})
+	now := h.clock.Now()
+	tasks, err := h.store.List(c.Request.Context(), filter.TaskFilter(now))
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "не удалось получить метрики")
+		respondStoreError(c, err, "insights_failed")
 		return
 	}
 
-	insights := service.ComputeInsights(h.clock.Now(), tasks)
+	insights := h.policy.Insights(now, tasks)
 	c.JSON(http.StatusOK, insights)
 }
 
-func parseListQuery(c *gin.Context) (ListQuery, service.SortOption, error) {
-	statuses, err := parseCSVEnum(c.Query("status"), service.NormalizeStatus)
+// FlowTimes отдаёт распределения lead time и cycle time задач под фильтрами
+// списка, завершённых в окне since..until.
+func (h *TaskHandler) FlowTimes(c *gin.Context) {
+	values := c.Request.URL.Query()
+	now := h.clock.Now()
+	var errs service.ValidationErrors
+	filter, _, err := parseListValues(values)
+	errs.Add(err)
+	since, until, err := service.ParseDurationWindow(values.Get("since"), values.Get("until"), now)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	tasks, err := h.store.List(c.Request.Context(), filter.TaskFilter(now))
 	if err != nil {
-		return ListQuery{}, service.SortOption{}, err
+		respondStoreError(c, err, "insights_failed")
+		return
 	}
+	c.JSON(http.StatusOK, h.policy.FlowTimes(tasks, since, until))
+}
 
-	priorities, err := parseCSVEnum(c.Query("priority"), service.NormalizePriority)
+// AgingWIP сравнивает возраст задач в работе с cycle time задач,
+// завершённых в окне; фильтры списка применяются к тем и другим.
+func (h *TaskHandler) AgingWIP(c *gin.Context) {
+	values := c.Request.URL.Query()
+	now := h.clock.Now()
+	var errs service.ValidationErrors
+	filter, _, err := parseListValues(values)
+	errs.Add(err)
+	since, until, err := service.ParseDurationWindow(values.Get("since"), values.Get("until"), now)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+
+	tasks, err := h.store.List(c.Request.Context(), filter.TaskFilter(now))
 	if err != nil {
+		respondStoreError(c, err, "insights_failed")
+		return
+	}
+	c.JSON(http.StatusOK, h.policy.AgingWIP(now, tasks, since, until))
+}
+
+func parseListValues(values url.Values) (ListQuery, service.SortOption, error) {
+	var errs service.ValidationErrors
+	statuses, err := parseCSVEnum(values.Get("status"), service.NormalizeStatus)
+	errs.Add(err)
+	priorities, err := parseCSVEnum(values.Get("priority"), service.NormalizePriority)
+	errs.Add(err)
+	expression, err := taskquery.Parse(values.Get("filter"))
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
 		return ListQuery{}, service.SortOption{}, err
 	}
 
-	sortOption := service.NormalizeSort(c.Query("sort"), c.Query("order"))
+	sortOption := service.NormalizeSort(values.Get("sort"), values.Get("order"))
 	return ListQuery{
 		Statuses:   statuses,
 		Priorities: priorities,
-		Owner:      strings.TrimSpace(c.Query("owner")),
-		Tag:        strings.TrimSpace(c.Query("tag")),
-		Search:     strings.TrimSpace(c.Query("q")),
+		Owner:      strings.TrimSpace(values.Get("owner")),
+		Tag:        strings.TrimSpace(values.Get("tag")),
+		Search:     strings.TrimSpace(values.Get("q")),
+		Expression: expression,
 		Sort:       sortOption,
 	}, sortOption, nil
 }
 
+// TaskFilter переводит параметры в фильтр хранилища; now фиксирует момент
+// отсчёта для относительных дат выражения filter.
+func (q ListQuery) TaskFilter(now time.Time) repository.TaskFilter {
+	return repository.TaskFilter{
+		Statuses:   q.Statuses,
+		Priorities: q.Priorities,
+		Owner:      q.Owner,
+		Query:      q.Search,
+		Tag:        q.Tag,
+		Expression: q.Expression,
+		Now:        now,
+	}
+}
+
+// Values возвращает параметры фильтра в каноническом виде, который снова
+// разбирается parseListValues; пустые параметры опускаются.
+func (q ListQuery) Values() url.Values {
+	values := url.Values{}
+	set := func(key string, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	set("status", strings.Join(q.Statuses, ","))
+	set("priority", strings.Join(q.Priorities, ","))
+	set("owner", q.Owner)
+	set("tag", q.Tag)
+	set("q", q.Search)
+	if q.Expression != nil {
+		values.Set("filter", q.Expression.String())
+	}
+	return values
+}
+
+// listValues переводит фильтр из тела запроса в параметры строки запроса.
+func listValues(filter map[string]string) url.Values {
+	values := url.Values{}
+	for key, value := range filter {
+		values.Set(key, value)
+	}
+	return values
+}
+
 func parseCSVEnum(raw string, normalize func(string) (string, error)) ([]string, error) {
 	if strings.TrimSpace(raw) == "" {
 		return nil, nil
@@ -391,7 +325,7 @@ func parseID(c *gin.Context) (uint, bool) {
 	idParam := c.Param("id")
 	id64, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "некорректный идентификатор")
+		respondError(c, http.StatusBadRequest, CodeInvalidID, "id")
 		return 0, false
 	}
 	return uint(id64), true
@@ -407,73 +341,24 @@ func parseDueDate(raw *string) (*time.Time, error) {
 	}
 	parsed, err := time.Parse(time.RFC3339, value)
 	if err != nil {
-		return nil, err
+		return nil, service.NewFieldError(service.CodeInvalidDueDate, "dueDate", nil)
 	}
 	return &parsed, nil
 }
 
-func parseDueDatePatch(raw json.RawMessage) (bool, *time.Time, error) {
-	if len(raw) == 0 {
-		return false, nil, nil
-	}
-	if strings.TrimSpace(string(raw)) == "null" {
-		return true, nil, nil
-	}
-	var value string
-	if err := json.Unmarshal(raw, &value); err != nil {
-		return true, nil, fmt.Errorf("некорректное значение даты")
-	}
-	parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
-	if err != nil {
-		return true, nil, err
-	}
-	return true, &parsed, nil
-}
-
-func parseTagsPatch(raw json.RawMessage) (bool, domain.StringList, error) {
-	if len(raw) == 0 {
-		return false, nil, nil
-	}
-	if strings.TrimSpace(string(raw)) == "null" {
-		return true, domain.StringList{}, nil
-	}
-	var value []string
-	if err := json.Unmarshal(raw, &value); err != nil {
-		return true, nil, err
-	}
-	normalized, err := service.NormalizeTags(value)
-	if err != nil {
-		return true, nil, err
-	}
-	return true, normalized, nil
-}
-
-func normalizeEffort(value int) (int, error) {
-	if value == 0 {
-		return defaultEffortVal, nil
-	}
-	if value < 0 || value > maxEffortHours {
-		return 0, fmt.Errorf("effortHours должен быть от 1 до %d", maxEffortHours)
-	}
-	return value, nil
-}
-
 func parseForce(c *gin.Context) bool {
-	value := strings.ToLower(strings.TrimSpace(c.Query("force")))
-	return value == "true" || value == "1" || value == "yes"
+	return isTruthy(c.Query("force"))
 }
 
-func toTaskResponse(task domain.Task, now time.Time) TaskResponse {
-	metrics := service.ComputeMetrics(now, task)
+func (h *TaskHandler) toTaskResponse(task domain.Task, now time.Time) TaskResponse {
+	metrics := h.policy.Metrics(now, task)
 	return TaskResponse{
-		Task:       task,
-		Risk:       metrics.Risk,
-		Score:      metrics.Score,
-		AgeHours:   metrics.AgeHours,
-		CycleHours: metrics.CycleHours,
+		Task:        task,
+		Risk:        metrics.Risk,
+		Score:       metrics.Score,
+		AgeHours:    metrics.AgeHours,
+		StatusHours: metrics.StatusHours,
+		CycleHours:  metrics.CycleHours,
+		SLA:         metrics.SLA,
 	}
 }
-
-func respondError(c *gin.Context, code int, message string) {
-	c.JSON(code, gin.H{"error": message})
-}
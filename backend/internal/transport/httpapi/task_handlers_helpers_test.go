@@ -3,7 +3,9 @@ package httpapi
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -96,7 +98,7 @@ func TestHandlerStoreErrors(t *testing.T) {
 	updateHandler := NewTaskHandler(stubStore{task: task, updateErr: errors.New("fail")}, clock)
 	w = httptest.NewRecorder()
 	c, _ = gin.CreateTestContext(w)
-	c.Request = httptest.NewRequest(http.MethodPut, "/api/tasks/1", bytes.NewBufferString(`{"description":"x"}`))
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/tasks/1", bytes.NewBufferString(`{"title":"A","description":"x"}`))
 	c.Request.Header.Set("Content-Type", "application/json")
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 	updateHandler.Update(c)
@@ -105,7 +107,7 @@ func TestHandlerStoreErrors(t *testing.T) {
 	updateGetHandler := NewTaskHandler(stubStore{getErr: errors.New("fail")}, clock)
 	w = httptest.NewRecorder()
 	c, _ = gin.CreateTestContext(w)
-	c.Request = httptest.NewRequest(http.MethodPut, "/api/tasks/1", bytes.NewBufferString(`{"description":"x"}`))
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/tasks/1", bytes.NewBufferString(`{"title":"A","description":"x"}`))
 	c.Request.Header.Set("Content-Type", "application/json")
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
 	updateGetHandler.Update(c)
@@ -127,6 +129,46 @@ func TestHandlerStoreErrors(t *testing.T) {
 	require.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestHandlerMapsStoreErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{err: fmt.Errorf("wrapped: %w", repository.ErrNotFound), status: http.StatusNotFound, code: CodeNotFound},
+		{err: repository.ErrConflict, status: http.StatusConflict, code: CodeConflict},
+		{err: repository.ErrValidation, status: http.StatusBadRequest, code: CodeValidation},
+		{err: repository.ErrUnavailable, status: http.StatusServiceUnavailable, code: CodeUnavailable},
+		{err: errors.New("fail"), status: http.StatusInternalServerError, code: CodeInternal},
+	}
+
+	for _, tc := range cases {
+		handler := NewTaskHandler(stubStore{getErr: tc.err, deleteErr: tc.err}, clock)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/tasks/1", nil)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		handler.Get(c)
+		require.Equal(t, tc.status, w.Code)
+
+		var body ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Equal(t, tc.code, body.Code)
+		require.NotEmpty(t, body.Message)
+
+		w = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/tasks/1", nil)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		handler.Delete(c)
+		require.Equal(t, tc.status, w.Code)
+	}
+}
+
 func TestHandlerApplyStatusError(t *testing.T) {
 	original := applyStatusTransition
 	applyStatusTransition = func(now time.Time, task *domain.Task, newStatus string, force bool) error {
@@ -148,7 +190,7 @@ func TestHandlerApplyStatusError(t *testing.T) {
 
 func TestHandlerHelpers(t *testing.T) {
 	empty := ""
-	nullRaw := []byte("null")
+	bad := "bad"
 
 	date, err := parseDueDate(nil)
 	require.NoError(t, err)
@@ -158,67 +200,18 @@ func TestHandlerHelpers(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, date)
 
-	set, value, err := parseDueDatePatch([]byte{})
-	require.NoError(t, err)
-	require.False(t, set)
-	require.Nil(t, value)
-
-	set, value, err = parseDueDatePatch(nullRaw)
-	require.NoError(t, err)
-	require.True(t, set)
-	require.Nil(t, value)
-
-	set, value, err = parseDueDatePatch([]byte(`"2026-02-10T12:00:00Z"`))
-	require.NoError(t, err)
-	require.True(t, set)
-	require.NotNil(t, value)
-
-	set, _, err = parseDueDatePatch([]byte(`"bad"`))
+	_, err = parseDueDate(&bad)
 	require.Error(t, err)
-	require.True(t, set)
 
-	set, _, err = parseDueDatePatch([]byte(`{`))
-	require.Error(t, err)
-	require.True(t, set)
-
-	set, tags, err := parseTagsPatch([]byte{})
-	require.NoError(t, err)
-	require.False(t, set)
-	require.Nil(t, tags)
-
-	set, tags, err = parseTagsPatch(nullRaw)
-	require.NoError(t, err)
-	require.True(t, set)
-	require.Len(t, tags, 0)
-
-	set, tags, err = parseTagsPatch([]byte(`["ci"]`))
-	require.NoError(t, err)
-	require.True(t, set)
-	require.Equal(t, domain.StringList{"ci"}, tags)
-
-	set, _, err = parseTagsPatch([]byte(`{"bad":true}`))
-	require.Error(t, err)
-	require.True(t, set)
-
-	set, _, err = parseTagsPatch([]byte(`["this-tag-name-is-way-too-long"]`))
-	require.Error(t, err)
-	require.True(t, set)
-
-	_, err = normalizeEffort(-1)
-	require.Error(t, err)
-
-	effort, err := normalizeEffort(0)
-	require.NoError(t, err)
-	require.Equal(t, 1, effort)
-
-	effort, err = normalizeEffort(5)
-	require.NoError(t, err)
-	require.Equal(t, 5, effort)
-
-	_, err = normalizeEffort(999)
-	require.Error(t, err)
+	document := taskDocument(domain.Task{Title: "A", DueDate: ptrTime(time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC))})
+	require.Equal(t, "2026-02-10T12:00:00Z", *document.DueDate)
+	require.Equal(t, []string{}, document.Tags)
 
 	values, err := parseCSVEnum(" , ", service.NormalizeStatus)
 	require.NoError(t, err)
 	require.Len(t, values, 0)
 }
+
+func ptrTime(value time.Time) *time.Time {
+	return &value
+}
@@ -0,0 +1,192 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"devopslabs/internal/openapi"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxRequestBodyBytes = 1 << 20
+	validatedBodyKey    = "httpapi.validatedBody"
+)
+
+const (
+	CodeBodyTooLarge  = "body_too_large"
+	CodeUnknownField  = "unknown_field"
+	CodeInvalidType   = "invalid_type"
+	CodeFieldRequired = "field_required"
+	CodeTooLong       = "too_long"
+	CodeTooManyItems  = "too_many_items"
+	CodeOutOfRange    = "out_of_range"
+	CodeInvalidFormat = "invalid_format"
+)
+
+var keywordCodes = map[string]string{
+	openapi.KeywordType:                 CodeInvalidType,
+	openapi.KeywordRequired:             CodeFieldRequired,
+	openapi.KeywordAdditionalProperties: CodeUnknownField,
+	openapi.KeywordEnum:                 service.CodeInvalidValue,
+	openapi.KeywordMaxLength:            CodeTooLong,
+	openapi.KeywordMaxItems:             CodeTooManyItems,
+	openapi.KeywordMinimum:              CodeOutOfRange,
+	openapi.KeywordMaximum:              CodeOutOfRange,
+	openapi.KeywordFormat:               CodeInvalidFormat,
+}
+
+// fieldCodes заменяет общий код нарушения схемы на доменный там, где он есть,
+// чтобы схема и доменные правила сообщали об одной ошибке одинаково.
+// Ключ — корневое поле и ключевое слово схемы.
+var fieldCodes = map[string]string{
+	"title/maxLength":     service.CodeTitleTooLong,
+	"status/enum":         service.CodeInvalidStatus,
+	"priority/enum":       service.CodeInvalidPriority,
+	"effortHours/minimum": service.CodeInvalidEffort,
+	"effortHours/maximum": service.CodeInvalidEffort,
+	"dueDate/type":        service.CodeInvalidDueDate,
+	"dueDate/format":      service.CodeInvalidDueDate,
+	"tags/type":           service.CodeInvalidTags,
+	"tags/maxItems":       service.CodeTooManyTags,
+	"tags/maxLength":      service.CodeTagTooLong,
+	"operation/enum":      CodeBulkInvalidOperation,
+	"ids/maxItems":        CodeBulkTooMany,
+}
+
+// ValidateBody читает JSON-тело, проверяет его по схеме schemaName из
+// OpenAPIDocument и доменными правилами rules и передаёт обработчику уже
+// декодированное значение T. Обо всех найденных ошибках сообщается разом.
+func ValidateBody[T any](schemaName string, rules func(T) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := validateRequestBody(c, schemaName, rules)
+		if !ok {
+			c.Abort()
+			return
+		}
+		c.Set(validatedBodyKey, value)
+		c.Next()
+	}
+}
+
+// ValidateTaskRequest проверяет тело POST и PUT /api/tasks.
+func ValidateTaskRequest() gin.HandlerFunc {
+	return ValidateBody("TaskCreateRequest", taskDocumentRules)
+}
+
+// ValidateBulkRequest проверяет тело POST /api/tasks/bulk.
+func ValidateBulkRequest() gin.HandlerFunc {
+	return ValidateBody("BulkRequest", bulkRequestRules)
+}
+
+// validatedBody возвращает тело, проверенное ValidateBody. Если маршрут
+// зарегистрирован без middleware, проверка выполняется здесь же.
+func validatedBody[T any](c *gin.Context, schemaName string, rules func(T) error) (T, bool) {
+	if value, ok := c.Get(validatedBodyKey); ok {
+		if typed, ok := value.(T); ok {
+			return typed, true
+		}
+	}
+	return validateRequestBody(c, schemaName, rules)
+}
+
+func validateRequestBody[T any](c *gin.Context, schemaName string, rules func(T) error) (T, bool) {
+	var value T
+	body, ok := readBody(c)
+	if !ok {
+		return value, false
+	}
+
+	value, err := decodeDocument(body, schemaName, rules)
+	switch {
+	case errors.Is(err, errMalformedBody):
+		respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+		return value, false
+	case err != nil:
+		respondInvalid(c, err)
+		return value, false
+	}
+	return value, true
+}
+
+// readBody читает тело не длиннее maxRequestBodyBytes и оставляет его
+// доступным для следующих обработчиков.
+func readBody(c *gin.Context) ([]byte, bool) {
+	if c.Request.Body == nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRequestBodyBytes+1))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidBody, "")
+		return nil, false
+	}
+	if len(body) > maxRequestBodyBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, newErrorResponse(c, CodeBodyTooLarge, CodeBodyTooLarge, "", map[string]any{"max": maxRequestBodyBytes}))
+		return nil, false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true
+}
+
+var errMalformedBody = errors.New("тело запроса не является корректным JSON")
+
+// decodeDocument проверяет документ по схеме и правилам и декодирует его в T.
+// Ошибки доменных правил дополняют нарушения схемы.
+func decodeDocument[T any](body []byte, schemaName string, rules func(T) error) (T, error) {
+	var value T
+
+	var raw any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil || decoder.More() {
+		return value, errMalformedBody
+	}
+
+	var errs service.ValidationErrors
+	schemas := OpenAPIDocument().Components.Schemas
+	for _, violation := range openapi.Validate(schemas, openapi.RefTo(schemaName), raw) {
+		errs.Add(violationError(violation))
+	}
+
+	// Лишние поля уже учтены схемой, поэтому декодер их пропускает: доменные
+	// правила проверят остальные поля. Ошибка типа означает, что значение T
+	// заполнено не полностью, и правила к нему не применяются.
+	if err := json.Unmarshal(body, &value); err != nil {
+		if len(errs) == 0 {
+			return value, errMalformedBody
+		}
+		return value, errs.Err()
+	}
+
+	if rules != nil {
+		errs.Add(rules(value))
+	}
+	return value, errs.Err()
+}
+
+func violationError(violation openapi.Violation) *service.FieldError {
+	field := violation.Field()
+	root, _, _ := strings.Cut(field, ".")
+
+	code, ok := fieldCodes[root+"/"+violation.Keyword]
+	if !ok {
+		code = keywordCodes[violation.Keyword]
+	}
+	if code == "" {
+		code = service.CodeInvalidValue
+	}
+	params := violation.Params
+	if code == service.CodeInvalidEffort {
+		// Сообщение совпадает с доменным: 0 допустим как значение по
+		// умолчанию, но в сообщении указан диапазон явных значений.
+		params = service.EffortRange()
+	}
+	return service.NewFieldError(code, field, params)
+}
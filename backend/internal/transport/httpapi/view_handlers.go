@@ -0,0 +1,237 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// UserHeader называет пользователя, от имени которого выполняется запрос.
+// Аутентификации в сервисе нет, поэтому заголовок лишь разделяет личные
+// представления разных пользователей.
+const UserHeader = "X-User"
+
+const (
+	CodeUserRequired = "user_required"
+	CodeForbidden    = "forbidden"
+)
+
+const (
+	maxViewNameLength = 120
+	maxUserLength     = 80
+)
+
+type ViewRequest struct {
+	Name       string            `json:"name"`
+	Visibility string            `json:"visibility"`
+	Filter     map[string]string `json:"filter"`
+	Sort       string            `json:"sort"`
+	Order      string            `json:"order"`
+}
+
+type ViewHandler struct {
+	views repository.ViewStore
+	tasks *TaskHandler
+}
+
+func NewViewHandler(views repository.ViewStore, tasks *TaskHandler) *ViewHandler {
+	return &ViewHandler{views: views, tasks: tasks}
+}
+
+func (h *ViewHandler) List(c *gin.Context) {
+	views, err := h.views.List(c.Request.Context(), requestUser(c))
+	if err != nil {
+		respondStoreError(c, err, "view_list_failed")
+		return
+	}
+	c.JSON(http.StatusOK, views)
+}
+
+func (h *ViewHandler) Get(c *gin.Context) {
+	view, ok := h.load(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *ViewHandler) Create(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	req, ok := validatedBody(c, "ViewRequest", viewRequestRules)
+	if !ok {
+		return
+	}
+
+	view := domain.SavedView{Owner: user}
+	if err := applyViewRequest(&view, req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+	if err := h.views.Create(c.Request.Context(), &view); err != nil {
+		respondStoreError(c, err, "view_create_failed")
+		return
+	}
+	c.JSON(http.StatusCreated, view)
+}
+
+// Update полностью заменяет представление; менять и удалять его может
+// только владелец, даже если оно общее.
+func (h *ViewHandler) Update(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	req, ok := validatedBody(c, "ViewRequest", viewRequestRules)
+	if !ok {
+		return
+	}
+	view, ok := h.loadOwned(c, user)
+	if !ok {
+		return
+	}
+
+	if err := applyViewRequest(view, req); err != nil {
+		respondInvalid(c, err)
+		return
+	}
+	if err := h.views.Update(c.Request.Context(), view); err != nil {
+		respondStoreError(c, err, "view_update_failed")
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *ViewHandler) Delete(c *gin.Context) {
+	user, ok := requireUser(c)
+	if !ok {
+		return
+	}
+	view, ok := h.loadOwned(c, user)
+	if !ok {
+		return
+	}
+
+	if err := h.views.Delete(c.Request.Context(), view.ID); err != nil {
+		respondStoreError(c, err, "view_delete_failed")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Tasks выполняет представление так же, как GET /api/tasks с сохранёнными
+// параметрами.
+func (h *ViewHandler) Tasks(c *gin.Context) {
+	view, ok := h.load(c)
+	if !ok {
+		return
+	}
+	h.tasks.respondList(c, viewValues(*view))
+}
+
+func (h *ViewHandler) Insights(c *gin.Context) {
+	view, ok := h.load(c)
+	if !ok {
+		return
+	}
+	h.tasks.respondInsights(c, viewValues(*view))
+}
+
+// load возвращает представление, видимое текущему пользователю; чужое
+// личное представление неотличимо от отсутствующего.
+func (h *ViewHandler) load(c *gin.Context) (*domain.SavedView, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, false
+	}
+
+	view, err := h.views.Get(c.Request.Context(), id)
+	if err != nil {
+		respondStoreError(c, err, "view_load_failed")
+		return nil, false
+	}
+	if !view.VisibleTo(requestUser(c)) {
+		respondError(c, http.StatusNotFound, CodeNotFound, "")
+		return nil, false
+	}
+	return view, true
+}
+
+func (h *ViewHandler) loadOwned(c *gin.Context, user string) (*domain.SavedView, bool) {
+	view, ok := h.load(c)
+	if !ok {
+		return nil, false
+	}
+	if view.Owner != user {
+		respondError(c, http.StatusForbidden, CodeForbidden, "")
+		return nil, false
+	}
+	return view, true
+}
+
+func requestUser(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader(UserHeader))
+}
+
+func requireUser(c *gin.Context) (string, bool) {
+	user := requestUser(c)
+	if user == "" {
+		respondError(c, http.StatusUnauthorized, CodeUserRequired, UserHeader)
+		return "", false
+	}
+	if len(user) > maxUserLength {
+		respondInvalid(c, service.NewFieldError(CodeTooLong, UserHeader, map[string]any{"max": maxUserLength}))
+		return "", false
+	}
+	return user, true
+}
+
+// viewRequestRules дополняет схему ViewRequest: имя не может состоять из
+// пробелов, а фильтр проверяется так же, как параметры GET /api/tasks, в том
+// числе на неизвестные статусы и приоритеты.
+func viewRequestRules(req ViewRequest) error {
+	var errs service.ValidationErrors
+	if strings.TrimSpace(req.Name) == "" {
+		errs.Add(service.NewFieldError(CodeFieldRequired, "name", map[string]any{"field": "name"}))
+	}
+	_, _, err := parseListValues(listValues(req.Filter))
+	errs.Add(err)
+	return errs.Err()
+}
+
+// applyViewRequest сохраняет фильтр в каноническом виде, поэтому
+// представление выполняется одинаково независимо от записи параметров.
+func applyViewRequest(view *domain.SavedView, req ViewRequest) error {
+	query, _, err := parseListValues(listValues(req.Filter))
+	if err != nil {
+		return err
+	}
+
+	view.Name = strings.TrimSpace(req.Name)
+	view.Visibility = strings.ToLower(strings.TrimSpace(req.Visibility))
+	if view.Visibility == "" {
+		view.Visibility = domain.VisibilityPrivate
+	}
+	view.Filter = domain.StringMap{}
+	for key, values := range query.Values() {
+		view.Filter[key] = values[0]
+	}
+	sortOption := service.NormalizeSort(req.Sort, req.Order)
+	view.Sort = sortOption.By
+	view.Order = sortOption.Order
+	return nil
+}
+
+func viewValues(view domain.SavedView) url.Values {
+	values := listValues(view.Filter)
+	values.Set("sort", view.Sort)
+	values.Set("order", view.Order)
+	return values
+}
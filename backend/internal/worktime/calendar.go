@@ -0,0 +1,201 @@
+package worktime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Weekdays — названия дней недели в настройках календаря.
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Spec — настройки рабочего календаря. Start и End задают рабочий день в
+// формате HH:MM в часовом поясе TimeZone; End может быть 24:00.
+type Spec struct {
+	TimeZone     string   `json:"timeZone"`
+	Weekdays     []string `json:"weekdays"`
+	Start        string   `json:"start"`
+	End          string   `json:"end"`
+	Holidays     []string `json:"holidays"`
+	HolidaysFile string   `json:"holidaysFile"`
+}
+
+// DefaultSpec — пятидневка с 09:00 до 18:00 UTC без праздников.
+func DefaultSpec() Spec {
+	return Spec{
+		TimeZone: "UTC",
+		Weekdays: []string{"mon", "tue", "wed", "thu", "fri"},
+		Start:    "09:00",
+		End:      "18:00",
+	}
+}
+
+// Calendar считает рабочие часы. nil означает круглосуточный календарь, в
+// котором рабочие часы совпадают с астрономическими.
+type Calendar struct {
+	location *time.Location
+	weekdays [7]bool
+	// start и end — минуты от начала дня.
+	start    int
+	end      int
+	holidays map[string]bool
+}
+
+// Compile проверяет настройки; праздники из HolidaysFile читаются
+// относительно каталога dir.
+func (s Spec) Compile(dir string) (*Calendar, error) {
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("рабочий календарь: неизвестный часовой пояс %q", s.TimeZone)
+	}
+	calendar := &Calendar{location: location, holidays: make(map[string]bool)}
+
+	if len(s.Weekdays) == 0 {
+		return nil, fmt.Errorf("рабочий календарь: не заданы рабочие дни недели")
+	}
+	for _, name := range s.Weekdays {
+		day, ok := weekday(name)
+		if !ok {
+			return nil, fmt.Errorf("рабочий календарь: неизвестный день недели %q", name)
+		}
+		calendar.weekdays[day] = true
+	}
+
+	if calendar.start, err = clockMinutes(s.Start); err != nil {
+		return nil, err
+	}
+	if calendar.end, err = clockMinutes(s.End); err != nil {
+		return nil, err
+	}
+	if calendar.end <= calendar.start {
+		return nil, fmt.Errorf("рабочий календарь: конец рабочего дня %s должен быть позже начала %s", s.End, s.Start)
+	}
+
+	for _, value := range s.Holidays {
+		day, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("рабочий календарь: неверная дата праздника %q", value)
+		}
+		calendar.holidays[day.Format(time.DateOnly)] = true
+	}
+	if s.HolidaysFile != "" {
+		days, err := LoadHolidays(resolvePath(dir, s.HolidaysFile))
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range days {
+			calendar.holidays[day] = true
+		}
+	}
+	return calendar, nil
+}
+
+// Workday сообщает, рабочий ли день, в который попадает момент at.
+func (c *Calendar) Workday(at time.Time) bool {
+	if c == nil {
+		return true
+	}
+	local := at.In(c.location)
+	return c.weekdays[local.Weekday()] && !c.holidays[local.Format(time.DateOnly)]
+}
+
+// Hours возвращает рабочие часы между from и to; если to раньше from,
+// результат отрицательный. Первая и последняя недели обходятся по дням, а
+// целые недели между ними считаются по номинальной длине рабочего дня без
+// учёта перевода часов, поэтому время расчёта не зависит от длины интервала.
+func (c *Calendar) Hours(from time.Time, to time.Time) float64 {
+	if c == nil {
+		return to.Sub(from).Hours()
+	}
+	if to.Before(from) {
+		return -c.Hours(to, from)
+	}
+
+	year, month, day := from.In(c.location).Date()
+	if weeks := (dayNumber(to.In(c.location))-dayNumber(from.In(c.location)))/7 - 1; weeks > 0 {
+		first := time.Date(year, month, day+7, 0, 0, 0, 0, c.location)
+		last := time.Date(year, month, day+7*(weeks+1), 0, 0, 0, 0, c.location)
+		return c.walk(from, first) + c.wholeWeeks(first, weeks) + c.walk(last, to)
+	}
+	return c.walk(from, to)
+}
+
+// walk суммирует рабочие часы по дням от from до to.
+func (c *Calendar) walk(from time.Time, to time.Time) float64 {
+	total := 0.0
+	year, month, day := from.In(c.location).Date()
+	for offset := 0; ; offset++ {
+		midnight := time.Date(year, month, day+offset, 0, 0, 0, 0, c.location)
+		if !midnight.Before(to) {
+			break
+		}
+		if !c.Workday(midnight) {
+			continue
+		}
+		open := time.Date(year, month, day+offset, 0, c.start, 0, 0, c.location)
+		closed := time.Date(year, month, day+offset, 0, c.end, 0, 0, c.location)
+		if open.Before(from) {
+			open = from
+		}
+		if closed.After(to) {
+			closed = to
+		}
+		if closed.After(open) {
+			total += closed.Sub(open).Hours()
+		}
+	}
+	return total
+}
+
+// wholeWeeks считает рабочие часы в weeks неделях, начиная с полуночи start:
+// рабочие дни недели за вычетом праздников, выпавших на них.
+func (c *Calendar) wholeWeeks(start time.Time, weeks int) float64 {
+	workdays := 0
+	for _, open := range c.weekdays {
+		if open {
+			workdays++
+		}
+	}
+	dayHours := float64(c.end-c.start) / 60
+	total := float64(weeks*workdays) * dayHours
+
+	from := start.Format(time.DateOnly)
+	to := start.AddDate(0, 0, 7*weeks).Format(time.DateOnly)
+	for holiday := range c.holidays {
+		if holiday < from || holiday >= to {
+			continue
+		}
+		if day, err := time.Parse(time.DateOnly, holiday); err == nil && c.weekdays[day.Weekday()] {
+			total -= dayHours
+		}
+	}
+	return total
+}
+
+// dayNumber — номер календарного дня at, не зависящий от часового пояса.
+func dayNumber(at time.Time) int {
+	year, month, day := at.Date()
+	return int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60))
+}
+
+func weekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for index, candidate := range Weekdays {
+		if candidate == name {
+			return time.Weekday(index), true
+		}
+	}
+	return 0, false
+}
+
+func clockMinutes(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	h, hoursErr := strconv.Atoi(hours)
+	m, minutesErr := strconv.Atoi(minutes)
+	if !ok || hoursErr != nil || minutesErr != nil || len(minutes) != 2 ||
+		h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("рабочий календарь: неверное время %q, ожидается HH:MM", value)
+	}
+	return h*60 + m, nil
+}
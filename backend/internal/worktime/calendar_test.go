@@ -0,0 +1,143 @@
+package worktime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func compile(t *testing.T, spec Spec) *Calendar {
+	t.Helper()
+	calendar, err := spec.Compile("")
+	require.NoError(t, err)
+	return calendar
+}
+
+func at(value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestCalendarHoursSkipsNightsWeekendsAndHolidays(t *testing.T) {
+	spec := DefaultSpec()
+	spec.Holidays = []string{"2026-03-10"}
+	calendar := compile(t, spec)
+
+	// Пятница 17:00 — понедельник 10:00: час в пятницу и час в понедельник.
+	require.Equal(t, 2.0, calendar.Hours(at("2026-03-06T17:00:00Z"), at("2026-03-09T10:00:00Z")))
+	require.Equal(t, -2.0, calendar.Hours(at("2026-03-09T10:00:00Z"), at("2026-03-06T17:00:00Z")))
+	// Выходные целиком нерабочие.
+	require.Equal(t, 0.0, calendar.Hours(at("2026-03-07T08:00:00Z"), at("2026-03-08T20:00:00Z")))
+	// Вторник — праздник.
+	require.Equal(t, 9.0, calendar.Hours(at("2026-03-09T09:00:00Z"), at("2026-03-11T09:00:00Z")))
+	require.False(t, calendar.Workday(at("2026-03-10T12:00:00Z")))
+	require.True(t, calendar.Workday(at("2026-03-11T12:00:00Z")))
+
+	var wallClock *Calendar
+	require.Equal(t, 65.0, wallClock.Hours(at("2026-03-06T17:00:00Z"), at("2026-03-09T10:00:00Z")))
+	require.True(t, wallClock.Workday(at("2026-03-07T12:00:00Z")))
+}
+
+func TestCalendarHoursUsesTimeZone(t *testing.T) {
+	calendar := compile(t, Spec{TimeZone: "Europe/Berlin", Weekdays: []string{"sun", "mon"}, Start: "22:00", End: "24:00"})
+
+	// 22:00–24:00 по Берлину в воскресенье 2026-03-29 (переход на летнее
+	// время) — это 20:00–22:00 UTC.
+	require.Equal(t, 1.0, calendar.Hours(at("2026-03-29T21:00:00Z"), at("2026-03-30T08:00:00Z")))
+	require.Equal(t, 4.0, calendar.Hours(at("2026-03-29T00:00:00Z"), at("2026-03-31T00:00:00Z")))
+}
+
+func TestCalendarHoursCountsWholeWeeksArithmetically(t *testing.T) {
+	spec := DefaultSpec()
+	spec.Holidays = []string{"2026-03-10", "2026-03-21", "2026-04-14"}
+	calendar := compile(t, spec)
+
+	from, to := at("2026-03-04T12:00:00Z"), at("2026-05-06T15:00:00Z")
+	require.Equal(t, calendar.walk(from, to), calendar.Hours(from, to))
+	// Суббота 2026-03-21 выходная и праздником часы не уменьшает.
+	require.Equal(t, 9.0*(45-2)-3+6, calendar.Hours(from, to))
+
+	started := time.Now()
+	hours := calendar.Hours(at("2026-03-04T12:00:00Z"), at("9999-12-31T00:00:00Z"))
+	require.Greater(t, hours, 0.0)
+	require.Less(t, time.Since(started), 50*time.Millisecond)
+}
+
+func TestSpecCompileRejectsInvalidSettings(t *testing.T) {
+	for _, spec := range []Spec{
+		{TimeZone: "Mars/Olympus", Weekdays: []string{"mon"}, Start: "09:00", End: "18:00"},
+		{TimeZone: "UTC", Start: "09:00", End: "18:00"},
+		{TimeZone: "UTC", Weekdays: []string{"monday"}, Start: "09:00", End: "18:00"},
+		{TimeZone: "UTC", Weekdays: []string{"mon"}, Start: "9", End: "18:00"},
+		{TimeZone: "UTC", Weekdays: []string{"mon"}, Start: "09:00", End: "24:30"},
+		{TimeZone: "UTC", Weekdays: []string{"mon"}, Start: "18:00", End: "09:00"},
+		{TimeZone: "UTC", Weekdays: []string{"mon"}, Start: "09:00", End: "18:00", Holidays: []string{"01.01.2026"}},
+		{TimeZone: "UTC", Weekdays: []string{"mon"}, Start: "09:00", End: "18:00", HolidaysFile: "missing.txt"},
+	} {
+		_, err := spec.Compile(t.TempDir())
+		require.Error(t, err, spec)
+	}
+}
+
+func TestParseHolidays(t *testing.T) {
+	days, err := ParseHolidays(strings.NewReader("# Праздники\n2026-01-02 Новогодние каникулы\n\n2026-01-01,Новый год\n2026-01-02\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"2026-01-01", "2026-01-02"}, days)
+
+	_, err = ParseHolidays(strings.NewReader("2026-01-01\n1 мая\n"))
+	require.ErrorContains(t, err, "строка 2")
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Новогодние каникулы",
+		"DTSTART;VALUE=DATE:20260101",
+		"DTEND;VALUE=DATE:20260104",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260309",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	days, err = ParseHolidays(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Equal(t, []string{"2026-01-01", "2026-01-02", "2026-01-03", "2026-03-09"}, days)
+}
+
+func TestParseCalendarsInheritsDefault(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "holidays.txt"), []byte("2026-03-09\n"), 0o600))
+
+	calendars, err := Parse([]byte(`{
+		"default": {"start": "10:00", "end": "18:00", "holidaysFile": "holidays.txt"},
+		"owners": {"anna": {"weekdays": ["tue", "wed"], "holidays": ["2026-03-11"]}}
+	}`), dir)
+	require.NoError(t, err)
+
+	monday, wednesday := at("2026-03-09T12:00:00Z"), at("2026-03-11T12:00:00Z")
+	require.False(t, calendars.For("ivan").Workday(monday))
+	require.True(t, calendars.For("ivan").Workday(wednesday))
+	require.False(t, calendars.For("anna").Workday(monday))
+	require.False(t, calendars.For("anna").Workday(wednesday))
+	// Часы дня и пояс календарь исполнителя берёт из default.
+	require.Equal(t, 8.0, calendars.For("anna").Hours(at("2026-03-10T00:00:00Z"), at("2026-03-11T00:00:00Z")))
+
+	var none *Calendars
+	require.Nil(t, none.For("anna"))
+
+	_, err = Parse([]byte(`{"owners": {"anna": {"timeZone": "Nowhere"}}}`), dir)
+	require.ErrorContains(t, err, "anna")
+	_, err = Parse([]byte(`{"default": {"hours": 8}}`), dir)
+	require.Error(t, err)
+
+	calendars, err = Load("")
+	require.NoError(t, err)
+	require.Nil(t, calendars)
+}
@@ -0,0 +1,99 @@
+package worktime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Calendars — общий рабочий календарь и календари исполнителей. nil
+// означает круглосуточный календарь для всех.
+type Calendars struct {
+	Default *Calendar
+	owners  map[string]*Calendar
+}
+
+// For возвращает календарь исполнителя или общий, если своего нет.
+func (c *Calendars) For(owner string) *Calendar {
+	if c == nil {
+		return nil
+	}
+	if calendar, ok := c.owners[owner]; ok {
+		return calendar
+	}
+	return c.Default
+}
+
+// Parse читает настройки календарей:
+//
+//	{"default": Spec, "owners": {"anna": Spec}}
+//
+// Незаданные поля default берутся из DefaultSpec, а поля календаря
+// исполнителя — из default, кроме праздников: праздники исполнителя
+// добавляются к общим. Пути holidaysFile считаются от каталога dir.
+func Parse(data []byte, dir string) (*Calendars, error) {
+	var config struct {
+		Default json.RawMessage            `json:"default"`
+		Owners  map[string]json.RawMessage `json:"owners"`
+	}
+	if err := strictDecode(data, &config); err != nil {
+		return nil, err
+	}
+
+	base := DefaultSpec()
+	if len(config.Default) > 0 {
+		if err := strictDecode(config.Default, &base); err != nil {
+			return nil, err
+		}
+	}
+	calendars := &Calendars{owners: make(map[string]*Calendar, len(config.Owners))}
+	var err error
+	if calendars.Default, err = base.Compile(dir); err != nil {
+		return nil, err
+	}
+
+	for owner, raw := range config.Owners {
+		if strings.TrimSpace(owner) == "" {
+			return nil, fmt.Errorf("рабочий календарь: пустое имя исполнителя")
+		}
+		spec := base
+		spec.Holidays, spec.HolidaysFile = nil, ""
+		if err := strictDecode(raw, &spec); err != nil {
+			return nil, fmt.Errorf("%w (исполнитель %s)", err, owner)
+		}
+		calendar, err := spec.Compile(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%w (исполнитель %s)", err, owner)
+		}
+		for day := range calendars.Default.holidays {
+			calendar.holidays[day] = true
+		}
+		calendars.owners[owner] = calendar
+	}
+	return calendars, nil
+}
+
+// Load читает настройки календарей из файла; пустой путь означает
+// круглосуточный календарь.
+func Load(path string) (*Calendars, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("рабочий календарь: %w", err)
+	}
+	return Parse(data, filepath.Dir(path))
+}
+
+func strictDecode(data []byte, target any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("рабочий календарь: %w", err)
+	}
+	return nil
+}
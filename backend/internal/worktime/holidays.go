@@ -0,0 +1,137 @@
+package worktime
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LoadHolidays читает список праздников из файла; формат описан в
+// ParseHolidays.
+func LoadHolidays(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("рабочий календарь: %w", err)
+	}
+	days, err := ParseHolidays(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", err, path)
+	}
+	return days, nil
+}
+
+// ParseHolidays читает праздники в одном из двух форматов:
+//   - текст: дата YYYY-MM-DD в начале строки, после неё через пробел, запятую
+//     или точку с запятой — название; пустые строки и строки с # пропускаются;
+//   - iCalendar (.ics), например выгрузка календаря праздников: каждое событие
+//     на целые дни (DTSTART;VALUE=DATE) даёт дни с DTSTART до DTEND.
+//
+// Возвращает упорядоченные дни без повторов.
+func ParseHolidays(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("рабочий календарь: %w", err)
+	}
+
+	set := make(map[string]bool)
+	var err error
+	if isICalendar(lines) {
+		err = parseICalendar(lines, set)
+	} else {
+		err = parseHolidayList(lines, set)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]string, 0, len(set))
+	for day := range set {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	return days, nil
+}
+
+func isICalendar(lines []string) bool {
+	for _, line := range lines {
+		if line != "" {
+			return strings.EqualFold(line, "BEGIN:VCALENDAR")
+		}
+	}
+	return false
+}
+
+func parseHolidayList(lines []string, set map[string]bool) error {
+	for number, line := range lines {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		value := line
+		if index := strings.IndexAny(line, " \t,;"); index >= 0 {
+			value = line[:index]
+		}
+		day, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return fmt.Errorf("рабочий календарь: строка %d: неверная дата праздника %q", number+1, value)
+		}
+		set[day.Format(time.DateOnly)] = true
+	}
+	return nil
+}
+
+func parseICalendar(lines []string, set map[string]bool) error {
+	var start, end *time.Time
+	for number, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name = strings.ToUpper(name)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			start, end = nil, nil
+		case strings.HasPrefix(name, "DTSTART"), strings.HasPrefix(name, "DTEND"):
+			if len(value) < 8 {
+				return fmt.Errorf("рабочий календарь: строка %d: неверная дата %q", number+1, value)
+			}
+			day, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return fmt.Errorf("рабочий календарь: строка %d: неверная дата %q", number+1, value)
+			}
+			if strings.HasPrefix(name, "DTSTART") {
+				start = &day
+			} else {
+				end = &day
+			}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if start == nil {
+				continue
+			}
+			set[start.Format(time.DateOnly)] = true
+			if end != nil {
+				for day := start.AddDate(0, 0, 1); day.Before(*end); day = day.AddDate(0, 0, 1) {
+					set[day.Format(time.DateOnly)] = true
+				}
+			}
+			start, end = nil, nil
+		}
+	}
+	return nil
+}
+
+func resolvePath(dir string, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
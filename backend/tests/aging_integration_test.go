@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgingWIPAndStatusHours(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	hoursAgo := func(hours int) *time.Time {
+		value := now.Add(-time.Duration(hours) * time.Hour)
+		return &value
+	}
+
+	store := newInMemoryTaskStore()
+	for _, hours := range []int{10, 20, 30, 40, 50} {
+		task := domain.Task{Title: "Done", Status: domain.StatusDone, Owner: "alice", StartedAt: hoursAgo(hours + 24), CompletedAt: hoursAgo(24), CreatedAt: *hoursAgo(200)}
+		require.NoError(t, store.Create(ctx, &task))
+	}
+	stale := domain.Task{Title: "Stale", Status: domain.StatusTodo, Priority: domain.PriorityMedium, Owner: "alice", CreatedAt: *hoursAgo(300)}
+	require.NoError(t, store.Create(ctx, &stale))
+	fresh := domain.Task{Title: "Fresh", Status: domain.StatusInProgress, Priority: domain.PriorityMedium, Owner: "bob", StartedAt: hoursAgo(5), CreatedAt: *hoursAgo(6)}
+	require.NoError(t, store.Create(ctx, &fresh))
+
+	clock := &service.FixedClock{NowValue: now.Add(-100 * time.Hour)}
+	router := httpapi.NewRouter(store, httpapi.WithClock(clock))
+	resp := performPatch(router, "/api/tasks/"+itoa(stale.ID), httpapi.MediaTypeMergePatch, `{"status":"in_progress"}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	clock.NowValue = now
+	resp = performRequest(router, http.MethodGet, "/api/tasks/"+itoa(stale.ID), nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var task httpapi.TaskResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &task))
+	require.Equal(t, 300.0, task.AgeHours)
+	require.Equal(t, 100.0, task.StatusHours)
+	require.Equal(t, *hoursAgo(100), *task.StatusChangedAt)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/aging-wip", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var report service.AgingWIP
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+	require.Equal(t, 44.0, report.CycleTime.P85Hours)
+	require.Equal(t, 2, report.Total)
+	require.Equal(t, 1, report.Flagged)
+	require.Len(t, report.Groups, 2)
+	require.Equal(t, "alice", report.Groups[0].Owner)
+	require.Equal(t, stale.ID, report.Groups[0].Items[0].TaskID)
+	require.Equal(t, service.AgingPastP95, report.Groups[0].Items[0].Band)
+	require.True(t, report.Groups[0].Items[0].Flagged)
+	require.Equal(t, "bob", report.Groups[1].Owner)
+	require.False(t, report.Groups[1].Items[0].Flagged)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/aging-wip?owner=bob", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var bob service.AgingWIP
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &bob))
+	require.Zero(t, bob.CycleTime.Count)
+	require.Equal(t, 1, bob.Total)
+	require.Empty(t, bob.Groups[0].Items[0].Band)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/aging-wip?since=yesterday", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
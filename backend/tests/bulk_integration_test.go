@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func createTask(t *testing.T, router *gin.Engine, body string) taskResponse {
+	t.Helper()
+
+	resp := performRequest(router, http.MethodPost, "/api/tasks", []byte(body))
+	require.Equal(t, http.StatusCreated, resp.Code)
+
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	return created
+}
+
+func TestBulkOperationsByIDsAndFilter(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	first := createTask(t, router, `{"title":"Groom backlog","owner":"anna","tags":["infra"]}`)
+	second := createTask(t, router, `{"title":"Fix flaky test","owner":"anna","tags":["ci"]}`)
+	third := createTask(t, router, `{"title":"Write docs","owner":"ivan"}`)
+
+	statusBody := `{"ids":[` + itoa(first.ID) + `,` + itoa(second.ID) + `,999],"operation":"set_status","status":"in_progress"}`
+	statusResp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(statusBody))
+	require.Equal(t, http.StatusOK, statusResp.Code)
+
+	var statusResult httpapi.BulkResponse
+	require.NoError(t, json.Unmarshal(statusResp.Body.Bytes(), &statusResult))
+	require.Equal(t, 2, statusResult.Applied)
+	require.Equal(t, 1, statusResult.Failed)
+	require.Len(t, statusResult.Results, 3)
+	require.Equal(t, httpapi.BulkResultUpdated, statusResult.Results[0].Result)
+	require.Equal(t, domain.StatusInProgress, statusResult.Results[0].Task.Status)
+	require.Equal(t, httpapi.BulkResultFailed, statusResult.Results[2].Result)
+	require.Equal(t, httpapi.CodeNotFound, statusResult.Results[2].Error.Code)
+
+	tagBody := `{"filter":{"owner":"anna"},"operation":"add_tags","tags":["Sprint-7"]}`
+	tagResp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(tagBody))
+	require.Equal(t, http.StatusOK, tagResp.Code)
+
+	var tagResult httpapi.BulkResponse
+	require.NoError(t, json.Unmarshal(tagResp.Body.Bytes(), &tagResult))
+	require.Equal(t, 2, tagResult.Applied)
+	for _, item := range tagResult.Results {
+		require.Contains(t, item.Task.Tags, "sprint-7")
+	}
+
+	removeBody := `{"ids":[` + itoa(first.ID) + `],"operation":"remove_tags","tags":["INFRA"]}`
+	removeResp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(removeBody))
+	require.Equal(t, http.StatusOK, removeResp.Code)
+
+	var removeResult httpapi.BulkResponse
+	require.NoError(t, json.Unmarshal(removeResp.Body.Bytes(), &removeResult))
+	require.Equal(t, domain.StringList{"sprint-7"}, removeResult.Results[0].Task.Tags)
+
+	ownerBody := `{"ids":[` + itoa(third.ID) + `],"operation":"set_owner","owner":"anna"}`
+	require.Equal(t, http.StatusOK, performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(ownerBody)).Code)
+
+	priorityBody := `{"filter":{"owner":"anna"},"operation":"set_priority","priority":"critical"}`
+	priorityResp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(priorityBody))
+	require.Equal(t, http.StatusOK, priorityResp.Code)
+
+	var priorityResult httpapi.BulkResponse
+	require.NoError(t, json.Unmarshal(priorityResp.Body.Bytes(), &priorityResult))
+	require.Equal(t, 3, priorityResult.Applied)
+
+	deleteBody := `{"filter":{"status":"todo"},"operation":"delete"}`
+	deleteResp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(deleteBody))
+	require.Equal(t, http.StatusOK, deleteResp.Code)
+
+	var deleteResult httpapi.BulkResponse
+	require.NoError(t, json.Unmarshal(deleteResp.Body.Bytes(), &deleteResult))
+	require.Equal(t, 1, deleteResult.Applied)
+	require.Equal(t, httpapi.BulkResultDeleted, deleteResult.Results[0].Result)
+
+	missing := performRequest(router, http.MethodGet, "/api/tasks/"+itoa(third.ID), nil)
+	require.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestBulkAtomicRollsBackOnFailure(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	todo := createTask(t, router, `{"title":"Todo task"}`)
+	started := createTask(t, router, `{"title":"Started task","status":"in_progress"}`)
+
+	body := `{"ids":[` + itoa(started.ID) + `,` + itoa(todo.ID) + `],"operation":"set_status","status":"done","atomic":true}`
+	resp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(body))
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	var result httpapi.BulkResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.True(t, result.RolledBack)
+	require.Equal(t, 0, result.Applied)
+	require.Equal(t, 1, result.Failed)
+	require.Equal(t, httpapi.BulkResultSkipped, result.Results[0].Result)
+	require.Equal(t, httpapi.BulkResultFailed, result.Results[1].Result)
+	require.Equal(t, service.CodeInvalidTransition, result.Results[1].Error.Code)
+
+	getResp := performRequest(router, http.MethodGet, "/api/tasks/"+itoa(started.ID), nil)
+	var unchanged taskResponse
+	require.NoError(t, json.Unmarshal(getResp.Body.Bytes(), &unchanged))
+	require.Equal(t, domain.StatusInProgress, unchanged.Status)
+
+	missingBody := `{"ids":[` + itoa(todo.ID) + `,999],"operation":"delete","atomic":true}`
+	missingResp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(missingBody))
+	require.Equal(t, http.StatusUnprocessableEntity, missingResp.Code)
+	require.Equal(t, http.StatusOK, performRequest(router, http.MethodGet, "/api/tasks/"+itoa(todo.ID), nil).Code)
+}
+
+func TestBulkValidation(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	cases := map[string]string{
+		`{`:                                    httpapi.CodeInvalidBody,
+		`{"operation":"delete"}`:               httpapi.CodeBulkTargetRequired,
+		`{"ids":[1],"operation":"archive"}`:    httpapi.CodeBulkInvalidOperation,
+		`{"ids":[1],"operation":"set_status"}`: httpapi.CodeBulkValueRequired,
+		`{"ids":[1],"operation":"set_priority","priority":" "}`: httpapi.CodeBulkValueRequired,
+		`{"ids":[1],"operation":"set_owner"}`:                   httpapi.CodeBulkValueRequired,
+		`{"ids":[1],"operation":"add_tags"}`:                    httpapi.CodeBulkValueRequired,
+		`{"filter":{"status":"weird"},"operation":"delete"}`:    service.CodeInvalidStatus,
+	}
+
+	for body, code := range cases {
+		resp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(body))
+		require.Equal(t, http.StatusBadRequest, resp.Code, body)
+
+		var errBody httpapi.ErrorResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errBody))
+		require.Equal(t, code, errBody.Code, body)
+	}
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarFeedTracksUpdatesAndDeletions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	task := createTask(t, router, `{"title":"Renew certificate","priority":"critical","owner":"anna","dueDate":"2026-03-01T09:00:00Z"}`)
+	createTask(t, router, `{"title":"Someday","owner":"anna"}`)
+
+	resp := performRequest(router, http.MethodGet, "/api/calendar.ics", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "text/calendar; charset=utf-8", resp.Header().Get("Content-Type"))
+	body := resp.Body.String()
+	uid := fmt.Sprintf("UID:task-%d@flowboard\r\n", task.ID)
+	require.Contains(t, body, uid)
+	require.Contains(t, body, "DUE:20260301T090000Z\r\n")
+	require.Contains(t, body, "PRIORITY:1\r\n")
+	require.NotContains(t, body, "Someday")
+
+	etag := resp.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar.ics", nil)
+	req.Header.Set("If-None-Match", etag)
+	cached := httptest.NewRecorder()
+	router.ServeHTTP(cached, req)
+	require.Equal(t, http.StatusNotModified, cached.Code)
+	require.Empty(t, cached.Body.String())
+
+	resp = performRequest(router, http.MethodPatch, fmt.Sprintf("/api/tasks/%d", task.ID), []byte(`{"title":"Renew TLS certificate"}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	resp = performRequest(router, http.MethodGet, "/api/calendar.ics?component=event", nil)
+	require.NotEqual(t, etag, resp.Header().Get("ETag"))
+	require.Contains(t, resp.Body.String(), uid)
+	require.Contains(t, resp.Body.String(), "SUMMARY:Renew TLS certificate\r\n")
+	require.Contains(t, resp.Body.String(), "DTSTART;VALUE=DATE:20260301\r\n")
+
+	resp = performRequest(router, http.MethodDelete, fmt.Sprintf("/api/tasks/%d", task.ID), nil)
+	require.Equal(t, http.StatusNoContent, resp.Code)
+	resp = performRequest(router, http.MethodGet, "/api/calendar.ics", nil)
+	require.NotContains(t, resp.Body.String(), uid)
+
+	resp = performRequest(router, http.MethodGet, "/api/calendar.ics?component=journal&tz=Mars/Base", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	var errBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errBody))
+	require.Len(t, errBody.Errors, 2)
+}
+
+func TestCalendarFeedsRequireToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore(), httpapi.WithCalendarSecret("s3cret"))
+
+	createTask(t, router, `{"title":"Anna task","owner":"anna","tags":["infra"],"dueDate":"2026-03-01T09:00:00Z"}`)
+	createTask(t, router, `{"title":"Ivan task","owner":"ivan","tags":["infra"],"dueDate":"2026-03-02T09:00:00Z"}`)
+	view := createView(t, router, "ivan", `{"name":"Infra","visibility":"shared","filter":{"tag":"infra"}}`)
+
+	resp := performAs(router, "", http.MethodGet, "/api/calendar/feeds", nil)
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = performAs(router, "anna", http.MethodGet, "/api/calendar/feeds", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var feeds []httpapi.CalendarFeed
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &feeds))
+	require.Len(t, feeds, 2)
+	require.Equal(t, httpapi.CalendarFeedOwner, feeds[0].Kind)
+	require.Equal(t, httpapi.CalendarFeedView, feeds[1].Kind)
+	require.Equal(t, view.ID, feeds[1].ViewID)
+
+	ownerFeed := feedPath(t, feeds[0].URL)
+	require.True(t, strings.HasPrefix(ownerFeed, "/api/calendar/owners/anna.ics?token="))
+	resp = performRequest(router, http.MethodGet, ownerFeed, nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), "Anna task")
+	require.NotContains(t, resp.Body.String(), "Ivan task")
+
+	resp = performRequest(router, http.MethodGet, strings.Replace(ownerFeed, "anna", "ivan", 1), nil)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+
+	viewFeed := feedPath(t, feeds[1].URL)
+	resp = performRequest(router, http.MethodGet, viewFeed, nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), "X-WR-CALNAME:FlowBoard: Infra\r\n")
+	require.Contains(t, resp.Body.String(), "Ivan task")
+
+	// Владелец делает представление личным: выданная ссылка отзывается.
+	resp = performAs(router, "ivan", http.MethodPut, fmt.Sprintf("/api/views/%d", view.ID), []byte(`{"name":"Infra","filter":{"tag":"infra"}}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	resp = performRequest(router, http.MethodGet, viewFeed, nil)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = performRequest(router, http.MethodGet, "/api/views/999/calendar.ics?token=x", nil)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+func feedPath(t *testing.T, raw string) string {
+	t.Helper()
+	parsed, err := url.Parse(raw)
+	require.NoError(t, err)
+	require.Equal(t, "http", parsed.Scheme)
+	return parsed.RequestURI()
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"devopslabs/internal/capacity"
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapacitySettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	resp := performRequest(router, http.MethodGet, "/api/capacity", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `[]`, resp.Body.String())
+
+	resp = performRequest(router, http.MethodPut, "/api/capacity/anna", []byte(`{"hoursPerWeek":30,"daysOff":["2026-03-10","2026-03-09","2026-03-10"]}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var saved domain.OwnerCapacity
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &saved))
+	require.Equal(t, "anna", saved.Owner)
+	require.Equal(t, domain.StringList{"2026-03-09", "2026-03-10"}, saved.DaysOff)
+
+	resp = performRequest(router, http.MethodGet, "/api/capacity/anna", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), `"hoursPerWeek":30`)
+
+	resp = performRequest(router, http.MethodPut, "/api/capacity/anna", []byte(`{"hoursPerWeek":200,"daysOff":["next monday"]}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	var body httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 2)
+	require.Equal(t, httpapi.CodeOutOfRange, body.Errors[0].Code)
+	require.Equal(t, capacity.CodeInvalidDayOff, body.Errors[1].Code)
+
+	resp = performRequest(router, http.MethodDelete, "/api/capacity/anna", nil)
+	require.Equal(t, http.StatusNoContent, resp.Code)
+	resp = performRequest(router, http.MethodGet, "/api/capacity/anna", nil)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestWorkloadEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	// Понедельник.
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store := newInMemoryTaskStore()
+	router := httpapi.NewRouter(store, httpapi.WithClock(service.FixedClock{NowValue: now}))
+
+	seed := func(title, owner, priority string, effort int, dueDay int) {
+		dueDate := time.Date(2026, 3, dueDay, 18, 0, 0, 0, time.UTC)
+		task := domain.Task{Title: title, Status: domain.StatusTodo, Priority: priority, Owner: owner, EffortHours: effort, DueDate: &dueDate, Tags: domain.StringList{"ops"}}
+		require.NoError(t, store.Create(ctx, &task))
+	}
+	seed("Migrate", "anna", domain.PriorityHigh, 10, 6)
+	seed("Docs", "anna", domain.PriorityLow, 6, 6)
+	seed("Deploy", "ivan", domain.PriorityMedium, 4, 13)
+
+	resp := performRequest(router, http.MethodPut, "/api/capacity/anna", []byte(`{"hoursPerWeek":15,"daysOff":["2026-03-06"]}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/workload?weeks=2&tag=ops", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var workload capacity.Workload
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &workload))
+	require.Equal(t, "2026-03-02", workload.From)
+	require.Equal(t, "2026-03-15", workload.To)
+	require.Len(t, workload.Owners, 2)
+
+	anna := workload.Owners[0]
+	require.Equal(t, capacity.WeekLoad{Week: "2026-03-02", CapacityHours: 12, AssignedHours: 16, Utilization: 1.33, Overallocated: true}, anna.Weeks[0])
+	ivan := workload.Owners[1]
+	require.False(t, ivan.Configured)
+	require.False(t, ivan.Overallocated)
+
+	require.Len(t, workload.Suggestions, 1)
+	require.Equal(t, "Docs", workload.Suggestions[0].Title)
+	require.Equal(t, "ivan", workload.Suggestions[0].To)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/workload?weeks=0", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), capacity.CodeInvalidWeeks)
+}
+
+func TestWorkloadKeepsTasksWithoutOwnerUnassigned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Понедельник.
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	router := httpapi.NewRouter(newInMemoryTaskStore(), httpapi.WithClock(service.FixedClock{NowValue: now}))
+
+	created := createTask(t, router, `{"title":"Triage","effortHours":5,"dueDate":"2026-03-04T18:00:00Z"}`)
+	require.Equal(t, service.DefaultOwner, created.Owner)
+	createTask(t, router, `{"title":"Migrate","owner":"anna","effortHours":60,"dueDate":"2026-03-06T18:00:00Z"}`)
+
+	resp := performRequest(router, http.MethodGet, "/api/insights/workload?weeks=1", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var workload capacity.Workload
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &workload))
+	require.Equal(t, 5.0, workload.UnassignedHours)
+	require.Len(t, workload.Owners, 1)
+	require.Equal(t, "anna", workload.Owners[0].Owner)
+	require.True(t, workload.Owners[0].Overallocated)
+	require.Empty(t, workload.Suggestions)
+}
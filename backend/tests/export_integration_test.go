@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"devopslabs/internal/export"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTasksAsCSV(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	createTask(t, router, `{"title":"Ship CI","priority":"high","owner":"alice","dueDate":"2026-02-07T21:30:00Z"}`)
+	createTask(t, router, `{"title":"=cmd|' /C calc'!A0","priority":"low","owner":"bob"}`)
+	createTask(t, router, `{"title":"Rotate keys","priority":"critical","owner":"alice","tags":["infra","security"]}`)
+
+	resp := performRequest(router, http.MethodGet, "/api/tasks/export?owner=alice&sort=title&order=asc&columns=title,tags,dueDate,risk,score,ageHours,cycleHours&tz=Europe/Moscow", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	require.Equal(t, `attachment; filename="tasks-20260206.csv"`, resp.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"title", "tags", "dueDate", "risk", "score", "ageHours", "cycleHours"},
+		{"Rotate keys", "infra, security", "", "unscheduled", "40.1", "0", ""},
+		{"Ship CI", "", "2026-02-08 00:30:00", "at_risk", "40.1", "0", ""},
+	}, records)
+
+	resp = performRequest(router, http.MethodGet, "/api/tasks/export?owner=bob&columns=title", nil)
+	records, err = csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "'=cmd|' /C calc'!A0", records[1][0])
+
+	resp = performRequest(router, http.MethodGet, "/api/tasks/export?owner=nobody", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	records, err = csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Len(t, records[0], len(export.DefaultColumns))
+}
+
+func TestExportTasksAsXLSXAndInsights(t *testing.T) {
+	router, _ := setupTestRouter(t)
+	createTask(t, router, `{"title":"Ship CI","priority":"high","owner":"alice"}`)
+	createTask(t, router, `{"title":"Write docs","priority":"low","owner":"alice","status":"done"}`)
+
+	resp := performRequest(router, http.MethodGet, "/api/tasks/export?format=xlsx&columns=id,title", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, export.ContentType(export.FormatXLSX), resp.Header().Get("Content-Type"))
+	archive, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	require.NoError(t, err)
+	require.Len(t, archive.File, 5)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/export?status=done", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"metric", "value"}, records[0])
+	require.Equal(t, []string{"total", "1"}, records[1])
+	require.Contains(t, records, []string{"byStatus.done", "1"})
+}
+
+func TestExportValidationReportsAllErrors(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	resp := performRequest(router, http.MethodGet, "/api/tasks/export?format=pdf&columns=title,assignee&tz=Mars/Olympus&status=weird", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	var body httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Equal(t, httpapi.CodeValidation, body.Code)
+	codes := []string{}
+	for _, fieldErr := range body.Errors {
+		codes = append(codes, fieldErr.Code)
+	}
+	require.Equal(t, []string{"invalid_status", export.CodeInvalidFormat, export.CodeInvalidTimezone, export.CodeUnknownColumn}, codes)
+}
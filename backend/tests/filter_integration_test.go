@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/stretchr/testify/require"
+)
+
+func listTitles(t *testing.T, router http.Handler, filter string) []string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?sort=title&order=asc&filter="+url.QueryEscape(filter), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var tasks []taskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tasks))
+	titles := []string{}
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func TestListWithFilterExpression(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	createTask(t, router, `{"title":"Ship CI","priority":"high","owner":"alice","status":"in_progress","dueDate":"2026-02-10T12:00:00Z"}`)
+	createTask(t, router, `{"title":"Rotate keys","priority":"critical","owner":"bob","tags":["infra"],"dueDate":"2026-03-01T12:00:00Z"}`)
+	createTask(t, router, `{"title":"Write docs","priority":"high","owner":"alice","status":"done"}`)
+	createTask(t, router, `{"title":"Triage","priority":"low","owner":"carol","tags":["infra"]}`)
+
+	require.Equal(t, []string{"Ship CI"}, listTitles(t, router, `priority>=high AND (owner:alice OR tag:infra) AND due<7d AND NOT status:done`))
+	require.Equal(t, []string{"Rotate keys", "Ship CI", "Write docs"}, listTitles(t, router, `priority>=high`))
+	require.Equal(t, []string{"Triage", "Write docs"}, listTitles(t, router, `due:none`))
+
+	combined := performRequest(router, http.MethodGet, "/api/insights?owner=alice&filter="+url.QueryEscape("NOT status:done"), nil)
+	require.Equal(t, http.StatusOK, combined.Code)
+	var insights service.Insights
+	require.NoError(t, json.Unmarshal(combined.Body.Bytes(), &insights))
+	require.Equal(t, 1, insights.Total)
+
+	bulk := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(`{"filter":{"filter":"tag:infra"},"operation":"set_priority","priority":"medium"}`))
+	require.Equal(t, http.StatusOK, bulk.Code)
+	var bulkResult httpapi.BulkResponse
+	require.NoError(t, json.Unmarshal(bulk.Body.Bytes(), &bulkResult))
+	require.Equal(t, 2, bulkResult.Applied)
+}
+
+func TestListFilterExpressionErrors(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?filter="+url.QueryEscape(`owner:alice AND priorty>=high`), nil)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var body httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, taskquery.CodeUnknownField, body.Code)
+	require.Equal(t, taskquery.ErrorField, body.Field)
+	require.Equal(t, float64(17), body.Details["position"])
+	require.Contains(t, body.Message, `unknown field "priorty" at position 17`)
+
+	w = performRequest(router, http.MethodGet, "/api/tasks?status=weird&filter="+url.QueryEscape("(owner:alice"), nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, httpapi.CodeValidation, body.Code)
+	require.Len(t, body.Errors, 2)
+	require.Equal(t, taskquery.CodeUnclosedGroup, body.Errors[1].Code)
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/forecast"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	store := newInMemoryTaskStore()
+	router := httpapi.NewRouter(store, httpapi.WithClock(service.FixedClock{NowValue: now}))
+
+	seed := func(owner string, status string, completedDay int) {
+		task := domain.Task{Title: "Task", Status: status, Priority: domain.PriorityMedium, Owner: owner, CreatedAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+		if completedDay > 0 {
+			completed := time.Date(2026, 3, completedDay, 15, 0, 0, 0, time.UTC)
+			task.CompletedAt = &completed
+		}
+		require.NoError(t, store.Create(ctx, &task))
+	}
+	for _, completedDay := range []int{17, 18, 18, 19} {
+		seed("anna", domain.StatusDone, completedDay)
+	}
+	seed("ivan", domain.StatusDone, 19)
+	for range 3 {
+		seed("anna", domain.StatusTodo, 0)
+	}
+
+	resp := performRequest(router, http.MethodGet, "/api/forecast?owner=anna&history=3&seed=7&trials=500", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var when forecast.Forecast
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &when))
+	require.Equal(t, forecast.ModeWhen, when.Mode)
+	require.Equal(t, "2026-03-17", when.HistoryFrom)
+	require.Equal(t, "2026-03-19", when.HistoryTo)
+	require.Equal(t, []int{1, 2, 1}, when.Throughput)
+	require.Equal(t, 3, *when.Items)
+	require.Equal(t, uint64(7), when.Seed)
+	require.Len(t, when.When, 3)
+	require.LessOrEqual(t, when.When[0].Days, when.When[2].Days)
+	require.LessOrEqual(t, when.When[2].Days, 3)
+
+	// При том же seed прогноз повторяется.
+	again := performRequest(router, http.MethodGet, "/api/forecast?owner=anna&history=3&seed=7&trials=500", nil)
+	require.Equal(t, resp.Body.String(), again.Body.String())
+
+	resp = performRequest(router, http.MethodGet, "/api/forecast?target=2026-03-22&history=3&seed=7", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var howMany forecast.Forecast
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &howMany))
+	require.Equal(t, forecast.ModeHowMany, howMany.Mode)
+	require.Equal(t, 2, howMany.Days)
+	require.Equal(t, 2, howMany.HowMany[2].Items)
+	require.GreaterOrEqual(t, howMany.HowMany[0].Items, howMany.HowMany[2].Items)
+
+	resp = performRequest(router, http.MethodGet, "/api/forecast?owner=ivan&history=1&items=2", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = performRequest(router, http.MethodGet, "/api/forecast?owner=nobody&items=2", nil)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	require.Contains(t, resp.Body.String(), forecast.CodeNoThroughput)
+
+	resp = performRequest(router, http.MethodGet, "/api/forecast?target=2026-03-01&trials=0", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	var body httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 2)
+	require.Equal(t, forecast.CodePastTarget, body.Errors[0].Code)
+	require.Equal(t, forecast.CodeInvalidTrials, body.Errors[1].Code)
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/transport/graphqlapi"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLError struct {
+	Message    string         `json:"message"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []graphQLError             `json:"errors"`
+}
+
+func graphQL(t *testing.T, router *gin.Engine, query string, variables map[string]any) (int, graphQLResponse) {
+	t.Helper()
+	body, err := json.Marshal(graphqlapi.Request{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	w := performRequest(router, http.MethodPost, "/api/graphql", body)
+	var response graphQLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+func TestGraphQLTaskFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	code, created := graphQL(t, router, `
+		mutation Create($input: TaskInput!) {
+			createTask(input: $input) { id title status owner tags risk }
+		}`, map[string]any{"input": map[string]any{"title": "Ship CI", "priority": "high", "tags": []string{"CI"}}})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, created.Errors)
+
+	var task struct {
+		ID     string   `json:"id"`
+		Title  string   `json:"title"`
+		Status string   `json:"status"`
+		Owner  string   `json:"owner"`
+		Tags   []string `json:"tags"`
+		Risk   string   `json:"risk"`
+	}
+	require.NoError(t, json.Unmarshal(created.Data["createTask"], &task))
+	require.Equal(t, "Ship CI", task.Title)
+	require.Equal(t, domain.StatusTodo, task.Status)
+	require.Equal(t, defaultOwner, task.Owner)
+	require.Equal(t, []string{"ci"}, task.Tags)
+
+	_, _ = graphQL(t, router, `mutation { createTask(input: {title: "Write docs", priority: "low"}) { id } }`, nil)
+
+	code, updated := graphQL(t, router, `
+		mutation Update($id: ID!) {
+			updateTask(id: $id, input: {owner: "alex", effortHours: 4}) { owner effortHours title }
+			transitionTask(id: $id, status: "in_progress") { status startedAt }
+		}`, map[string]any{"id": task.ID})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, updated.Errors)
+	require.JSONEq(t, `{"owner":"alex","effortHours":4,"title":"Ship CI"}`, string(updated.Data["updateTask"]))
+	require.Contains(t, string(updated.Data["transitionTask"]), `"status":"in_progress"`)
+
+	code, listed := graphQL(t, router, `{
+		tasks(filter: {statuses: ["in_progress"], owner: "alex"}) { totalCount nodes { title owner } }
+		insights { total byStatus { key count } }
+	}`, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, listed.Errors)
+	require.JSONEq(t, `{"totalCount":1,"nodes":[{"title":"Ship CI","owner":"alex"}]}`, string(listed.Data["tasks"]))
+	require.JSONEq(t, `{"total":2,"byStatus":[{"key":"in_progress","count":1},{"key":"todo","count":1}]}`, string(listed.Data["insights"]))
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	code, invalid := graphQL(t, router, `{ tasks(filter: {statuses: ["weird"]}, first: 500) { totalCount } }`, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, invalid.Errors, 1)
+	require.Equal(t, "validation_failed", invalid.Errors[0].Extensions["code"])
+	require.Len(t, invalid.Errors[0].Extensions["errors"], 2)
+
+	code, malformed := graphQL(t, router, `{ tasks { nodes { unknownField } } }`, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Nil(t, malformed.Data)
+	require.NotEmpty(t, malformed.Errors)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(`{"query":`))
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var broken graphQLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &broken))
+	require.Equal(t, httpapi.CodeInvalidBody, broken.Errors[0].Extensions["code"])
+	require.Equal(t, "invalid request body", broken.Errors[0].Message)
+}
+
+func TestGraphQLOverGET(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	query := url.Values{"query": {`query Count($first: Int) { tasks(first: $first) { totalCount } }`}, "variables": {`{"first": 5}`}}
+	w := performRequest(router, http.MethodGet, "/api/graphql?"+query.Encode(), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"data":{"tasks":{"totalCount":0}}}`, w.Body.String())
+
+	mutation := url.Values{"query": {`mutation { createTask(input: {title: "x"}) { id } }`}}
+	w = performRequest(router, http.MethodGet, "/api/graphql?"+mutation.Encode(), nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), graphqlapi.CodeMutationNotAllowed)
+}
@@ -0,0 +1,310 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/events"
+	"devopslabs/internal/service"
+	"devopslabs/internal/taskquery"
+	"devopslabs/internal/transport/grpcapi"
+	"devopslabs/internal/transport/grpcapi/taskpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type grpcFixture struct {
+	client taskpb.TaskServiceClient
+	store  *events.NotifyingStore
+	bus    *events.Bus
+	clock  service.FixedClock
+}
+
+func setupGRPC(t *testing.T) grpcFixture {
+	t.Helper()
+	return setupGRPCWithPolicy(t, nil)
+}
+
+func setupGRPCWithPolicy(t *testing.T, policy *service.Policy) grpcFixture {
+	t.Helper()
+
+	clock := service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+	bus := events.NewBus()
+	store := events.NewNotifyingStore(newInMemoryTaskStore(), bus, clock)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpcapi.NewServer(store, bus, clock, policy)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+
+	return grpcFixture{client: taskpb.NewTaskServiceClient(conn), store: store, bus: bus, clock: clock}
+}
+
+func fieldViolations(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	result := make(map[string]string)
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.GetFieldViolations() {
+			result[violation.GetField()] = violation.GetReason()
+		}
+	}
+	return result
+}
+
+func TestGRPCTaskCRUDFlow(t *testing.T) {
+	fixture := setupGRPC(t)
+	ctx := context.Background()
+
+	created, err := fixture.client.CreateTask(ctx, &taskpb.CreateTaskRequest{Task: &taskpb.TaskInput{
+		Title:    "Write report",
+		Status:   "in_progress",
+		Priority: "high",
+		DueDate:  timestamppb.New(fixture.clock.NowValue.Add(96 * time.Hour)),
+		Tags:     []string{"DevOps", "CI"},
+	}})
+	require.NoError(t, err)
+	require.NotZero(t, created.GetId())
+	require.Equal(t, domain.StatusInProgress, created.GetStatus())
+	require.Equal(t, defaultOwner, created.GetOwner())
+	require.Equal(t, int32(1), created.GetEffortHours())
+	require.NotNil(t, created.GetStartedAt())
+	require.Equal(t, service.RiskOnTrack, created.GetRisk())
+	require.ElementsMatch(t, []string{"devops", "ci"}, created.GetTags())
+
+	fetched, err := fixture.client.GetTask(ctx, &taskpb.GetTaskRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "Write report", fetched.GetTitle())
+
+	_, err = fixture.client.CreateTask(ctx, &taskpb.CreateTaskRequest{Task: &taskpb.TaskInput{Title: "Plan", Priority: "low"}})
+	require.NoError(t, err)
+
+	listed, err := fixture.client.ListTasks(ctx, &taskpb.ListTasksRequest{
+		Filter: &taskpb.TaskFilter{Statuses: []string{"IN_PROGRESS"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, listed.GetTasks(), 1)
+	require.Equal(t, created.GetId(), listed.GetTasks()[0].GetId())
+
+	expressed, err := fixture.client.ListTasks(ctx, &taskpb.ListTasksRequest{
+		Filter: &taskpb.TaskFilter{Expression: "priority<=medium OR NOT status:todo"},
+	})
+	require.NoError(t, err)
+	require.Len(t, expressed.GetTasks(), 2)
+
+	sorted, err := fixture.client.ListTasks(ctx, &taskpb.ListTasksRequest{Sort: "title", Order: "asc"})
+	require.NoError(t, err)
+	require.Len(t, sorted.GetTasks(), 2)
+	require.Equal(t, "Plan", sorted.GetTasks()[0].GetTitle())
+
+	replaced, err := fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id:   created.GetId(),
+		Task: &taskpb.TaskInput{Title: "Write report v2", Status: "done", Priority: "critical"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Write report v2", replaced.GetTitle())
+	require.Equal(t, domain.StatusDone, replaced.GetStatus())
+	require.NotNil(t, replaced.GetCompletedAt())
+	require.NotNil(t, replaced.CycleHours)
+	require.Empty(t, replaced.GetTags())
+	require.Nil(t, replaced.GetDueDate())
+
+	insights, err := fixture.client.GetInsights(ctx, &taskpb.GetInsightsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), insights.GetTotal())
+	require.Equal(t, int32(1), insights.GetDone())
+	require.Equal(t, int32(1), insights.GetByStatus()[domain.StatusDone])
+
+	_, err = fixture.client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: created.GetId()})
+	require.NoError(t, err)
+
+	_, err = fixture.client.GetTask(ctx, &taskpb.GetTaskRequest{Id: created.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = fixture.client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: created.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCUpdateWithMask(t *testing.T) {
+	fixture := setupGRPC(t)
+	ctx := context.Background()
+
+	created, err := fixture.client.CreateTask(ctx, &taskpb.CreateTaskRequest{Task: &taskpb.TaskInput{
+		Title:       "Deploy",
+		Description: "Roll out the release",
+		Priority:    "high",
+		Owner:       "alice",
+		EffortHours: 8,
+		Tags:        []string{"release"},
+	}})
+	require.NoError(t, err)
+
+	updated, err := fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id:         created.GetId(),
+		Task:       &taskpb.TaskInput{Owner: "bob", Title: "ignored"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"owner"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bob", updated.GetOwner())
+	require.Equal(t, "Deploy", updated.GetTitle())
+	require.Equal(t, "Roll out the release", updated.GetDescription())
+	require.Equal(t, int32(8), updated.GetEffortHours())
+	require.Equal(t, []string{"release"}, updated.GetTags())
+
+	_, err = fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id:         created.GetId(),
+		Task:       &taskpb.TaskInput{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"owner", "risk"}},
+	})
+	require.Equal(t, map[string]string{"update_mask": service.CodeInvalidValue}, fieldViolations(t, err))
+
+	_, err = fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id:         created.GetId(),
+		Task:       &taskpb.TaskInput{Status: "in_progress"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+	})
+	require.NoError(t, err)
+
+	_, err = fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id:         created.GetId(),
+		Task:       &taskpb.TaskInput{Status: "todo"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	reopened, err := fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id:         created.GetId(),
+		Task:       &taskpb.TaskInput{Status: "todo"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+		Force:      true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusTodo, reopened.GetStatus())
+
+	_, err = fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{Id: 999, Task: &taskpb.TaskInput{Title: "Missing"}})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCValidationErrors(t *testing.T) {
+	fixture := setupGRPC(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "en")
+
+	_, err := fixture.client.CreateTask(ctx, &taskpb.CreateTaskRequest{Task: &taskpb.TaskInput{
+		Status:      "weird",
+		Priority:    "urgent",
+		EffortHours: 500,
+	}})
+	require.Equal(t, map[string]string{
+		"title":       service.CodeTitleRequired,
+		"status":      service.CodeInvalidStatus,
+		"priority":    service.CodeInvalidPriority,
+		"effortHours": service.CodeInvalidEffort,
+	}, fieldViolations(t, err))
+
+	_, err = fixture.client.CreateTask(ctx, &taskpb.CreateTaskRequest{Task: &taskpb.TaskInput{}})
+	st, _ := status.FromError(err)
+	require.Equal(t, "title is required", st.Message())
+
+	_, err = fixture.client.ListTasks(ctx, &taskpb.ListTasksRequest{
+		Filter: &taskpb.TaskFilter{Statuses: []string{"weird"}, Priorities: []string{"urgent"}, Expression: "owner:"},
+	})
+	require.Equal(t, map[string]string{
+		"status":   service.CodeInvalidStatus,
+		"priority": service.CodeInvalidPriority,
+		"filter":   taskquery.CodeExpectedValue,
+	}, fieldViolations(t, err))
+
+	_, err = fixture.client.GetTask(ctx, &taskpb.GetTaskRequest{Id: 42})
+	st, _ = status.FromError(err)
+	require.Equal(t, codes.NotFound, st.Code())
+	require.Equal(t, "requested object was not found", st.Message())
+}
+
+func TestGRPCWatchTasks(t *testing.T) {
+	fixture := setupGRPC(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	existing := domain.Task{Title: "Existing", Status: domain.StatusTodo, Priority: domain.PriorityHigh}
+	require.NoError(t, fixture.store.Create(ctx, &existing))
+
+	stream, err := fixture.client.WatchTasks(ctx, &taskpb.WatchTasksRequest{
+		Filter:          &taskpb.TaskFilter{Priorities: []string{"high"}},
+		IncludeSnapshot: true,
+	})
+	require.NoError(t, err)
+
+	snapshot, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, taskpb.TaskEvent_TYPE_SNAPSHOT, snapshot.GetType())
+	require.Equal(t, uint32(existing.ID), snapshot.GetTaskId())
+
+	// Снимок отправляется после подписки, поэтому дальнейшие изменения
+	// гарантированно попадут в поток.
+	_, err = fixture.client.CreateTask(ctx, &taskpb.CreateTaskRequest{Task: &taskpb.TaskInput{Title: "Low", Priority: "low"}})
+	require.NoError(t, err)
+	created, err := fixture.client.CreateTask(ctx, &taskpb.CreateTaskRequest{Task: &taskpb.TaskInput{Title: "High", Priority: "high"}})
+	require.NoError(t, err)
+	_, err = fixture.client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{
+		Id:         created.GetId(),
+		Task:       &taskpb.TaskInput{Status: "in_progress"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+	})
+	require.NoError(t, err)
+	_, err = fixture.client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: created.GetId()})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, taskpb.TaskEvent_TYPE_CREATED, event.GetType())
+	require.Equal(t, "High", event.GetTask().GetTitle())
+	require.Equal(t, timestamppb.New(fixture.clock.NowValue).AsTime(), event.GetOccurredAt().AsTime())
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, taskpb.TaskEvent_TYPE_UPDATED, event.GetType())
+	require.Equal(t, domain.StatusInProgress, event.GetTask().GetStatus())
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, taskpb.TaskEvent_TYPE_DELETED, event.GetType())
+	require.Equal(t, created.GetId(), event.GetTaskId())
+	require.Nil(t, event.GetTask())
+
+	invalid, err := fixture.client.WatchTasks(ctx, &taskpb.WatchTasksRequest{Filter: &taskpb.TaskFilter{Statuses: []string{"weird"}}})
+	require.NoError(t, err)
+	_, err = invalid.Recv()
+	require.Equal(t, map[string]string{"status": service.CodeInvalidStatus}, fieldViolations(t, err))
+}
@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/history"
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsightsHistoryCombinesSnapshotsAndLiveState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	today := time.Now().UTC()
+	yesterday := history.Day(today.AddDate(0, 0, -1))
+
+	changes := repository.NewMemoryChangeStore()
+	snapshots := repository.NewMemorySnapshotStore()
+	require.NoError(t, snapshots.Save(context.Background(), []repository.InsightSnapshot{
+		{Day: yesterday, Scope: repository.SnapshotScopeOwner, Key: "anna", Insights: service.Insights{Total: 5, Overdue: 3}},
+	}))
+	store := history.NewRecordingStore(newInMemoryTaskStore(), changes, nil)
+	router := httpapi.NewRouter(store, httpapi.WithHistory(changes, snapshots))
+
+	createTask(t, router, `{"title":"Fix alerts","owner":"anna","tags":["ops"]}`)
+	createTask(t, router, `{"title":"Write docs","owner":"ivan"}`)
+
+	resp := performRequest(router, http.MethodGet, "/api/insights/history?owner=anna&from="+yesterday, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var series httpapi.InsightsHistory
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &series))
+	require.Equal(t, history.IntervalDay, series.Interval)
+	require.Equal(t, repository.SnapshotScopeOwner, series.Scope)
+	require.Equal(t, "anna", series.Key)
+	require.Len(t, series.Points, 2)
+	require.Equal(t, history.SourceSnapshot, series.Points[0].Source)
+	require.Equal(t, 3, series.Points[0].Insights.Overdue)
+	require.Equal(t, history.SourceLive, series.Points[1].Source)
+	require.Equal(t, 1, series.Points[1].Insights.Total)
+
+	recorded, err := changes.List(context.Background(), repository.ChangeFilter{})
+	require.NoError(t, err)
+	require.Len(t, recorded, 2)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/history?tag=OPS", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &series))
+	require.Len(t, series.Points, history.DefaultDays)
+	require.Equal(t, "ops", series.Key)
+	require.Equal(t, history.SourceBackfill, series.Points[0].Source)
+	require.Zero(t, series.Points[0].Insights.Total)
+	require.Equal(t, 1, series.Points[history.DefaultDays-1].Insights.Total)
+}
+
+func TestInsightsHistoryRecordsChangesWithoutOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+	createTask(t, router, `{"title":"Fix alerts"}`)
+
+	resp := performRequest(router, http.MethodGet, "/api/insights/history?interval=month", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var series httpapi.InsightsHistory
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &series))
+	last := series.Points[len(series.Points)-1]
+	require.Equal(t, history.SourceLive, last.Source)
+	require.Equal(t, 1, last.Insights.Total)
+}
+
+func TestInsightsHistoryRejectsInvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	resp := performRequest(router, http.MethodGet, "/api/insights/history?from=yesterday&interval=hour&owner=anna&tag=ops", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	var errBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errBody))
+	require.Len(t, errBody.Errors, 3)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/history?from=2020-01-01&to=2026-01-01", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), history.CodeInvalidRange)
+}
+
+// steppedClock — часы, которые тест переводит вручную.
+type steppedClock struct {
+	now time.Time
+}
+
+func (c *steppedClock) Now() time.Time {
+	return c.now
+}
+
+func TestBurndownTracksProgressAndScopeChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	clock := &steppedClock{now: time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC)}
+	changes := repository.NewMemoryChangeStore()
+	store := history.NewRecordingStore(newInMemoryTaskStore(), changes, clock)
+	router := httpapi.NewRouter(store, httpapi.WithHistory(changes, repository.NewMemorySnapshotStore()), httpapi.WithClock(clock))
+
+	create := func(task domain.Task) *domain.Task {
+		task.CreatedAt = clock.now
+		task.Status = domain.StatusTodo
+		require.NoError(t, store.Create(ctx, &task))
+		return &task
+	}
+	update := func(task *domain.Task) {
+		require.NoError(t, store.Update(ctx, task))
+	}
+
+	migration := create(domain.Task{Title: "Migrate DB", EffortHours: 8, Tags: domain.StringList{"ops"}})
+	alerts := create(domain.Task{Title: "Tune alerts", EffortHours: 4, Tags: domain.StringList{"ops"}})
+	create(domain.Task{Title: "Landing page", EffortHours: 5, Tags: domain.StringList{"web"}})
+	old := create(domain.Task{Title: "Old cleanup", EffortHours: 2, Tags: domain.StringList{"ops"}})
+	complete := func(task *domain.Task) {
+		completed := clock.now
+		task.Status, task.CompletedAt = domain.StatusDone, &completed
+		update(task)
+	}
+	clock.now = time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)
+	complete(old)
+
+	clock.now = time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	complete(migration)
+	clock.now = time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	hotfix := create(domain.Task{Title: "Hotfix", EffortHours: 3, Tags: domain.StringList{"ops"}})
+	clock.now = time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	alerts.EffortHours = 6
+	update(alerts)
+	clock.now = time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC)
+
+	resp := performRequest(router, http.MethodGet, "/api/insights/burndown?from=2026-03-02&to=2026-03-06&tag=ops", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var burndown history.Burndown
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &burndown))
+
+	require.Equal(t, []history.IdealPoint{
+		{Date: "2026-03-02", RemainingHours: 9.6},
+		{Date: "2026-03-03", RemainingHours: 7.2},
+		{Date: "2026-03-04", RemainingHours: 4.8},
+		{Date: "2026-03-05", RemainingHours: 2.4},
+		{Date: "2026-03-06", RemainingHours: 0},
+	}, burndown.Ideal)
+	require.Equal(t, []history.BurndownPoint{
+		{Date: "2026-03-02", RemainingHours: 12, ScopeHours: 12, RemainingTasks: 2, ScopeTasks: 2},
+		{Date: "2026-03-03", RemainingHours: 7, CompletedHours: 8, ScopeHours: 15, RemainingTasks: 2, CompletedTasks: 1, ScopeTasks: 3},
+		{Date: "2026-03-04", RemainingHours: 9, CompletedHours: 8, ScopeHours: 17, RemainingTasks: 2, CompletedTasks: 1, ScopeTasks: 3},
+	}, burndown.Points)
+	require.Equal(t, []history.ScopeChange{
+		{Date: "2026-03-03", TaskID: hotfix.ID, Title: "Hotfix", Kind: history.ScopeAdded, EffortHours: 3},
+		{Date: "2026-03-04", TaskID: alerts.ID, Title: "Tune alerts", Kind: history.ScopeResized, EffortHours: 2},
+	}, burndown.ScopeChanges)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/burndown?from=2026-03-10&to=2026-03-01&status=unknown", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	var errBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errBody))
+	require.Len(t, errBody.Errors, 2)
+}
+
+func TestCumulativeFlowAndTimeInStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := &steppedClock{now: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)}
+	changes := repository.NewMemoryChangeStore()
+	store := history.NewRecordingStore(newInMemoryTaskStore(), changes, clock)
+	router := httpapi.NewRouter(store, httpapi.WithHistory(changes, repository.NewMemorySnapshotStore()), httpapi.WithClock(clock))
+
+	deploy := createTask(t, router, `{"title":"Deploy","owner":"anna"}`)
+	createTask(t, router, `{"title":"Docs","owner":"ivan"}`)
+	setStatus := func(id uint, status string) {
+		resp := performRequest(router, http.MethodPatch, fmt.Sprintf("/api/tasks/%d", id), []byte(`{"status":"`+status+`"}`))
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	}
+	clock.now = time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	setStatus(deploy.ID, "in_progress")
+	clock.now = time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+	setStatus(deploy.ID, "blocked")
+	clock.now = time.Date(2026, 3, 4, 21, 0, 0, 0, time.UTC)
+	setStatus(deploy.ID, "in_progress")
+	clock.now = time.Date(2026, 3, 5, 3, 0, 0, 0, time.UTC)
+
+	resp := performRequest(router, http.MethodGet, "/api/insights/flow?from=2026-03-01&to=2026-03-10", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var flow history.CumulativeFlow
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &flow))
+	require.Equal(t, history.FlowStatuses, flow.Statuses)
+	require.Len(t, flow.Points, 5)
+	require.Equal(t, map[string]int{"todo": 0, "in_progress": 0, "blocked": 0, "done": 0}, flow.Points[0].Counts)
+	require.Equal(t, map[string]int{"todo": 1, "in_progress": 1, "blocked": 0, "done": 0}, flow.Points[1].Counts)
+	require.Equal(t, 1, flow.Points[2].Counts["blocked"])
+	require.Equal(t, 1, flow.Points[3].Counts["in_progress"])
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/flow?owner=ivan&from=2026-03-04", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &flow))
+	require.Len(t, flow.Points, 2)
+	require.Equal(t, 1, flow.Points[1].Counts["todo"])
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/time-in-status?owner=anna", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var stats history.TimeInStatus
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+	require.Len(t, stats.Tasks, 1)
+	require.Len(t, stats.Tasks[0].Transitions, 4)
+	require.Equal(t, map[string]float64{"todo": 6, "in_progress": 24, "blocked": 36}, stats.Tasks[0].Hours)
+	require.Equal(t, history.StatusTime{
+		Status:        "blocked",
+		DurationStats: service.DurationStats{Count: 1, MeanHours: 36, MedianHours: 36, P85Hours: 36, P95Hours: 36},
+		TotalHours:    36,
+	}, stats.Statuses[2])
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/time-in-status?status=paused", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestFlowTimesEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	store := newInMemoryTaskStore()
+	router := httpapi.NewRouter(store, httpapi.WithClock(service.FixedClock{NowValue: now}))
+
+	seed := func(owner string, created, started, completed time.Time) {
+		task := domain.Task{Title: "Task", Status: domain.StatusDone, Priority: domain.PriorityHigh, Owner: owner, CreatedAt: created, StartedAt: &started, CompletedAt: &completed}
+		require.NoError(t, store.Create(ctx, &task))
+	}
+	day := func(d int, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC) }
+	seed("anna", day(1, 9), day(2, 9), day(3, 9))
+	seed("anna", day(1, 9), day(5, 9), day(5, 21))
+	seed("ivan", day(1, 9), day(1, 10), day(1, 12))
+
+	resp := performRequest(router, http.MethodGet, "/api/insights/cycle-time?owner=anna&since=2026-03-02", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var times service.FlowTimes
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &times))
+	require.Equal(t, day(2, 0), times.Since)
+	require.Equal(t, now, times.Until)
+	require.Len(t, times.Points, 2)
+	require.Equal(t, 48.0, times.Points[0].LeadHours)
+	require.Equal(t, 12.0, *times.Points[1].CycleHours)
+	require.Equal(t, 18.0, times.CycleTime.MedianHours)
+	require.Equal(t, 2, times.LeadTime.ByPriority[domain.PriorityHigh].Count)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/cycle-time?since=2026-03-10&until=2026-03-01", nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), service.CodeInvalidWindow)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -19,7 +20,6 @@ import (
 	"devopslabs/internal/transport/httpapi"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
-	"gorm.io/gorm"
 )
 
 const defaultOwner = "unassigned"
@@ -72,6 +72,12 @@ func (s *inMemoryTaskStore) List(_ context.Context, filter repository.TaskFilter
 
 	result := make([]domain.Task, 0, len(s.tasks))
 	for _, task := range s.tasks {
+		if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, task.ID) {
+			continue
+		}
+		if len(filter.ExternalIDs) > 0 && !slices.Contains(filter.ExternalIDs, task.ExternalID) {
+			continue
+		}
 		if len(statusSet) > 0 && !statusSet[strings.ToLower(task.Status)] {
 			continue
 		}
@@ -99,6 +105,9 @@ func (s *inMemoryTaskStore) List(_ context.Context, filter repository.TaskFilter
 				continue
 			}
 		}
+		if filter.Expression != nil && !filter.Expression.Matches(task, filter.Now) {
+			continue
+		}
 
 		result = append(result, task)
 	}
@@ -116,7 +125,7 @@ func (s *inMemoryTaskStore) Get(_ context.Context, id uint) (*domain.Task, error
 
 	task, ok := s.tasks[id]
 	if !ok {
-		return nil, gorm.ErrRecordNotFound
+		return nil, repository.ErrNotFound
 	}
 	copyTask := task
 	return &copyTask, nil
@@ -146,7 +155,7 @@ func (s *inMemoryTaskStore) Update(_ context.Context, task *domain.Task) error {
 	defer s.mu.Unlock()
 
 	if _, exists := s.tasks[task.ID]; !exists {
-		return gorm.ErrRecordNotFound
+		return repository.ErrNotFound
 	}
 
 	task.UpdatedAt = time.Now().UTC()
@@ -158,10 +167,32 @@ func (s *inMemoryTaskStore) Delete(_ context.Context, id uint) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if _, exists := s.tasks[id]; !exists {
+		return repository.ErrNotFound
+	}
 	delete(s.tasks, id)
 	return nil
 }
 
+func (s *inMemoryTaskStore) WithinTransaction(ctx context.Context, fn func(store repository.TaskStore) error) error {
+	s.mu.Lock()
+	snapshot := make(map[uint]domain.Task, len(s.tasks))
+	for id, task := range s.tasks {
+		snapshot[id] = task
+	}
+	nextID := s.nextID
+	s.mu.Unlock()
+
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		s.tasks = snapshot
+		s.nextID = nextID
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
 func setupTestRouter(t *testing.T) (*gin.Engine, service.FixedClock) {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
@@ -176,11 +207,15 @@ func setupTestRouter(t *testing.T) (*gin.Engine, service.FixedClock) {
 	api := r.Group("/api")
 	{
 		api.GET("/tasks", h.List)
+		api.GET("/tasks/export", h.Export)
 		api.GET("/tasks/:id", h.Get)
 		api.POST("/tasks", h.Create)
+		api.POST("/tasks/bulk", h.Bulk)
 		api.PUT("/tasks/:id", h.Update)
+		api.PATCH("/tasks/:id", h.Patch)
 		api.DELETE("/tasks/:id", h.Delete)
 		api.GET("/insights", h.Insights)
+		api.GET("/insights/export", h.ExportInsights)
 	}
 
 	return r, clock
@@ -226,7 +261,7 @@ func TestTaskCRUDFlow(t *testing.T) {
 	require.Equal(t, http.StatusOK, getResp.Code)
 
 	patchBody := []byte(`{"title":"Write report v2","owner":"","effortHours":5,"priority":"critical","dueDate":"2026-02-11T12:00:00Z"}`)
-	patchResp := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), patchBody)
+	patchResp := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), patchBody)
 	require.Equal(t, http.StatusOK, patchResp.Code)
 
 	var patched taskResponse
@@ -238,7 +273,7 @@ func TestTaskCRUDFlow(t *testing.T) {
 	require.NotNil(t, patched.DueDate)
 
 	updateBody := []byte(`{"status":"done","dueDate":null,"tags":["release"]}`)
-	updateResp := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), updateBody)
+	updateResp := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), updateBody)
 	require.Equal(t, http.StatusOK, updateResp.Code)
 
 	var updated taskResponse
@@ -251,7 +286,7 @@ func TestTaskCRUDFlow(t *testing.T) {
 	require.WithinDuration(t, clock.NowValue, *updated.CompletedAt, 0)
 
 	forceUpdate := []byte(`{"status":"todo"}`)
-	forceResp := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID)+"?force=true", forceUpdate)
+	forceResp := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID)+"?force=true", forceUpdate)
 	require.Equal(t, http.StatusOK, forceResp.Code)
 
 	var forced taskResponse
@@ -272,6 +307,13 @@ func TestTaskCRUDFlow(t *testing.T) {
 
 	getResp = performRequest(router, http.MethodGet, "/api/tasks/"+itoa(created.ID), nil)
 	require.Equal(t, http.StatusNotFound, getResp.Code)
+
+	var notFound httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(getResp.Body.Bytes(), &notFound))
+	require.Equal(t, httpapi.CodeNotFound, notFound.Code)
+
+	deleteResp = performRequest(router, http.MethodDelete, "/api/tasks/"+itoa(created.ID), nil)
+	require.Equal(t, http.StatusNotFound, deleteResp.Code)
 }
 
 func TestTaskHandlerErrors(t *testing.T) {
@@ -323,40 +365,77 @@ func TestTaskHandlerErrors(t *testing.T) {
 	var created taskResponse
 	require.NoError(t, json.Unmarshal(create.Body.Bytes(), &created))
 
-	invalidTransition := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"status":"done"}`))
+	invalidTransition := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"status":"done"}`))
 	require.Equal(t, http.StatusBadRequest, invalidTransition.Code)
 
-	badUpdateJSON := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{`))
+	badUpdateJSON := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{`))
 	require.Equal(t, http.StatusBadRequest, badUpdateJSON.Code)
 
 	invalidUpdateID := performRequest(router, http.MethodPut, "/api/tasks/abc", []byte(`{"description":"x"}`))
 	require.Equal(t, http.StatusBadRequest, invalidUpdateID.Code)
 
-	emptyTitleUpdate := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"title":""}`))
+	emptyTitleUpdate := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"title":""}`))
 	require.Equal(t, http.StatusBadRequest, emptyTitleUpdate.Code)
 
-	badUpdateStatus := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"status":"invalid"}`))
+	badUpdateStatus := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"status":"invalid"}`))
 	require.Equal(t, http.StatusBadRequest, badUpdateStatus.Code)
 
-	badUpdatePriority := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"priority":"invalid"}`))
+	badUpdatePriority := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"priority":"invalid"}`))
 	require.Equal(t, http.StatusBadRequest, badUpdatePriority.Code)
 
-	badUpdateEffort := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"effortHours":-2}`))
+	badUpdateEffort := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"effortHours":-2}`))
 	require.Equal(t, http.StatusBadRequest, badUpdateEffort.Code)
 
-	badUpdateTitle := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"title":"`+longTitle+`"}`))
+	badUpdateTitle := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"title":"`+longTitle+`"}`))
 	require.Equal(t, http.StatusBadRequest, badUpdateTitle.Code)
 
-	badUpdateDate := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"dueDate":"bad"}`))
+	badUpdateDate := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"dueDate":"bad"}`))
 	require.Equal(t, http.StatusBadRequest, badUpdateDate.Code)
 
-	badUpdateTags := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"tags":"oops"}`))
+	badUpdateTags := performRequest(router, http.MethodPatch, "/api/tasks/"+itoa(created.ID), []byte(`{"tags":"oops"}`))
 	require.Equal(t, http.StatusBadRequest, badUpdateTags.Code)
 
-	updateMissing := performRequest(router, http.MethodPut, "/api/tasks/999", []byte(`{"description":"x"}`))
+	updateMissing := performRequest(router, http.MethodPut, "/api/tasks/999", []byte(`{"title":"x","description":"x"}`))
 	require.Equal(t, http.StatusNotFound, updateMissing.Code)
 }
 
+func TestErrorBodiesAreLocalized(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	defaultResp := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":"X","status":"weird"}`))
+	require.Equal(t, http.StatusBadRequest, defaultResp.Code)
+
+	var ruBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(defaultResp.Body.Bytes(), &ruBody))
+	require.Equal(t, service.CodeInvalidStatus, ruBody.Code)
+	require.Equal(t, "status", ruBody.Field)
+	require.Equal(t, "некорректный статус: weird", ruBody.Message)
+	require.Equal(t, "weird", ruBody.Details["value"])
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{"title":"X","status":"weird"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	enResp := httptest.NewRecorder()
+	router.ServeHTTP(enResp, req)
+	require.Equal(t, http.StatusBadRequest, enResp.Code)
+
+	var enBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(enResp.Body.Bytes(), &enBody))
+	require.Equal(t, service.CodeInvalidStatus, enBody.Code)
+	require.Equal(t, "invalid status: weird", enBody.Message)
+
+	missingTitle := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":" "}`))
+	var titleBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(missingTitle.Body.Bytes(), &titleBody))
+	require.Equal(t, service.CodeTitleRequired, titleBody.Code)
+	require.Equal(t, "title", titleBody.Field)
+
+	badID := performRequest(router, http.MethodGet, "/api/tasks/abc", nil)
+	var idBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(badID.Body.Bytes(), &idBody))
+	require.Equal(t, httpapi.CodeInvalidID, idBody.Code)
+}
+
 func TestRouterHealthAndCORS(t *testing.T) {
 	taskStore := newInMemoryTaskStore()
 	router := httpapi.NewRouter(taskStore)
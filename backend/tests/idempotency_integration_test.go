@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"devopslabs/internal/repository"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func setupIdempotentRouter(t *testing.T, store repository.IdempotencyStore) (*gin.Engine, *inMemoryTaskStore) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	clock := service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+	taskStore := newInMemoryTaskStore()
+	h := httpapi.NewTaskHandler(taskStore, clock)
+	idempotent := httpapi.Idempotency(store, time.Hour, clock)
+
+	r := gin.New()
+	r.POST("/api/tasks", idempotent, h.Create)
+	r.POST("/api/tasks/bulk", idempotent, h.Bulk)
+	r.POST("/api/fail", idempotent, func(c *gin.Context) {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": "internal"})
+	})
+	return r, taskStore
+}
+
+func performKeyed(router *gin.Engine, path string, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(httpapi.IdempotencyKeyHeader, key)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotentCreateReplaysResponse(t *testing.T) {
+	router, taskStore := setupIdempotentRouter(t, repository.NewMemoryIdempotencyStore())
+
+	first := performKeyed(router, "/api/tasks", "mobile-1", `{"title":"Sync","priority":"high"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Empty(t, first.Header().Get(httpapi.IdempotentReplayedHeader))
+
+	retry := performKeyed(router, "/api/tasks", "mobile-1", `{ "priority": "high", "title": "Sync" }`)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	require.Equal(t, "true", retry.Header().Get(httpapi.IdempotentReplayedHeader))
+	require.JSONEq(t, first.Body.String(), retry.Body.String())
+
+	tasks, err := taskStore.List(context.Background(), repository.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	reused := performKeyed(router, "/api/tasks", "mobile-1", `{"title":"Other"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+
+	var reusedBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(reused.Body.Bytes(), &reusedBody))
+	require.Equal(t, httpapi.CodeIdempotencyKeyReused, reusedBody.Code)
+
+	otherRoute := performKeyed(router, "/api/tasks/bulk", "mobile-1", `{"ids":[1],"operation":"set_owner","owner":"anna"}`)
+	require.Equal(t, http.StatusOK, otherRoute.Code)
+
+	invalid := performKeyed(router, "/api/tasks", "mobile-2", `{"title":""}`)
+	require.Equal(t, http.StatusBadRequest, invalid.Code)
+	replayedInvalid := performKeyed(router, "/api/tasks", "mobile-2", `{"title":""}`)
+	require.Equal(t, http.StatusBadRequest, replayedInvalid.Code)
+	require.Equal(t, "true", replayedInvalid.Header().Get(httpapi.IdempotentReplayedHeader))
+
+	tooLong := performKeyed(router, "/api/tasks", string(bytes.Repeat([]byte("k"), 256)), `{"title":"X"}`)
+	require.Equal(t, http.StatusBadRequest, tooLong.Code)
+}
+
+func TestIdempotencyInProgressAndServerErrors(t *testing.T) {
+	router, _ := setupIdempotentRouter(t, repository.NewMemoryIdempotencyStore())
+
+	failed := performKeyed(router, "/api/fail", "retry-me", `{}`)
+	require.Equal(t, http.StatusInternalServerError, failed.Code)
+	again := performKeyed(router, "/api/fail", "retry-me", `{}`)
+	require.Equal(t, http.StatusInternalServerError, again.Code)
+	require.Empty(t, again.Header().Get(httpapi.IdempotentReplayedHeader))
+
+	clock := service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := gin.New()
+	slow.POST("/api/slow", httpapi.Idempotency(repository.NewMemoryIdempotencyStore(), time.Hour, clock), func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- performKeyed(slow, "/api/slow", "slow", `{"title":"Slow"}`)
+	}()
+	<-started
+
+	duplicate := performKeyed(slow, "/api/slow", "slow", `{"title":"Slow"}`)
+	require.Equal(t, http.StatusConflict, duplicate.Code)
+	require.Equal(t, "1", duplicate.Header().Get("Retry-After"))
+
+	close(release)
+	require.Equal(t, http.StatusCreated, (<-done).Code)
+
+	replayed := performKeyed(slow, "/api/slow", "slow", `{"title":"Slow"}`)
+	require.Equal(t, http.StatusCreated, replayed.Code)
+	require.Equal(t, "true", replayed.Header().Get(httpapi.IdempotentReplayedHeader))
+}
+
+func TestIdempotentConcurrentDuplicates(t *testing.T) {
+	router, taskStore := setupIdempotentRouter(t, repository.NewMemoryIdempotencyStore())
+
+	const attempts = 8
+	codes := make([]int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			codes[index] = performKeyed(router, "/api/tasks", "burst", `{"title":"Burst"}`).Code
+		}(i)
+	}
+	wg.Wait()
+
+	for _, code := range codes {
+		require.Contains(t, []int{http.StatusCreated, http.StatusConflict}, code)
+	}
+
+	tasks, err := taskStore.List(context.Background(), repository.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+}
+
+func TestIdempotencyReleasesKeyAfterClientDisconnect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB, PreferSimpleProtocol: true}), &gorm.Config{})
+	require.NoError(t, err)
+
+	clock := service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+	var disconnect context.CancelFunc
+	calls := 0
+	router := gin.New()
+	router.POST("/api/tasks", httpapi.Idempotency(repository.NewGormIdempotencyStore(db), time.Hour, clock), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			// Клиент отключился, пока запрос обрабатывался.
+			disconnect()
+			c.JSON(http.StatusServiceUnavailable, gin.H{"code": "unavailable"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	expectExec := func(query string, affected int64) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, affected))
+		mock.ExpectCommit()
+	}
+	expectExec(`DELETE FROM "idempotency_records"`, 0)
+	expectExec(`DELETE FROM "idempotency_records"`, 0)
+	expectExec(`INSERT INTO "idempotency_records" .* ON CONFLICT DO NOTHING`, 1)
+	expectExec(`DELETE FROM "idempotency_records"`, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	disconnect = cancel
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", bytes.NewReader([]byte(`{"title":"Sync"}`))).WithContext(ctx)
+	req.Header.Set(httpapi.IdempotencyKeyHeader, "mobile-1")
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, req)
+	require.Equal(t, http.StatusServiceUnavailable, first.Code)
+
+	expectExec(`DELETE FROM "idempotency_records"`, 0)
+	expectExec(`INSERT INTO "idempotency_records" .* ON CONFLICT DO NOTHING`, 1)
+	expectExec(`UPDATE "idempotency_records"`, 1)
+	retry := performKeyed(router, "/api/tasks", "mobile-1", `{"title":"Sync"}`)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	require.Equal(t, 2, calls)
+}
+
+func TestIdempotencyReleasesKeyAfterPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+	calls := 0
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.POST("/api/tasks", httpapi.Idempotency(repository.NewMemoryIdempotencyStore(), time.Hour, clock), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("обработчик упал")
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	require.Equal(t, http.StatusInternalServerError, performKeyed(router, "/api/tasks", "panic", `{}`).Code)
+	require.Equal(t, http.StatusCreated, performKeyed(router, "/api/tasks", "panic", `{}`).Code)
+}
+
+func TestIdempotencyRejectsOversizeBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := service.FixedClock{NowValue: time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC)}
+	calls := 0
+	router := gin.New()
+	router.POST("/api/tasks/import", httpapi.Idempotency(repository.NewMemoryIdempotencyStore(), time.Hour, clock), func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusOK)
+	})
+
+	oversize := performKeyed(router, "/api/tasks/import", "big", string(bytes.Repeat([]byte("x"), 10<<20+1)))
+	require.Equal(t, http.StatusRequestEntityTooLarge, oversize.Code)
+	require.Contains(t, oversize.Body.String(), `"code":"body_too_large"`)
+	require.Zero(t, calls)
+
+	atLimit := performKeyed(router, "/api/tasks/import", "big", string(bytes.Repeat([]byte("x"), 10<<20)))
+	require.Equal(t, http.StatusOK, atLimit.Code)
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"devopslabs/internal/importer"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func importTasks(t *testing.T, router *gin.Engine, query string, contentType string, body string) httpapi.ImportResponse {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/import"+query, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var report httpapi.ImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return report
+}
+
+func TestImportCSVWithDryRunAndUpsert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	csv := "Key,Summary,Status,Priority,Assignee,Labels,Estimate,Due,Epic\n" +
+		"OPS-1,Ship CI,In_Progress,high,alice,ci;release,3,2026-02-10,Platform\n" +
+		"OPS-2,Rotate keys,someday,urgent,bob,,2.5,tomorrow,Security\n" +
+		"OPS-3,Write docs,done,low,,docs,,,\n"
+
+	dryRun := importTasks(t, router, "?dryRun=true", "text/csv", csv)
+	require.True(t, dryRun.DryRun)
+	require.Equal(t, importer.FormatCSV, dryRun.Format)
+	require.Equal(t, []string{"Epic"}, dryRun.IgnoredColumns)
+	require.Equal(t, 2, dryRun.Created)
+	require.Equal(t, 1, dryRun.Failed)
+
+	failed := dryRun.Rows[1]
+	require.Equal(t, 3, failed.Row)
+	require.Equal(t, "OPS-2", failed.ExternalID)
+	require.Equal(t, importer.ResultFailed, failed.Result)
+	require.Equal(t, httpapi.CodeValidation, failed.Error.Code)
+	require.Len(t, failed.Error.Errors, 4)
+
+	list := performRequest(router, http.MethodGet, "/api/tasks", nil)
+	require.JSONEq(t, `[]`, list.Body.String())
+
+	report := importTasks(t, router, "", "text/csv", csv)
+	require.False(t, report.DryRun)
+	require.Equal(t, 2, report.Created)
+	require.Equal(t, "OPS-1", report.Rows[0].Task.ExternalID)
+	require.Equal(t, "in_progress", report.Rows[0].Task.Status)
+	require.NotNil(t, report.Rows[0].Task.StartedAt)
+	require.Equal(t, []string{"ci", "release"}, []string(report.Rows[0].Task.Tags))
+	require.Equal(t, service.DefaultOwner, report.Rows[2].Task.Owner)
+
+	update := importTasks(t, router, "?map=Summary:description,Title:title", "text/csv", "Key,Title,Summary,Status\nOPS-1,Ship CI v2,Now with caching,done\n")
+	require.Equal(t, 1, update.Updated)
+	require.Equal(t, "Now with caching", update.Rows[0].Task.Description)
+	require.Equal(t, "done", update.Rows[0].Task.Status)
+
+	var tasks []taskResponse
+	require.NoError(t, json.Unmarshal(performRequest(router, http.MethodGet, "/api/tasks", nil).Body.Bytes(), &tasks))
+	require.Len(t, tasks, 2)
+}
+
+func TestImportJSONAndRejectedFiles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	report := importTasks(t, router, "", "application/json", `[
+		{"externalId":"GH-7","title":"Fix flaky test","priority":"critical","tags":["ci"],"effortHours":2,"dueDate":"2026-03-01T09:00:00Z"},
+		{"externalId":"GH-7","title":"Duplicate"}
+	]`)
+	require.Equal(t, importer.FormatJSON, report.Format)
+	require.Equal(t, 1, report.Created)
+	require.Equal(t, importer.CodeDuplicateExternal, report.Rows[1].Error.Code)
+	require.Equal(t, "external ID GH-7 already appears in row 1", report.Rows[1].Error.Message)
+
+	resp := performRequest(router, http.MethodPost, "/api/tasks/import?format=xml&map=Summary", []byte("x"))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	var body httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 2)
+
+	resp = performRequest(router, http.MethodPost, "/api/tasks/import", []byte(`{"title":"not an array"}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Equal(t, importer.CodeMalformed, body.Code)
+}
+
+func TestImportJiraExportWithMappingFile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	file, err := writer.CreateFormFile("file", "jira.xml")
+	require.NoError(t, err)
+	_, err = file.Write([]byte(`<rss><channel>
+		<item><key>OPS-7</key><summary>Upgrade Postgres</summary><status>Ready for QA</status><priority>Major</priority>
+			<assignee username="j.doe">John Doe</assignee><labels><label>db</label></labels></item>
+	</channel></rss>`))
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteField("mapping", `{"statuses": {"Ready for QA": "in_progress"}, "users": {"j.doe": "john"}}`))
+	require.NoError(t, writer.Close())
+
+	report := importTasks(t, router, "?source=jira", writer.FormDataContentType(), form.String())
+	require.Equal(t, importer.SourceJira, report.Source)
+	require.Empty(t, report.Format)
+	require.Equal(t, 1, report.Created)
+	task := report.Rows[0].Task
+	require.Equal(t, "jira:OPS-7", task.ExternalID)
+	require.Equal(t, "in_progress", task.Status)
+	require.Equal(t, "high", task.Priority)
+	require.Equal(t, "john", task.Owner)
+
+	resp := performRequest(router, http.MethodPost, "/api/tasks/import?source=asana", []byte("x"))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	var body httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Equal(t, importer.CodeInvalidSource, body.Code)
+}
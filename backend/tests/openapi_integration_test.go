@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+var routeParamPattern = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+	doc := httpapi.OpenAPIDocument()
+
+	described := 0
+	for _, route := range router.Routes() {
+		path := routeParamPattern.ReplaceAllString(route.Path, "{$1}")
+		operations, ok := doc.Paths[path]
+		require.Truef(t, ok, "маршрут %s %s отсутствует в спецификации", route.Method, route.Path)
+		_, ok = operations[strings.ToLower(route.Method)]
+		require.Truef(t, ok, "метод %s %s отсутствует в спецификации", route.Method, route.Path)
+		described++
+	}
+
+	total := 0
+	for _, operations := range doc.Paths {
+		total += len(operations)
+	}
+	require.Equal(t, described, total, "в спецификации есть маршруты, которых нет в роутере")
+}
+
+func TestOpenAPIEndpointServesDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	require.Equal(t, "3.1.0", doc["openapi"])
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	for _, name := range []string{"TaskResponse", "TaskCreateRequest", "TaskMergePatch", "ErrorResponse", "BulkRequest", "BulkResponse", "Insights", "Operation"} {
+		require.Contains(t, schemas, name)
+	}
+
+	task := schemas["TaskResponse"].(map[string]any)["properties"].(map[string]any)
+	require.ElementsMatch(t, []any{"blocked", "done", "in_progress", "todo"}, task["status"].(map[string]any)["enum"])
+
+	create := schemas["TaskCreateRequest"].(map[string]any)
+	require.Equal(t, []any{"title"}, create["required"])
+}
+
+func TestDocsPageIsSelfContained(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/docs", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, w.Body.String(), "openapi.json")
+	require.NotContains(t, w.Body.String(), "https://")
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func performPatch(router *gin.Engine, path string, contentType string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, path, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", contentType)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeTask(t *testing.T, resp *httptest.ResponseRecorder) taskResponse {
+	t.Helper()
+
+	var task taskResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &task))
+	return task
+}
+
+func TestPutReplacesWholeTask(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	created := createTask(t, router, `{"title":"Release","description":"notes","priority":"high","owner":"anna","effortHours":5,"dueDate":"2026-02-10T12:00:00Z","tags":["release"]}`)
+
+	resp := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"title":"Release v2","status":"in_progress"}`))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	replaced := decodeTask(t, resp)
+	require.Equal(t, "Release v2", replaced.Title)
+	require.Equal(t, "", replaced.Description)
+	require.Equal(t, domain.StatusInProgress, replaced.Status)
+	require.Equal(t, domain.PriorityMedium, replaced.Priority)
+	require.Equal(t, defaultOwner, replaced.Owner)
+	require.Equal(t, 1, replaced.EffortHours)
+	require.Nil(t, replaced.DueDate)
+	require.Empty(t, replaced.Tags)
+	require.NotNil(t, replaced.StartedAt)
+
+	missingTitle := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"description":"only"}`))
+	require.Equal(t, http.StatusBadRequest, missingTitle.Code)
+}
+
+func TestMergePatch(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	created := createTask(t, router, `{"title":"Release","owner":"anna","dueDate":"2026-02-10T12:00:00Z","tags":["release"]}`)
+	path := "/api/tasks/" + itoa(created.ID)
+
+	resp := performPatch(router, path, httpapi.MediaTypeMergePatch, `{"priority":"critical","dueDate":null,"owner":null}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	patched := decodeTask(t, resp)
+	require.Equal(t, "Release", patched.Title)
+	require.Equal(t, domain.PriorityCritical, patched.Priority)
+	require.Nil(t, patched.DueDate)
+	require.Equal(t, defaultOwner, patched.Owner)
+	require.Equal(t, []string{"release"}, patched.Tags)
+
+	unknown := performPatch(router, path, httpapi.MediaTypeMergePatch, `{"id":7}`)
+	require.Equal(t, http.StatusBadRequest, unknown.Code)
+
+	var unknownBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(unknown.Body.Bytes(), &unknownBody))
+	require.Equal(t, httpapi.CodePatchUnknownField, unknownBody.Code)
+	require.Equal(t, "id", unknownBody.Field)
+
+	invalid := performPatch(router, path, httpapi.MediaTypeMergePatch, `{"title":null}`)
+	require.Equal(t, http.StatusBadRequest, invalid.Code)
+
+	unsupported := performPatch(router, path, "text/plain", `title=x`)
+	require.Equal(t, http.StatusUnsupportedMediaType, unsupported.Code)
+	require.Contains(t, unsupported.Header().Get("Accept-Patch"), httpapi.MediaTypeJSONPatch)
+
+	missing := performPatch(router, "/api/tasks/999", httpapi.MediaTypeMergePatch, `{}`)
+	require.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestJSONPatch(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	created := createTask(t, router, `{"title":"Deploy","tags":["ci","infra"]}`)
+	path := "/api/tasks/" + itoa(created.ID)
+
+	ops := `[
+		{"op":"test","path":"/status","value":"todo"},
+		{"op":"replace","path":"/status","value":"in_progress"},
+		{"op":"add","path":"/tags/-","value":"Release"},
+		{"op":"remove","path":"/tags/0"},
+		{"op":"add","path":"/dueDate","value":"2026-02-12T09:00:00Z"}
+	]`
+	resp := performPatch(router, path, httpapi.MediaTypeJSONPatch, ops)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	patched := decodeTask(t, resp)
+	require.Equal(t, domain.StatusInProgress, patched.Status)
+	require.Equal(t, []string{"infra", "release"}, patched.Tags)
+	require.NotNil(t, patched.DueDate)
+	require.NotNil(t, patched.StartedAt)
+
+	failedTest := performPatch(router, path, httpapi.MediaTypeJSONPatch, `[{"op":"test","path":"/status","value":"todo"},{"op":"remove","path":"/dueDate"}]`)
+	require.Equal(t, http.StatusConflict, failedTest.Code)
+
+	var testBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(failedTest.Body.Bytes(), &testBody))
+	require.Equal(t, httpapi.CodePatchTestFailed, testBody.Code)
+
+	unchanged := decodeTask(t, performRequest(router, http.MethodGet, path, nil))
+	require.NotNil(t, unchanged.DueDate)
+
+	badOps := performPatch(router, path, httpapi.MediaTypeJSONPatch, `{"op":"add"}`)
+	require.Equal(t, http.StatusBadRequest, badOps.Code)
+
+	badPath := performPatch(router, path, httpapi.MediaTypeJSONPatch, `[{"op":"remove","path":"/tags/9"}]`)
+	require.Equal(t, http.StatusBadRequest, badPath.Code)
+
+	var pathBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(badPath.Body.Bytes(), &pathBody))
+	require.Equal(t, httpapi.CodeInvalidPatch, pathBody.Code)
+	require.Equal(t, "/tags/9", pathBody.Details["path"])
+
+	onlyFinalStatus := performPatch(router, path, httpapi.MediaTypeJSONPatch, `[{"op":"replace","path":"/status","value":"todo"},{"op":"replace","path":"/status","value":"done"}]`)
+	require.Equal(t, http.StatusOK, onlyFinalStatus.Code)
+
+	readOnly := performPatch(router, path, httpapi.MediaTypeJSONPatch, `[{"op":"add","path":"/createdAt","value":"2020-01-01T00:00:00Z"}]`)
+	require.Equal(t, http.StatusBadRequest, readOnly.Code)
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"devopslabs/internal/worktime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreExplanationUsesPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store := newInMemoryTaskStore()
+	dueDate := now.Add(60 * time.Hour)
+	task := domain.Task{Title: "Deploy", Status: domain.StatusTodo, Priority: domain.PriorityCritical, EffortHours: 10, DueDate: &dueDate}
+	require.NoError(t, store.Create(ctx, &task))
+
+	policy, err := service.ParsePolicy([]byte(`{"priorityPoints":{"critical":100},"atRiskHours":72}`))
+	require.NoError(t, err)
+	router := httpapi.NewRouter(store, httpapi.WithClock(service.FixedClock{NowValue: now}), httpapi.WithPolicy(policy))
+
+	resp := performRequest(router, http.MethodGet, "/api/tasks/1", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var got httpapi.TaskResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+	require.Equal(t, 106.0, got.Score)
+	require.Equal(t, service.RiskAtRisk, got.Risk)
+
+	resp = performRequest(router, http.MethodGet, "/api/tasks/1/score", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var breakdown service.ScoreBreakdown
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &breakdown))
+	require.Equal(t, got.Score, breakdown.Score)
+	require.Equal(t, []service.ScoreTerm{
+		{Term: service.TermPriority, Input: domain.PriorityCritical, Points: 100},
+		{Term: service.TermDue, Input: "<=96h", Points: 5},
+		{Term: service.TermEffort, Input: "10", Points: 1},
+	}, breakdown.Terms)
+	require.Equal(t, 72.0, breakdown.Policy.AtRiskHours)
+
+	resp = performRequest(router, http.MethodGet, "/api/tasks/99/score", nil)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = performRequest(router, http.MethodGet, "/api/policy", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var current service.Policy
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &current))
+	require.Equal(t, policy, current)
+
+	// Без WithPolicy действуют исходные правила.
+	resp = performRequest(httpapi.NewRouter(store, httpapi.WithClock(service.FixedClock{NowValue: now})), http.MethodGet, "/api/tasks/1/score", nil)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &breakdown))
+	require.Equal(t, 46.0, breakdown.Score)
+}
+
+func TestWorkingCalendarAffectsRiskAndFlowTimes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	// Суббота.
+	now := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+	store := newInMemoryTaskStore()
+	dueDate := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	open := domain.Task{Title: "Release", Status: domain.StatusTodo, Priority: domain.PriorityMedium, Owner: "anna", DueDate: &dueDate}
+	require.NoError(t, store.Create(ctx, &open))
+	startedAt := time.Date(2026, 3, 5, 17, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, 3, 6, 10, 0, 0, 0, time.UTC)
+	done := domain.Task{Title: "Prepare", Status: domain.StatusDone, Priority: domain.PriorityMedium, Owner: "anna", StartedAt: &startedAt, CompletedAt: &completedAt}
+	require.NoError(t, store.Create(ctx, &done))
+
+	calendars, err := worktime.Parse([]byte(`{"default": {"timeZone": "UTC", "holidays": ["2026-03-09"]}}`), "")
+	require.NoError(t, err)
+	policy := service.DefaultPolicy()
+	policy.Calendars = calendars
+	router := httpapi.NewRouter(store, httpapi.WithClock(service.FixedClock{NowValue: now}), httpapi.WithPolicy(policy))
+
+	// До срока в понедельник-праздник рабочих часов нет: выходные не делают
+	// задачу близкой к сроку, а высшая полоса остаётся за просроченными.
+	resp := performRequest(router, http.MethodGet, "/api/tasks/1/score", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var breakdown service.ScoreBreakdown
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &breakdown))
+	require.Equal(t, 0.0, *breakdown.HoursUntilDue)
+	require.Equal(t, service.RiskOnTrack, breakdown.Risk)
+	require.Equal(t, service.ScoreTerm{Term: service.TermDue, Input: "<=48h", Points: 10}, breakdown.Terms[1])
+
+	resp = performRequest(router, http.MethodGet, "/api/insights/cycle-time?since=2026-03-01", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var flow service.FlowTimes
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &flow))
+	require.Len(t, flow.Points, 1)
+	require.Equal(t, 2.0, *flow.Points[0].CycleHours)
+}
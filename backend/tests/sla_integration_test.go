@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/events"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/grpcapi/taskpb"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func slaTestPolicy(t *testing.T) service.Policy {
+	t.Helper()
+	policy, err := service.ParsePolicy([]byte(`{"sla": {"targets": {"critical": {"responseHours": 1, "resolveHours": 8}}}}`))
+	require.NoError(t, err)
+	return policy
+}
+
+func TestSLAInTaskResponseAndInsights(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	store := newInMemoryTaskStore()
+	for _, task := range []domain.Task{
+		{Title: "Outage", Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: now.Add(-2 * time.Hour)},
+		{Title: "Alert", Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: now.Add(-50 * time.Minute)},
+		{Title: "Docs", Status: domain.StatusTodo, Priority: domain.PriorityLow, CreatedAt: now.Add(-2 * time.Hour)},
+	} {
+		require.NoError(t, store.Create(ctx, &task))
+	}
+	router := httpapi.NewRouter(store, httpapi.WithClock(service.FixedClock{NowValue: now}), httpapi.WithPolicy(slaTestPolicy(t)))
+
+	resp := performRequest(router, http.MethodGet, "/api/tasks/1", nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var outage httpapi.TaskResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &outage))
+	require.True(t, outage.SLA.Breached)
+	require.Equal(t, -1.0, outage.SLA.Response.RemainingHours)
+	require.Equal(t, service.SLABreached, outage.SLA.Response.State)
+	require.Equal(t, 6.0, outage.SLA.Resolve.RemainingHours)
+
+	resp = performRequest(router, http.MethodGet, "/api/tasks/3", nil)
+	require.NotContains(t, resp.Body.String(), `"sla"`)
+
+	resp = performRequest(router, http.MethodGet, "/api/insights", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var insights service.Insights
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &insights))
+	require.Equal(t, 1, insights.SLABreached)
+	require.Equal(t, 1, insights.SLAImminent)
+}
+
+func TestGRPCWatchReceivesSLAEvents(t *testing.T) {
+	policy := slaTestPolicy(t)
+	fixture := setupGRPCWithPolicy(t, &policy)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Монитор запущен за час до проверки, когда цель ещё не была нарушена: о
+	// нарушениях до запуска он не сообщает.
+	monitorClock := &service.FixedClock{NowValue: fixture.clock.NowValue.Add(-time.Hour)}
+	monitor := events.NewSLAMonitor(fixture.store, fixture.bus, monitorClock, &policy)
+	monitorClock.NowValue = fixture.clock.NowValue
+	outage := domain.Task{Title: "Outage", Status: domain.StatusTodo, Priority: domain.PriorityCritical, CreatedAt: fixture.clock.NowValue.Add(-2 * time.Hour)}
+	require.NoError(t, fixture.store.Create(ctx, &outage))
+
+	stream, err := fixture.client.WatchTasks(ctx, &taskpb.WatchTasksRequest{IncludeSnapshot: true})
+	require.NoError(t, err)
+	snapshot, err := stream.Recv()
+	require.NoError(t, err)
+	require.True(t, snapshot.GetTask().GetSla().GetBreached())
+
+	count, err := monitor.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, taskpb.TaskEvent_TYPE_SLA_BREACHED, event.GetType())
+	require.Equal(t, service.SLAResponse, event.GetSlaTarget())
+	require.Equal(t, uint32(outage.ID), event.GetTaskId())
+	require.Equal(t, service.SLABreached, event.GetTask().GetSla().GetResponse().GetState())
+
+	insights, err := fixture.client.GetInsights(ctx, &taskpb.GetInsightsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), insights.GetSlaBreached())
+}
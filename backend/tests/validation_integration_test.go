@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeError(t *testing.T, body []byte) httpapi.ErrorResponse {
+	t.Helper()
+	var errBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &errBody))
+	return errBody
+}
+
+func errorCodes(errBody httpapi.ErrorResponse) map[string]string {
+	codes := make(map[string]string, len(errBody.Errors))
+	for _, item := range errBody.Errors {
+		codes[item.Field] = item.Code
+	}
+	return codes
+}
+
+func TestValidationReportsAllFieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	body := `{"title":"X","status":"weird","priority":"p0","effortHours":500,"dueDate":"soon","tags":["ok",1],"extra":true}`
+	resp := performRequest(router, http.MethodPost, "/api/tasks", []byte(body))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	errBody := decodeError(t, resp.Body.Bytes())
+	require.Equal(t, httpapi.CodeValidation, errBody.Code)
+	require.Equal(t, map[string]string{
+		"status":      service.CodeInvalidStatus,
+		"priority":    service.CodeInvalidPriority,
+		"effortHours": service.CodeInvalidEffort,
+		"dueDate":     service.CodeInvalidDueDate,
+		"tags.1":      service.CodeInvalidTags,
+		"extra":       httpapi.CodeUnknownField,
+	}, errorCodes(errBody))
+
+	domainOnly := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":" ","owner":"alex","tags":["`+strings.Repeat("x", 30)+`"]}`))
+	require.Equal(t, http.StatusBadRequest, domainOnly.Code)
+	require.Equal(t, map[string]string{
+		"title":  service.CodeTitleRequired,
+		"tags.0": service.CodeTagTooLong,
+	}, errorCodes(decodeError(t, domainOnly.Body.Bytes())))
+}
+
+func TestValidationSingleErrorKeepsTopLevelCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	resp := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":"X","owner":"alex","unknown":1}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	errBody := decodeError(t, resp.Body.Bytes())
+	require.Equal(t, httpapi.CodeUnknownField, errBody.Code)
+	require.Equal(t, "unknown", errBody.Field)
+	require.Equal(t, "неизвестное поле unknown", errBody.Message)
+	require.Len(t, errBody.Errors, 1)
+
+	typeResp := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":42}`))
+	typeBody := decodeError(t, typeResp.Body.Bytes())
+	require.Equal(t, httpapi.CodeInvalidType, typeBody.Code)
+	require.Equal(t, "title", typeBody.Field)
+
+	notObject := performRequest(router, http.MethodPost, "/api/tasks", []byte(`[]`))
+	require.Equal(t, http.StatusBadRequest, notObject.Code)
+	require.Equal(t, httpapi.CodeInvalidType, decodeError(t, notObject.Body.Bytes()).Code)
+
+	trailing := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":"X"} {}`))
+	require.Equal(t, httpapi.CodeInvalidBody, decodeError(t, trailing.Body.Bytes()).Code)
+}
+
+func TestValidationRejectsOversizedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	body := `{"title":"X","description":"` + strings.Repeat("a", 1<<20) + `"}`
+	resp := performRequest(router, http.MethodPost, "/api/tasks", []byte(body))
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	require.Equal(t, httpapi.CodeBodyTooLarge, decodeError(t, resp.Body.Bytes()).Code)
+
+	created := createTask(t, router, `{"title":"Target"}`)
+	patch := performPatch(router, "/api/tasks/"+itoa(created.ID), httpapi.MediaTypeMergePatch, body)
+	require.Equal(t, http.StatusRequestEntityTooLarge, patch.Code)
+}
+
+func TestValidationAppliesToPatchedDocument(t *testing.T) {
+	router, _ := setupTestRouter(t)
+	created := createTask(t, router, `{"title":"Target"}`)
+
+	resp := performPatch(router, "/api/tasks/"+itoa(created.ID), httpapi.MediaTypeMergePatch, `{"title":"","priority":"p0","id":5}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Equal(t, map[string]string{
+		"title":    service.CodeTitleRequired,
+		"priority": service.CodeInvalidPriority,
+		"id":       httpapi.CodePatchUnknownField,
+	}, errorCodes(decodeError(t, resp.Body.Bytes())))
+}
+
+func TestBulkValidationReportsAllErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	resp := performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(`{"operation":"set_status","filter":{"status":"weird","color":"red"}}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Equal(t, map[string]string{
+		"filter.color": httpapi.CodeUnknownField,
+		"status":       httpapi.CodeBulkValueRequired,
+	}, errorCodes(decodeError(t, resp.Body.Bytes())))
+
+	listResp := performRequest(router, http.MethodGet, "/api/tasks?status=weird&priority=p0", nil)
+	require.Equal(t, http.StatusBadRequest, listResp.Code)
+	require.Equal(t, map[string]string{
+		"status":   service.CodeInvalidStatus,
+		"priority": service.CodeInvalidPriority,
+	}, errorCodes(decodeError(t, listResp.Body.Bytes())))
+}
+
+func TestValidationAcceptsValuesTheDomainNormalizes(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	created := createTask(t, router, `{"title":"Defaults","status":"","priority":" HIGH "}`)
+	require.Equal(t, domain.StatusTodo, created.Status)
+	require.Equal(t, domain.PriorityHigh, created.Priority)
+
+	resp := performRequest(router, http.MethodPut, "/api/tasks/"+itoa(created.ID), []byte(`{"title":"Defaults","status":"In_Progress","priority":""}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	updated := decodeTask(t, resp)
+	require.Equal(t, domain.StatusInProgress, updated.Status)
+	require.Equal(t, domain.PriorityMedium, updated.Priority)
+
+	resp = performRequest(router, http.MethodPost, "/api/tasks/bulk", []byte(`{"operation":" SET_STATUS ","ids":[`+itoa(created.ID)+`],"status":"DONE"}`))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
+func TestValidationCountsTitleLengthInCharacters(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	title := strings.Repeat("я", service.MaxTitleLength)
+	created := createTask(t, router, `{"title":"`+title+`"}`)
+	require.Equal(t, title, created.Title)
+
+	resp := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":"`+title+`я"}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Equal(t, service.CodeTitleTooLong, decodeError(t, resp.Body.Bytes()).Code)
+}
+
+func TestValidationEffortHours(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	created := createTask(t, router, `{"title":"Default effort","effortHours":null}`)
+	require.Equal(t, service.DefaultEffortHours, created.EffortHours)
+
+	resp := performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":"X","effortHours":-1}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	errBody := decodeError(t, resp.Body.Bytes())
+	require.Equal(t, service.CodeInvalidEffort, errBody.Code)
+	require.Equal(t, "effortHours должен быть от 1 до 200", errBody.Message)
+
+	resp = performRequest(router, http.MethodPost, "/api/tasks", []byte(`{"title":"X","effortHours":"many"}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	errBody = decodeError(t, resp.Body.Bytes())
+	require.Equal(t, httpapi.CodeInvalidType, errBody.Code)
+	require.Equal(t, "effortHours", errBody.Field)
+	require.NotContains(t, errBody.Message, "{")
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"devopslabs/internal/domain"
+	"devopslabs/internal/service"
+	"devopslabs/internal/transport/httpapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func performAs(router *gin.Engine, user string, method, path string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.Header.Set(httpapi.UserHeader, user)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func createView(t *testing.T, router *gin.Engine, user string, body string) domain.SavedView {
+	t.Helper()
+
+	resp := performAs(router, user, http.MethodPost, "/api/views", []byte(body))
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var view domain.SavedView
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &view))
+	return view
+}
+
+func TestSavedViewsVisibilityAndOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	private := createView(t, router, "anna", `{"name":" My blockers ","filter":{"status":"BLOCKED","owner":"anna"}}`)
+	require.Equal(t, "My blockers", private.Name)
+	require.Equal(t, domain.VisibilityPrivate, private.Visibility)
+	require.Equal(t, domain.StringMap{"status": domain.StatusBlocked, "owner": "anna"}, private.Filter)
+	require.Equal(t, "score", private.Sort)
+	require.Equal(t, "desc", private.Order)
+
+	shared := createView(t, router, "ivan", `{"name":"Team infra","visibility":"shared","filter":{"tag":"infra"},"sort":"title","order":"asc"}`)
+
+	var views []domain.SavedView
+	resp := performAs(router, "anna", http.MethodGet, "/api/views", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &views))
+	require.Len(t, views, 2)
+
+	resp = performAs(router, "", http.MethodGet, "/api/views", nil)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &views))
+	require.Len(t, views, 1)
+	require.Equal(t, shared.ID, views[0].ID)
+
+	privatePath := fmt.Sprintf("/api/views/%d", private.ID)
+	require.Equal(t, http.StatusNotFound, performAs(router, "ivan", http.MethodGet, privatePath, nil).Code)
+	require.Equal(t, http.StatusNotFound, performAs(router, "ivan", http.MethodGet, privatePath+"/tasks", nil).Code)
+
+	sharedPath := fmt.Sprintf("/api/views/%d", shared.ID)
+	require.Equal(t, http.StatusOK, performAs(router, "anna", http.MethodGet, sharedPath, nil).Code)
+
+	resp = performAs(router, "anna", http.MethodPut, sharedPath, []byte(`{"name":"Hijacked"}`))
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	var errBody httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errBody))
+	require.Equal(t, httpapi.CodeForbidden, errBody.Code)
+	require.Equal(t, http.StatusForbidden, performAs(router, "anna", http.MethodDelete, sharedPath, nil).Code)
+
+	resp = performAs(router, "", http.MethodPost, "/api/views", []byte(`{"name":"Anonymous"}`))
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errBody))
+	require.Equal(t, httpapi.CodeUserRequired, errBody.Code)
+
+	resp = performAs(router, "ivan", http.MethodPut, sharedPath, []byte(`{"name":"Team infra","visibility":"private"}`))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, http.StatusNotFound, performAs(router, "anna", http.MethodGet, sharedPath, nil).Code)
+
+	require.Equal(t, http.StatusNoContent, performAs(router, "ivan", http.MethodDelete, sharedPath, nil).Code)
+	require.Equal(t, http.StatusNotFound, performAs(router, "ivan", http.MethodGet, sharedPath, nil).Code)
+}
+
+func TestSavedViewValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	resp := performAs(router, "anna", http.MethodPost, "/api/views", []byte(`{"name":"  ","filter":{"status":"todo,someday","priority":"urgent"}}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	var body httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Equal(t, httpapi.CodeValidation, body.Code)
+	codes := []string{}
+	for _, fieldErr := range body.Errors {
+		codes = append(codes, fieldErr.Code)
+	}
+	require.ElementsMatch(t, []string{httpapi.CodeFieldRequired, service.CodeInvalidStatus, service.CodeInvalidPriority}, codes)
+
+	resp = performAs(router, "anna", http.MethodPost, "/api/views", []byte(`{"name":"Mine","visibility":"public","filter":{"assignee":"anna"}}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 2)
+
+	resp = performAs(router, "anna", http.MethodPost, "/api/views", []byte(`{"name":"Broken","filter":{"filter":"priority>>high"}}`))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestSavedViewExecutesFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := httpapi.NewRouter(newInMemoryTaskStore())
+
+	createTask(t, router, `{"title":"Rotate keys","priority":"critical","owner":"bob","tags":["infra"]}`)
+	createTask(t, router, `{"title":"Patch hosts","priority":"high","owner":"alice","tags":["infra"]}`)
+	createTask(t, router, `{"title":"Update wiki","priority":"low","owner":"alice","tags":["infra"]}`)
+	createTask(t, router, `{"title":"Ship CI","priority":"high","owner":"alice"}`)
+
+	view := createView(t, router, "alice", `{"name":"Hot infra","visibility":"shared","filter":{"tag":"infra","filter":"priority >= high"},"sort":"title","order":"asc"}`)
+	require.Equal(t, "priority>=high", view.Filter["filter"])
+
+	resp := performAs(router, "bob", http.MethodGet, fmt.Sprintf("/api/views/%d/tasks", view.ID), nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var tasks []taskResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tasks))
+	require.Len(t, tasks, 2)
+	require.Equal(t, "Patch hosts", tasks[0].Title)
+	require.Equal(t, "Rotate keys", tasks[1].Title)
+
+	resp = performAs(router, "bob", http.MethodGet, fmt.Sprintf("/api/views/%d/insights", view.ID), nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var insights service.Insights
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &insights))
+	require.Equal(t, 2, insights.Total)
+}
@@ -27,7 +27,8 @@ export function createTask(payload: TaskPayload): Promise<Task> {
 export function updateTask(id: number, payload: TaskPayload, force = false): Promise<Task> {
   const suffix = force ? "?force=true" : "";
   return request<Task>(`/api/tasks/${id}${suffix}`, {
-    method: "PUT",
+    method: "PATCH",
+    headers: { "Content-Type": "application/merge-patch+json" },
     body: JSON.stringify(payload),
   });
 }
@@ -8,7 +8,10 @@ export async function request<T>(path: string, options?: RequestInit): Promise<T
 
   if (!response.ok) {
     const body = await response.json().catch(() => ({}));
-    const message = body?.error || "Ошибка запроса";
+    const details = Array.isArray(body?.errors) && body.errors.length > 1
+      ? body.errors.map((item: { message?: string }) => item.message).filter(Boolean).join("; ")
+      : "";
+    const message = details || body?.message || body?.error || "Ошибка запроса";
     throw new Error(message);
   }
 
@@ -42,7 +42,10 @@ describe("tasks api", () => {
     expect(global.fetch).toHaveBeenNthCalledWith(
       2,
       expect.stringContaining("/api/tasks/1?force=true"),
-      expect.objectContaining({ method: "PUT" })
+      expect.objectContaining({
+        method: "PATCH",
+        headers: { "Content-Type": "application/merge-patch+json" },
+      })
     );
 
     const thirdCallUrl = (global.fetch as ReturnType<typeof vi.fn>).mock.calls[2][0] as string;
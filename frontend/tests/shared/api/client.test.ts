@@ -43,6 +43,33 @@ describe("client", () => {
     await expect(request("/boom")).rejects.toThrow("Boom");
   });
 
+  it("throws structured error messages", async () => {
+    (global.fetch as ReturnType<typeof vi.fn>).mockResolvedValueOnce(
+      response({ code: "invalid_status", message: "некорректный статус: weird", field: "status" }, false, 400)
+    );
+
+    await expect(request("/structured")).rejects.toThrow("некорректный статус: weird");
+  });
+
+  it("joins messages of several validation errors", async () => {
+    (global.fetch as ReturnType<typeof vi.fn>).mockResolvedValueOnce(
+      response(
+        {
+          code: "validation_failed",
+          message: "данные не прошли проверку",
+          errors: [
+            { code: "title_required", message: "нужно указать название", field: "title" },
+            { code: "invalid_status", message: "некорректный статус: weird", field: "status" },
+          ],
+        },
+        false,
+        400
+      )
+    );
+
+    await expect(request("/validation")).rejects.toThrow("нужно указать название; некорректный статус: weird");
+  });
+
   it("throws fallback error messages", async () => {
     (global.fetch as ReturnType<typeof vi.fn>).mockResolvedValueOnce({
       ok: false,